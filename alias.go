@@ -0,0 +1,162 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/afero"
+	"os"
+	"path/filepath"
+)
+
+// AliasRule computes alternative paths for the manga directory,
+// relative to the DownloadOptions.Directory.
+// Symlinks to the manga directory will be created at those paths.
+//
+// Anilist is nil if the manga wasn't found on Anilist.
+//
+// See DownloadOptions.Aliases
+type AliasRule func(manga Manga, anilist *AnilistManga) []string
+
+// AliasAnilistTitles creates aliases named after the english,
+// romaji and native titles of the manga on Anilist.
+func AliasAnilistTitles() AliasRule {
+	return func(_ Manga, anilist *AnilistManga) []string {
+		if anilist == nil {
+			return nil
+		}
+
+		var aliases []string
+		for _, title := range []string{
+			anilist.Title.English,
+			anilist.Title.Romaji,
+			anilist.Title.Native,
+		} {
+			if title != "" {
+				aliases = append(aliases, sanitizePath(title))
+			}
+		}
+
+		return aliases
+	}
+}
+
+// AliasGenres creates per-genre views of the library
+// in the given directory, e.g. "Genres/Action/Berserk".
+func AliasGenres(dir string) AliasRule {
+	return func(manga Manga, anilist *AnilistManga) []string {
+		if anilist == nil {
+			return nil
+		}
+
+		aliases := make([]string, len(anilist.Genres))
+		for i, genre := range anilist.Genres {
+			aliases[i] = filepath.Join(dir, sanitizePath(genre), sanitizePath(manga.Info().Title))
+		}
+
+		return aliases
+	}
+}
+
+// createAliases creates symlinks to the manga directory
+// according to the DownloadOptions.Aliases.
+func (c *Client) createAliases(ctx context.Context, manga Manga, options DownloadOptions) error {
+	if len(options.Aliases) == 0 {
+		return nil
+	}
+
+	if !options.CreateMangaDir {
+		return errors.New("aliases require manga directory")
+	}
+
+	linker, ok := c.options.FS.(symlinkFs)
+	if !ok {
+		return errors.New("filesystem doesn't support symlinks")
+	}
+
+	var anilist *AnilistManga
+	mangaWithAnilist, ok, err := c.Anilist().MakeMangaWithAnilist(ctx, manga)
+	if err != nil {
+		return err
+	}
+
+	if ok {
+		anilist = &mangaWithAnilist.Anilist
+	}
+
+	mangaDir := filepath.Join(options.Directory, c.ComputeMangaFilename(manga))
+
+	for _, rule := range options.Aliases {
+		for _, alias := range rule(manga, anilist) {
+			aliasPath := filepath.Join(options.Directory, alias)
+			if aliasPath == mangaDir {
+				continue
+			}
+
+			// relative target keeps the link valid if the library is moved
+			target, err := filepath.Rel(filepath.Dir(aliasPath), mangaDir)
+			if err != nil {
+				return err
+			}
+
+			create, err := c.shouldCreateAlias(linker, aliasPath)
+			if err != nil {
+				return err
+			}
+
+			if !create {
+				continue
+			}
+
+			if err := c.options.FS.MkdirAll(filepath.Dir(aliasPath), modeDir); err != nil {
+				return err
+			}
+
+			c.options.Log(fmt.Sprintf("Creating alias %s", aliasPath))
+			if err := linker.SymlinkIfPossible(target, aliasPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// symlinkFs is the filesystem that can create and inspect symlinks
+type symlinkFs interface {
+	afero.Linker
+	afero.Lstater
+}
+
+// shouldCreateAlias checks whether the alias is missing or is a stale link,
+// i.e. its target no longer exists, in which case the link is removed.
+// Links are not followed, so that stale ones are told apart from missing.
+//
+// Existing files, directories and valid links are kept,
+// e.g. the same alias of another manga.
+func (c *Client) shouldCreateAlias(linker symlinkFs, aliasPath string) (bool, error) {
+	info, _, err := linker.LstatIfPossible(aliasPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return true, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	if info.Mode()&os.ModeSymlink == 0 {
+		return false, nil
+	}
+
+	exists, err := afero.Exists(c.options.FS, aliasPath)
+	if err != nil || exists {
+		return false, err
+	}
+
+	c.options.Log(fmt.Sprintf("Removing stale alias %s", aliasPath))
+	if err := c.options.FS.Remove(aliasPath); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,76 @@
+package libmangal
+
+import (
+	"context"
+	"github.com/spf13/afero"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCreateAliasesReplacesStaleLinks(t *testing.T) {
+	dir := t.TempDir()
+
+	provider := newFakeProvider(t, 1, 1)
+	client := newTestClient(t, provider)
+	client.options.FS = afero.NewOsFs()
+	seedAnilist(t, client, AnilistManga{ID: 1})
+
+	options := testDownloadOptions()
+	options.Directory = dir
+	options.CreateMangaDir = true
+	options.Aliases = []AliasRule{func(Manga, *AnilistManga) []string {
+		return []string{"stale", "valid", "directory"}
+	}}
+
+	manga := provider.manga()
+	mangaDir := filepath.Join(dir, client.ComputeMangaFilename(manga))
+	if err := os.Mkdir(mangaDir, modeDir); err != nil {
+		t.Fatal(err)
+	}
+
+	// stale link of the renamed manga, valid link of another one
+	// and the directory that is not a link at all
+	if err := os.Symlink("renamed", filepath.Join(dir, "stale")); err != nil {
+		t.Skipf("symlinks are not supported: %s", err)
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "another"), modeDir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Symlink("another", filepath.Join(dir, "valid")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.Mkdir(filepath.Join(dir, "directory"), modeDir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.createAliases(context.Background(), manga, options); err != nil {
+		t.Fatal(err)
+	}
+
+	for alias, want := range map[string]string{
+		"stale": filepath.Base(mangaDir),
+		"valid": "another",
+	} {
+		target, err := os.Readlink(filepath.Join(dir, alias))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if target != want {
+			t.Errorf("%s: got link to %q, want %q", alias, target, want)
+		}
+	}
+
+	info, err := os.Lstat(filepath.Join(dir, "directory"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !info.IsDir() {
+		t.Error("directory was replaced")
+	}
+}
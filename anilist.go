@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -19,31 +20,62 @@ type anilistRequestBody struct {
 	Variables map[string]any `json:"variables"`
 }
 
+// Date is a possibly partial date, unknown parts are zero.
+// See PublicationRun
 type Date struct {
 	Year  int `json:"year"`
 	Month int `json:"month"`
 	Day   int `json:"day"`
 }
 
+// Anilist is the Anilist API client.
+// It is safe for concurrent use by multiple goroutines.
 type Anilist struct {
-	accessToken string
-	options     AnilistOptions
+	// token is a pointer so that it's shared between copies
+	token        *atomic.Pointer[AnilistToken]
+	cacheIndexes anilistCacheIndexes
+
+	// pendingBindings is nil if the review is disabled
+	pendingBindings *storeIndex
+
+	options AnilistOptions
 }
 
 // NewAnilist constructs new Anilist client
 func NewAnilist(options AnilistOptions) Anilist {
-	var accessToken string
-	found, err := options.AccessTokenStore.Get(anilistStoreAccessCodeStoreKey, &accessToken)
+	anilist := Anilist{
+		token: &atomic.Pointer[AnilistToken]{},
+		cacheIndexes: anilistCacheIndexes{
+			queryToIDs: newStoreIndex(options.QueryToIDsStore),
+			idToManga:  newStoreIndex(options.IDToMangaStore),
+		},
+		options: options,
+	}
 
-	anilist := Anilist{options: options}
+	if options.PendingBindingsStore != nil {
+		anilist.pendingBindings = newStoreIndex(options.PendingBindingsStore)
+	}
 
-	if err == nil && found {
-		anilist.accessToken = accessToken
+	if token, ok := loadAnilistToken(options.AccessTokenStore); ok {
+		anilist.setToken(token)
 	}
 
 	return anilist
 }
 
+// getAccessToken returns the access token or empty string if it has expired
+func (a *Anilist) getAccessToken() string {
+	if token := a.token.Load(); token != nil && !token.Expired() {
+		return token.AccessToken
+	}
+
+	return ""
+}
+
+func (a *Anilist) setToken(token AnilistToken) {
+	a.token.Store(&token)
+}
+
 // GetByID gets anilist manga by its id
 func (a *Anilist) GetByID(
 	ctx context.Context,
@@ -161,28 +193,12 @@ func (a *Anilist) searchMangas(
 	ctx context.Context,
 	query string,
 ) ([]AnilistManga, error) {
-	body := anilistRequestBody{
-		Query: anilistQuerySearchByName,
-		Variables: map[string]any{
-			"query": query,
-		},
-	}
-
-	data, err := sendRequest[struct {
-		Page struct {
-			Media []AnilistManga `json:"media"`
-		} `json:"page"`
-	}](ctx, a, body)
-
+	page, err := a.searchMangasPage(ctx, query, 1, anilistSearchPerPage)
 	if err != nil {
 		return nil, err
 	}
 
-	mangas := data.Page.Media
-
-	a.options.Log(fmt.Sprintf("Found %d manga(s) on AnilistSearch.", len(mangas)))
-
-	return mangas, nil
+	return page.Mangas, nil
 }
 
 type anilistResponse[Data any] struct {
@@ -198,23 +214,30 @@ func sendRequest[Data any](
 	anilist *Anilist,
 	requestBody anilistRequestBody,
 ) (data Data, err error) {
-	marshalled, err := json.Marshal(requestBody)
+	compress := anilist.options.CompressRequests
+
+	encoded, err := encodeAnilistRequestBody(requestBody, compress)
 	if err != nil {
 		return data, err
 	}
 
-	request, err := http.NewRequestWithContext(ctx, http.MethodPost, anilistAPIURL, bytes.NewReader(marshalled))
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, anilistAPIURL, bytes.NewReader(encoded))
 	if err != nil {
 		return data, err
 	}
 
 	request.Header.Set("Content-Type", "application/json")
 	request.Header.Set("Accept", "application/json")
+	request.Header.Set("Accept-Encoding", "gzip")
+
+	if compress {
+		request.Header.Set("Content-Encoding", "gzip")
+	}
 
 	if anilist.IsAuthorized() {
 		request.Header.Set(
 			"Authorization",
-			fmt.Sprintf("Bearer %s", anilist.accessToken),
+			fmt.Sprintf("Bearer %s", anilist.getAccessToken()),
 		)
 	}
 
@@ -253,9 +276,15 @@ func sendRequest[Data any](
 		return data, fmt.Errorf(response.Status)
 	}
 
+	responseBody, err := anilistResponseBody(response)
+	if err != nil {
+		return data, err
+	}
+	defer responseBody.Close()
+
 	var body anilistResponse[Data]
 
-	err = json.NewDecoder(response.Body).Decode(&body)
+	err = json.NewDecoder(responseBody).Decode(&body)
 	if err != nil {
 		return data, err
 	}
@@ -309,6 +338,11 @@ func (a *Anilist) FindClosestManga(
 		return AnilistManga{}, false, AnilistError{err}
 	}
 
+	err = a.queueBindingReview(title, manga)
+	if err != nil {
+		return AnilistManga{}, false, AnilistError{err}
+	}
+
 	return manga, true, nil
 }
 
@@ -328,8 +362,7 @@ func (a *Anilist) findClosestManga(
 			return AnilistManga{}, false, err
 		}
 
-		if len(mangas) > 0 {
-			closest := mangas[0]
+		if closest, ok := a.closestManga(title, mangas); ok {
 			a.options.Log(fmt.Sprintf("Found closest manga on AnilistSearch: %q #%d", closest.String(), closest.ID))
 			return closest, true, nil
 		}
@@ -355,6 +388,28 @@ func (a *Anilist) findClosestManga(
 	return AnilistManga{}, false, nil
 }
 
+// closestManga picks the search result with the highest matchConfidence.
+// Results are ordered by relevance, so the first one wins the ties.
+// False is returned if none reaches AnilistOptions.MinTitleSimilarity
+func (a *Anilist) closestManga(title string, mangas []AnilistManga) (AnilistManga, bool) {
+	var (
+		closest    AnilistManga
+		confidence = -1.0
+	)
+
+	for _, manga := range mangas {
+		if current := a.matchConfidence(title, manga); current > confidence {
+			closest, confidence = manga, current
+		}
+	}
+
+	if confidence < 0 || confidence < a.options.MinTitleSimilarity {
+		return AnilistManga{}, false
+	}
+
+	return closest, true
+}
+
 func (a *Anilist) BindTitleWithID(title string, anilistMangaId int) error {
 	err := a.options.TitleToIDStore.Set(title, anilistMangaId)
 	if err != nil {
@@ -364,12 +419,38 @@ func (a *Anilist) BindTitleWithID(title string, anilistMangaId int) error {
 	return nil
 }
 
+// SetMangaProgress sets the progress of the manga in the user's list.
+//
+// If the manga is not on the list yet it will be added with
+// the status CURRENT, unless AnilistOptions.AddToListOnProgress is false.
+// Planned mangas are moved to CURRENT as well.
 func (a *Anilist) SetMangaProgress(ctx context.Context, mangaID, chapterNumber int) error {
 	if !a.IsAuthorized() {
 		return AnilistError{errors.New("not authorized")}
 	}
 
-	_, err := sendRequest[struct {
+	entry, err := a.mediaListEntry(ctx, mangaID)
+	if err != nil {
+		return AnilistError{err}
+	}
+
+	variables := map[string]any{
+		"id":       mangaID,
+		"progress": chapterNumber,
+	}
+
+	query := anilistMutationSaveProgress
+
+	switch {
+	case entry == nil && !a.options.AddToListOnProgress:
+		a.options.Log(fmt.Sprintf("Manga %d is not on the list, skipping progress", mangaID))
+		return nil
+	case entry == nil, entry.Status == "PLANNING":
+		query = anilistMutationSaveProgressWithStatus
+		variables["status"] = "CURRENT"
+	}
+
+	_, err = sendRequest[struct {
 		SaveMediaListEntry struct {
 			ID int `json:"id"`
 		} `json:"SaveMediaListEntry"`
@@ -377,11 +458,8 @@ func (a *Anilist) SetMangaProgress(ctx context.Context, mangaID, chapterNumber i
 		ctx,
 		a,
 		anilistRequestBody{
-			Query: anilistMutationSaveProgress,
-			Variables: map[string]any{
-				"id":       mangaID,
-				"progress": chapterNumber,
-			},
+			Query:     query,
+			Variables: variables,
 		},
 	)
 
@@ -392,6 +470,40 @@ func (a *Anilist) SetMangaProgress(ctx context.Context, mangaID, chapterNumber i
 	return nil
 }
 
+type anilistMediaListEntry struct {
+	ID     int    `json:"id"`
+	Status string `json:"status"`
+}
+
+// mediaListEntry returns the user's list entry for the manga
+// or nil if the manga is not on the list
+func (a *Anilist) mediaListEntry(ctx context.Context, mangaID int) (*anilistMediaListEntry, error) {
+	data, err := sendRequest[struct {
+		Media *struct {
+			MediaListEntry *anilistMediaListEntry `json:"mediaListEntry"`
+		} `json:"media"`
+	}](
+		ctx,
+		a,
+		anilistRequestBody{
+			Query: anilistQueryMediaListEntry,
+			Variables: map[string]any{
+				"id": mangaID,
+			},
+		},
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Media == nil {
+		return nil, fmt.Errorf("manga with id %d not found", mangaID)
+	}
+
+	return data.Media.MediaListEntry, nil
+}
+
 func (a *Anilist) MakeMangaWithAnilist(
 	ctx context.Context,
 	manga Manga,
@@ -414,6 +526,13 @@ func (a *Anilist) MakeMangaWithAnilist(
 		return MangaWithAnilist{}, false, nil
 	}
 
+	if a.options.FullCredits {
+		anilistManga, err = a.WithFullCredits(ctx, anilistManga)
+		if err != nil {
+			return MangaWithAnilist{}, false, err
+		}
+	}
+
 	return MangaWithAnilist{
 		Manga:   manga,
 		Anilist: anilistManga,
@@ -6,19 +6,93 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/philippgille/gokv"
 	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	anilistOAuthAuthorizeURL = "https://anilist.co/api/v2/oauth/authorize"
+	anilistOAuthTokenURL     = "https://anilist.co/api/v2/oauth/token"
+
+	// AnilistPinRedirectURI makes Anilist show the authorization code
+	// to the user instead of redirecting, so that it can be pasted
+	// into the app. Used for the out-of-band authorization.
+	AnilistPinRedirectURI = "https://anilist.co/api/v2/oauth/pin"
 )
 
 type AnilistLoginCredentials struct {
 	ID     string
 	Secret string
 	Code   string
+
+	// RedirectURI must match the one of the authorization URL.
+	// Empty value means AnilistPinRedirectURI
+	RedirectURI string
+}
+
+func (c AnilistLoginCredentials) redirectURI() string {
+	if c.RedirectURI == "" {
+		return AnilistPinRedirectURI
+	}
+
+	return c.RedirectURI
+}
+
+// AnilistToken is the Anilist OAuth token
+type AnilistToken struct {
+	AccessToken string `json:"access_token"`
+
+	// RefreshToken is empty if Anilist didn't issue one
+	RefreshToken string `json:"refresh_token,omitempty"`
+
+	// ExpiresAt is zero if the expiry is unknown
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// Expired reports whether the token has expired
+func (t AnilistToken) Expired() bool {
+	return t.ExpiresWithin(0)
+}
+
+// ExpiresWithin reports whether the token expires within the given duration
+func (t AnilistToken) ExpiresWithin(duration time.Duration) bool {
+	if t.ExpiresAt.IsZero() {
+		return false
+	}
+
+	return time.Now().Add(duration).After(t.ExpiresAt)
 }
 
 // anilistStoreAccessCodeStoreKey is the key used to store Anilist access code.
 // It's needed, since the KV interface always expects a key to be passed.
+//
+// Deprecated: kept to read tokens stored by older versions, see anilistTokenStoreKey
 const anilistStoreAccessCodeStoreKey = "hi"
 
+// anilistTokenStoreKey is the key of the AnilistToken in the AccessTokenStore
+const anilistTokenStoreKey = "token"
+
+// AnilistAuthorizationURL returns the URL where the user grants access
+// to the app with the given client id.
+//
+// With the empty redirect URI, AnilistPinRedirectURI is used:
+// Anilist shows the code to the user, which is then passed to Authorize.
+// This allows to log in from apps that can't receive redirects, e.g. CLIs.
+func AnilistAuthorizationURL(clientID, redirectURI string) string {
+	if redirectURI == "" {
+		redirectURI = AnilistPinRedirectURI
+	}
+
+	query := url.Values{}
+	query.Set("client_id", clientID)
+	query.Set("redirect_uri", redirectURI)
+	query.Set("response_type", "code")
+
+	return anilistOAuthAuthorizeURL + "?" + query.Encode()
+}
+
 // Authorize will obtain Anilist token for API requests
 func (a *Anilist) Authorize(
 	ctx context.Context,
@@ -39,17 +113,54 @@ func (a *Anilist) Authorize(
 		}
 	}
 
-	body, err := json.Marshal(map[string]string{
+	return a.requestToken(ctx, map[string]string{
 		"client_id":     credentials.ID,
 		"client_secret": credentials.Secret,
 		"code":          credentials.Code,
 		"grant_type":    "authorization_code",
-		"redirect_uri":  "https://anilist.co/api/v2/oauth/pin",
+		"redirect_uri":  credentials.redirectURI(),
 	})
-	if err != nil {
-		return err
+}
+
+// RefreshToken obtains the new token using the refresh token
+// of the current one. Code of the credentials is not used.
+func (a *Anilist) RefreshToken(
+	ctx context.Context,
+	credentials AnilistLoginCredentials,
+) error {
+	a.options.Log("refreshing Anilist token")
+
+	token, ok := a.Token()
+	if !ok || token.RefreshToken == "" {
+		return AnilistError{errors.New("no refresh token")}
+	}
+
+	return a.requestToken(ctx, map[string]string{
+		"client_id":     credentials.ID,
+		"client_secret": credentials.Secret,
+		"refresh_token": token.RefreshToken,
+		"grant_type":    "refresh_token",
+		"redirect_uri":  credentials.redirectURI(),
+	})
+}
+
+// RefreshTokenIfExpiring refreshes the token if it expires within the given duration.
+// Nothing is done if the token can't be refreshed.
+func (a *Anilist) RefreshTokenIfExpiring(
+	ctx context.Context,
+	credentials AnilistLoginCredentials,
+	within time.Duration,
+) error {
+	token, ok := a.Token()
+	if !ok || token.RefreshToken == "" || !token.ExpiresWithin(within) {
+		return nil
 	}
 
+	return a.RefreshToken(ctx, credentials)
+}
+
+func (a *Anilist) requestToken(ctx context.Context, params map[string]string) error {
+	body, err := json.Marshal(params)
 	if err != nil {
 		return AnilistError{err}
 	}
@@ -57,7 +168,7 @@ func (a *Anilist) Authorize(
 	request, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodPost,
-		"https://anilist.co/api/v2/oauth/token",
+		anilistOAuthTokenURL,
 		bytes.NewBuffer(body),
 	)
 	if err != nil {
@@ -78,7 +189,9 @@ func (a *Anilist) Authorize(
 	}
 
 	var authResponse struct {
-		AccessToken string `json:"access_token"`
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int64  `json:"expires_in"`
 	}
 
 	err = json.NewDecoder(response.Body).Decode(&authResponse)
@@ -86,14 +199,58 @@ func (a *Anilist) Authorize(
 		return AnilistError{err}
 	}
 
-	if err := a.options.AccessTokenStore.Set(anilistStoreAccessCodeStoreKey, authResponse.AccessToken); err != nil {
+	token := AnilistToken{
+		AccessToken:  authResponse.AccessToken,
+		RefreshToken: authResponse.RefreshToken,
+	}
+
+	if authResponse.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(authResponse.ExpiresIn) * time.Second)
+	}
+
+	return a.SetToken(token)
+}
+
+// SetToken stores the token obtained elsewhere, e.g. with the implicit grant
+func (a *Anilist) SetToken(token AnilistToken) error {
+	if token.AccessToken == "" {
+		return AnilistError{errors.New("access token is empty")}
+	}
+
+	if err := a.options.AccessTokenStore.Set(anilistTokenStoreKey, token); err != nil {
 		return err
 	}
 
-	a.accessToken = authResponse.AccessToken
+	a.setToken(token)
 	return nil
 }
 
+// Token returns the current token, expired or not
+func (a *Anilist) Token() (AnilistToken, bool) {
+	if token := a.token.Load(); token != nil {
+		return *token, true
+	}
+
+	return AnilistToken{}, false
+}
+
+// IsAuthorized reports whether there is the token that hasn't expired
 func (a *Anilist) IsAuthorized() bool {
-	return a.accessToken != ""
+	return a.getAccessToken() != ""
+}
+
+// loadAnilistToken reads the token from the store,
+// falling back to the access code stored by older versions
+func loadAnilistToken(store gokv.Store) (AnilistToken, bool) {
+	var token AnilistToken
+	if found, err := store.Get(anilistTokenStoreKey, &token); err == nil && found && token.AccessToken != "" {
+		return token, true
+	}
+
+	var accessToken string
+	if found, err := store.Get(anilistStoreAccessCodeStoreKey, &accessToken); err == nil && found && accessToken != "" {
+		return AnilistToken{AccessToken: accessToken}, true
+	}
+
+	return AnilistToken{}, false
 }
@@ -0,0 +1,144 @@
+package libmangal
+
+import (
+	"sort"
+	"time"
+)
+
+// PendingBinding is the automatic title binding made by
+// Anilist.FindClosestManga with a low confidence, waiting for review.
+type PendingBinding struct {
+	// Title is the searched title
+	Title string `json:"title"`
+
+	// MangaID is the id of the bound Anilist manga
+	MangaID int `json:"mangaId"`
+
+	// MangaTitle is the title of the bound Anilist manga
+	MangaTitle string `json:"mangaTitle"`
+
+	// Confidence is the similarity of the titles from 0 to 1
+	Confidence float64 `json:"confidence"`
+
+	// CreatedAt is the time the binding was made
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// matchConfidence returns how close the manga is to the searched title
+// by comparing it with all the manga titles and synonyms
+// using AnilistOptions.TitleSimilarity
+func (a *Anilist) matchConfidence(title string, manga AnilistManga) float64 {
+	metric := a.options.TitleSimilarity
+	if metric == nil {
+		metric = LevenshteinSimilarity
+	}
+
+	candidates := append([]string{
+		manga.Title.English,
+		manga.Title.Romaji,
+		manga.Title.Native,
+	}, manga.Synonyms...)
+
+	var best float64
+	for _, candidate := range candidates {
+		if candidate == "" {
+			continue
+		}
+
+		if similarity := metric.Similarity(title, candidate); similarity > best {
+			best = similarity
+		}
+	}
+
+	return best
+}
+
+// queueBindingReview adds the binding to the review queue if
+// its confidence is below AnilistOptions.BindingReviewThreshold
+func (a *Anilist) queueBindingReview(title string, manga AnilistManga) error {
+	if a.pendingBindings == nil {
+		return nil
+	}
+
+	confidence := a.matchConfidence(title, manga)
+	if confidence >= a.options.BindingReviewThreshold {
+		return nil
+	}
+
+	return a.pendingBindings.set(title, PendingBinding{
+		Title:      title,
+		MangaID:    manga.ID,
+		MangaTitle: manga.String(),
+		Confidence: confidence,
+		CreatedAt:  time.Now(),
+	})
+}
+
+// PendingBindings returns automatic bindings waiting for review,
+// least confident first.
+// See ApproveBinding and RejectBinding
+func (a *Anilist) PendingBindings() ([]PendingBinding, error) {
+	if a.pendingBindings == nil {
+		return nil, nil
+	}
+
+	keys, err := a.pendingBindings.keys()
+	if err != nil {
+		return nil, AnilistError{err}
+	}
+
+	bindings := make([]PendingBinding, 0, len(keys))
+	for _, key := range keys {
+		var binding PendingBinding
+		found, err := a.options.PendingBindingsStore.Get(key, &binding)
+		if err != nil {
+			return nil, AnilistError{err}
+		}
+
+		if found {
+			bindings = append(bindings, binding)
+		}
+	}
+
+	sort.Slice(bindings, func(i, j int) bool {
+		return bindings[i].Confidence < bindings[j].Confidence
+	})
+
+	return bindings, nil
+}
+
+// ApproveBinding keeps the binding of the title and removes it from the review queue
+func (a *Anilist) ApproveBinding(title string) error {
+	if a.pendingBindings == nil {
+		return nil
+	}
+
+	if err := a.pendingBindings.delete(title); err != nil {
+		return AnilistError{err}
+	}
+
+	return nil
+}
+
+// RejectBinding removes the binding of the title from the review queue
+// and binds it to the correct manga id instead.
+//
+// If the correct id is 0 the binding is removed,
+// so the title will be searched again next time.
+func (a *Anilist) RejectBinding(title string, correctID int) error {
+	if correctID != 0 {
+		if err := a.BindTitleWithID(title, correctID); err != nil {
+			return err
+		}
+	} else if err := a.options.TitleToIDStore.Delete(title); err != nil {
+		return AnilistError{err}
+	}
+
+	return a.ApproveBinding(title)
+}
+
+// PendingBindings returns Anilist bindings waiting for review.
+// See Anilist.PendingBindings
+func (c *Client) PendingBindings() ([]PendingBinding, error) {
+	return c.Anilist().PendingBindings()
+}
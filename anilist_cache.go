@@ -1,6 +1,35 @@
 package libmangal
 
-import "strconv"
+import (
+	"strconv"
+	"time"
+)
+
+// anilistCacheIndexes track writes to the Anilist cache stores
+type anilistCacheIndexes struct {
+	queryToIDs, idToManga *storeIndex
+}
+
+// PruneCache removes cached search results and mangas
+// that were stored more than maxAge ago.
+// Title to id mappings are kept, since those may be set by BindTitleWithID.
+//
+// It returns the number of removed entries.
+func (a *Anilist) PruneCache(maxAge time.Duration) (int, error) {
+	var pruned int
+	for _, index := range []*storeIndex{
+		a.cacheIndexes.queryToIDs,
+		a.cacheIndexes.idToManga,
+	} {
+		n, err := index.prune(maxAge)
+		pruned += n
+		if err != nil {
+			return pruned, AnilistError{err}
+		}
+	}
+
+	return pruned, nil
+}
 
 func (a *Anilist) cacheStatusQuery(
 	query string,
@@ -13,7 +42,7 @@ func (a *Anilist) cacheSetQuery(
 	query string,
 	ids []int,
 ) error {
-	return a.options.QueryToIDsStore.Set(query, ids)
+	return a.cacheIndexes.queryToIDs.set(query, ids)
 }
 
 func (a *Anilist) cacheStatusTitle(
@@ -41,5 +70,5 @@ func (a *Anilist) cacheSetId(
 	id int,
 	manga AnilistManga,
 ) error {
-	return a.options.IDToMangaStore.Set(strconv.Itoa(id), manga)
+	return a.cacheIndexes.idToManga.set(strconv.Itoa(id), manga)
 }
@@ -0,0 +1,130 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+)
+
+// AnilistCharacter is the character of the manga on Anilist
+type AnilistCharacter struct {
+	// ID is the id of the character on Anilist
+	ID   int `json:"id" jsonschema:"description=ID of the character on Anilist."`
+	Name struct {
+		// Full is the full name of the character.
+		Full string `json:"full" jsonschema:"description=Full name of the character."`
+		// Native is the native name of the character. Usually in kanji.
+		Native string `json:"native" jsonschema:"description=Native name of the character. Usually in kanji."`
+	} `json:"name"`
+	// Role of the character in the manga: MAIN, SUPPORTING or BACKGROUND.
+	// It's set only for the full credits.
+	Role string `json:"role" jsonschema:"enum=MAIN,enum=SUPPORTING,enum=BACKGROUND"`
+}
+
+// AnilistStaffEdge is the staff member credited for the manga
+type AnilistStaffEdge struct {
+	Role string `json:"role" jsonschema:"description=Role of the staff member."`
+	Node struct {
+		Name struct {
+			Full string `json:"full" jsonschema:"description=Full name of the staff member."`
+		} `json:"name"`
+	} `json:"node"`
+}
+
+// anilistCreditsPerPage is the page size of the characters and staff queries
+const anilistCreditsPerPage = 25
+
+// WithFullCredits returns the manga with complete lists of characters
+// and staff instead of the main ones only. The lists are fetched page
+// by page, up to AnilistOptions.MaxCreditPages, and cached.
+func (a *Anilist) WithFullCredits(
+	ctx context.Context,
+	manga AnilistManga,
+) (AnilistManga, error) {
+	if manga.FullCredits {
+		return manga, nil
+	}
+
+	found, cached, err := a.cacheStatusId(manga.ID)
+	if err != nil {
+		return AnilistManga{}, AnilistError{err}
+	}
+
+	if found && cached.FullCredits {
+		return cached, nil
+	}
+
+	a.options.Log(fmt.Sprintf("Getting characters and staff of #%d on Anilist...", manga.ID))
+
+	var (
+		characters []AnilistCharacter
+		staff      []AnilistStaffEdge
+	)
+
+	moreCharacters, moreStaff := true, true
+	for page := 1; moreCharacters || moreStaff; page++ {
+		if limit := a.options.MaxCreditPages; limit > 0 && page > limit {
+			a.options.Log(fmt.Sprintf("Credits of #%d are truncated to %d pages", manga.ID, limit))
+			break
+		}
+
+		data, err := sendRequest[struct {
+			Media *struct {
+				Characters struct {
+					PageInfo struct {
+						HasNextPage bool `json:"hasNextPage"`
+					} `json:"pageInfo"`
+					Edges []struct {
+						Role string           `json:"role"`
+						Node AnilistCharacter `json:"node"`
+					} `json:"edges"`
+				} `json:"characters"`
+				Staff struct {
+					PageInfo struct {
+						HasNextPage bool `json:"hasNextPage"`
+					} `json:"pageInfo"`
+					Edges []AnilistStaffEdge `json:"edges"`
+				} `json:"staff"`
+			} `json:"media"`
+		}](ctx, a, anilistRequestBody{
+			Query: anilistQueryCredits,
+			Variables: map[string]any{
+				"id":      manga.ID,
+				"page":    page,
+				"perPage": anilistCreditsPerPage,
+			},
+		})
+
+		if err != nil {
+			return AnilistManga{}, AnilistError{err}
+		}
+
+		if data.Media == nil {
+			return AnilistManga{}, AnilistError{fmt.Errorf("manga with id %d not found", manga.ID)}
+		}
+
+		if moreCharacters {
+			for _, edge := range data.Media.Characters.Edges {
+				character := edge.Node
+				character.Role = edge.Role
+				characters = append(characters, character)
+			}
+
+			moreCharacters = data.Media.Characters.PageInfo.HasNextPage
+		}
+
+		if moreStaff {
+			staff = append(staff, data.Media.Staff.Edges...)
+			moreStaff = data.Media.Staff.PageInfo.HasNextPage
+		}
+	}
+
+	manga.Characters.Nodes = characters
+	manga.Staff.Edges = staff
+	manga.FullCredits = true
+
+	if err := a.cacheSetId(manga.ID, manga); err != nil {
+		return AnilistManga{}, AnilistError{err}
+	}
+
+	return manga, nil
+}
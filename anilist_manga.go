@@ -1,9 +1,6 @@
 package libmangal
 
-import (
-	"fmt"
-	"strings"
-)
+import "strings"
 
 type AnilistManga struct {
 	// Title of the manga
@@ -46,26 +43,16 @@ type AnilistManga struct {
 	// Genres of the manga
 	Genres []string `json:"genres" jsonschema:"description=Genres of the manga."`
 	// Characters are the primary characters of the manga.
+	// All characters are listed if FullCredits is true.
 	Characters struct {
-		Nodes []struct {
-			Name struct {
-				// Full is the full name of the character.
-				Full string `json:"full" jsonschema:"description=Full name of the character."`
-				// Native is the native name of the character. Usually in kanji.
-				Native string `json:"native" jsonschema:"description=Native name of the character. Usually in kanji."`
-			} `json:"name"`
-		} `json:"nodes"`
+		Nodes []AnilistCharacter `json:"nodes"`
 	} `json:"characters"`
 	Staff struct {
-		Edges []struct {
-			Role string `json:"role" jsonschema:"description=Role of the staff member."`
-			Node struct {
-				Name struct {
-					Full string `json:"full" jsonschema:"description=Full name of the staff member."`
-				} `json:"name"`
-			} `json:"node"`
-		} `json:"edges"`
+		Edges []AnilistStaffEdge `json:"edges"`
 	} `json:"staff"`
+	// FullCredits is true if Characters and Staff are complete lists.
+	// See Anilist.WithFullCredits
+	FullCredits bool `json:"fullCredits" jsonschema:"description=Whether characters and staff are complete lists."`
 	// StartDate is the date the manga started publishing.
 	StartDate Date `json:"startDate" jsonschema:"description=Date the manga started publishing."`
 	// EndDate is the date the manga ended publishing.
@@ -74,6 +61,8 @@ type AnilistManga struct {
 	Synonyms []string `json:"synonyms" jsonschema:"description=Synonyms of the manga (Alternative titles)."`
 	// Status is the status of the manga. (FINISHED, RELEASING, NOT_YET_RELEASED, CANCELLED)
 	Status string `json:"status" jsonschema:"enum=FINISHED,enum=RELEASING,enum=NOT_YET_RELEASED,enum=CANCELLED,enum=HIATUS"`
+	// IsAdult is true if the manga is intended only for 18+ adult audiences.
+	IsAdult bool `json:"isAdult" jsonschema:"description=Whether the manga is intended only for 18+ adult audiences."`
 	// IDMal is the id of the manga on MyAnimeList.
 	IDMal int `json:"idMal" jsonschema:"description=ID of the manga on MyAnimeList."`
 	// Chapters is the amount of chapters the manga has when complete.
@@ -124,12 +113,6 @@ func (m *MangaWithAnilist) SeriesJSON() SeriesJSON {
 		}
 	}
 
-	publicationRun := fmt.Sprintf(
-		"%d %d - %d %d",
-		m.Anilist.StartDate.Month, m.Anilist.StartDate.Year,
-		m.Anilist.EndDate.Month, m.Anilist.EndDate.Year,
-	)
-
 	return SeriesJSON{
 		Type:                 "comicSeries",
 		Name:                 m.Info().Title,
@@ -142,7 +125,7 @@ func (m *MangaWithAnilist) SeriesJSON() SeriesJSON {
 		ComicID:              m.Anilist.ID,
 		BookType:             "Print",
 		TotalIssues:          m.Anilist.Chapters,
-		PublicationRun:       publicationRun,
+		PublicationRun:       PublicationRun(m.Anilist.StartDate, m.Anilist.EndDate, m.Anilist.Status == "RELEASING"),
 	}
 }
 
@@ -204,11 +187,11 @@ func (c ChapterOfMangaWithAnilist) ComicInfoXML() ComicInfoXML {
 		Month:           date.Month,
 		Day:             date.Day,
 		Publisher:       "",
-		LanguageISO:     "",
+		LanguageISO:     c.languageISO(),
 		StoryArc:        "",
 		StoryArcNumber:  0,
 		ScanInformation: "",
-		AgeRating:       "",
+		AgeRating:       c.MangaWithAnilist.Anilist.ageRating(),
 		CommunityRating: float32(c.MangaWithAnilist.Anilist.AverageScore) / 20,
 		Review:          "",
 		GTIN:            "",
@@ -221,3 +204,53 @@ func (c ChapterOfMangaWithAnilist) ComicInfoXML() ComicInfoXML {
 		Notes:           "",
 	}
 }
+
+// matureTags are Anilist tags that make manga unsuitable for teens
+var matureTags = map[string]struct{}{
+	"Nudity":          {},
+	"Gore":            {},
+	"Sexual Violence": {},
+	"Rape":            {},
+	"Torture":         {},
+	"Suicide":         {},
+}
+
+// ageRating maps Anilist adult flag, genres and tags to the ComicInfo age rating.
+// Empty string means unknown.
+func (a AnilistManga) ageRating() string {
+	if a.IsAdult {
+		return "Adults Only 18+"
+	}
+
+	for _, genre := range a.Genres {
+		if genre == "Ecchi" {
+			return "Mature 17+"
+		}
+	}
+
+	for _, tag := range a.Tags {
+		if _, ok := matureTags[tag.Name]; ok {
+			return "Mature 17+"
+		}
+	}
+
+	return ""
+}
+
+// countryLanguages maps Anilist country of origin to the ISO 639-1 language code
+var countryLanguages = map[string]string{
+	"JP": "ja",
+	"KR": "ko",
+	"CN": "zh",
+	"TW": "zh",
+}
+
+// languageISO returns the language of the chapter if provider specified it,
+// otherwise the original language of the manga derived from its country of origin.
+func (c ChapterOfMangaWithAnilist) languageISO() string {
+	if language := c.Info().Language; language != "" {
+		return language
+	}
+
+	return countryLanguages[c.MangaWithAnilist.Anilist.Country]
+}
@@ -54,6 +54,7 @@ staff {
 	}
 }
 status
+isAdult
 synonyms
 siteUrl
 chapters
@@ -64,8 +65,15 @@ externalLinks {
 `
 
 const anilistQuerySearchByName = `
-query ($query: String) {
-	Page (page: 1, perPage: 30) {
+query ($query: String, $page: Int, $perPage: Int) {
+	Page (page: $page, perPage: $perPage) {
+		pageInfo {
+			total
+			currentPage
+			lastPage
+			hasNextPage
+			perPage
+		}
 		media (search: $query, type: MANGA) {
 			` + anilistQueryCommon + `
 		}
@@ -79,9 +87,122 @@ query ($id: Int) {
 	}
 }`
 
+const anilistQueryMediaListEntry = `
+query ($id: Int) {
+	Media (id: $id, type: MANGA) {
+		mediaListEntry {
+			id
+			status
+		}
+	}
+}`
+
 const anilistMutationSaveProgress = `
 mutation ($id: Int, $progress: Int) {
-	SaveMediaListEntry (mediaId: $id, progress: $progress, status: CURRENT) {
+	SaveMediaListEntry (mediaId: $id, progress: $progress) {
+		id
+	}
+}`
+
+const anilistMutationSaveProgressWithStatus = `
+mutation ($id: Int, $progress: Int, $status: MediaListStatus) {
+	SaveMediaListEntry (mediaId: $id, progress: $progress, status: $status) {
 		id
 	}
 }`
+
+const anilistQuerySearchStaff = `
+query ($query: String) {
+	Page (page: 1, perPage: 30) {
+		staff (search: $query) {
+			id
+			name {
+				full
+				native
+			}
+			primaryOccupations
+			siteUrl
+		}
+	}
+}`
+
+const anilistQueryStaffMangas = `
+query ($id: Int, $page: Int) {
+	Staff (id: $id) {
+		staffMedia (type: MANGA, page: $page, perPage: 50) {
+			pageInfo {
+				hasNextPage
+			}
+			nodes {
+				` + anilistQueryCommon + `
+			}
+		}
+	}
+}`
+
+const anilistQueryRecommendations = `
+query ($id: Int, $page: Int) {
+	Media (id: $id, type: MANGA) {
+		recommendations (page: $page, perPage: 25, sort: [RATING_DESC, ID]) {
+			pageInfo {
+				hasNextPage
+			}
+			nodes {
+				rating
+				mediaRecommendation {
+					type
+					` + anilistQueryCommon + `
+				}
+			}
+		}
+	}
+}`
+
+const anilistQueryRelations = `
+query ($id: Int) {
+	Media (id: $id, type: MANGA) {
+		relations {
+			edges {
+				relationType
+				node {
+					type
+					` + anilistQueryCommon + `
+				}
+			}
+		}
+	}
+}`
+
+const anilistQueryCredits = `
+query ($id: Int, $page: Int, $perPage: Int) {
+	Media (id: $id, type: MANGA) {
+		characters (page: $page, perPage: $perPage, sort: [ROLE, RELEVANCE, ID]) {
+			pageInfo {
+				hasNextPage
+			}
+			edges {
+				role
+				node {
+					id
+					name {
+						full
+						native
+					}
+				}
+			}
+		}
+		staff (page: $page, perPage: $perPage, sort: [RELEVANCE, ID]) {
+			pageInfo {
+				hasNextPage
+			}
+			edges {
+				role
+				node {
+					name {
+						full
+					}
+				}
+			}
+		}
+	}
+}`
@@ -0,0 +1,146 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+)
+
+// AnilistRecommendation is the manga recommended by Anilist users
+type AnilistRecommendation struct {
+	// Rating is the number of users who agreed with the recommendation
+	Rating int `json:"rating"`
+
+	// Manga is the recommended manga
+	Manga AnilistManga `json:"manga"`
+}
+
+// AnilistRelation is the manga related to another one
+type AnilistRelation struct {
+	// Type of the relation, e.g. SEQUEL, PREQUEL, SIDE_STORY, SPIN_OFF or ADAPTATION
+	Type string `json:"type"`
+
+	// Manga is the related manga
+	Manga AnilistManga `json:"manga"`
+}
+
+// anilistMediaNode is the related media which may be not a manga
+type anilistMediaNode struct {
+	Type string `json:"type"`
+	AnilistManga
+}
+
+// Recommendations returns the page of the manga recommendations,
+// the most rated first. Pages start from 1.
+//
+// The bool reports whether there are more pages.
+func (a *Anilist) Recommendations(
+	ctx context.Context,
+	mangaID int,
+	page int,
+) ([]AnilistRecommendation, bool, error) {
+	a.options.Log(fmt.Sprintf("Getting recommendations for #%d on Anilist (page %d)...", mangaID, page))
+
+	data, err := sendRequest[struct {
+		Media *struct {
+			Recommendations struct {
+				PageInfo struct {
+					HasNextPage bool `json:"hasNextPage"`
+				} `json:"pageInfo"`
+				Nodes []struct {
+					Rating              int               `json:"rating"`
+					MediaRecommendation *anilistMediaNode `json:"mediaRecommendation"`
+				} `json:"nodes"`
+			} `json:"recommendations"`
+		} `json:"media"`
+	}](ctx, a, anilistRequestBody{
+		Query: anilistQueryRecommendations,
+		Variables: map[string]any{
+			"id":   mangaID,
+			"page": page,
+		},
+	})
+
+	if err != nil {
+		return nil, false, AnilistError{err}
+	}
+
+	if data.Media == nil {
+		return nil, false, AnilistError{fmt.Errorf("manga with id %d not found", mangaID)}
+	}
+
+	var recommendations []AnilistRecommendation
+	for _, node := range data.Media.Recommendations.Nodes {
+		// recommended media is null if it was deleted
+		if node.MediaRecommendation == nil || node.MediaRecommendation.Type != "MANGA" {
+			continue
+		}
+
+		manga := node.MediaRecommendation.AnilistManga
+		if err := a.cacheSetId(manga.ID, manga); err != nil {
+			return nil, false, AnilistError{err}
+		}
+
+		recommendations = append(recommendations, AnilistRecommendation{
+			Rating: node.Rating,
+			Manga:  manga,
+		})
+	}
+
+	a.options.Log(fmt.Sprintf("Found %d recommendation(s) for #%d on Anilist.", len(recommendations), mangaID))
+
+	return recommendations, data.Media.Recommendations.PageInfo.HasNextPage, nil
+}
+
+// Relations returns mangas related to the manga, e.g. its sequel.
+// Related anime are skipped.
+func (a *Anilist) Relations(
+	ctx context.Context,
+	mangaID int,
+) ([]AnilistRelation, error) {
+	a.options.Log(fmt.Sprintf("Getting relations of #%d on Anilist...", mangaID))
+
+	data, err := sendRequest[struct {
+		Media *struct {
+			Relations struct {
+				Edges []struct {
+					RelationType string            `json:"relationType"`
+					Node         *anilistMediaNode `json:"node"`
+				} `json:"edges"`
+			} `json:"relations"`
+		} `json:"media"`
+	}](ctx, a, anilistRequestBody{
+		Query: anilistQueryRelations,
+		Variables: map[string]any{
+			"id": mangaID,
+		},
+	})
+
+	if err != nil {
+		return nil, AnilistError{err}
+	}
+
+	if data.Media == nil {
+		return nil, AnilistError{fmt.Errorf("manga with id %d not found", mangaID)}
+	}
+
+	var relations []AnilistRelation
+	for _, edge := range data.Media.Relations.Edges {
+		if edge.Node == nil || edge.Node.Type != "MANGA" {
+			continue
+		}
+
+		manga := edge.Node.AnilistManga
+		if err := a.cacheSetId(manga.ID, manga); err != nil {
+			return nil, AnilistError{err}
+		}
+
+		relations = append(relations, AnilistRelation{
+			Type:  edge.RelationType,
+			Manga: manga,
+		})
+	}
+
+	a.options.Log(fmt.Sprintf("Found %d relation(s) of #%d on Anilist.", len(relations), mangaID))
+
+	return relations, nil
+}
@@ -0,0 +1,191 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	// anilistSearchPerPage is the page size of SearchMangas
+	anilistSearchPerPage = 30
+
+	// anilistMaxPerPage is the largest page size Anilist allows
+	anilistMaxPerPage = 50
+)
+
+// AnilistPageInfo describes the page of Anilist results
+type AnilistPageInfo struct {
+	// Total number of results. Anilist may cap it for large result sets.
+	Total       int  `json:"total"`
+	CurrentPage int  `json:"currentPage"`
+	LastPage    int  `json:"lastPage"`
+	HasNextPage bool `json:"hasNextPage"`
+	PerPage     int  `json:"perPage"`
+}
+
+// AnilistSearchPage is the single page of the search results
+type AnilistSearchPage struct {
+	Mangas   []AnilistManga
+	PageInfo AnilistPageInfo
+}
+
+// anilistCachedSearchPage is stored in the QueryToIDsStore
+type anilistCachedSearchPage struct {
+	IDs      []int           `json:"ids"`
+	PageInfo AnilistPageInfo `json:"pageInfo"`
+}
+
+// anilistSearchPageKey is the cache key of the search page.
+// It differs from the plain query used by SearchMangas.
+func anilistSearchPageKey(query string, page, perPage int) string {
+	return fmt.Sprintf("%s\x00page=%d,perPage=%d", query, page, perPage)
+}
+
+// SearchMangasPaged searches mangas on Anilist returning the given page.
+// Pages start from 1, perPage is at most 50.
+func (a *Anilist) SearchMangasPaged(
+	ctx context.Context,
+	query string,
+	page, perPage int,
+) (AnilistSearchPage, error) {
+	if page < 1 {
+		return AnilistSearchPage{}, AnilistError{fmt.Errorf("invalid page: %d", page)}
+	}
+
+	if perPage < 1 || perPage > anilistMaxPerPage {
+		return AnilistSearchPage{}, AnilistError{fmt.Errorf("perPage must be between 1 and %d", anilistMaxPerPage)}
+	}
+
+	a.options.Log(fmt.Sprintf("Searching manga on AnilistSearch, page %d...", page))
+
+	key := anilistSearchPageKey(query, page, perPage)
+
+	var cached anilistCachedSearchPage
+	found, err := a.options.QueryToIDsStore.Get(key, &cached)
+	if err != nil {
+		return AnilistSearchPage{}, AnilistError{err}
+	}
+
+	if found {
+		result := AnilistSearchPage{PageInfo: cached.PageInfo}
+
+		for _, id := range cached.IDs {
+			manga, ok, err := a.GetByID(ctx, id)
+			if err != nil {
+				return AnilistSearchPage{}, err
+			}
+
+			if ok {
+				result.Mangas = append(result.Mangas, manga)
+			}
+		}
+
+		return result, nil
+	}
+
+	result, err := a.searchMangasPage(ctx, query, page, perPage)
+	if err != nil {
+		return AnilistSearchPage{}, err
+	}
+
+	cached = anilistCachedSearchPage{
+		IDs:      make([]int, len(result.Mangas)),
+		PageInfo: result.PageInfo,
+	}
+
+	for i, manga := range result.Mangas {
+		if err := a.cacheSetId(manga.ID, manga); err != nil {
+			return AnilistSearchPage{}, AnilistError{err}
+		}
+
+		cached.IDs[i] = manga.ID
+	}
+
+	if err := a.cacheIndexes.queryToIDs.set(key, cached); err != nil {
+		return AnilistSearchPage{}, AnilistError{err}
+	}
+
+	return result, nil
+}
+
+func (a *Anilist) searchMangasPage(
+	ctx context.Context,
+	query string,
+	page, perPage int,
+) (AnilistSearchPage, error) {
+	body := anilistRequestBody{
+		Query: anilistQuerySearchByName,
+		Variables: map[string]any{
+			"query":   query,
+			"page":    page,
+			"perPage": perPage,
+		},
+	}
+
+	data, err := sendRequest[struct {
+		Page struct {
+			PageInfo AnilistPageInfo `json:"pageInfo"`
+			Media    []AnilistManga  `json:"media"`
+		} `json:"page"`
+	}](ctx, a, body)
+
+	if err != nil {
+		return AnilistSearchPage{}, err
+	}
+
+	a.options.Log(fmt.Sprintf("Found %d manga(s) on AnilistSearch.", len(data.Page.Media)))
+
+	return AnilistSearchPage{
+		Mangas:   data.Page.Media,
+		PageInfo: data.Page.PageInfo,
+	}, nil
+}
+
+// AnilistSearchIterator streams all search results, fetching pages as needed.
+// See Anilist.SearchMangasIter
+//
+// Unlike Anilist, it's not safe for concurrent use.
+type AnilistSearchIterator struct {
+	anilist *Anilist
+	query   string
+	perPage int
+
+	// page is the last fetched page
+	page    int
+	mangas  []AnilistManga
+	hasNext bool
+}
+
+// SearchMangasIter returns the iterator over all search results.
+// Pages of the given size are fetched lazily with SearchMangasPaged.
+func (a *Anilist) SearchMangasIter(query string, perPage int) *AnilistSearchIterator {
+	return &AnilistSearchIterator{
+		anilist: a,
+		query:   query,
+		perPage: perPage,
+		hasNext: true,
+	}
+}
+
+// Next returns the next manga.
+// False is returned when there are no results left.
+func (s *AnilistSearchIterator) Next(ctx context.Context) (AnilistManga, bool, error) {
+	for len(s.mangas) == 0 {
+		if !s.hasNext {
+			return AnilistManga{}, false, nil
+		}
+
+		page, err := s.anilist.SearchMangasPaged(ctx, s.query, s.page+1, s.perPage)
+		if err != nil {
+			return AnilistManga{}, false, err
+		}
+
+		s.page++
+		s.mangas = page.Mangas
+		s.hasNext = page.PageInfo.HasNextPage && len(page.Mangas) > 0
+	}
+
+	manga := s.mangas[0]
+	s.mangas = s.mangas[1:]
+	return manga, true, nil
+}
@@ -0,0 +1,114 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+)
+
+// AnilistStaff is a person credited on Anilist, e.g. author or artist
+type AnilistStaff struct {
+	// ID is the id of the staff on Anilist
+	ID int `json:"id"`
+
+	// Name of the staff
+	Name struct {
+		Full   string `json:"full"`
+		Native string `json:"native"`
+	} `json:"name"`
+
+	// PrimaryOccupations of the staff, e.g. Mangaka
+	PrimaryOccupations []string `json:"primaryOccupations"`
+
+	// SiteURL is the url of the staff page on Anilist
+	SiteURL string `json:"siteUrl"`
+}
+
+func (a AnilistStaff) String() string {
+	if a.Name.Full != "" {
+		return a.Name.Full
+	}
+
+	return a.Name.Native
+}
+
+// SearchStaff searches for staff (authors, artists, etc.) by name
+func (a *Anilist) SearchStaff(
+	ctx context.Context,
+	name string,
+) ([]AnilistStaff, error) {
+	a.options.Log(fmt.Sprintf("Searching staff %q on Anilist...", name))
+
+	data, err := sendRequest[struct {
+		Page struct {
+			Staff []AnilistStaff `json:"staff"`
+		} `json:"page"`
+	}](ctx, a, anilistRequestBody{
+		Query: anilistQuerySearchStaff,
+		Variables: map[string]any{
+			"query": name,
+		},
+	})
+
+	if err != nil {
+		return nil, AnilistError{err}
+	}
+
+	staff := data.Page.Staff
+
+	a.options.Log(fmt.Sprintf("Found %d staff on Anilist.", len(staff)))
+
+	return staff, nil
+}
+
+// MangasByStaff returns all mangas the staff has worked on
+func (a *Anilist) MangasByStaff(
+	ctx context.Context,
+	staffID int,
+) ([]AnilistManga, error) {
+	a.options.Log(fmt.Sprintf("Getting mangas of staff #%d on Anilist...", staffID))
+
+	var mangas []AnilistManga
+
+	for page := 1; ; page++ {
+		data, err := sendRequest[struct {
+			Staff *struct {
+				StaffMedia struct {
+					PageInfo struct {
+						HasNextPage bool `json:"hasNextPage"`
+					} `json:"pageInfo"`
+					Nodes []AnilistManga `json:"nodes"`
+				} `json:"staffMedia"`
+			} `json:"staff"`
+		}](ctx, a, anilistRequestBody{
+			Query: anilistQueryStaffMangas,
+			Variables: map[string]any{
+				"id":   staffID,
+				"page": page,
+			},
+		})
+
+		if err != nil {
+			return nil, AnilistError{err}
+		}
+
+		if data.Staff == nil {
+			return nil, AnilistError{fmt.Errorf("staff with id %d not found", staffID)}
+		}
+
+		for _, manga := range data.Staff.StaffMedia.Nodes {
+			if err := a.cacheSetId(manga.ID, manga); err != nil {
+				return nil, AnilistError{err}
+			}
+
+			mangas = append(mangas, manga)
+		}
+
+		if !data.Staff.StaffMedia.PageInfo.HasNextPage {
+			break
+		}
+	}
+
+	a.options.Log(fmt.Sprintf("Found %d manga(s) of staff #%d on Anilist.", len(mangas), staffID))
+
+	return mangas, nil
+}
@@ -0,0 +1,97 @@
+package libmangal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// anilistCompactQueries caches compacted queries,
+// since the same few queries are sent over and over.
+var anilistCompactQueries sync.Map
+
+// compactQuery removes redundant whitespace from the GraphQL query
+// to reduce the request size. GraphQL treats any whitespace
+// sequence the same way, so it's safe.
+func compactQuery(query string) string {
+	if compacted, ok := anilistCompactQueries.Load(query); ok {
+		return compacted.(string)
+	}
+
+	compacted := strings.Join(strings.Fields(query), " ")
+	anilistCompactQueries.Store(query, compacted)
+
+	return compacted
+}
+
+// newAnilistHTTPClient constructs http client tuned for
+// sending many small requests to the single host.
+func newAnilistHTTPClient() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 8
+	transport.IdleConnTimeout = 90 * time.Second
+	transport.ForceAttemptHTTP2 = true
+
+	return &http.Client{
+		Transport: transport,
+	}
+}
+
+// encodeAnilistRequestBody marshals request body with the compacted query.
+// Body is gzipped if compress is true.
+func encodeAnilistRequestBody(requestBody anilistRequestBody, compress bool) ([]byte, error) {
+	requestBody.Query = compactQuery(requestBody.Query)
+
+	marshalled, err := json.Marshal(requestBody)
+	if err != nil {
+		return nil, err
+	}
+
+	if !compress {
+		return marshalled, nil
+	}
+
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+
+	if _, err := gzipWriter.Write(marshalled); err != nil {
+		return nil, err
+	}
+
+	if err := gzipWriter.Close(); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// anilistResponseBody returns decompressed response body.
+// Closing it also drains the underlying body so that
+// the connection can be reused.
+func anilistResponseBody(response *http.Response) (io.ReadCloser, error) {
+	if response.Header.Get("Content-Encoding") != "gzip" {
+		return drainingBody{Reader: response.Body, body: response.Body}, nil
+	}
+
+	gzipReader, err := gzip.NewReader(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return drainingBody{Reader: gzipReader, body: response.Body}, nil
+}
+
+type drainingBody struct {
+	io.Reader
+	body io.ReadCloser
+}
+
+func (d drainingBody) Close() error {
+	_, _ = io.Copy(io.Discard, d.body)
+	return d.body.Close()
+}
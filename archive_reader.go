@@ -0,0 +1,110 @@
+package libmangal
+
+import (
+	"archive/zip"
+	"fmt"
+	"github.com/spf13/afero"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// archiveImageExtensions are the extensions of images read from archives
+var archiveImageExtensions = map[string]bool{
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+	".gif":  true,
+	".webp": true,
+}
+
+// archiveImage is the image file read from the archive
+type archiveImage struct {
+	name  string
+	image []byte
+}
+
+// archiveReaderFunc reads images from the archive file
+type archiveReaderFunc func(file afero.File, size int64) ([]archiveImage, error)
+
+// archiveReaders map archive extensions to their readers
+var archiveReaders = map[string]archiveReaderFunc{
+	".cbz": readZIPImages,
+	".zip": readZIPImages,
+}
+
+// readArchiveImages reads images from the archive at path sorted by their names
+func readArchiveImages(fs afero.Fs, path string) ([]archiveImage, error) {
+	reader, ok := archiveReaders[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		return nil, fmt.Errorf("unsupported archive: %s", path)
+	}
+
+	info, err := fs.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := fs.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	images, err := reader(file, info.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	// embedded cover comes first as it does when saving
+	sort.SliceStable(images, func(i, j int) bool {
+		return images[i].name < images[j].name
+	})
+
+	return images, nil
+}
+
+// isArchiveImage checks whether the archive entry is a page image
+func isArchiveImage(name string) bool {
+	base := filepath.Base(name)
+
+	// e.g. __MACOSX resource forks
+	if strings.HasPrefix(base, ".") || strings.HasPrefix(name, "__MACOSX") {
+		return false
+	}
+
+	return archiveImageExtensions[strings.ToLower(filepath.Ext(base))]
+}
+
+func readZIPImages(file afero.File, size int64) ([]archiveImage, error) {
+	archive, err := zip.NewReader(file, size)
+	if err != nil {
+		return nil, err
+	}
+
+	var images []archiveImage
+	for _, entry := range archive.File {
+		if entry.FileInfo().IsDir() || !isArchiveImage(entry.Name) {
+			continue
+		}
+
+		reader, err := entry.Open()
+		if err != nil {
+			return nil, err
+		}
+
+		image, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		images = append(images, archiveImage{
+			name:  entry.Name,
+			image: image,
+		})
+	}
+
+	return images, nil
+}
@@ -0,0 +1,50 @@
+package libmangal
+
+import (
+	"errors"
+	"github.com/nwaples/rardecode"
+	"github.com/spf13/afero"
+	"io"
+)
+
+func init() {
+	archiveReaders[".cbr"] = readRARImages
+	archiveReaders[".rar"] = readRARImages
+}
+
+// readRARImages reads images from the RAR archive.
+// Encrypted and multi-volume archives are not supported.
+func readRARImages(file afero.File, _ int64) ([]archiveImage, error) {
+	archive, err := rardecode.NewReader(file, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var images []archiveImage
+	for {
+		header, err := archive.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		if header.IsDir || !isArchiveImage(header.Name) {
+			continue
+		}
+
+		image, err := io.ReadAll(archive)
+		if err != nil {
+			return nil, err
+		}
+
+		images = append(images, archiveImage{
+			name:  header.Name,
+			image: image,
+		})
+	}
+
+	return images, nil
+}
@@ -0,0 +1,122 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// benchmarkPages is the number of pages of the benchmarked chapters
+const benchmarkPages = 32
+
+func BenchmarkDownloadPagesInBatch(b *testing.B) {
+	provider := newFakeProvider(b, 1, benchmarkPages)
+	client := newTestClient(b, provider)
+
+	ctx := context.Background()
+	pages, err := client.ChapterPages(ctx, provider.chapterList()[0])
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := client.DownloadPagesInBatch(ctx, pages); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSaveCBZ(b *testing.B) {
+	client := newTestClient(b, newFakeProvider(b, 1, 0))
+	pages := testPages(benchmarkPages, testJPEG(b, 200, 300))
+	comicInfoXML := ComicInfoXML{Title: "Chapter 1"}.wrapper(DefaultComicInfoOptions())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := client.saveCBZ(pages, io.Discard, nil, comicInfoXML, ""); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSavePDF(b *testing.B) {
+	client := newTestClient(b, newFakeProvider(b, 1, 0))
+	pages := testPages(benchmarkPages, testJPEG(b, 200, 300))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := client.savePDF(pages, io.Discard, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkWrite7z(b *testing.B) {
+	image := testJPEG(b, 200, 300)
+
+	names := make([]string, benchmarkPages)
+	files := make([][]byte, benchmarkPages)
+	for i := range files {
+		names[i] = fmt.Sprintf("%04d.jpg", i+1)
+		files[i] = image
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := write7z(io.Discard, names, files); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// performanceBudget is the upper bound of allocations per operation of the benchmark.
+// Budgets are about twice the measured values, so that only regressions fail them.
+type performanceBudget struct {
+	name      string
+	benchmark func(*testing.B)
+	allocs    int64
+	bytes     int64
+}
+
+func TestPerformanceBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("performance budget is not checked in short mode")
+	}
+
+	if raceEnabled {
+		t.Skip("performance budget is not checked with the race detector")
+	}
+
+	budgets := []performanceBudget{
+		{name: "DownloadPagesInBatch", benchmark: BenchmarkDownloadPagesInBatch, allocs: 3_500, bytes: 1_500_000},
+		{name: "SaveCBZ", benchmark: BenchmarkSaveCBZ, allocs: 1_600, bytes: 1_300_000},
+		{name: "SavePDF", benchmark: BenchmarkSavePDF, allocs: 50_000, bytes: 80_000_000},
+		{name: "Write7z", benchmark: BenchmarkWrite7z, allocs: 100, bytes: 8_000},
+	}
+
+	for _, budget := range budgets {
+		result := testing.Benchmark(budget.benchmark)
+		if result.N == 0 {
+			t.Errorf("%s: benchmark failed", budget.name)
+			continue
+		}
+
+		if allocs := result.AllocsPerOp(); allocs > budget.allocs {
+			t.Errorf("%s: %d allocs/op exceeds the budget of %d", budget.name, allocs, budget.allocs)
+		}
+
+		if bytes := result.AllocedBytesPerOp(); bytes > budget.bytes {
+			t.Errorf("%s: %d B/op exceeds the budget of %d", budget.name, bytes, budget.bytes)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package libmangal
+
+import (
+	"fmt"
+	"github.com/spf13/afero"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CleanPolicy configures Client.CleanCaches.
+// Zero max age disables cleaning of the corresponding category.
+type CleanPolicy struct {
+	// AnilistCacheMaxAge prunes cached Anilist search results
+	// and mangas older than this. See Anilist.PruneCache
+	AnilistCacheMaxAge time.Duration
+
+	// HTTPCaches are the provider caches to prune.
+	HTTPCaches []*ConditionalCache
+
+	// HTTPCacheMaxAge prunes HTTPCaches responses older than this
+	HTTPCacheMaxAge time.Duration
+
+	// PageCacheMaxAge prunes prefetched pages older than this.
+	// See Client.PrefetchChapter
+	PageCacheMaxAge time.Duration
+
+	// Directory is the downloads directory to look for
+	// partial files of the interrupted downloads in.
+	// Empty value skips removing partial files.
+	Directory string
+
+	// PartialMaxAge removes partial files and pages of the interrupted
+	// resumable downloads older than this.
+	// It should be long enough not to touch downloads in progress.
+	PartialMaxAge time.Duration
+
+	// TrashDir is the directory RemoveChapter moves chapters to.
+	// Empty value skips emptying the trash. See RemoveOptions
+	TrashDir string
+
+	// TrashMaxAge removes chapters trashed more than this ago
+	TrashMaxAge time.Duration
+}
+
+// DefaultCleanPolicy constructs default CleanPolicy
+func DefaultCleanPolicy() CleanPolicy {
+	return CleanPolicy{
+		AnilistCacheMaxAge: 30 * 24 * time.Hour,
+		HTTPCacheMaxAge:    7 * 24 * time.Hour,
+		PageCacheMaxAge:    24 * time.Hour,
+		PartialMaxAge:      24 * time.Hour,
+		TrashMaxAge:        30 * 24 * time.Hour,
+	}
+}
+
+// CleanReport is the result of Client.CleanCaches
+type CleanReport struct {
+	// AnilistEntries is the number of removed Anilist cache entries
+	AnilistEntries int
+
+	// HTTPCacheEntries is the number of removed HTTP responses
+	HTTPCacheEntries int
+
+	// PageCacheEntries is the number of removed prefetched pages
+	PageCacheEntries int
+
+	// RemovedFiles are the paths of removed partial files,
+	// partial download directories and trashed chapters
+	RemovedFiles []string
+
+	// ReclaimedBytes is the size of RemovedFiles.
+	// Size of the store entries is not known and not included.
+	ReclaimedBytes int64
+}
+
+// CleanCaches prunes stale entries of the stores and removes
+// leftovers of interrupted downloads and old trash according to the policy,
+// so that long-running applications don't grow them without bound.
+func (c *Client) CleanCaches(policy CleanPolicy) (CleanReport, error) {
+	var report CleanReport
+
+	if policy.AnilistCacheMaxAge > 0 {
+		pruned, err := c.Anilist().PruneCache(policy.AnilistCacheMaxAge)
+		report.AnilistEntries = pruned
+		if err != nil {
+			return report, err
+		}
+	}
+
+	if policy.HTTPCacheMaxAge > 0 {
+		for _, cache := range policy.HTTPCaches {
+			pruned, err := cache.Prune(policy.HTTPCacheMaxAge)
+			report.HTTPCacheEntries += pruned
+			if err != nil {
+				return report, err
+			}
+		}
+	}
+
+	if policy.PageCacheMaxAge > 0 && c.pageCache != nil {
+		pruned, err := c.pageCache.prune(policy.PageCacheMaxAge)
+		report.PageCacheEntries = pruned
+		if err != nil {
+			return report, err
+		}
+	}
+
+	if policy.PartialMaxAge > 0 && policy.Directory != "" {
+		if err := c.removePartialFiles(policy.Directory, policy.PartialMaxAge, &report); err != nil {
+			return report, err
+		}
+	}
+
+	if policy.TrashMaxAge > 0 && policy.TrashDir != "" {
+		// sizes must be computed before the removal
+		sizes := make(map[string]int64)
+		if entries, err := afero.ReadDir(c.options.FS, policy.TrashDir); err == nil {
+			for _, entry := range entries {
+				path := filepath.Join(policy.TrashDir, entry.Name())
+				if sizes[path], err = dirSize(c.options.FS, path); err != nil {
+					return report, err
+				}
+			}
+		}
+
+		removed, err := c.EmptyTrash(policy.TrashDir, policy.TrashMaxAge)
+		for _, path := range removed {
+			report.RemovedFiles = append(report.RemovedFiles, path)
+			report.ReclaimedBytes += sizes[path]
+		}
+
+		if err != nil {
+			return report, err
+		}
+	}
+
+	c.options.Log(fmt.Sprintf(
+		"Cleaned %d anilist entries, %d http responses, %d pages and %d files (%d bytes)",
+		report.AnilistEntries,
+		report.HTTPCacheEntries,
+		report.PageCacheEntries,
+		len(report.RemovedFiles),
+		report.ReclaimedBytes,
+	))
+
+	return report, nil
+}
+
+// removePartialFiles removes files left by the interrupted copies
+// and directories with the pages of the interrupted downloads
+func (c *Client) removePartialFiles(dir string, maxAge time.Duration, report *CleanReport) error {
+	exists, err := afero.DirExists(c.options.FS, dir)
+	if err != nil || !exists {
+		return err
+	}
+
+	var (
+		paths []string
+		sizes []int64
+	)
+
+	err = afero.Walk(c.options.FS, dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			if !strings.HasSuffix(info.Name(), suffixPartial) {
+				return nil
+			}
+
+			// pages are added as the download goes,
+			// so the directory is as old as its newest page
+			modTime, size, err := c.partialDirStat(path)
+			if err != nil {
+				return err
+			}
+
+			if time.Since(modTime) >= maxAge {
+				paths = append(paths, path)
+				sizes = append(sizes, size)
+			}
+
+			return filepath.SkipDir
+		}
+
+		if !strings.HasSuffix(info.Name(), partialFileSuffix) {
+			return nil
+		}
+
+		if time.Since(info.ModTime()) < maxAge {
+			return nil
+		}
+
+		paths = append(paths, path)
+		sizes = append(sizes, info.Size())
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for i, path := range paths {
+		c.options.Log(fmt.Sprintf("Removing partial file %s", path))
+
+		if err := c.options.FS.RemoveAll(path); err != nil {
+			return err
+		}
+
+		report.RemovedFiles = append(report.RemovedFiles, path)
+		report.ReclaimedBytes += sizes[i]
+	}
+
+	return nil
+}
+
+// partialDirStat returns the latest modification time
+// and the total size of the files in the directory
+func (c *Client) partialDirStat(dir string) (time.Time, int64, error) {
+	var (
+		modTime time.Time
+		size    int64
+	)
+
+	err := afero.Walk(c.options.FS, dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info.ModTime().After(modTime) {
+			modTime = info.ModTime()
+		}
+
+		if !info.IsDir() {
+			size += info.Size()
+		}
+
+		return nil
+	})
+
+	return modTime, size, err
+}
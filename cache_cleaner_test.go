@@ -0,0 +1,112 @@
+package libmangal
+
+import (
+	"github.com/spf13/afero"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestCleanCachesPartialFiles(t *testing.T) {
+	client := newTestClient(t, newFakeProvider(t, 0, 0))
+	fs := client.FS()
+
+	old := time.Now().Add(-48 * time.Hour)
+
+	files := []struct {
+		path string
+		old  bool
+	}{
+		{path: "/library/Manga/old.cbz" + suffixPartial + "/0001", old: true},
+		{path: "/library/Manga/old.cbz" + suffixPartial + "/manifest.json", old: true},
+		{path: "/library/Manga/resumed.cbz" + suffixPartial + "/0001", old: true},
+		{path: "/library/Manga/resumed.cbz" + suffixPartial + "/0002"},
+		{path: "/library/Manga/copy.cbz" + partialFileSuffix, old: true},
+		{path: "/library/Manga/fresh.cbz" + partialFileSuffix},
+		{path: "/library/Manga/chapter.cbz", old: true},
+
+		// files of the other tools
+		{path: "/library/Manga/video.mkv.part", old: true},
+		{path: "/library/Manga/sync.partial/file", old: true},
+	}
+
+	for _, file := range files {
+		if err := afero.WriteFile(fs, file.path, []byte("data"), modeFile); err != nil {
+			t.Fatal(err)
+		}
+
+		if file.old {
+			if err := fs.Chtimes(file.path, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	for _, dir := range []string{"/library/Manga/old.cbz" + suffixPartial, "/library/Manga/resumed.cbz" + suffixPartial} {
+		if err := fs.Chtimes(dir, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	report, err := client.CleanCaches(CleanPolicy{
+		Directory:     "/library",
+		PartialMaxAge: 24 * time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(report.RemovedFiles)
+	want := []string{"/library/Manga/copy.cbz" + partialFileSuffix, "/library/Manga/old.cbz" + suffixPartial}
+	if len(report.RemovedFiles) != len(want) || report.RemovedFiles[0] != want[0] || report.RemovedFiles[1] != want[1] {
+		t.Fatalf("got removed %v, want %v", report.RemovedFiles, want)
+	}
+
+	if report.ReclaimedBytes != 3*int64(len("data")) {
+		t.Errorf("got %d reclaimed bytes, want %d", report.ReclaimedBytes, 3*len("data"))
+	}
+
+	for path, want := range map[string]bool{
+		"/library/Manga/old.cbz" + suffixPartial:       false,
+		"/library/Manga/resumed.cbz" + suffixPartial:   true,
+		"/library/Manga/fresh.cbz" + partialFileSuffix: true,
+		"/library/Manga/chapter.cbz":                   true,
+		"/library/Manga/video.mkv.part":                true,
+		"/library/Manga/sync.partial/file":             true,
+	} {
+		exists, err := afero.Exists(fs, path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if exists != want {
+			t.Errorf("%s: got exists %v, want %v", path, exists, want)
+		}
+	}
+}
+
+func TestCleanCachesWithoutDirectory(t *testing.T) {
+	client := newTestClient(t, newFakeProvider(t, 0, 0))
+
+	path := "/library/copy.cbz" + partialFileSuffix
+	if err := afero.WriteFile(client.FS(), path, nil, modeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	old := time.Now().Add(-48 * time.Hour)
+	if err := client.FS().Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	policy := DefaultCleanPolicy()
+	policy.PartialMaxAge = time.Hour
+
+	report, err := client.CleanCaches(policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.RemovedFiles) != 0 {
+		t.Errorf("removed %v without the directory", report.RemovedFiles)
+	}
+}
@@ -0,0 +1,86 @@
+package libmangal
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// captchaSniffLen is how much of the response is searched for signatures
+const captchaSniffLen = 64 << 10
+
+// DefaultCaptchaSignatures returns markers of the common
+// CAPTCHA and bot-check interstitial pages
+func DefaultCaptchaSignatures() []string {
+	return []string{
+		"cf-challenge",
+		"cf_chl_",
+		"challenges.cloudflare.com",
+		"Just a moment...",
+		"Attention Required! | Cloudflare",
+		"g-recaptcha",
+		"www.google.com/recaptcha",
+		"h-captcha",
+		"hcaptcha.com",
+		"ddos-guard",
+	}
+}
+
+// detectCaptcha returns the signature matching the data.
+// Only textual data is searched, so that image bytes
+// never match by accident.
+func detectCaptcha(data []byte, signatures []string) (string, bool) {
+	if len(signatures) == 0 {
+		return "", false
+	}
+
+	if !strings.HasPrefix(http.DetectContentType(data), "text/") {
+		return "", false
+	}
+
+	if len(data) > captchaSniffLen {
+		data = data[:captchaSniffLen]
+	}
+
+	lower := bytes.ToLower(data)
+	for _, signature := range signatures {
+		if bytes.Contains(lower, []byte(strings.ToLower(signature))) {
+			return signature, true
+		}
+	}
+
+	return "", false
+}
+
+// checkCaptcha returns CaptchaRequiredError if the data
+// is the CAPTCHA page to be solved at the given URL
+func (c *Client) checkCaptcha(data []byte, URL string) error {
+	signature, ok := detectCaptcha(data, c.options.CaptchaSignatures)
+	if !ok {
+		return nil
+	}
+
+	c.options.Log("CAPTCHA required: " + URL)
+
+	return CaptchaRequiredError{
+		URL:       URL,
+		Signature: signature,
+	}
+}
+
+// checkCaptchaResponse checks the body of the HTML response
+// returned instead of the image. The body is left readable.
+func (c *Client) checkCaptchaResponse(response *http.Response) error {
+	if !strings.HasPrefix(response.Header.Get("Content-Type"), "text/html") {
+		return nil
+	}
+
+	data, err := io.ReadAll(io.LimitReader(response.Body, captchaSniffLen))
+	response.Body = io.NopCloser(io.MultiReader(bytes.NewReader(data), response.Body))
+	if err != nil {
+		return nil
+	}
+
+	return c.checkCaptcha(data, response.Request.URL.String())
+}
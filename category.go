@@ -0,0 +1,332 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Category is the user-defined collection of mangas,
+// e.g. "Reading", "Completed" or "Plan to download"
+type Category struct {
+	// Name of the category, must be unique
+	Name string `json:"name"`
+
+	// Watch marks mangas of the category to be checked for new chapters.
+	// See Client.WatchedMangas and Watcher
+	Watch bool `json:"watch"`
+
+	// AutoDownload marks mangas of the category to have their
+	// new chapters downloaded automatically. See Client.AutoDownloadMangas
+	AutoDownload bool `json:"autoDownload"`
+}
+
+// CategorizedManga is the manga with the categories it belongs to
+type CategorizedManga struct {
+	Manga      MangaInfo `json:"manga"`
+	Categories []string  `json:"categories"`
+
+	// Completed is true if the series has ended and all
+	// its chapters are available. See Client.IsSeriesComplete
+	Completed bool `json:"completed"`
+}
+
+const (
+	categoryKeyPrefix         = "category/"
+	categorizedMangaKeyPrefix = "manga/"
+)
+
+// categoryStore keeps categories in the ClientOptions.CategoryStore.
+// It's shared between the copies of the client.
+type categoryStore struct {
+	index *storeIndex
+
+	// mu guards read-modify-write of the mangas categories
+	mu sync.Mutex
+}
+
+func (c *Client) requireCategories() (*categoryStore, error) {
+	if c.categories == nil {
+		return nil, fmt.Errorf("category store is not configured")
+	}
+
+	return c.categories, nil
+}
+
+// SetCategory creates the category or updates the existing one.
+// It returns an error if ClientOptions.CategoryStore is nil.
+func (c *Client) SetCategory(category Category) error {
+	if category.Name == "" {
+		return fmt.Errorf("category name must be non-empty")
+	}
+
+	store, err := c.requireCategories()
+	if err != nil {
+		return err
+	}
+
+	return store.index.set(categoryKeyPrefix+category.Name, category)
+}
+
+// Category returns the category with the given name
+func (c *Client) Category(name string) (Category, bool, error) {
+	if c.categories == nil {
+		return Category{}, false, nil
+	}
+
+	var category Category
+	found, err := c.categories.index.store.Get(categoryKeyPrefix+name, &category)
+	return category, found, err
+}
+
+// Categories returns all categories sorted by name
+func (c *Client) Categories() ([]Category, error) {
+	if c.categories == nil {
+		return nil, nil
+	}
+
+	keys, err := c.categories.index.keys()
+	if err != nil {
+		return nil, err
+	}
+
+	var categories []Category
+	for _, key := range keys {
+		name, ok := strings.CutPrefix(key, categoryKeyPrefix)
+		if !ok {
+			continue
+		}
+
+		category, found, err := c.Category(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			categories = append(categories, category)
+		}
+	}
+
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+
+	return categories, nil
+}
+
+// RemoveCategory removes the category and detaches mangas from it
+func (c *Client) RemoveCategory(name string) error {
+	if c.categories == nil {
+		return nil
+	}
+
+	mangas, err := c.categorizedMangas()
+	if err != nil {
+		return err
+	}
+
+	for _, manga := range mangas {
+		if err := c.updateMangaCategories(manga.Manga, func(categories []string) []string {
+			return removeString(categories, name)
+		}); err != nil {
+			return err
+		}
+	}
+
+	return c.categories.index.delete(categoryKeyPrefix + name)
+}
+
+// AddToCategory attaches the manga to the existing category
+func (c *Client) AddToCategory(manga Manga, name string) error {
+	if _, err := c.requireCategories(); err != nil {
+		return err
+	}
+
+	_, found, err := c.Category(name)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("category %q doesn't exist", name)
+	}
+
+	return c.updateMangaCategories(manga.Info(), func(categories []string) []string {
+		return append(removeString(categories, name), name)
+	})
+}
+
+// RemoveFromCategory detaches the manga from the category
+func (c *Client) RemoveFromCategory(manga Manga, name string) error {
+	if c.categories == nil {
+		return nil
+	}
+
+	return c.updateMangaCategories(manga.Info(), func(categories []string) []string {
+		return removeString(categories, name)
+	})
+}
+
+// MangaCategories returns names of the categories of the manga
+func (c *Client) MangaCategories(manga Manga) ([]string, error) {
+	if c.categories == nil {
+		return nil, nil
+	}
+
+	var categorized CategorizedManga
+	_, err := c.categories.index.store.Get(c.categorizedMangaKey(manga.Info()), &categorized)
+	return categorized.Categories, err
+}
+
+// CategoryMangas returns mangas of the category.
+//
+// Mangas are returned as MangaInfo, since the provider mangas can't be
+// restored from the store. See Client.FindManga
+func (c *Client) CategoryMangas(name string) ([]MangaInfo, error) {
+	return c.filterCategorizedMangas(func(category Category) bool {
+		return category.Name == name
+	})
+}
+
+// WatchedMangas returns mangas of the categories with Category.Watch
+func (c *Client) WatchedMangas() ([]MangaInfo, error) {
+	return c.filterCategorizedMangas(func(category Category) bool {
+		return category.Watch
+	})
+}
+
+// AutoDownloadMangas returns mangas of the categories with Category.AutoDownload
+func (c *Client) AutoDownloadMangas() ([]MangaInfo, error) {
+	return c.filterCategorizedMangas(func(category Category) bool {
+		return category.AutoDownload
+	})
+}
+
+// FindManga finds the provider manga by its info,
+// searching for its title and matching the ID
+func (c *Client) FindManga(ctx context.Context, info MangaInfo) (Manga, bool, error) {
+	mangas, err := c.SearchMangas(ctx, info.Title)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, manga := range mangas {
+		if manga.Info().ID == info.ID {
+			return manga, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+func (c *Client) categorizedMangaKey(manga MangaInfo) string {
+	return fmt.Sprintf("%s%s/%s", categorizedMangaKeyPrefix, c.Info().ID, manga.ID)
+}
+
+// categorizedMangas returns mangas of this provider that have categories
+func (c *Client) categorizedMangas() ([]CategorizedManga, error) {
+	keys, err := c.categories.index.keys()
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := fmt.Sprintf("%s%s/", categorizedMangaKeyPrefix, c.Info().ID)
+
+	var mangas []CategorizedManga
+	for _, key := range keys {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+
+		var manga CategorizedManga
+		found, err := c.categories.index.store.Get(key, &manga)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			mangas = append(mangas, manga)
+		}
+	}
+
+	sort.Slice(mangas, func(i, j int) bool {
+		return mangas[i].Manga.Title < mangas[j].Manga.Title
+	})
+
+	return mangas, nil
+}
+
+// filterCategorizedMangas returns mangas having at least one category matching the filter
+func (c *Client) filterCategorizedMangas(filter func(category Category) bool) ([]MangaInfo, error) {
+	if c.categories == nil {
+		return nil, nil
+	}
+
+	categories, err := c.Categories()
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make(map[string]struct{})
+	for _, category := range categories {
+		if filter(category) {
+			matching[category.Name] = struct{}{}
+		}
+	}
+
+	mangas, err := c.categorizedMangas()
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []MangaInfo
+	for _, manga := range mangas {
+		for _, name := range manga.Categories {
+			if _, ok := matching[name]; ok {
+				infos = append(infos, manga.Manga)
+				break
+			}
+		}
+	}
+
+	return infos, nil
+}
+
+// updateMangaCategories replaces categories of the manga with the
+// result of the update. Mangas left without categories are forgotten.
+func (c *Client) updateMangaCategories(manga MangaInfo, update func(categories []string) []string) error {
+	store := c.categories
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	key := c.categorizedMangaKey(manga)
+
+	var categorized CategorizedManga
+	if _, err := store.index.store.Get(key, &categorized); err != nil {
+		return err
+	}
+
+	categorized.Manga = manga
+	categorized.Categories = update(categorized.Categories)
+
+	if len(categorized.Categories) == 0 {
+		return store.index.delete(key)
+	}
+
+	return store.index.set(key, categorized)
+}
+
+// removeString returns values without the given one
+func removeString(values []string, value string) []string {
+	var result []string
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
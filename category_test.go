@@ -0,0 +1,168 @@
+package libmangal
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestCategories(t *testing.T) {
+	provider := newFakeProvider(t, 1, 1)
+	client := newTestClient(t, provider)
+
+	for _, category := range []Category{
+		{Name: "Reading", Watch: true},
+		{Name: "Auto", Watch: true, AutoDownload: true},
+		{Name: "Archive"},
+	} {
+		if err := client.SetCategory(category); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := client.SetCategory(Category{}); err == nil {
+		t.Error("expected an error for the category without a name")
+	}
+
+	categories, err := client.Categories()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, category := range categories {
+		names = append(names, category.Name)
+	}
+
+	if want := []string{"Archive", "Auto", "Reading"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("got categories %v, want %v", names, want)
+	}
+
+	fake := provider.manga()
+	other := fakeManga{info: MangaInfo{Title: "Another Manga", ID: "another-manga"}}
+
+	for _, add := range []struct {
+		manga Manga
+		name  string
+	}{
+		{fake, "Reading"},
+		{fake, "Archive"},
+		{fake, "Reading"},
+		{other, "Auto"},
+	} {
+		if err := client.AddToCategory(add.manga, add.name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := client.AddToCategory(fake, "Missing"); err == nil {
+		t.Error("manga was added to the missing category")
+	}
+
+	mangaCategories, err := client.MangaCategories(fake)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"Archive", "Reading"}; !reflect.DeepEqual(mangaCategories, want) {
+		t.Errorf("got manga categories %v, want %v", mangaCategories, want)
+	}
+
+	titles := func(mangas []MangaInfo, err error) []string {
+		t.Helper()
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var titles []string
+		for _, manga := range mangas {
+			titles = append(titles, manga.Title)
+		}
+
+		return titles
+	}
+
+	for _, test := range []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"watched", titles(client.WatchedMangas()), []string{"Another Manga", "Fake Manga"}},
+		{"auto download", titles(client.AutoDownloadMangas()), []string{"Another Manga"}},
+		{"archive", titles(client.CategoryMangas("Archive")), []string{"Fake Manga"}},
+	} {
+		if !reflect.DeepEqual(test.got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, test.got, test.want)
+		}
+	}
+
+	// removed categories are detached from mangas
+	if err := client.RemoveCategory("Reading"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := titles(client.WatchedMangas()); !reflect.DeepEqual(got, []string{"Another Manga"}) {
+		t.Errorf("got watched %v after removing the category", got)
+	}
+
+	if _, found, err := client.Category("Reading"); err != nil || found {
+		t.Errorf("removed category is found: %v", err)
+	}
+
+	// mangas without categories are forgotten
+	if err := client.RemoveFromCategory(fake, "Archive"); err != nil {
+		t.Fatal(err)
+	}
+
+	mangas, err := client.categorizedMangas()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(mangas) != 1 || mangas[0].Manga.ID != "another-manga" {
+		t.Errorf("got categorized mangas %v", mangas)
+	}
+}
+
+func TestCategoriesWithoutStore(t *testing.T) {
+	provider := newFakeProvider(t, 1, 1)
+
+	options := testClientOptions()
+	options.CategoryStore = nil
+
+	client, err := NewClient(context.Background(), provider, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.SetCategory(Category{Name: "Reading"}); err == nil {
+		t.Error("expected an error without the store")
+	}
+
+	if err := client.AddToCategory(provider.manga(), "Reading"); err == nil {
+		t.Error("expected an error without the store")
+	}
+
+	if mangas, err := client.WatchedMangas(); err != nil || mangas != nil {
+		t.Errorf("got %v, %v without the store", mangas, err)
+	}
+}
+
+func TestFindManga(t *testing.T) {
+	provider := newFakeProvider(t, 1, 1)
+	client := newTestClient(t, provider)
+	ctx := context.Background()
+
+	manga, found, err := client.FindManga(ctx, provider.manga().Info())
+	if err != nil || !found || manga.Info().ID != "fake-manga" {
+		t.Errorf("got %v, %t, %v, want the fake manga", manga, found, err)
+	}
+
+	info := provider.manga().Info()
+	info.ID = "other"
+
+	if _, found, err := client.FindManga(ctx, info); err != nil || found {
+		t.Errorf("manga with another ID is found: %v", err)
+	}
+}
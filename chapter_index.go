@@ -0,0 +1,137 @@
+package libmangal
+
+import (
+	"github.com/philippgille/gokv"
+	"github.com/spf13/afero"
+)
+
+// chapterIndexPathPrefix prefixes keys of the reverse path to chapter mapping
+const chapterIndexPathPrefix = "path:"
+
+// ChapterIndex maps downloaded chapters to their paths,
+// so that checking whether a chapter exists doesn't hit the filesystem,
+// which is slow for the network filesystems.
+//
+// It's kept in sync by DownloadChapter and RemoveChapter.
+// See Client.RebuildChapterIndex for syncing it with the filesystem.
+//
+// It's safe for concurrent use if the store is.
+type ChapterIndex struct {
+	store gokv.Store
+}
+
+// NewChapterIndex constructs new ChapterIndex backed by the given store
+func NewChapterIndex(store gokv.Store) *ChapterIndex {
+	return &ChapterIndex{store: store}
+}
+
+// Get returns the path of the downloaded chapter
+func (c *ChapterIndex) Get(provider string, chapter Chapter) (string, bool, error) {
+	var path string
+	found, err := c.store.Get(chapterKey(provider, chapter), &path)
+	return path, found, err
+}
+
+// Set records the path of the downloaded chapter
+func (c *ChapterIndex) Set(provider string, chapter Chapter, path string) error {
+	key := chapterKey(provider, chapter)
+
+	previous, found, err := c.Get(provider, chapter)
+	if err != nil {
+		return err
+	}
+
+	// chapter was moved, e.g. by a hook
+	if found && previous != path {
+		if err := c.store.Delete(chapterIndexPathPrefix + previous); err != nil {
+			return err
+		}
+	}
+
+	if err := c.store.Set(key, path); err != nil {
+		return err
+	}
+
+	return c.store.Set(chapterIndexPathPrefix+path, key)
+}
+
+// Remove removes the chapter from the index
+func (c *ChapterIndex) Remove(provider string, chapter Chapter) error {
+	key := chapterKey(provider, chapter)
+
+	path, found, err := c.Get(provider, chapter)
+	if err != nil || !found {
+		return err
+	}
+
+	if err := c.store.Delete(chapterIndexPathPrefix + path); err != nil {
+		return err
+	}
+
+	return c.store.Delete(key)
+}
+
+// RemovePath removes the chapter with the given path from the index
+func (c *ChapterIndex) RemovePath(path string) error {
+	var key string
+	found, err := c.store.Get(chapterIndexPathPrefix+path, &key)
+	if err != nil || !found {
+		return err
+	}
+
+	if err := c.store.Delete(key); err != nil {
+		return err
+	}
+
+	return c.store.Delete(chapterIndexPathPrefix + path)
+}
+
+// ChapterIndex returns the index of downloaded chapters.
+// It may be nil if the index is disabled.
+func (c *Client) ChapterIndex() *ChapterIndex {
+	return c.options.ChapterIndex
+}
+
+// RebuildChapterIndex syncs the index with the filesystem
+// for the given chapters downloaded with the options.
+// Existing chapters are added and missing ones are removed.
+func (c *Client) RebuildChapterIndex(chapters []Chapter, options DownloadOptions) error {
+	index := c.options.ChapterIndex
+	if index == nil {
+		return nil
+	}
+
+	provider := c.Info().ID
+
+	for _, chapter := range chapters {
+		path, _ := c.chapterPath(chapter, options)
+
+		exists, err := afero.Exists(c.options.FS, path)
+		if err != nil {
+			return err
+		}
+
+		if exists {
+			err = index.Set(provider, chapter, path)
+		} else {
+			err = index.Remove(provider, chapter)
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// chapterIndexed returns the indexed path of the chapter if it's in the index.
+// It may differ from the chapter path if AfterDownload hooks reported another one.
+func (c *Client) chapterIndexed(chapter Chapter) (string, bool, error) {
+	index := c.options.ChapterIndex
+	if index == nil {
+		return "", false, nil
+	}
+
+	return index.Get(c.Info().ID, chapter)
+}
@@ -0,0 +1,72 @@
+package libmangal
+
+import (
+	"github.com/philippgille/gokv/syncmap"
+	"testing"
+)
+
+func TestChapterIndexMovedChapter(t *testing.T) {
+	provider := newFakeProvider(t, 2, 1)
+	chapters := provider.chapterList()
+
+	index := NewChapterIndex(syncmap.NewStore(syncmap.DefaultOptions))
+	for _, path := range []string{"/old.cbz", "/new.cbz"} {
+		if err := index.Set(fakeProviderInfo.ID, chapters[0], path); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the old path may be reused by another chapter
+	if err := index.Set(fakeProviderInfo.ID, chapters[1], "/old.cbz"); err != nil {
+		t.Fatal(err)
+	}
+
+	// removing the old path must not forget the moved chapter
+	if err := index.RemovePath("/old.cbz"); err != nil {
+		t.Fatal(err)
+	}
+
+	path, found, err := index.Get(fakeProviderInfo.ID, chapters[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found || path != "/new.cbz" {
+		t.Errorf("got %q, %v; want the moved chapter at /new.cbz", path, found)
+	}
+
+	if _, found, _ := index.Get(fakeProviderInfo.ID, chapters[1]); found {
+		t.Error("chapter at the removed path is still indexed")
+	}
+}
+
+func TestChapterExistsIndexed(t *testing.T) {
+	provider := newFakeProvider(t, 1, 1)
+	chapter := provider.chapterList()[0]
+
+	client := newTestClient(t, provider)
+	options := testDownloadOptions()
+
+	exists, err := client.ChapterExists(chapter, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if exists {
+		t.Fatal("chapter exists before it was downloaded")
+	}
+
+	client.options.ChapterIndex = NewChapterIndex(syncmap.NewStore(syncmap.DefaultOptions))
+	if err := client.options.ChapterIndex.Set(client.Info().ID, chapter, "/moved.cbz"); err != nil {
+		t.Fatal(err)
+	}
+
+	exists, err = client.ChapterExists(chapter, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !exists {
+		t.Error("indexed chapter doesn't exist")
+	}
+}
@@ -0,0 +1,134 @@
+package libmangal
+
+import "context"
+
+// ChapterIterator yields chapters one by one, so that providers
+// with thousands of chapters don't have to build them all at once.
+type ChapterIterator interface {
+	// Next returns the next chapter.
+	// False is returned when there are no chapters left.
+	Next(ctx context.Context) (Chapter, bool, error)
+
+	// Close releases the resources held by the iterator.
+	// It's safe to call it before the iterator is exhausted.
+	Close() error
+}
+
+// ProviderWithChaptersIter is a Provider that can stream chapters
+// of the volume instead of returning them as a slice,
+// e.g. converting the script results lazily.
+type ProviderWithChaptersIter interface {
+	Provider
+
+	// VolumeChaptersIter returns the iterator over chapters of the given volume.
+	//
+	// Implementation should utilize given LogFunc
+	VolumeChaptersIter(
+		ctx context.Context,
+		log LogFunc,
+		volume Volume,
+	) (ChapterIterator, error)
+}
+
+// sliceChapterIterator iterates over the already fetched chapters
+type sliceChapterIterator struct {
+	chapters []Chapter
+}
+
+func (s *sliceChapterIterator) Next(context.Context) (Chapter, bool, error) {
+	if len(s.chapters) == 0 {
+		return nil, false, nil
+	}
+
+	chapter := s.chapters[0]
+	s.chapters = s.chapters[1:]
+	return chapter, true, nil
+}
+
+func (s *sliceChapterIterator) Close() error {
+	s.chapters = nil
+	return nil
+}
+
+// VolumeChaptersIter returns the iterator over chapters of the given volume.
+//
+// Chapters are streamed if the provider implements ProviderWithChaptersIter.
+// Otherwise, they are fetched with VolumeChapters and iterated over.
+func (c *Client) VolumeChaptersIter(ctx context.Context, volume Volume) (ChapterIterator, error) {
+	if withIter, ok := c.provider.(ProviderWithChaptersIter); ok {
+		return withIter.VolumeChaptersIter(ctx, c.options.Log, volume)
+	}
+
+	chapters, err := c.VolumeChapters(ctx, volume)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sliceChapterIterator{chapters: chapters}, nil
+}
+
+// mangaChapterIterator iterates over chapters of all volumes of the manga
+type mangaChapterIterator struct {
+	client  *Client
+	volumes []Volume
+	current ChapterIterator
+}
+
+func (m *mangaChapterIterator) Next(ctx context.Context) (Chapter, bool, error) {
+	for {
+		if m.current == nil {
+			if len(m.volumes) == 0 {
+				return nil, false, nil
+			}
+
+			iter, err := m.client.VolumeChaptersIter(ctx, m.volumes[0])
+			if err != nil {
+				return nil, false, err
+			}
+
+			m.volumes = m.volumes[1:]
+			m.current = iter
+		}
+
+		chapter, ok, err := m.current.Next(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+
+		if ok {
+			return chapter, true, nil
+		}
+
+		if err := m.current.Close(); err != nil {
+			return nil, false, err
+		}
+
+		m.current = nil
+	}
+}
+
+func (m *mangaChapterIterator) Close() error {
+	m.volumes = nil
+	if m.current == nil {
+		return nil
+	}
+
+	err := m.current.Close()
+	m.current = nil
+	return err
+}
+
+// ChaptersIter returns the iterator over chapters of all volumes of the manga.
+// Volumes are fetched upfront, chapters of each volume are fetched
+// when the iterator reaches it. See VolumeChaptersIter
+func (c *Client) ChaptersIter(ctx context.Context, manga Manga) (ChapterIterator, error) {
+	volumes, err := c.MangaVolumes(ctx, manga)
+	if err != nil {
+		return nil, err
+	}
+
+	return &mangaChapterIterator{
+		client:  c,
+		volumes: volumes,
+	}, nil
+}
@@ -0,0 +1,148 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// iterProvider streams two chapters of each of its two volumes
+type iterProvider struct {
+	*fakeProvider
+
+	opened, closed int
+}
+
+func (p *iterProvider) Load(context.Context) (Provider, error) {
+	return p, nil
+}
+
+func (p *iterProvider) MangaVolumes(context.Context, LogFunc, Manga) ([]Volume, error) {
+	return []Volume{
+		fakeVolume{number: 1, manga: p.manga()},
+		fakeVolume{number: 2, manga: p.manga()},
+	}, nil
+}
+
+func (p *iterProvider) VolumeChaptersIter(_ context.Context, _ LogFunc, volume Volume) (ChapterIterator, error) {
+	p.opened++
+
+	var chapters []Chapter
+	for i := 1; i <= 2; i++ {
+		number := volume.Info().Number*10 + i
+		chapters = append(chapters, fakeChapter{
+			info:   ChapterInfo{Title: fmt.Sprintf("Chapter %d", number), Number: float32(number)},
+			volume: volume.(fakeVolume),
+		})
+	}
+
+	return &closeCountingIterator{
+		ChapterIterator: &sliceChapterIterator{chapters: chapters},
+		closed:          &p.closed,
+	}, nil
+}
+
+// closeCountingIterator counts Close calls
+type closeCountingIterator struct {
+	ChapterIterator
+	closed *int
+}
+
+func (c *closeCountingIterator) Close() error {
+	*c.closed++
+	return c.ChapterIterator.Close()
+}
+
+// iterNumbers drains the iterator and returns the chapter numbers
+func iterNumbers(t *testing.T, iter ChapterIterator) []float32 {
+	t.Helper()
+
+	var numbers []float32
+	for {
+		chapter, ok, err := iter.Next(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !ok {
+			return numbers
+		}
+
+		numbers = append(numbers, chapter.Info().Number)
+	}
+}
+
+func TestChaptersIter(t *testing.T) {
+	provider := &iterProvider{fakeProvider: newFakeProvider(t, 0, 1)}
+	ctx := context.Background()
+
+	client, err := NewClient(ctx, provider, testClientOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	iter, err := client.ChaptersIter(ctx, provider.manga())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// volumes are opened when the iterator reaches them
+	if _, _, err := iter.Next(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if provider.opened != 1 {
+		t.Errorf("opened %d volumes after the first chapter, want 1", provider.opened)
+	}
+
+	if got, want := iterNumbers(t, iter), []float32{12, 21, 22}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got chapters %v, want %v", got, want)
+	}
+
+	if provider.opened != 2 || provider.closed != 2 {
+		t.Errorf("opened %d and closed %d volumes, want 2", provider.opened, provider.closed)
+	}
+
+	if err := iter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// closing early closes the current volume
+	iter, err = client.ChaptersIter(ctx, provider.manga())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := iter.Next(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := iter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if provider.closed != 3 {
+		t.Errorf("closed %d volumes, want 3", provider.closed)
+	}
+
+	if _, ok, err := iter.Next(ctx); ok || err != nil {
+		t.Errorf("got %t, %v from the closed iterator", ok, err)
+	}
+}
+
+func TestChaptersIterSlice(t *testing.T) {
+	provider := newFakeProvider(t, 3, 1)
+	client := newTestClient(t, provider)
+
+	// providers without iterators are iterated over their chapters
+	iter, err := client.ChaptersIter(context.Background(), provider.manga())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer iter.Close()
+
+	if got, want := iterNumbers(t, iter), []float32{1, 2, 3}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got chapters %v, want %v", got, want)
+	}
+}
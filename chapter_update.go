@@ -0,0 +1,155 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// storedPage is the page read from the already downloaded chapter
+type storedPage struct {
+	name    string
+	chapter Chapter
+}
+
+func (s storedPage) String() string {
+	return s.name
+}
+
+func (s storedPage) GetExtension() string {
+	return strings.ToLower(filepath.Ext(s.name))
+}
+
+func (s storedPage) Chapter() Chapter {
+	return s.chapter
+}
+
+var _ Page = storedPage{}
+
+// readStoredPages reads pages of the chapter downloaded at path.
+// False is returned if the format doesn't allow reading them back.
+func readStoredPages(fs afero.Fs, chapter Chapter, path string, format Format) ([]PageWithImage, bool, error) {
+	var images []archiveImage
+
+	switch format {
+	case FormatCBZ, FormatZIP:
+		archived, err := readArchiveImages(fs, path)
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, image := range archived {
+			if image.name != filenameEmbeddedCoverJPG {
+				images = append(images, image)
+			}
+		}
+	case FormatImages:
+		entries, err := afero.ReadDir(fs, path)
+		if err != nil {
+			return nil, false, err
+		}
+
+		for _, entry := range entries {
+			if entry.IsDir() || !isArchiveImage(entry.Name()) {
+				continue
+			}
+
+			image, err := afero.ReadFile(fs, filepath.Join(path, entry.Name()))
+			if err != nil {
+				return nil, false, err
+			}
+
+			images = append(images, archiveImage{
+				name:  entry.Name(),
+				image: image,
+			})
+		}
+
+		sort.Slice(images, func(i, j int) bool {
+			return images[i].name < images[j].name
+		})
+	default:
+		return nil, false, nil
+	}
+
+	pages := make([]PageWithImage, len(images))
+	for i, image := range images {
+		pages[i] = &pageWithImage{
+			Page: storedPage{
+				name:    image.name,
+				chapter: chapter,
+			},
+			image: image.image,
+		}
+	}
+
+	return pages, true, nil
+}
+
+// updateChapter downloads only the pages appended to the chapter since its
+// last download (common on webtoon platforms) and saves them along with
+// the existing ones instead of downloading the whole chapter again.
+//
+// False is returned if the chapter can't be updated this way,
+// e.g. its pages were replaced or the format is not readable.
+func (c *Client) updateChapter(
+	ctx context.Context,
+	chapter Chapter,
+	path string,
+	existingFS afero.Fs,
+	options DownloadOptions,
+	result *DownloadResult,
+) (bool, error) {
+	// split chapters are not updated
+	if exists, err := afero.Exists(existingFS, path); err != nil || !exists {
+		return false, err
+	}
+
+	stored, found, err := c.StoredChapterFingerprint(chapter)
+	if err != nil || !found {
+		return false, err
+	}
+
+	pages, err := c.ChapterPages(ctx, chapter)
+	if err != nil {
+		return false, err
+	}
+
+	current := newChapterFingerprint(pages)
+	if len(current.Pages) <= len(stored.Pages) {
+		return false, nil
+	}
+
+	for i, page := range stored.Pages {
+		if current.Pages[i] != page {
+			return false, nil
+		}
+	}
+
+	storedPages, ok, err := readStoredPages(existingFS, chapter, path, options.Format)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if len(storedPages) != len(stored.Pages) {
+		// modified outside libmangal
+		return false, nil
+	}
+
+	appended := pages[len(stored.Pages):]
+	c.options.Log(fmt.Sprintf("Chapter %q has %d new pages, updating", chapter, len(appended)))
+
+	downloadedPages, err := c.downloadAndProcessPages(ctx, appended, options)
+	if err != nil {
+		return false, err
+	}
+
+	result.PageCount = len(pages)
+	result.Fingerprint = current
+	result.Updated = true
+
+	return true, c.saveChapter(ctx, chapter, path, append(storedPages, downloadedPages...), options, result)
+}
@@ -0,0 +1,108 @@
+package libmangal
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestDownloadChapterUpdate(t *testing.T) {
+	provider := newFakeProvider(t, 1, 2)
+	client := newTestClient(t, provider)
+	ctx := context.Background()
+
+	options := testDownloadOptions()
+	options.RedownloadIfChanged = true
+
+	chapter := provider.chapterList()[0]
+
+	if _, err := client.DownloadChapter(ctx, chapter, options); err != nil {
+		t.Fatal(err)
+	}
+
+	// two pages are appended, the stored ones are kept
+	stored := provider.image
+	provider.image = testJPEG(t, 100, 100)
+	provider.pages = 4
+	requests := provider.requests.Load()
+
+	result, err := client.DownloadChapter(ctx, chapter, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.Updated || result.Skipped || result.PageCount != 4 {
+		t.Fatalf("got updated %t, skipped %t, %d pages, want the update to 4 pages", result.Updated, result.Skipped, result.PageCount)
+	}
+
+	if n := provider.requests.Load() - requests; n != 2 {
+		t.Errorf("downloaded %d pages, want the 2 new ones", n)
+	}
+
+	pages, ok, err := readStoredPages(client.options.FS, chapter, result.Path, options.Format)
+	if err != nil || !ok {
+		t.Fatalf("can't read the updated chapter: %v", err)
+	}
+
+	if len(pages) != 4 {
+		t.Fatalf("got %d stored pages, want 4", len(pages))
+	}
+
+	for i, page := range pages {
+		want := provider.image
+		if i < 2 {
+			want = stored
+		}
+
+		if !bytes.Equal(page.GetImage(), want) {
+			t.Errorf("page #%d has the wrong image", i+1)
+		}
+	}
+
+	// replaced pages are downloaded again
+	provider.pages = 3
+	requests = provider.requests.Load()
+
+	result, err = client.DownloadChapter(ctx, chapter, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Updated || result.Skipped || provider.requests.Load()-requests != 3 {
+		t.Errorf("got updated %t, skipped %t, want the chapter downloaded again", result.Updated, result.Skipped)
+	}
+}
+
+func TestReadStoredPagesImages(t *testing.T) {
+	provider := newFakeProvider(t, 1, 3)
+	client := newTestClient(t, provider)
+
+	options := testDownloadOptions()
+	options.Format = FormatImages
+
+	chapter := provider.chapterList()[0]
+
+	result, err := client.DownloadChapter(context.Background(), chapter, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pages, ok, err := readStoredPages(client.options.FS, chapter, result.Path, options.Format)
+	if err != nil || !ok {
+		t.Fatalf("can't read the chapter: %v", err)
+	}
+
+	if len(pages) != 3 {
+		t.Fatalf("got %d pages, want 3", len(pages))
+	}
+
+	for i, page := range pages {
+		if page.GetExtension() != ".jpg" || !bytes.Equal(page.GetImage(), provider.image) {
+			t.Errorf("page #%d differs", i+1)
+		}
+	}
+
+	if _, ok, err := readStoredPages(client.options.FS, chapter, result.Path, FormatPDF); ok || err != nil {
+		t.Errorf("got %t, %v for PDF, want it unreadable", ok, err)
+	}
+}
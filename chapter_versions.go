@@ -0,0 +1,156 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+)
+
+// ChapterVersionSelector picks one of the scanlators of the manga.
+// Returned scanlator must be one of the given ones.
+//
+// E.g. interactive apps can ask the user which group they prefer.
+type ChapterVersionSelector func(ctx context.Context, manga Manga, scanlators []string) (string, error)
+
+// ChapterVersions are the versions of the chapter
+// with the same number by different scanlators
+type ChapterVersions struct {
+	// Number of the chapter
+	Number float32
+
+	// Chapters are the versions in the provider order
+	Chapters []Chapter
+}
+
+// Scanlators returns scanlators of the versions
+func (c ChapterVersions) Scanlators() []string {
+	scanlators := make([]string, len(c.Chapters))
+	for i, chapter := range c.Chapters {
+		scanlators[i] = chapter.Info().Scanlator
+	}
+
+	return scanlators
+}
+
+// groupChapterVersions groups chapters by number preserving their order
+func groupChapterVersions(chapters []Chapter) []ChapterVersions {
+	var (
+		versions []ChapterVersions
+		indexes  = make(map[float32]int)
+	)
+
+	for _, chapter := range chapters {
+		number := chapter.Info().Number
+
+		index, ok := indexes[number]
+		if !ok {
+			index = len(versions)
+			indexes[number] = index
+			versions = append(versions, ChapterVersions{Number: number})
+		}
+
+		versions[index].Chapters = append(versions[index].Chapters, chapter)
+	}
+
+	return versions
+}
+
+// ChapterVersions returns chapters of the manga grouped by number
+func (c *Client) ChapterVersions(ctx context.Context, manga Manga) ([]ChapterVersions, error) {
+	chapters, err := c.mangaChapters(ctx, manga)
+	if err != nil {
+		return nil, err
+	}
+
+	return groupChapterVersions(chapters), nil
+}
+
+// SetPreferredScanlator remembers the preferred scanlator of the manga.
+// It returns an error if ClientOptions.ScanlatorStore is nil.
+func (c *Client) SetPreferredScanlator(manga Manga, scanlator string) error {
+	store := c.options.ScanlatorStore
+	if store == nil {
+		return fmt.Errorf("scanlator store is not configured")
+	}
+
+	return store.Set(mangaKey(c.Info().ID, manga), scanlator)
+}
+
+// PreferredScanlator returns the remembered preferred scanlator of the manga
+func (c *Client) PreferredScanlator(manga Manga) (string, bool, error) {
+	store := c.options.ScanlatorStore
+	if store == nil {
+		return "", false, nil
+	}
+
+	var scanlator string
+	found, err := store.Get(mangaKey(c.Info().ID, manga), &scanlator)
+	return scanlator, found, err
+}
+
+// PreferredChapters returns a single version of each chapter of the manga.
+//
+// If the manga has chapters by several scanlators, the preferred one
+// is used, see PreferredScanlator. If there is none,
+// ClientOptions.ChapterVersionSelector is asked and its answer is remembered.
+// Chapters missing from the preferred scanlator are taken from the others.
+func (c *Client) PreferredChapters(ctx context.Context, manga Manga) ([]Chapter, error) {
+	versions, err := c.ChapterVersions(ctx, manga)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		scanlators []string
+		seen       = make(map[string]struct{})
+	)
+
+	for _, version := range versions {
+		for _, scanlator := range version.Scanlators() {
+			if _, ok := seen[scanlator]; !ok {
+				seen[scanlator] = struct{}{}
+				scanlators = append(scanlators, scanlator)
+			}
+		}
+	}
+
+	var preferred string
+	if len(scanlators) > 1 {
+		scanlator, found, err := c.PreferredScanlator(manga)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			preferred = scanlator
+		} else if c.options.ChapterVersionSelector != nil {
+			preferred, err = c.options.ChapterVersionSelector(ctx, manga, scanlators)
+			if err != nil {
+				return nil, err
+			}
+
+			if _, ok := seen[preferred]; !ok {
+				return nil, fmt.Errorf("unknown scanlator %q", preferred)
+			}
+
+			if c.options.ScanlatorStore != nil {
+				if err := c.SetPreferredScanlator(manga, preferred); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	chapters := make([]Chapter, len(versions))
+	for i, version := range versions {
+		chapters[i] = version.Chapters[0]
+
+		for _, chapter := range version.Chapters {
+			if chapter.Info().Scanlator == preferred {
+				chapters[i] = chapter
+				break
+			}
+		}
+	}
+
+	return chapters, nil
+}
@@ -0,0 +1,205 @@
+// Package chapterrange parses chapter range expressions
+// like "1-5,10,12.5,latest-3" and selects matching chapters.
+//
+// Expression is a comma-separated list of terms:
+//
+//	*, all     every chapter
+//	10         chapter 10
+//	12.5       chapter 12.5
+//	1-5        chapters from 1 to 5 inclusive
+//	10-        chapters from 10 to the latest
+//	-5         chapters up to 5
+//	latest     the latest chapter
+//	latest-3   the latest chapter and 3 before it, by number
+//
+// "latest" can also be used as a range bound, e.g. "100-latest".
+package chapterrange
+
+import (
+	"fmt"
+	"github.com/mangalorg/libmangal"
+	"strconv"
+	"strings"
+)
+
+const keywordLatest = "latest"
+
+// bound is a chapter number relative to the latest chapter or absolute
+type bound struct {
+	latest bool
+	number float64
+}
+
+func (b bound) resolve(latest float64) float64 {
+	if b.latest {
+		return latest - b.number
+	}
+
+	return b.number
+}
+
+// term is an inclusive range. Nil bounds are open.
+type term struct {
+	from, to *bound
+}
+
+// Selection is the parsed range expression
+type Selection struct {
+	terms []term
+}
+
+// Parse parses the range expression
+func Parse(expr string) (Selection, error) {
+	var selection Selection
+
+	for _, part := range strings.Split(expr, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part == "" {
+			continue
+		}
+
+		t, err := parseTerm(part)
+		if err != nil {
+			return Selection{}, fmt.Errorf("invalid range %q: %w", part, err)
+		}
+
+		selection.terms = append(selection.terms, t)
+	}
+
+	if len(selection.terms) == 0 {
+		return Selection{}, fmt.Errorf("empty range expression")
+	}
+
+	return selection, nil
+}
+
+// MustParse is like Parse but panics on error
+func MustParse(expr string) Selection {
+	selection, err := Parse(expr)
+	if err != nil {
+		panic(err)
+	}
+
+	return selection
+}
+
+func parseTerm(s string) (term, error) {
+	switch {
+	case s == "*" || s == "all":
+		return term{}, nil
+	case s == keywordLatest:
+		latest := &bound{latest: true}
+		return term{from: latest, to: latest}, nil
+	case strings.HasPrefix(s, keywordLatest+"-"):
+		offset, err := parseNumber(strings.TrimPrefix(s, keywordLatest+"-"))
+		if err != nil {
+			return term{}, err
+		}
+
+		return term{
+			from: &bound{latest: true, number: offset},
+			to:   &bound{latest: true},
+		}, nil
+	}
+
+	from, to, isRange := strings.Cut(s, "-")
+	if !isRange {
+		b, err := parseBound(s)
+		if err != nil {
+			return term{}, err
+		}
+
+		return term{from: &b, to: &b}, nil
+	}
+
+	var t term
+	if from = strings.TrimSpace(from); from != "" {
+		b, err := parseBound(from)
+		if err != nil {
+			return term{}, err
+		}
+
+		t.from = &b
+	}
+
+	if to = strings.TrimSpace(to); to != "" {
+		b, err := parseBound(to)
+		if err != nil {
+			return term{}, err
+		}
+
+		t.to = &b
+	}
+
+	if t.from == nil && t.to == nil {
+		return term{}, fmt.Errorf("range without bounds")
+	}
+
+	return t, nil
+}
+
+func parseBound(s string) (bound, error) {
+	if s == keywordLatest {
+		return bound{latest: true}, nil
+	}
+
+	number, err := parseNumber(s)
+	if err != nil {
+		return bound{}, err
+	}
+
+	return bound{number: number}, nil
+}
+
+func parseNumber(s string) (float64, error) {
+	number, err := strconv.ParseFloat(strings.TrimSpace(s), 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid chapter number %q", s)
+	}
+
+	if number < 0 {
+		return 0, fmt.Errorf("negative chapter number %q", s)
+	}
+
+	return number, nil
+}
+
+// Matches checks whether the chapter number is selected,
+// given the number of the latest chapter.
+func (s Selection) Matches(number, latest float32) bool {
+	n, l := float64(number), float64(latest)
+
+	for _, t := range s.terms {
+		if t.from != nil && n < t.from.resolve(l) {
+			continue
+		}
+
+		if t.to != nil && n > t.to.resolve(l) {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// Select returns chapters matching the selection preserving their order.
+// The latest chapter is the one with the greatest number.
+func (s Selection) Select(chapters []libmangal.Chapter) []libmangal.Chapter {
+	var latest float32
+	for _, chapter := range chapters {
+		if number := chapter.Info().Number; number > latest {
+			latest = number
+		}
+	}
+
+	var selected []libmangal.Chapter
+	for _, chapter := range chapters {
+		if s.Matches(chapter.Info().Number, latest) {
+			selected = append(selected, chapter)
+		}
+	}
+
+	return selected
+}
@@ -0,0 +1,116 @@
+package chapterrange
+
+import (
+	"fmt"
+	"github.com/mangalorg/libmangal"
+	"testing"
+)
+
+type testChapter float32
+
+func (c testChapter) String() string              { return fmt.Sprint(float32(c)) }
+func (c testChapter) Info() libmangal.ChapterInfo { return libmangal.ChapterInfo{Number: float32(c)} }
+func (c testChapter) Volume() libmangal.Volume    { return nil }
+
+func chapters(numbers ...float32) []libmangal.Chapter {
+	chapters := make([]libmangal.Chapter, len(numbers))
+	for i, number := range numbers {
+		chapters[i] = testChapter(number)
+	}
+
+	return chapters
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		expr  string
+		valid bool
+	}{
+		{expr: "*", valid: true},
+		{expr: "all", valid: true},
+		{expr: "10", valid: true},
+		{expr: "12.5", valid: true},
+		{expr: "1-5", valid: true},
+		{expr: "10-", valid: true},
+		{expr: "-5", valid: true},
+		{expr: "latest", valid: true},
+		{expr: "LATEST", valid: true},
+		{expr: "latest-3", valid: true},
+		{expr: "100-latest", valid: true},
+		{expr: " 1 - 5 , 7 ,, ", valid: true},
+		{expr: ""},
+		{expr: " , "},
+		{expr: "-"},
+		{expr: "x"},
+		{expr: "1-x"},
+		{expr: "x-1"},
+		{expr: "1--2"},
+		{expr: "latest--1"},
+		{expr: "latest-x"},
+		{expr: "1-2-3"},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(test.expr)
+		if valid := err == nil; valid != test.valid {
+			t.Errorf("%q: got valid %v, want %v (%v)", test.expr, valid, test.valid, err)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	all := chapters(1, 2, 3, 4, 5, 5.5, 6, 7, 8, 9, 10)
+
+	tests := []struct {
+		expr string
+		want []float32
+	}{
+		{"*", []float32{1, 2, 3, 4, 5, 5.5, 6, 7, 8, 9, 10}},
+		{"3", []float32{3}},
+		{"5.5", []float32{5.5}},
+		{"11", nil},
+		{"2-4", []float32{2, 3, 4}},
+		{"5-6", []float32{5, 5.5, 6}},
+		{"8-", []float32{8, 9, 10}},
+		{"-2", []float32{1, 2}},
+		{"latest", []float32{10}},
+		{"latest-2", []float32{8, 9, 10}},
+		{"9-latest", []float32{9, 10}},
+		{"1,3,latest", []float32{1, 3, 10}},
+		{"4-2", nil},
+	}
+
+	for _, test := range tests {
+		selection, err := Parse(test.expr)
+		if err != nil {
+			t.Errorf("%q: %s", test.expr, err)
+			continue
+		}
+
+		var got []float32
+		for _, chapter := range selection.Select(all) {
+			got = append(got, chapter.Info().Number)
+		}
+
+		if fmt.Sprint(got) != fmt.Sprint(test.want) {
+			t.Errorf("%q: got %v, want %v", test.expr, got, test.want)
+		}
+	}
+}
+
+func TestSelectKeepsOrder(t *testing.T) {
+	got := MustParse("1-3").Select(chapters(3, 1, 4, 2))
+	if fmt.Sprint(got) != fmt.Sprint(chapters(3, 1, 2)) {
+		t.Errorf("got %v, want [3 1 2]", got)
+	}
+}
+
+func TestMustParsePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("invalid expression didn't panic")
+		}
+	}()
+
+	MustParse("")
+}
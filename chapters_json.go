@@ -0,0 +1,208 @@
+package libmangal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/spf13/afero"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const filenameChaptersJSON = "chapters.json"
+
+// ChaptersJSONChapter is the downloaded chapter listed in ChaptersJSON
+type ChaptersJSONChapter struct {
+	Number float32 `json:"number"`
+	Title  string  `json:"title"`
+	Volume int     `json:"volume"`
+
+	// Path of the chapter relative to the manga directory
+	Path string `json:"path"`
+
+	// Parts are the paths of the chapter parts relative to the manga directory
+	// if it was split because of DownloadOptions.MaxFileSize
+	Parts []string `json:"parts,omitempty"`
+
+	Format Format `json:"format"`
+
+	// SHA256 of the chapter file. Empty for FormatImages and split chapters.
+	SHA256 string `json:"sha256,omitempty"`
+
+	// URL of the chapter at the source. Empty if unknown.
+	URL string `json:"url,omitempty"`
+
+	DownloadedAt time.Time `json:"downloadedAt"`
+}
+
+// ChaptersJSON is the index of the downloaded chapters of the manga,
+// so that external tools don't have to parse filenames.
+//
+// It's written as chapters.json to the manga directory,
+// see DownloadOptions.WriteChaptersJson, and kept up to date
+// by DownloadChapter and RemoveChapter.
+type ChaptersJSON struct {
+	Manga string `json:"manga"`
+
+	// Chapters ordered by volume and number
+	Chapters []ChaptersJSONChapter `json:"chapters"`
+}
+
+// ReadChaptersJSON reads chapters.json of the given manga directory
+func (c *Client) ReadChaptersJSON(mangaDir string) (ChaptersJSON, error) {
+	contents, err := afero.ReadFile(c.options.FS, filepath.Join(mangaDir, filenameChaptersJSON))
+	if err != nil {
+		return ChaptersJSON{}, err
+	}
+
+	var chaptersJSON ChaptersJSON
+	if err := json.Unmarshal(contents, &chaptersJSON); err != nil {
+		return ChaptersJSON{}, err
+	}
+
+	return chaptersJSON, nil
+}
+
+func (c *Client) writeChaptersJSON(mangaDir string, chaptersJSON ChaptersJSON) error {
+	c.options.Log(fmt.Sprintf("Writing %s", filenameChaptersJSON))
+
+	sort.SliceStable(chaptersJSON.Chapters, func(i, j int) bool {
+		a, b := chaptersJSON.Chapters[i], chaptersJSON.Chapters[j]
+		if a.Volume != b.Volume {
+			return a.Volume < b.Volume
+		}
+
+		return a.Number < b.Number
+	})
+
+	marshalled, err := json.MarshalIndent(chaptersJSON, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(c.options.FS, filepath.Join(mangaDir, filenameChaptersJSON), marshalled, modeFile)
+}
+
+// updateChaptersJSON lists the downloaded chapter in chapters.json of the manga directory.
+// Skipped chapters that are already listed are left as is.
+func (c *Client) updateChaptersJSON(chapter Chapter, mangaDir string, result DownloadResult) (bool, error) {
+	c.chaptersJSONMu.Lock()
+	defer c.chaptersJSONMu.Unlock()
+
+	chaptersJSON, err := c.ReadChaptersJSON(mangaDir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+
+	path, err := filepath.Rel(mangaDir, result.Path)
+	if err != nil {
+		return false, err
+	}
+
+	listed := -1
+	for i, entry := range chaptersJSON.Chapters {
+		if entry.Path == path {
+			listed = i
+			break
+		}
+	}
+
+	if listed >= 0 && result.Skipped {
+		return false, nil
+	}
+
+	normalized := c.normalizeChapter(chapter)
+	info := normalized.Info()
+
+	entry := ChaptersJSONChapter{
+		Number:       info.Number,
+		Title:        info.Title,
+		Volume:       chapter.Volume().Info().Number,
+		Path:         path,
+		Format:       result.Format,
+		URL:          info.URL,
+		DownloadedAt: time.Now(),
+	}
+
+	for _, part := range result.Parts {
+		part, err := filepath.Rel(mangaDir, part)
+		if err != nil {
+			return false, err
+		}
+
+		entry.Parts = append(entry.Parts, part)
+	}
+
+	if len(result.Parts) == 0 {
+		isDir, err := afero.IsDir(c.options.FS, result.Path)
+		if err != nil {
+			return false, err
+		}
+
+		if !isDir {
+			entry.SHA256, err = c.fileSHA256(result.Path)
+			if err != nil {
+				return false, err
+			}
+		}
+	}
+
+	if listed >= 0 {
+		chaptersJSON.Chapters[listed] = entry
+	} else {
+		chaptersJSON.Chapters = append(chaptersJSON.Chapters, entry)
+	}
+
+	chaptersJSON.Manga = normalized.Volume().Manga().Info().Title
+
+	if err := c.writeChaptersJSON(mangaDir, chaptersJSON); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// removeFromChaptersJSON removes the chapter at path from chapters.json.
+// The manga directory is either the parent of the chapter
+// or the parent of its volume directory.
+func (c *Client) removeFromChaptersJSON(path string) error {
+	c.chaptersJSONMu.Lock()
+	defer c.chaptersJSONMu.Unlock()
+
+	for _, mangaDir := range []string{
+		filepath.Dir(path),
+		filepath.Dir(filepath.Dir(path)),
+	} {
+		chaptersJSON, err := c.ReadChaptersJSON(mangaDir)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		relative, err := filepath.Rel(mangaDir, path)
+		if err != nil {
+			return err
+		}
+
+		chapters := chaptersJSON.Chapters[:0]
+		for _, entry := range chaptersJSON.Chapters {
+			if entry.Path != relative {
+				chapters = append(chapters, entry)
+			}
+		}
+
+		if len(chapters) == len(chaptersJSON.Chapters) {
+			continue
+		}
+
+		chaptersJSON.Chapters = chapters
+		return c.writeChaptersJSON(mangaDir, chaptersJSON)
+	}
+
+	return nil
+}
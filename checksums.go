@@ -0,0 +1,211 @@
+package libmangal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/afero"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// suffixChecksumsJSON is appended to the chapter path
+// to get the path of its ChecksumManifest sidecar
+const suffixChecksumsJSON = ".sha256.json"
+
+// PageChecksum is the checksum of the saved page image
+type PageChecksum struct {
+	// Name of the image in the archive or the directory
+	Name   string `json:"name"`
+	Size   int    `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// ChecksumManifest is the sidecar with checksums of the downloaded chapter.
+// See DownloadOptions.Checksums and Client.VerifyChapter
+type ChecksumManifest struct {
+	// File is the checksum of the chapter file.
+	// Empty for FormatImages.
+	File string `json:"file,omitempty"`
+
+	Pages []PageChecksum `json:"pages"`
+}
+
+// Duplicates returns groups of names of identical pages,
+// e.g. repeated credit pages or placeholders served in place of missing ones
+func (m ChecksumManifest) Duplicates() [][]string {
+	names := make(map[string][]string)
+	var order []string
+
+	for _, page := range m.Pages {
+		if _, ok := names[page.SHA256]; !ok {
+			order = append(order, page.SHA256)
+		}
+
+		names[page.SHA256] = append(names[page.SHA256], page.Name)
+	}
+
+	var duplicates [][]string
+	for _, sum := range order {
+		if len(names[sum]) > 1 {
+			duplicates = append(duplicates, names[sum])
+		}
+	}
+
+	return duplicates
+}
+
+// ChecksumsPath returns the path of the ChecksumManifest sidecar
+// of the chapter downloaded at the given path
+func ChecksumsPath(chapterPath string) string {
+	return chapterPath + suffixChecksumsJSON
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (c *Client) fileSHA256(path string) (string, error) {
+	file, err := c.options.FS.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// writeChecksums writes the ChecksumManifest of the pages saved at path
+func (c *Client) writeChecksums(path string, pages []PageWithImage) error {
+	c.options.Log(fmt.Sprintf("Writing checksums of %d pages", len(pages)))
+
+	manifest := ChecksumManifest{
+		Pages: make([]PageChecksum, len(pages)),
+	}
+
+	for i, page := range pages {
+		image := page.GetImage()
+		manifest.Pages[i] = PageChecksum{
+			Name:   fmt.Sprintf("%04d%s", i+1, page.GetExtension()),
+			Size:   len(image),
+			SHA256: sha256Hex(image),
+		}
+	}
+
+	isDir, err := afero.IsDir(c.options.FS, path)
+	if err != nil {
+		return err
+	}
+
+	if !isDir {
+		manifest.File, err = c.fileSHA256(path)
+		if err != nil {
+			return err
+		}
+	}
+
+	marshalled, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(c.options.FS, ChecksumsPath(path), marshalled, modeFile)
+}
+
+// ReadChecksums reads the ChecksumManifest sidecar
+// of the chapter downloaded at the given path
+func (c *Client) ReadChecksums(chapterPath string) (ChecksumManifest, error) {
+	contents, err := afero.ReadFile(c.options.FS, ChecksumsPath(chapterPath))
+	if err != nil {
+		return ChecksumManifest{}, err
+	}
+
+	var manifest ChecksumManifest
+	if err := json.Unmarshal(contents, &manifest); err != nil {
+		return ChecksumManifest{}, err
+	}
+
+	return manifest, nil
+}
+
+// VerifyChapter checks the chapter downloaded at path against its ChecksumManifest.
+// ChecksumError is returned if the chapter is corrupted.
+//
+// Pages of FormatCBZ, FormatZIP and FormatImages chapters are verified
+// one by one, so that corrupted pages are named. Other formats
+// are verified as a whole file.
+func (c *Client) VerifyChapter(path string) error {
+	manifest, err := c.ReadChecksums(path)
+	if err != nil {
+		return err
+	}
+
+	isDir, err := afero.IsDir(c.options.FS, path)
+	if err != nil {
+		return err
+	}
+
+	var format Format
+	switch ext := strings.ToLower(filepath.Ext(path)); {
+	case isDir:
+		format = FormatImages
+	case ext == FormatCBZ.Extension():
+		format = FormatCBZ
+	case ext == FormatZIP.Extension():
+		format = FormatZIP
+	default:
+		sum, err := c.fileSHA256(path)
+		if err != nil {
+			return err
+		}
+
+		if sum != manifest.File {
+			return ChecksumError{Path: path, Corrupted: []string{filepath.Base(path)}}
+		}
+
+		return nil
+	}
+
+	pages, _, err := readStoredPages(c.options.FS, nil, path, format)
+	if err != nil {
+		return err
+	}
+
+	stored := make(map[string]string, len(pages))
+	for _, page := range pages {
+		stored[page.String()] = sha256Hex(page.GetImage())
+	}
+
+	var corrupted []string
+	for _, page := range manifest.Pages {
+		if sum, ok := stored[page.Name]; !ok || sum != page.SHA256 {
+			corrupted = append(corrupted, page.Name)
+		}
+
+		delete(stored, page.Name)
+	}
+
+	// pages the manifest doesn't know of
+	var unknown []string
+	for name := range stored {
+		unknown = append(unknown, name)
+	}
+
+	sort.Strings(unknown)
+	corrupted = append(corrupted, unknown...)
+
+	if len(corrupted) > 0 {
+		return ChecksumError{Path: path, Corrupted: corrupted}
+	}
+
+	return nil
+}
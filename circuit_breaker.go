@@ -0,0 +1,233 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of the circuit of the host
+type CircuitState uint8
+
+const (
+	// CircuitClosed lets requests through
+	CircuitClosed CircuitState = iota
+
+	// CircuitOpen fails requests immediately with CircuitOpenError
+	CircuitOpen
+
+	// CircuitHalfOpen lets a single trial request through
+	// to check whether the host has recovered
+	CircuitHalfOpen
+)
+
+func (c CircuitState) String() string {
+	switch c {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerOptions configures CircuitBreakerTransport
+type CircuitBreakerOptions struct {
+	// FailureThreshold is the number of consecutive failures
+	// after which the circuit of the host opens
+	FailureThreshold int
+
+	// OpenTimeout is how long the circuit stays open
+	// before the trial request is let through
+	OpenTimeout time.Duration
+
+	// IsFailure decides whether the response is a failure.
+	// By default, transport errors, 5xx and 429 responses are failures.
+	IsFailure func(response *http.Response, err error) bool
+}
+
+// DefaultCircuitBreakerOptions constructs default CircuitBreakerOptions
+func DefaultCircuitBreakerOptions() CircuitBreakerOptions {
+	return CircuitBreakerOptions{
+		FailureThreshold: 5,
+		OpenTimeout:      30 * time.Second,
+		IsFailure: func(response *http.Response, err error) bool {
+			if err != nil {
+				return true
+			}
+
+			return response.StatusCode >= 500 || response.StatusCode == http.StatusTooManyRequests
+		},
+	}
+}
+
+type hostCircuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+}
+
+// CircuitBreakerTransport is the http.RoundTripper that stops sending
+// requests to the hosts that keep failing, so that batch jobs fail fast
+// on a dead mirror instead of timing out over and over again.
+//
+// It is safe for concurrent use by multiple goroutines.
+type CircuitBreakerTransport struct {
+	base    http.RoundTripper
+	options CircuitBreakerOptions
+
+	mu       sync.Mutex
+	circuits map[string]*hostCircuit
+}
+
+// NewCircuitBreakerTransport wraps the base transport.
+// http.DefaultTransport is used if base is nil.
+//
+// It's opt-in, e.g.
+//
+//	options.HTTPClient = &http.Client{
+//		Transport: NewCircuitBreakerTransport(nil, DefaultCircuitBreakerOptions()),
+//	}
+//
+// Providers should use ClientOptions.HTTPClient, so that
+// their requests share the circuits with the client ones.
+func NewCircuitBreakerTransport(base http.RoundTripper, options CircuitBreakerOptions) *CircuitBreakerTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if options.IsFailure == nil {
+		options.IsFailure = DefaultCircuitBreakerOptions().IsFailure
+	}
+
+	return &CircuitBreakerTransport{
+		base:     base,
+		options:  options,
+		circuits: make(map[string]*hostCircuit),
+	}
+}
+
+func (c *CircuitBreakerTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	host := request.URL.Host
+
+	if err := c.allow(host); err != nil {
+		return nil, err
+	}
+
+	response, err := c.base.RoundTrip(request)
+
+	// canceled requests say nothing about the host
+	if request.Context().Err() != nil || errors.Is(err, context.Canceled) {
+		c.release(host)
+		return response, err
+	}
+
+	c.record(host, c.options.IsFailure(response, err))
+
+	return response, err
+}
+
+// allow checks whether the request to the host can be made
+func (c *CircuitBreakerTransport) allow(host string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	circuit, ok := c.circuits[host]
+	if !ok {
+		return nil
+	}
+
+	switch circuit.state {
+	case CircuitOpen:
+		retryAt := circuit.openedAt.Add(c.options.OpenTimeout)
+		if time.Now().Before(retryAt) {
+			return CircuitOpenError{Host: host, RetryAt: retryAt}
+		}
+
+		// this request is the trial one
+		circuit.state = CircuitHalfOpen
+		return nil
+	case CircuitHalfOpen:
+		// the trial request is in flight
+		return CircuitOpenError{Host: host, RetryAt: time.Now().Add(c.options.OpenTimeout)}
+	default:
+		return nil
+	}
+}
+
+// record updates the circuit of the host with the request outcome
+func (c *CircuitBreakerTransport) record(host string, failed bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	circuit, ok := c.circuits[host]
+	if !ok {
+		if !failed {
+			return
+		}
+
+		circuit = &hostCircuit{}
+		c.circuits[host] = circuit
+	}
+
+	if !failed {
+		delete(c.circuits, host)
+		return
+	}
+
+	circuit.failures++
+	if circuit.state == CircuitHalfOpen || circuit.failures >= c.options.FailureThreshold {
+		circuit.state = CircuitOpen
+		circuit.openedAt = time.Now()
+	}
+}
+
+// release lets the next request through as the trial one
+// if the canceled request was the trial
+func (c *CircuitBreakerTransport) release(host string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if circuit, ok := c.circuits[host]; ok && circuit.state == CircuitHalfOpen {
+		circuit.state = CircuitOpen
+	}
+}
+
+// State returns the circuit state of the host
+func (c *CircuitBreakerTransport) State(host string) CircuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if circuit, ok := c.circuits[host]; ok {
+		return circuit.state
+	}
+
+	return CircuitClosed
+}
+
+// States returns states of the hosts which circuits are not closed
+// or have recent failures, e.g. for metrics.
+func (c *CircuitBreakerTransport) States() map[string]CircuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	states := make(map[string]CircuitState, len(c.circuits))
+	for host, circuit := range c.circuits {
+		states[host] = circuit.state
+	}
+
+	return states
+}
+
+// Reset closes the circuit of the host
+func (c *CircuitBreakerTransport) Reset(host string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.circuits, host)
+}
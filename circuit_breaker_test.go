@@ -0,0 +1,119 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newTestCircuitBreaker(base roundTripperFunc) *CircuitBreakerTransport {
+	options := DefaultCircuitBreakerOptions()
+	options.FailureThreshold = 2
+	options.OpenTimeout = time.Millisecond
+	return NewCircuitBreakerTransport(base, options)
+}
+
+func roundTrip(t *testing.T, transport http.RoundTripper, ctx context.Context) error {
+	t.Helper()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com/page", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := transport.RoundTrip(request)
+	if response != nil {
+		response.Body.Close()
+	}
+
+	return err
+}
+
+func TestCircuitBreakerOpens(t *testing.T) {
+	breaker := newTestCircuitBreaker(func(*http.Request) (*http.Response, error) {
+		return nil, errTestUnreachable
+	})
+
+	for i := 0; i < 2; i++ {
+		if err := roundTrip(t, breaker, context.Background()); !errors.Is(err, errTestUnreachable) {
+			t.Fatalf("got %v, want %v", err, errTestUnreachable)
+		}
+	}
+
+	if state := breaker.State("example.com"); state != CircuitOpen {
+		t.Fatalf("got %s circuit, want open", state)
+	}
+
+	var openErr CircuitOpenError
+	if err := roundTrip(t, breaker, context.Background()); !errors.As(err, &openErr) {
+		t.Errorf("got %v, want CircuitOpenError", err)
+	}
+}
+
+func TestCircuitBreakerIgnoresCanceledRequests(t *testing.T) {
+	breaker := newTestCircuitBreaker(func(request *http.Request) (*http.Response, error) {
+		<-request.Context().Done()
+		return nil, request.Context().Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for i := 0; i < 5; i++ {
+		if err := roundTrip(t, breaker, ctx); !errors.Is(err, context.Canceled) {
+			t.Fatalf("got %v, want %v", err, context.Canceled)
+		}
+	}
+
+	if state := breaker.State("example.com"); state != CircuitClosed {
+		t.Errorf("got %s circuit, want closed", state)
+	}
+}
+
+func TestCircuitBreakerCanceledTrial(t *testing.T) {
+	var fail bool
+	breaker := newTestCircuitBreaker(func(request *http.Request) (*http.Response, error) {
+		if err := request.Context().Err(); err != nil {
+			return nil, err
+		}
+
+		if fail {
+			return nil, errTestUnreachable
+		}
+
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	fail = true
+	for i := 0; i < 2; i++ {
+		_ = roundTrip(t, breaker, context.Background())
+	}
+
+	time.Sleep(2 * time.Millisecond)
+
+	// canceled trial must not keep the circuit half-open
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_ = roundTrip(t, breaker, ctx)
+
+	if state := breaker.State("example.com"); state != CircuitOpen {
+		t.Fatalf("got %s circuit, want open", state)
+	}
+
+	fail = false
+	if err := roundTrip(t, breaker, context.Background()); err != nil {
+		t.Fatalf("trial request failed: %s", err)
+	}
+
+	if state := breaker.State("example.com"); state != CircuitClosed {
+		t.Errorf("got %s circuit, want closed", state)
+	}
+}
+
+func TestDefaultClientOptionsWithoutCircuitBreaker(t *testing.T) {
+	if _, ok := DefaultClientOptions().HTTPClient.Transport.(*CircuitBreakerTransport); ok {
+		t.Error("circuit breaker must be opt-in")
+	}
+}
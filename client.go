@@ -2,9 +2,14 @@ package libmangal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/spf13/afero"
 	"golang.org/x/sync/errgroup"
+	"runtime/pprof"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // NewClient creates a new client from ProviderLoader.
@@ -24,17 +29,61 @@ func NewClient(
 		return nil, err
 	}
 
-	return &Client{
+	client := &Client{
 		provider: provider,
 		options:  options,
-	}, nil
+		log:      &atomic.Pointer[LogFunc]{},
+
+		chaptersJSONMu: &sync.Mutex{},
+	}
+
+	if options.PageCache != nil {
+		client.pageCache = newStoreIndex(options.PageCache)
+	}
+
+	if options.UsageStore != nil {
+		client.usage = &usageTracker{index: newStoreIndex(options.UsageStore)}
+	}
+
+	if options.CategoryStore != nil {
+		client.categories = &categoryStore{index: newStoreIndex(options.CategoryStore)}
+	}
+
+	client.SetLogFunc(options.Log)
+
+	// log func may be changed with SetLogFunc at any time,
+	// so it's resolved on every call
+	client.options.Log = client.logWith(LogFields{})
+
+	return client, nil
 }
 
 // Client is the wrapper around Provider with the extended functionality.
 // It's the core of the libmangal
+//
+// Client is safe for concurrent use by multiple goroutines
+// as long as the underlying Provider, ClientOptions.FS and
+// stores are safe for concurrent use too. Client options are never
+// modified after construction except for the log function (see SetLogFunc).
 type Client struct {
 	provider Provider
 	options  ClientOptions
+
+	// log is shared with the copies of the client
+	log *atomic.Pointer[LogFunc]
+
+	// pageCache is nil if ClientOptions.PageCache is nil
+	pageCache *storeIndex
+
+	// usage is nil if ClientOptions.UsageStore is nil
+	usage *usageTracker
+
+	// categories is nil if ClientOptions.CategoryStore is nil
+	categories *categoryStore
+
+	// chaptersJSONMu serializes updates of chapters.json files,
+	// which are read and written back by concurrent downloads
+	chaptersJSONMu *sync.Mutex
 }
 
 func (c *Client) FS() afero.Fs {
@@ -45,8 +94,17 @@ func (c *Client) Anilist() *Anilist {
 	return c.options.Anilist
 }
 
+// FailureJournal returns the journal of failed downloads.
+// It may be nil if the journal is disabled.
+func (c *Client) FailureJournal() *FailureJournal {
+	return c.options.FailureJournal
+}
+
+// SetLogFunc replaces the log function.
+// It's safe to call it while other operations are in progress,
+// they will use the new function for the subsequent messages.
 func (c *Client) SetLogFunc(log LogFunc) {
-	c.options.Log = log
+	c.log.Store(&log)
 }
 
 // SearchMangas searches for mangas with the given query
@@ -56,17 +114,17 @@ func (c *Client) SearchMangas(ctx context.Context, query string) ([]Manga, error
 
 // MangaVolumes gets chapters of the given manga
 func (c *Client) MangaVolumes(ctx context.Context, manga Manga) ([]Volume, error) {
-	return c.provider.MangaVolumes(ctx, c.options.Log, manga)
+	return c.provider.MangaVolumes(ctx, c.logWith(mangaLogFields(manga)), manga)
 }
 
 // VolumeChapters gets chapters of the given manga
 func (c *Client) VolumeChapters(ctx context.Context, volume Volume) ([]Chapter, error) {
-	return c.provider.VolumeChapters(ctx, c.options.Log, volume)
+	return c.provider.VolumeChapters(ctx, c.logWith(mangaLogFields(volume.Manga())), volume)
 }
 
 // ChapterPages gets pages of the given chapter
 func (c *Client) ChapterPages(ctx context.Context, chapter Chapter) ([]Page, error) {
-	return c.provider.ChapterPages(ctx, c.options.Log, chapter)
+	return c.provider.ChapterPages(ctx, c.logWith(chapterLogFields(chapter)), chapter)
 }
 
 func (c *Client) String() string {
@@ -81,40 +139,198 @@ func (c *Client) Info() ProviderInfo {
 // DownloadChapter downloads and saves chapter to the specified
 // directory in the given format.
 //
-// It will return resulting chapter path joined with DownloadOptions.Directory
+// It will return DownloadResult with the resulting chapter path
+// joined with DownloadOptions.Directory.
+//
+// Failures are recorded in the ClientOptions.FailureJournal,
+// chapters excluded there will result in ChapterExcludedError.
+//
+// See DownloadOptions.DryRun for previewing the download.
+//
+// Different chapters may be downloaded concurrently.
+// Concurrent downloads of the same chapter are not deduplicated,
+// the one that finishes last overwrites the others.
 func (c *Client) DownloadChapter(
 	ctx context.Context,
 	chapter Chapter,
 	options DownloadOptions,
-) (string, error) {
-	c.options.Log(fmt.Sprintf("Downloading chapter %q as %s", chapter, options.Format))
+) (DownloadResult, error) {
+	// paths depend on it
+	if err := c.resolveAnilistMangaDir(ctx, chapter.Volume().Manga()); err != nil {
+		return DownloadResult{}, err
+	}
+
+	if options.DryRun {
+		return c.planChapterDownload(ctx, chapter, options)
+	}
+
+	journal := c.options.FailureJournal
+	if journal == nil {
+		return c.downloadChapterToFS(ctx, chapter, options)
+	}
+
+	excluded, err := c.chapterExcluded(chapter)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+
+	if excluded {
+		return DownloadResult{}, ChapterExcludedError{Chapter: chapter}
+	}
+
+	result, err := c.downloadChapterToFS(ctx, chapter, options)
+	if err != nil {
+		// cancellation is not a failure of the chapter itself
+		if ctx.Err() == nil {
+			if err := journal.Record(c.Info().ID, chapter, err); err != nil {
+				c.options.Log(fmt.Sprintf("Failed to record failure: %s", err))
+			}
+		}
+
+		return DownloadResult{}, err
+	}
+
+	if err := journal.Remove(chapterKey(c.Info().ID, chapter)); err != nil {
+		c.options.Log(fmt.Sprintf("Failed to remove failure record: %s", err))
+	}
+
+	return result, nil
+}
+
+// downloadChapterToFS is a helper function for DownloadChapter.
+// It downloads chapter to the in-memory filesystem first
+// and then moves it to the client's one.
+func (c *Client) downloadChapterToFS(
+	ctx context.Context,
+	chapter Chapter,
+	options DownloadOptions,
+) (DownloadResult, error) {
+	// messages of concurrent downloads are told apart by the chapter
+	log := c.logWith(chapterLogFields(chapter))
+	log(fmt.Sprintf("Downloading chapter %q as %s", chapter, options.Format.Name()))
+
+	started := time.Now()
 
 	tmpClient := Client{
-		provider: c.provider,
-		options:  c.options,
+		provider:   c.provider,
+		options:    c.options,
+		log:        c.log,
+		pageCache:  c.pageCache,
+		usage:      c.usage,
+		categories: c.categories,
+
+		chaptersJSONMu: c.chaptersJSONMu,
 	}
 
 	tmpClient.options.FS = afero.NewMemMapFs()
+	tmpClient.options.Log = log
 
-	path, err := tmpClient.downloadChapterWithMetadata(ctx, chapter, options, func(path string) (bool, error) {
-		return afero.Exists(c.options.FS, path)
-	})
+	result, err := tmpClient.downloadChapterWithMetadata(ctx, chapter, options, c.options.FS)
 	if err != nil {
-		return "", err
+		return DownloadResult{}, err
+	}
+
+	result.BytesWritten, err = dirSize(tmpClient.FS(), options.Directory)
+	if err != nil {
+		return DownloadResult{}, err
 	}
 
 	if err := mergeDirectories(
 		c.FS(), options.Directory,
 		tmpClient.FS(), options.Directory,
 	); err != nil {
-		return "", err
+		return DownloadResult{}, err
+	}
+
+	if options.Resume {
+		if err := c.options.FS.RemoveAll(partialPath(result.Path)); err != nil {
+			return DownloadResult{}, err
+		}
+	}
+
+	// hooks run before the bookkeeping, so that the paths they report are recorded
+	if !result.Skipped {
+		if err := c.runHooks(ctx, chapter, options.AfterDownload, &result); err != nil {
+			return DownloadResult{}, err
+		}
+
+		if err := c.storeChapterFingerprint(chapter, result.Fingerprint); err != nil {
+			return DownloadResult{}, err
+		}
+	}
+
+	if index := c.options.ChapterIndex; index != nil {
+		if err := index.Set(c.Info().ID, chapter, result.Path); err != nil {
+			return DownloadResult{}, err
+		}
+	}
+
+	if options.WriteChaptersJson {
+		_, mangaDir := c.chapterPath(chapter, options)
+
+		written, err := c.updateChaptersJSON(chapter, mangaDir, result)
+		if err != nil {
+			if options.Strict {
+				return DownloadResult{}, MetadataError{err}
+			}
+
+			result.warn(err)
+		}
+
+		result.ChaptersJSONWritten = written
+	}
+
+	if err := c.createAliases(ctx, chapter.Volume().Manga(), options); err != nil {
+		if options.Strict {
+			return DownloadResult{}, MetadataError{err}
+		}
+
+		result.warn(err)
 	}
 
+	result.Duration = time.Since(started)
+
 	if options.ReadAfter {
-		return path, c.readChapter(ctx, path, chapter, options.ReadIncognito)
+		return result, c.readChapter(ctx, result.Path, chapter, options.ReadIncognito)
 	}
 
-	return path, nil
+	return result, nil
+}
+
+// RemoveChapter removes chapter at the given path.
+// Doesn't matter if it's a directory or a file.
+//
+// If RemoveOptions.Trash is true chapter will be moved to the
+// RemoveOptions.TrashDir instead, so it can be restored later.
+// See EmptyTrash for cleaning it up.
+//
+// The chapter is removed from chapters.json if there is one.
+func (c *Client) RemoveChapter(path string, options RemoveOptions) error {
+	if options.Trash && options.TrashDir == "" {
+		return errors.New("trash directory is not set")
+	}
+
+	if index := c.options.ChapterIndex; index != nil {
+		if err := index.RemovePath(path); err != nil {
+			return err
+		}
+	}
+
+	if err := c.removeFromChaptersJSON(path); err != nil {
+		return err
+	}
+
+	if !options.Trash {
+		return c.removeChapter(path)
+	}
+
+	trashPath, err := moveToTrash(c.options.FS, path, options.TrashDir)
+	if err != nil {
+		return err
+	}
+
+	c.options.Log(fmt.Sprintf("Moved %s to %s", path, trashPath))
+	return nil
 }
 
 // DownloadPagesInBatch downloads multiple pages in batch
@@ -127,25 +343,36 @@ func (c *Client) DownloadPagesInBatch(
 ) ([]PageWithImage, error) {
 	c.options.Log(fmt.Sprintf("Downloading %d pages", len(pages)))
 
-	g, _ := errgroup.WithContext(ctx)
+	g, ctx := errgroup.WithContext(ctx)
 
 	downloadedPages := make([]PageWithImage, len(pages))
 
 	for i, page := range pages {
 		// https://github.com/golang/go/wiki/CommonMistakes#using-goroutines-on-loop-iterator-variables
 		i, page := i, page
-		g.Go(func() error {
-			c.options.Log(fmt.Sprintf("Page #%03d: downloading", i+1))
-			downloaded, err := c.DownloadPage(ctx, page)
-			if err != nil {
-				return err
-			}
+		g.Go(func() (err error) {
+			// labels make page downloads distinguishable in profiles
+			pprof.Do(ctx, pprof.Labels(
+				"libmangal.provider", c.Info().ID,
+				"libmangal.stage", "download-page",
+			), func(ctx context.Context) {
+				ctx = contextWithPageIndex(ctx, i+1)
+				log := c.logWith(pageLogFields(ctx, page))
+
+				log(fmt.Sprintf("Page #%03d: downloading", i+1))
+
+				var downloaded PageWithImage
+				downloaded, err = c.DownloadPage(ctx, page)
+				if err != nil {
+					return
+				}
 
-			c.options.Log(fmt.Sprintf("Page #%03d: done", i+1))
+				log(fmt.Sprintf("Page #%03d: done", i+1))
 
-			downloadedPages[i] = downloaded
+				downloadedPages[i] = downloaded
+			})
 
-			return nil
+			return err
 		})
 	}
 
@@ -156,25 +383,139 @@ func (c *Client) DownloadPagesInBatch(
 	return downloadedPages, nil
 }
 
-// DownloadPage downloads a page contents (image)
+// DownloadPage downloads a page contents (image).
+//
+// If the page implements PageWithMirrors and downloading it fails,
+// mirrors will be tried in order until one of them succeeds.
 func (c *Client) DownloadPage(ctx context.Context, page Page) (PageWithImage, error) {
+	downloaded, err := c.downloadPage(ctx, page)
+	if err == nil {
+		return downloaded, nil
+	}
+
+	withMirrors, ok := page.(PageWithMirrors)
+	if !ok {
+		return nil, err
+	}
+
+	errs := []error{err}
+	for i, mirror := range withMirrors.Mirrors() {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		c.logWith(pageLogFields(ctx, page))(fmt.Sprintf("Page %q: trying mirror #%d", page, i+1))
+
+		downloaded, err := c.downloadPage(ctx, mirror)
+		if err == nil {
+			return downloaded, nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// downloadPage downloads a single page without trying its mirrors
+func (c *Client) downloadPage(ctx context.Context, page Page) (PageWithImage, error) {
 	if withImage, ok := page.(PageWithImage); ok {
 		return withImage, nil
 	}
 
-	image, err := c.provider.GetPageImage(ctx, c.options.Log, page)
+	if cached, ok := c.cachedPage(page); ok {
+		return cached, nil
+	}
+
+	if withDataURI, ok := page.(PageWithDataURI); ok {
+		image, err := decodeDataURI(withDataURI.DataURI(), c.options.MaxImageSize)
+		if err != nil {
+			return nil, fmt.Errorf("page %q: %w", page, err)
+		}
+
+		return &pageWithImage{
+			Page:  page,
+			image: image,
+		}, nil
+	}
+
+	log := c.logWith(pageLogFields(ctx, page))
+
+	// let the provider stop reading oversized images early
+	ctx = contextWithImageSizeLimit(ctx, c.options.MaxImageSize)
+
+	var image []byte
+	err := c.options.RetryPolicy.do(ctx, log, fmt.Sprintf("Page %q", page), func() (err error) {
+		image, err = c.provider.GetPageImage(ctx, log, page)
+		if err != nil {
+			return err
+		}
+
+		// interstitials are served in place of images
+		if err := c.checkCaptcha(image, page.Chapter().Info().URL); err != nil {
+			return err
+		}
+
+		return validatePageImage(page, image, c.options.PageValidation)
+	})
 	if err != nil {
 		return nil, err
 	}
 
+	// for the providers that ignore ImageSizeLimit
+	if err := checkSizeLimit(int64(len(image)), c.options.MaxImageSize); err != nil {
+		return nil, fmt.Errorf("page %q: %w", page, err)
+	}
+
+	c.recordUsage(page.Chapter().Volume().Manga(), int64(len(image)))
+
 	return &pageWithImage{
 		Page:  page,
 		image: image,
 	}, nil
 }
 
+// ChapterPath computes the path where the chapter would be
+// downloaded with the given options without downloading it.
+// It performs the same resolution as DownloadChapter.
+//
+// With ClientOptions.AnilistMangaDir the manga must be found on Anilist
+// first, e.g. with Anilist.MakeMangaWithAnilist, it's named
+// with ClientOptions.MangaNameTemplate otherwise.
+func (c *Client) ChapterPath(chapter Chapter, options DownloadOptions) (string, error) {
+	if !options.Format.IsValid() {
+		return "", fmt.Errorf("invalid format: %s", options.Format)
+	}
+
+	path, _ := c.chapterPath(chapter, options)
+	return path, nil
+}
+
+// ChapterExists checks whether the chapter is already
+// downloaded with the given options. Chapters in the ChapterIndex
+// exist wherever they were moved to.
+func (c *Client) ChapterExists(chapter Chapter, options DownloadOptions) (bool, error) {
+	path, err := c.ChapterPath(chapter, options)
+	if err != nil {
+		return false, err
+	}
+
+	_, exists, err := c.chapterExistsAt(chapter, path, options, c.options.FS)
+	return exists, err
+}
+
+// ComputeMangaFilename computes the name of the manga directory.
+// See ClientOptions.AnilistMangaDir
 func (c *Client) ComputeMangaFilename(manga Manga) string {
-	return c.options.MangaNameTemplate(c.String(), manga)
+	if c.options.AnilistMangaDir != AnilistMangaDirNone {
+		if filename, ok := c.anilistMangaFilename(manga); ok {
+			return filename
+		}
+	}
+
+	return c.options.MangaNameTemplate(c.String(), c.translateManga(manga))
 }
 
 func (c *Client) ComputeVolumeFilename(volume Volume) string {
@@ -182,5 +523,5 @@ func (c *Client) ComputeVolumeFilename(volume Volume) string {
 }
 
 func (c *Client) ComputeChapterFilename(chapter Chapter, format Format) string {
-	return c.options.ChapterNameTemplate(c.String(), chapter) + format.Extension()
+	return c.options.ChapterNameTemplate(c.String(), c.translateChapter(c.normalizeChapter(chapter))) + format.Extension()
 }
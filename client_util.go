@@ -15,12 +15,11 @@ import (
 	"math"
 	"net/http"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
-type pathExistsFunc func(string) (bool, error)
-
-// removeChapter will remove chapter at given path.
+// removeChapter will remove chapter at given path permanently.
 // Doesn't matter if it's a directory or a file.
 func (c *Client) removeChapter(chapterPath string) error {
 	c.options.Log("Removing " + chapterPath)
@@ -41,13 +40,28 @@ func (c *Client) removeChapter(chapterPath string) error {
 // For example this can be either banner image or cover image.
 // Manga is required to set Referer header.
 func (c *Client) downloadMangaImage(ctx context.Context, manga Manga, URL string, out io.Writer) error {
+	// image is buffered so that failed attempts don't leave partial data in out
+	var buffer bytes.Buffer
+	err := c.options.RetryPolicy.do(ctx, c.options.Log, URL, func() error {
+		buffer.Reset()
+		return c.fetchMangaImage(ctx, manga, URL, &buffer)
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = buffer.WriteTo(out)
+	return err
+}
+
+func (c *Client) fetchMangaImage(ctx context.Context, manga Manga, URL string, out io.Writer) error {
 	request, err := http.NewRequestWithContext(ctx, http.MethodGet, URL, nil)
 	if err != nil {
 		return err
 	}
 
 	request.Header.Set("Referer", manga.Info().URL)
-	request.Header.Set("User-Agent", UserAgent)
+	request.Header.Set("User-Agent", c.userAgent(request.URL.Host))
 	request.Header.Set("Accept", "image/webp,image/apng,image/*,*/*;q=0.8")
 
 	response, err := c.options.HTTPClient.Do(request)
@@ -57,29 +71,27 @@ func (c *Client) downloadMangaImage(ctx context.Context, manga Manga, URL string
 
 	defer response.Body.Close()
 
-	if response.StatusCode != http.StatusOK {
-		return fmt.Errorf("unexpected http status: %s", response.Status)
+	if err := c.checkCaptchaResponse(response); err != nil {
+		return err
 	}
 
-	_, err = io.Copy(out, response.Body)
-	return err
-}
-
-// downloadCover will download cover if it doesn't exist
-func (c *Client) downloadCover(ctx context.Context, manga Manga, out io.Writer) error {
-	c.options.Log("Downloading cover")
+	if response.StatusCode != http.StatusOK {
+		return HTTPStatusError{
+			StatusCode: response.StatusCode,
+			Status:     response.Status,
+			RetryAfter: parseRetryAfter(response.Header),
+		}
+	}
 
-	coverURL, ok, err := c.getCoverURL(ctx, manga)
-	if err != nil {
+	// fail early if the server announces an oversized body
+	if err := checkSizeLimit(response.ContentLength, c.options.MaxImageSize); err != nil {
 		return err
 	}
-	c.options.Log(coverURL)
 
-	if !ok {
-		return errors.New("cover url not found")
-	}
+	written, err := copyLimited(out, response.Body, c.options.MaxImageSize)
+	c.recordUsage(manga, written)
 
-	return c.downloadMangaImage(ctx, manga, coverURL, out)
+	return err
 }
 
 // downloadBanner will download banner if it doesn't exist
@@ -99,33 +111,6 @@ func (c *Client) downloadBanner(ctx context.Context, manga Manga, out io.Writer)
 	return c.downloadMangaImage(ctx, manga, bannerURL, out)
 }
 
-func (c *Client) getCoverURL(ctx context.Context, manga Manga) (string, bool, error) {
-	coverURL := manga.Info().Cover
-	if coverURL != "" {
-		return coverURL, true, nil
-	}
-
-	mangaWithAnilist, ok, err := c.Anilist().MakeMangaWithAnilist(ctx, manga)
-	if err != nil {
-		return "", false, err
-	}
-
-	if !ok {
-		return "", false, nil
-	}
-
-	for _, coverURL := range []string{
-		mangaWithAnilist.Anilist.CoverImage.ExtraLarge,
-		mangaWithAnilist.Anilist.CoverImage.Medium,
-	} {
-		if coverURL != "" {
-			return coverURL, true, nil
-		}
-	}
-
-	return "", false, nil
-}
-
 func (c *Client) getBannerURL(ctx context.Context, manga Manga) (string, bool, error) {
 	bannerURL := manga.Info().Banner
 	if bannerURL != "" {
@@ -177,7 +162,12 @@ func (c *Client) getSeriesJSON(ctx context.Context, manga Manga) (SeriesJSON, er
 	return withAnilist.SeriesJSON(), nil
 }
 
-func (c *Client) writeSeriesJSON(ctx context.Context, manga Manga, out io.Writer) error {
+func (c *Client) writeSeriesJSON(
+	ctx context.Context,
+	manga Manga,
+	description DescriptionOptions,
+	out io.Writer,
+) error {
 	c.options.Log(fmt.Sprintf("Writing %s", filenameSeriesJSON))
 
 	seriesJSON, err := c.getSeriesJSON(ctx, manga)
@@ -185,6 +175,12 @@ func (c *Client) writeSeriesJSON(ctx context.Context, manga Manga, out io.Writer
 		return err
 	}
 
+	// formatted description is meant to stay HTML
+	seriesJSON.DescriptionText = description.apply(seriesJSON.DescriptionText)
+	seriesJSON.DescriptionFormatted = DescriptionOptions{
+		CutSpoilers: description.CutSpoilers,
+	}.apply(seriesJSON.DescriptionFormatted)
+
 	marshalled, err := seriesJSON.wrapper().marshal()
 	if err != nil {
 		return err
@@ -200,32 +196,208 @@ func (c *Client) downloadChapter(
 	chapter Chapter,
 	path string,
 	options DownloadOptions,
+	existingFS afero.Fs,
+	result *DownloadResult,
 ) error {
 	pages, err := c.ChapterPages(ctx, chapter)
 	if err != nil {
 		return err
 	}
 
-	downloadedPages, err := c.DownloadPagesInBatch(ctx, pages)
+	result.PageCount = len(pages)
+	result.Fingerprint = newChapterFingerprint(pages)
+
+	var downloadedPages []PageWithImage
+	if options.Resume {
+		downloadedPages, err = c.downloadPagesResumable(ctx, existingFS, partialPath(path), pages, result.Fingerprint)
+	} else {
+		downloadedPages, err = c.DownloadPagesInBatch(ctx, pages)
+	}
+
 	if err != nil {
 		return err
 	}
 
+	downloadedPages, err = c.processPages(ctx, downloadedPages, options)
+	if err != nil {
+		return err
+	}
+
+	return c.saveChapter(ctx, chapter, path, downloadedPages, options, result)
+}
+
+// downloadAndProcessPages downloads pages and applies
+// DownloadOptions.ImageTransformer and DownloadOptions.ImagePolicies to them
+func (c *Client) downloadAndProcessPages(
+	ctx context.Context,
+	pages []Page,
+	options DownloadOptions,
+) ([]PageWithImage, error) {
+	downloadedPages, err := c.DownloadPagesInBatch(ctx, pages)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.processPages(ctx, downloadedPages, options)
+}
+
+// processPages applies DownloadOptions.ImageTransformer,
+// DownloadOptions.ImageConverters and DownloadOptions.ImagePolicies
+// to the downloaded pages
+func (c *Client) processPages(
+	ctx context.Context,
+	downloadedPages []PageWithImage,
+	options DownloadOptions,
+) ([]PageWithImage, error) {
+	var err error
 	for _, page := range downloadedPages {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return nil, ctx.Err()
 		default:
 		}
 
 		image, err := options.ImageTransformer(page.GetImage())
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		page.SetImage(image)
 	}
 
+	if len(options.ImageConverters) > 0 {
+		if err := convertImages(downloadedPages, options.ImageConverters, c.options.Log); err != nil {
+			return nil, err
+		}
+	}
+
+	if policy, ok := options.ImagePolicies[options.Format]; ok {
+		for i, page := range downloadedPages {
+			downloadedPages[i], err = policy.apply(page)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return downloadedPages, nil
+}
+
+// saveChapter extracts text from the downloaded pages if needed
+// and saves them, splitting into parts if they exceed DownloadOptions.MaxFileSize
+func (c *Client) saveChapter(
+	ctx context.Context,
+	chapter Chapter,
+	path string,
+	downloadedPages []PageWithImage,
+	options DownloadOptions,
+	result *DownloadResult,
+) error {
+	if options.TextExtractor != nil {
+		if err := c.extractChapterText(ctx, options.TextExtractor, chapter, path, downloadedPages); err != nil {
+			if options.Strict {
+				return err
+			}
+
+			result.warn(err)
+		} else {
+			result.TextWritten = true
+		}
+	}
+
+	direction := chapter.Volume().Manga().Info().ReadingDirection
+	if options.LongStrip.isLongStrip(downloadedPages, direction) {
+		result.LongStrip = true
+
+		if options.LongStrip.SliceHeight > 0 {
+			c.options.Log(fmt.Sprintf("Slicing long strip %q", chapter))
+
+			sliced, err := sliceLongStrip(downloadedPages, options.LongStrip.SliceHeight)
+			if err != nil {
+				return err
+			}
+
+			downloadedPages = sliced
+		}
+	}
+
+	if options.Spreads.Mode == SpreadSplit && !result.LongStrip {
+		split, spreads, err := options.Spreads.splitSpreads(downloadedPages, direction)
+		if err != nil {
+			return err
+		}
+
+		if spreads > 0 {
+			c.options.Log(fmt.Sprintf("Split %d spreads of %q", spreads, chapter))
+		}
+
+		downloadedPages = split
+		result.SpreadsSplit = spreads
+	}
+
+	if options.MaxFileSize > 0 && options.Format != FormatImages {
+		parts := splitPages(downloadedPages, options.MaxFileSize)
+		if len(parts) > 1 {
+			c.options.Log(fmt.Sprintf("Splitting chapter %q into %d parts", chapter, len(parts)))
+
+			for i, part := range parts {
+				partPath := chapterPartPath(path, i+1, options.Format)
+				if err := c.savePages(ctx, chapter, partPath, part, options, result); err != nil {
+					return err
+				}
+
+				result.Parts = append(result.Parts, partPath)
+			}
+
+			return nil
+		}
+	}
+
+	return c.savePages(ctx, chapter, path, downloadedPages, options, result)
+}
+
+// savePages saves downloaded pages of the chapter at path in the DownloadOptions.Format
+// and records their checksums if needed
+func (c *Client) savePages(
+	ctx context.Context,
+	chapter Chapter,
+	path string,
+	downloadedPages []PageWithImage,
+	options DownloadOptions,
+	result *DownloadResult,
+) error {
+	if err := c.saveFormat(ctx, chapter, path, downloadedPages, options, result); err != nil {
+		return err
+	}
+
+	if options.Checksums {
+		return c.writeChecksums(path, downloadedPages)
+	}
+
+	return nil
+}
+
+// saveFormat saves downloaded pages of the chapter at path in the DownloadOptions.Format
+func (c *Client) saveFormat(
+	ctx context.Context,
+	chapter Chapter,
+	path string,
+	downloadedPages []PageWithImage,
+	options DownloadOptions,
+	result *DownloadResult,
+) error {
+	provenance := c.provenance(chapter)
+	if options.Provenance.Sidecar {
+		if err := c.writeProvenance(path, provenance); err != nil {
+			return err
+		}
+	}
+
+	var comment string
+	if options.Provenance.ArchiveComment {
+		comment = provenance.comment()
+	}
+
 	switch options.Format {
 	case FormatPDF:
 		file, err := c.options.FS.Create(path)
@@ -234,8 +406,13 @@ func (c *Client) downloadChapter(
 		}
 		defer file.Close()
 
-		return c.savePDF(downloadedPages, file)
-	case FormatTAR:
+		var properties map[string]string
+		if options.Provenance.PDFMetadata {
+			properties = provenance.pdfProperties()
+		}
+
+		return c.savePDF(downloadedPages, file, properties)
+	case FormatTAR, FormatCBT:
 		file, err := c.options.FS.Create(path)
 		if err != nil {
 			return err
@@ -243,6 +420,14 @@ func (c *Client) downloadChapter(
 		defer file.Close()
 
 		return c.saveTAR(downloadedPages, file)
+	case FormatCB7:
+		file, err := c.options.FS.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		return c.saveCB7(downloadedPages, file)
 	case FormatTARGZ:
 		file, err := c.options.FS.Create(path)
 		if err != nil {
@@ -258,12 +443,61 @@ func (c *Client) downloadChapter(
 		}
 		defer file.Close()
 
-		return c.saveZIP(downloadedPages, file)
-	case FormatCBZ:
-		comicInfoXML, err := c.getComicInfoXML(ctx, chapter)
-		if err != nil && options.Strict {
+		return c.saveZIP(downloadedPages, file, comment)
+	case FormatHTML:
+		file, err := c.options.FS.Create(path)
+		if err != nil {
 			return err
 		}
+		defer file.Close()
+
+		return c.saveHTML(chapter, downloadedPages, file)
+	case FormatCBZ:
+		comicInfoXML, err := c.getComicInfoXML(ctx, chapter, options.ComicInfoXMLOptions.Source)
+		if err != nil {
+			if options.Strict {
+				return err
+			}
+
+			result.warn(err)
+		} else {
+			result.ComicInfoXMLWritten = true
+		}
+
+		// long strips are read top to bottom without paging
+		if result.LongStrip {
+			comicInfoXML.Manga = ReadingDirectionVertical.comicInfoManga()
+		} else if comicInfoXML.Manga == "" {
+			comicInfoXML.Manga = chapter.Volume().Manga().Info().ReadingDirection.comicInfoManga()
+		}
+
+		comicInfoXML.Title = c.translate(ctx, c.normalizeChapterTitle(comicInfoXML.Title))
+		comicInfoXML.Series = c.translate(ctx, comicInfoXML.Series)
+		comicInfoXML.Summary = options.Description.apply(comicInfoXML.Summary)
+
+		// chapters from different providers may share the manga directory,
+		// so record where this one came from
+		comicInfoXML.Notes = strings.TrimSpace(fmt.Sprintf("%s\nProvider: %s", comicInfoXML.Notes, c.Info().Name))
+
+		var cover []byte
+		if options.EmbedCover {
+			var buffer bytes.Buffer
+			if err := c.downloadVolumeCover(ctx, chapter.Volume(), c.coverProviders(options), &buffer); err != nil {
+				if options.Strict {
+					return MetadataError{err}
+				}
+
+				result.warn(err)
+			} else {
+				cover = buffer.Bytes()
+			}
+		}
+
+		wrapper := comicInfoXML.wrapper(options.ComicInfoXMLOptions)
+		wrapper.PageCount = len(downloadedPages)
+		for _, warning := range wrapper.sanitize() {
+			result.warn(warning)
+		}
 
 		file, err := c.options.FS.Create(path)
 		if err != nil {
@@ -271,7 +505,7 @@ func (c *Client) downloadChapter(
 		}
 		defer file.Close()
 
-		return c.saveCBZ(downloadedPages, file, comicInfoXML, options.ComicInfoXMLOptions)
+		return c.saveCBZ(downloadedPages, file, cover, wrapper, comment)
 	case FormatImages:
 		if err := c.options.FS.MkdirAll(path, modeDir); err != nil {
 			return err
@@ -292,25 +526,59 @@ func (c *Client) downloadChapter(
 
 		return nil
 	default:
-		// format validation was done before
-		panic("unreachable")
+		custom, ok := getCustomFormat(options.Format)
+		if !ok {
+			return fmt.Errorf("invalid format: %s", options.Format)
+		}
+
+		file, err := c.options.FS.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		c.options.Log(fmt.Sprintf("Saving %d pages as %s", len(downloadedPages), custom.info.Name))
+		return custom.saver(file, downloadedPages, chapter)
 	}
 }
 
 func (c *Client) getComicInfoXML(
 	ctx context.Context,
 	chapter Chapter,
+	source ComicInfoXMLSource,
 ) (ComicInfoXML, error) {
 	withComicInfoXML, ok := chapter.(ChapterWithComicInfoXML)
-	if ok {
-		comicInfo, err := withComicInfoXML.ComicInfoXML()
-		if err != nil {
-			return ComicInfoXML{}, err
-		}
+	if !ok || source == ComicInfoXMLAnilistOnly {
+		return c.getAnilistComicInfoXML(ctx, chapter)
+	}
+
+	providerComicInfo, err := withComicInfoXML.ComicInfoXML()
+	if err != nil {
+		return ComicInfoXML{}, err
+	}
+
+	if source == ComicInfoXMLProviderOnly {
+		return providerComicInfo, nil
+	}
 
-		return comicInfo, nil
+	anilistComicInfo, err := c.getAnilistComicInfoXML(ctx, chapter)
+	if err != nil {
+		// provider metadata is still good without Anilist
+		c.options.Log(fmt.Sprintf("Using provider ComicInfo only: %s", err))
+		return providerComicInfo, nil
+	}
+
+	if source == ComicInfoXMLMergeAnilistFirst {
+		return mergeComicInfoXML(anilistComicInfo, providerComicInfo), nil
 	}
 
+	return mergeComicInfoXML(providerComicInfo, anilistComicInfo), nil
+}
+
+func (c *Client) getAnilistComicInfoXML(
+	ctx context.Context,
+	chapter Chapter,
+) (ComicInfoXML, error) {
 	chapterWithAnilist, ok, err := c.Anilist().MakeChapterWithAnilist(ctx, chapter)
 	if err != nil {
 		return ComicInfoXML{}, err
@@ -364,34 +632,80 @@ func (c *Client) markChapterAsRead(ctx context.Context, chapter Chapter) error {
 	return c.Anilist().SetMangaProgress(ctx, manga.ID, progress)
 }
 
-// savePDF saves pages in FormatPDF
+// savePDF saves pages in FormatPDF.
+// Properties are written to the document information if not empty.
 func (c *Client) savePDF(
 	pages []PageWithImage,
 	out io.Writer,
+	properties map[string]string,
 ) error {
 	c.options.Log(fmt.Sprintf("Saving %d pages as PDF", len(pages)))
 
+	chunks := pdfChunks(pages, c.options.PDFMemoryBudget)
+	if len(chunks) > 1 {
+		return c.savePDFChunked(chunks, out, properties)
+	}
+
+	if len(properties) == 0 {
+		return c.importPDFImages(pages, out)
+	}
+
+	var buffer bytes.Buffer
+	if err := c.importPDFImages(pages, &buffer); err != nil {
+		return err
+	}
+
+	return api.AddProperties(bytes.NewReader(buffer.Bytes()), out, properties, nil)
+}
+
+// importPDFImages writes the PDF document with the page images
+func (c *Client) importPDFImages(pages []PageWithImage, out io.Writer) error {
 	// convert to readers
 	var images = make([]io.Reader, len(pages))
 	for i, page := range pages {
-		images[i] = bytes.NewReader(page.GetImage())
+		image, err := pdfCompatibleImage(page)
+		if err != nil {
+			return err
+		}
+
+		images[i] = bytes.NewReader(image)
 	}
 
 	return api.ImportImages(nil, out, images, nil, nil)
 }
 
-// saveCBZ saves pages in FormatCBZ
+// saveCBZ saves pages in FormatCBZ.
+// Cover is embedded as the first file if it's not empty.
 func (c *Client) saveCBZ(
 	pages []PageWithImage,
 	out io.Writer,
-	comicInfoXml ComicInfoXML,
-	options ComicInfoXMLOptions,
+	cover []byte,
+	comicInfoXML comicInfoXMLWrapper,
+	comment string,
 ) error {
 	c.options.Log(fmt.Sprintf("Saving %d pages as CBZ", len(pages)))
 
 	zipWriter := zip.NewWriter(out)
 	defer zipWriter.Close()
 
+	if err := zipWriter.SetComment(comment); err != nil {
+		return err
+	}
+
+	if len(cover) > 0 {
+		writer, err := zipWriter.CreateHeader(&zip.FileHeader{
+			Name:   filenameEmbeddedCoverJPG,
+			Method: zip.Store,
+		})
+		if err != nil {
+			return err
+		}
+
+		if _, err := writer.Write(cover); err != nil {
+			return err
+		}
+	}
+
 	for i, page := range pages {
 		writer, err := zipWriter.CreateHeader(&zip.FileHeader{
 			Name:   fmt.Sprintf("%04d%s", i+1, page.GetExtension()),
@@ -408,9 +722,9 @@ func (c *Client) saveCBZ(
 		}
 	}
 
-	wrapper := comicInfoXml.wrapper(options)
-	wrapper.PageCount = len(pages)
-	marshalled, err := wrapper.marshal()
+	comicInfoXML.Pages = comicInfoXML.describePages(pages, cover)
+
+	marshalled, err := comicInfoXML.marshal()
 	if err != nil {
 		return err
 	}
@@ -459,6 +773,23 @@ func (c *Client) saveTAR(
 	return nil
 }
 
+// saveCB7 saves pages in FormatCB7
+func (c *Client) saveCB7(
+	pages []PageWithImage,
+	out io.Writer,
+) error {
+	c.options.Log(fmt.Sprintf("Saving %d pages as CB7", len(pages)))
+
+	names := make([]string, len(pages))
+	images := make([][]byte, len(pages))
+	for i, page := range pages {
+		names[i] = fmt.Sprintf("%04d%s", i+1, page.GetExtension())
+		images[i] = page.GetImage()
+	}
+
+	return write7z(out, names, images)
+}
+
 func (c *Client) saveTARGZ(
 	pages []PageWithImage,
 	out io.Writer,
@@ -472,10 +803,15 @@ func (c *Client) saveTARGZ(
 func (c *Client) saveZIP(
 	pages []PageWithImage,
 	out io.Writer,
+	comment string,
 ) error {
 	zipWriter := zip.NewWriter(out)
 	defer zipWriter.Close()
 
+	if err := zipWriter.SetComment(comment); err != nil {
+		return err
+	}
+
 	for i, page := range pages {
 		writer, err := zipWriter.CreateHeader(&zip.FileHeader{
 			Name:   fmt.Sprintf("%04d%s", i+1, page.GetExtension()),
@@ -495,112 +831,313 @@ func (c *Client) saveZIP(
 	return nil
 }
 
+// chapterPath computes the path of the chapter
+// and the directory where manga metadata files are stored.
+func (c *Client) chapterPath(chapter Chapter, options DownloadOptions) (chapterPath, mangaDir string) {
+	directory := options.Directory
+
+	if options.CreateMangaDir {
+		directory = filepath.Join(directory, c.ComputeMangaFilename(chapter.Volume().Manga()))
+	}
+
+	mangaDir = directory
+
+	if options.CreateVolumeDir {
+		directory = filepath.Join(directory, c.ComputeVolumeFilename(chapter.Volume()))
+	}
+
+	return filepath.Join(directory, c.ComputeChapterFilename(chapter, options.Format)), mangaDir
+}
+
+// chapterState is what is known about the chapter before downloading it
+type chapterState struct {
+	// indexedPath is the path of the chapter in the ChapterIndex, if any
+	indexedPath string
+
+	// exists is true if the chapter or its first part exists
+	exists bool
+
+	// excluded is true if the chapter is excluded in the FailureJournal
+	excluded bool
+
+	// changed is true if the existing chapter has changed since its download.
+	// Only checked with DownloadOptions.RedownloadIfChanged
+	changed bool
+}
+
+// shouldDownload reports whether DownloadChapter would download the chapter
+func (s chapterState) shouldDownload(options DownloadOptions) bool {
+	return !s.exists || !options.SkipIfExists || s.changed
+}
+
+// chapterState checks whether the chapter at the chapterPath
+// exists in the existingFS and whether it needs to be downloaded again.
+// It's shared by the downloads, dry runs and MissingChapters.
+func (c *Client) chapterState(
+	ctx context.Context,
+	chapter Chapter,
+	chapterPath string,
+	options DownloadOptions,
+	existingFS afero.Fs,
+) (chapterState, error) {
+	var (
+		state chapterState
+		err   error
+	)
+
+	state.indexedPath, state.exists, err = c.chapterExistsAt(chapter, chapterPath, options, existingFS)
+	if err != nil {
+		return chapterState{}, err
+	}
+
+	state.excluded, err = c.chapterExcluded(chapter)
+	if err != nil {
+		return chapterState{}, err
+	}
+
+	if state.exists && options.SkipIfExists && options.RedownloadIfChanged {
+		state.changed, err = c.ChapterChanged(ctx, chapter)
+		if err != nil {
+			return chapterState{}, err
+		}
+	}
+
+	return state, nil
+}
+
+// chapterExistsAt checks whether the chapter is in the ChapterIndex
+// or exists at the chapterPath, possibly split into parts.
+// It returns the indexed path of the chapter, if any.
+func (c *Client) chapterExistsAt(
+	chapter Chapter,
+	chapterPath string,
+	options DownloadOptions,
+	existingFS afero.Fs,
+) (string, bool, error) {
+	indexedPath, exists, err := c.chapterIndexed(chapter)
+	if err != nil || exists {
+		return indexedPath, exists, err
+	}
+
+	exists, err = afero.Exists(existingFS, chapterPath)
+	if err != nil || exists {
+		return "", exists, err
+	}
+
+	if options.MaxFileSize > 0 {
+		// chapter might have been split
+		exists, err = afero.Exists(existingFS, chapterPartPath(chapterPath, 1, options.Format))
+	}
+
+	return "", exists, err
+}
+
+// chapterExcluded checks whether the chapter is excluded in the FailureJournal
+func (c *Client) chapterExcluded(chapter Chapter) (bool, error) {
+	journal := c.options.FailureJournal
+	if journal == nil {
+		return false, nil
+	}
+
+	return journal.IsExcluded(c.Info().ID, chapter)
+}
+
 func (c *Client) downloadChapterWithMetadata(
 	ctx context.Context,
 	chapter Chapter,
 	options DownloadOptions,
-	existsFunc pathExistsFunc,
-) (string, error) {
-	directory := options.Directory
+	existingFS afero.Fs,
+) (DownloadResult, error) {
+	chapterPath, mangaDir := c.chapterPath(chapter, options)
 
 	var (
-		seriesJSONDir = directory
-		coverDir      = directory
-		bannerDir     = directory
+		seriesJSONDir = mangaDir
+		coverDir      = mangaDir
+		bannerDir     = mangaDir
 	)
 
-	if options.CreateMangaDir {
-		directory = filepath.Join(directory, c.ComputeMangaFilename(chapter.Volume().Manga()))
-		seriesJSONDir = directory
-		coverDir = directory
-		bannerDir = directory
+	result := DownloadResult{
+		Path:   chapterPath,
+		Format: options.Format,
 	}
 
-	if options.CreateVolumeDir {
-		directory = filepath.Join(directory, c.ComputeVolumeFilename(chapter.Volume()))
+	err := c.options.FS.MkdirAll(filepath.Dir(chapterPath), modeDir)
+	if err != nil {
+		return DownloadResult{}, err
 	}
 
-	err := c.options.FS.MkdirAll(directory, modeDir)
+	state, err := c.chapterState(ctx, chapter, chapterPath, options, existingFS)
 	if err != nil {
-		return "", err
+		return DownloadResult{}, err
 	}
 
-	chapterPath := filepath.Join(directory, c.ComputeChapterFilename(chapter, options.Format))
+	shouldDownload := state.shouldDownload(options)
+	if state.changed {
+		updated, err := c.updateChapter(ctx, chapter, chapterPath, existingFS, options, &result)
+		if err != nil {
+			return DownloadResult{}, err
+		}
 
-	chapterExists, err := existsFunc(chapterPath)
-	if err != nil {
-		return "", err
+		if updated {
+			shouldDownload = false
+		} else {
+			c.options.Log(fmt.Sprintf("Chapter %q has changed, downloading again", chapter))
+		}
 	}
 
-	if !chapterExists || !options.SkipIfExists {
-		err = c.downloadChapter(ctx, chapter, chapterPath, options)
+	if shouldDownload {
+		err = c.downloadChapter(ctx, chapter, chapterPath, options, existingFS, &result)
 		if err != nil {
-			return "", err
+			return DownloadResult{}, err
+		}
+	} else if !result.Updated {
+		result.Skipped = true
+
+		if state.indexedPath != "" {
+			result.Path = state.indexedPath
 		}
 	}
 
 	if options.WriteSeriesJson {
 		path := filepath.Join(seriesJSONDir, filenameSeriesJSON)
-		exists, err := existsFunc(path)
+		exists, err := afero.Exists(existingFS, path)
 		if err != nil {
-			return "", err
+			return DownloadResult{}, err
 		}
 
 		if !exists {
 			file, err := c.options.FS.Create(path)
 			if err != nil {
-				return "", err
+				return DownloadResult{}, err
 			}
 			defer file.Close()
 
-			err = c.writeSeriesJSON(ctx, chapter.Volume().Manga(), file)
-			if err != nil && options.Strict {
-				return "", MetadataError{err}
+			err = c.writeSeriesJSON(ctx, chapter.Volume().Manga(), options.Description, file)
+			if err != nil {
+				if options.Strict {
+					return DownloadResult{}, MetadataError{err}
+				}
+
+				result.warn(err)
+			} else {
+				result.SeriesJSONWritten = true
 			}
 		}
 	}
 
 	if options.DownloadMangaCover {
 		path := filepath.Join(coverDir, filenameCoverJPG)
-		exists, err := existsFunc(path)
+		exists, err := afero.Exists(existingFS, path)
+		if err != nil {
+			return DownloadResult{}, err
+		}
+
+		if !exists {
+			file, err := c.options.FS.Create(path)
+			if err != nil {
+				return DownloadResult{}, err
+			}
+			defer file.Close()
+
+			err = c.downloadCover(ctx, chapter.Volume().Manga(), c.coverProviders(options), file)
+			if err != nil {
+				if options.Strict {
+					return DownloadResult{}, MetadataError{err}
+				}
+
+				result.warn(err)
+			} else {
+				result.CoverWritten = true
+			}
+		}
+	}
+
+	if options.DownloadVolumeCover && options.CreateVolumeDir {
+		path := filepath.Join(filepath.Dir(chapterPath), filenameCoverJPG)
+		exists, err := afero.Exists(existingFS, path)
 		if err != nil {
-			return "", err
+			return DownloadResult{}, err
 		}
 
 		if !exists {
 			file, err := c.options.FS.Create(path)
 			if err != nil {
-				return "", err
+				return DownloadResult{}, err
 			}
 			defer file.Close()
 
-			err = c.downloadCover(ctx, chapter.Volume().Manga(), file)
-			if err != nil && options.Strict {
-				return "", MetadataError{err}
+			err = c.downloadVolumeCover(ctx, chapter.Volume(), c.coverProviders(options), file)
+			if err != nil {
+				if options.Strict {
+					return DownloadResult{}, MetadataError{err}
+				}
+
+				result.warn(err)
+			} else {
+				result.VolumeCoverWritten = true
 			}
 		}
 	}
 
-	if options.DownloadMangaBanner {
-		path := filepath.Join(bannerDir, filenameBannerJPG)
-		exists, err := existsFunc(path)
+	if options.WriteVolumeJson && options.CreateVolumeDir {
+		written, err := c.updateVolumeJSON(ctx, chapter, filepath.Dir(chapterPath), options, existingFS)
 		if err != nil {
-			return "", err
+			if options.Strict {
+				return DownloadResult{}, MetadataError{err}
+			}
+
+			result.warn(err)
 		}
 
-		file, err := c.options.FS.Create(path)
+		result.VolumeJSONWritten = written
+	}
+
+	if options.DownloadMangaBanner {
+		path := filepath.Join(bannerDir, filenameBannerJPG)
+		exists, err := afero.Exists(existingFS, path)
 		if err != nil {
-			return "", err
+			return DownloadResult{}, err
 		}
-		defer file.Close()
 
 		if !exists {
+			file, err := c.options.FS.Create(path)
+			if err != nil {
+				return DownloadResult{}, err
+			}
+			defer file.Close()
+
 			err = c.downloadBanner(ctx, chapter.Volume().Manga(), file)
-			if err != nil && options.Strict {
-				return "", MetadataError{err}
+			if err != nil {
+				if options.Strict {
+					return DownloadResult{}, MetadataError{err}
+				}
+
+				result.warn(err)
+			} else {
+				result.BannerWritten = true
 			}
 		}
 	}
 
-	return chapterPath, nil
+	return result, nil
+}
+
+// mangaChapters gets chapters of all volumes of the given manga
+func (c *Client) mangaChapters(ctx context.Context, manga Manga) ([]Chapter, error) {
+	volumes, err := c.MangaVolumes(ctx, manga)
+	if err != nil {
+		return nil, err
+	}
+
+	var chapters []Chapter
+	for _, volume := range volumes {
+		volumeChapters, err := c.VolumeChapters(ctx, volume)
+		if err != nil {
+			return nil, err
+		}
+
+		chapters = append(chapters, volumeChapters...)
+	}
+
+	return chapters, nil
 }
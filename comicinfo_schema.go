@@ -0,0 +1,111 @@
+package libmangal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// comicInfoAgeRatings are the AgeRating values allowed by the ComicInfo schema
+//
+// https://github.com/anansi-project/comicinfo/blob/main/schema/v2.0/ComicInfo.xsd
+var comicInfoAgeRatings = []string{
+	"Unknown",
+	"Adults Only 18+",
+	"Early Childhood",
+	"Everyone",
+	"Everyone 10+",
+	"G",
+	"Kids to Adults",
+	"M",
+	"MA15+",
+	"Mature 17+",
+	"PG",
+	"R18+",
+	"Rating Pending",
+	"Teen",
+	"X18+",
+}
+
+// comicInfoMangaValues are the Manga values allowed by the ComicInfo schema
+var comicInfoMangaValues = []string{
+	"Unknown",
+	"No",
+	"Yes",
+	"YesAndRightToLeft",
+}
+
+// comicInfoEnum returns the allowed value matching the given one ignoring case
+func comicInfoEnum(allowed []string, value string) (string, bool) {
+	for _, a := range allowed {
+		if strings.EqualFold(a, strings.TrimSpace(value)) {
+			return a, true
+		}
+	}
+
+	return "", false
+}
+
+// sanitize makes the ComicInfo.xml valid against the schema, so that
+// readers like Komga and Kavita don't reject or misparse it.
+// Invalid values are fixed or omitted, each fix is returned as a warning.
+func (c *comicInfoXMLWrapper) sanitize() []error {
+	var warnings []error
+	warn := func(field string, value any, fix string) {
+		warnings = append(warnings, fmt.Errorf("ComicInfo.xml: invalid %s %v, %s", field, value, fix))
+	}
+
+	if c.AgeRating != "" {
+		if rating, ok := comicInfoEnum(comicInfoAgeRatings, c.AgeRating); ok {
+			c.AgeRating = rating
+		} else {
+			warn("AgeRating", fmt.Sprintf("%q", c.AgeRating), "omitted")
+			c.AgeRating = ""
+		}
+	}
+
+	if c.Manga != "" {
+		if manga, ok := comicInfoEnum(comicInfoMangaValues, c.Manga); ok {
+			c.Manga = manga
+		} else {
+			warn("Manga", fmt.Sprintf("%q", c.Manga), "omitted")
+			c.Manga = ""
+		}
+	}
+
+	switch {
+	case c.CommunityRating < 0:
+		warn("CommunityRating", c.CommunityRating, "clamped to 0")
+		c.CommunityRating = 0
+	case c.CommunityRating > 5:
+		warn("CommunityRating", c.CommunityRating, "clamped to 5")
+		c.CommunityRating = 5
+	}
+
+	if c.Month < 0 || c.Month > 12 {
+		warn("Month", c.Month, "omitted")
+		c.Month = 0
+	}
+
+	if c.Day < 0 || c.Day > 31 {
+		warn("Day", c.Day, "omitted")
+		c.Day = 0
+	}
+
+	for _, field := range []struct {
+		name  string
+		value *int
+	}{
+		{"Year", &c.Year},
+		{"Volume", &c.Volume},
+		{"Count", &c.Count},
+		{"PageCount", &c.PageCount},
+		{"StoryArcNumber", &c.StoryArcNumber},
+	} {
+		if *field.value < 0 {
+			warn(field.name, *field.value, "omitted")
+			*field.value = 0
+		}
+	}
+
+	return warnings
+}
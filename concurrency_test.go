@@ -0,0 +1,170 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// These tests are meant to be run with the race detector
+
+func TestClientConcurrentDownloadChapter(t *testing.T) {
+	const chapters = 8
+
+	provider := newFakeProvider(t, chapters, 4)
+	client := newTestClient(t, provider)
+
+	options := testDownloadOptions()
+	options.CreateMangaDir = true
+	options.CreateVolumeDir = false
+	options.WriteChaptersJson = true
+
+	results := make([]DownloadResult, chapters)
+	errs := make([]error, chapters)
+
+	var wg sync.WaitGroup
+	for i, chapter := range provider.chapterList() {
+		wg.Add(1)
+		go func(i int, chapter Chapter) {
+			defer wg.Done()
+			results[i], errs[i] = client.DownloadChapter(context.Background(), chapter, options)
+		}(i, chapter)
+	}
+
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("chapter %d: %s", i+1, err)
+		}
+
+		exists, err := afero.Exists(client.FS(), results[i].Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !exists {
+			t.Errorf("chapter %d: %s doesn't exist", i+1, results[i].Path)
+		}
+	}
+
+	chaptersJSON, err := client.ReadChaptersJSON(filepath.Dir(results[0].Path))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := len(chaptersJSON.Chapters); got != chapters {
+		t.Errorf("chapters.json lists %d chapters, want %d", got, chapters)
+	}
+
+	if got, want := provider.requests.Load(), int64(chapters*provider.pages); got != want {
+		t.Errorf("got %d page requests, want %d", got, want)
+	}
+}
+
+func TestClientSetLogFuncDuringDownload(t *testing.T) {
+	provider := newFakeProvider(t, 4, 8)
+	client := newTestClient(t, provider)
+
+	var logged atomic.Int64
+	logFunc := func(string) {
+		logged.Add(1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var swaps sync.WaitGroup
+	swaps.Add(1)
+	go func() {
+		defer swaps.Done()
+
+		for ctx.Err() == nil {
+			client.SetLogFunc(logFunc)
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	var downloads sync.WaitGroup
+	for _, chapter := range provider.chapterList() {
+		downloads.Add(1)
+		go func(chapter Chapter) {
+			defer downloads.Done()
+
+			if _, err := client.DownloadChapter(ctx, chapter, testDownloadOptions()); err != nil {
+				t.Error(err)
+			}
+		}(chapter)
+	}
+
+	downloads.Wait()
+	cancel()
+	swaps.Wait()
+
+	// messages logged after the first swap go to the new function
+	client.SetLogFunc(logFunc)
+	before := logged.Load()
+	client.options.Log("message")
+
+	if got := logged.Load(); got != before+1 {
+		t.Errorf("got %d messages, want %d", got, before+1)
+	}
+}
+
+func TestAnilistConcurrentToken(t *testing.T) {
+	anilist := NewAnilist(DefaultAnilistOptions())
+
+	// copies share the token
+	copied := anilist
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(2)
+
+		go func(i int) {
+			defer wg.Done()
+
+			token := AnilistToken{
+				AccessToken: fmt.Sprintf("token-%d", i),
+				ExpiresAt:   time.Now().Add(time.Hour),
+			}
+
+			if err := anilist.SetToken(token); err != nil {
+				t.Error(err)
+			}
+		}(i)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				if token, ok := copied.Token(); ok && token.AccessToken == "" {
+					t.Error("got empty access token")
+				}
+
+				_ = copied.IsAuthorized()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if !copied.IsAuthorized() {
+		t.Error("copy of the client is not authorized")
+	}
+
+	token, ok := anilist.Token()
+	if !ok {
+		t.Fatal("token is not set")
+	}
+
+	copiedToken, _ := copied.Token()
+	if token.AccessToken != copiedToken.AccessToken {
+		t.Errorf("copy has token %q, want %q", copiedToken.AccessToken, token.AccessToken)
+	}
+}
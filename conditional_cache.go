@@ -0,0 +1,131 @@
+package libmangal
+
+import (
+	"fmt"
+	"github.com/philippgille/gokv"
+	"io"
+	"net/http"
+	"time"
+)
+
+// CacheValidators are the HTTP cache validators used
+// to make conditional requests.
+type CacheValidators struct {
+	// ETag is the value of the ETag response header
+	ETag string `json:"etag"`
+
+	// LastModified is the value of the Last-Modified response header
+	LastModified string `json:"lastModified"`
+}
+
+// CacheValidatorsFromResponse extracts cache validators from the response headers
+func CacheValidatorsFromResponse(response *http.Response) CacheValidators {
+	return CacheValidators{
+		ETag:         response.Header.Get("ETag"),
+		LastModified: response.Header.Get("Last-Modified"),
+	}
+}
+
+// IsZero checks whether there are no validators
+func (c CacheValidators) IsZero() bool {
+	return c.ETag == "" && c.LastModified == ""
+}
+
+// Apply sets conditional headers on the request,
+// so that the server may respond with 304 Not Modified.
+func (c CacheValidators) Apply(request *http.Request) {
+	if c.ETag != "" {
+		request.Header.Set("If-None-Match", c.ETag)
+	}
+
+	if c.LastModified != "" {
+		request.Header.Set("If-Modified-Since", c.LastModified)
+	}
+}
+
+type conditionalCacheEntry struct {
+	Validators CacheValidators `json:"validators"`
+	Body       []byte          `json:"body"`
+}
+
+// ConditionalCache performs conditional GET requests, remembering
+// response bodies with their validators, so that the refreshes of pages
+// that didn't change (e.g. chapters list) are cheap 304 responses.
+//
+// It's intended to be used by providers.
+// It's safe for concurrent use if the client and the store are.
+type ConditionalCache struct {
+	client *http.Client
+	store  gokv.Store
+	index  *storeIndex
+}
+
+// NewConditionalCache constructs new ConditionalCache
+// that uses client for requests and stores responses in the store.
+func NewConditionalCache(client *http.Client, store gokv.Store) *ConditionalCache {
+	return &ConditionalCache{
+		client: client,
+		store:  store,
+		index:  newStoreIndex(store),
+	}
+}
+
+// Prune removes responses that were stored more than maxAge ago.
+// It returns the number of removed responses.
+func (c *ConditionalCache) Prune(maxAge time.Duration) (int, error) {
+	return c.index.prune(maxAge)
+}
+
+// Get performs the GET request with the validators of the
+// previous response if there is one. Request method must be GET.
+//
+// It returns response body and whether it was unchanged
+// since the last request, in which case the cached body is returned.
+func (c *ConditionalCache) Get(request *http.Request) (body []byte, notModified bool, err error) {
+	if request.Method != http.MethodGet {
+		return nil, false, fmt.Errorf("unsupported method %s", request.Method)
+	}
+
+	key := request.URL.String()
+
+	var entry conditionalCacheEntry
+	found, err := c.store.Get(key, &entry)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if found {
+		entry.Validators.Apply(request)
+	}
+
+	response, err := c.client.Do(request)
+	if err != nil {
+		return nil, false, err
+	}
+	defer response.Body.Close()
+
+	if found && response.StatusCode == http.StatusNotModified {
+		return entry.Body, true, nil
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return nil, false, fmt.Errorf("unexpected http status: %s", response.Status)
+	}
+
+	body, err = io.ReadAll(response.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	validators := CacheValidatorsFromResponse(response)
+	if validators.IsZero() {
+		return body, false, nil
+	}
+
+	err = c.index.set(key, conditionalCacheEntry{
+		Validators: validators,
+		Body:       body,
+	})
+
+	return body, false, err
+}
@@ -0,0 +1,222 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/spf13/afero"
+	"io"
+	"strings"
+)
+
+// Cover is the cover image found by the CoverProvider.
+// Either URL or Image is set.
+type Cover struct {
+	// URL of the image, downloaded with the manga page as the referer
+	URL string
+
+	// Image contents, e.g. read from the local file
+	Image []byte
+}
+
+// CoverProvider finds covers of mangas and volumes.
+//
+// See DownloadOptions.CoverProviders
+type CoverProvider interface {
+	// MangaCover returns the cover of the manga.
+	// False is returned if it's not found.
+	MangaCover(ctx context.Context, manga Manga) (Cover, bool, error)
+
+	// VolumeCover returns the cover of the volume.
+	// False is returned if it's not found or covers of volumes are not supported.
+	VolumeCover(ctx context.Context, volume Volume) (Cover, bool, error)
+}
+
+// SourceCoverProvider uses covers given by the manga provider,
+// see MangaInfo.Cover and VolumeInfo.Cover
+func SourceCoverProvider() CoverProvider {
+	return sourceCoverProvider{}
+}
+
+type sourceCoverProvider struct{}
+
+func (sourceCoverProvider) MangaCover(_ context.Context, manga Manga) (Cover, bool, error) {
+	URL := manga.Info().Cover
+	return Cover{URL: URL}, URL != "", nil
+}
+
+func (sourceCoverProvider) VolumeCover(_ context.Context, volume Volume) (Cover, bool, error) {
+	URL := volume.Info().Cover
+	return Cover{URL: URL}, URL != "", nil
+}
+
+// AnilistCoverProvider uses covers of the manga on Anilist.
+// Anilist has no volume covers.
+func AnilistCoverProvider(anilist *Anilist) CoverProvider {
+	return anilistCoverProvider{anilist: anilist}
+}
+
+type anilistCoverProvider struct {
+	anilist *Anilist
+}
+
+func (a anilistCoverProvider) MangaCover(ctx context.Context, manga Manga) (Cover, bool, error) {
+	mangaWithAnilist, ok, err := a.anilist.MakeMangaWithAnilist(ctx, manga)
+	if err != nil || !ok {
+		return Cover{}, false, err
+	}
+
+	for _, URL := range []string{
+		mangaWithAnilist.Anilist.CoverImage.ExtraLarge,
+		mangaWithAnilist.Anilist.CoverImage.Large,
+		mangaWithAnilist.Anilist.CoverImage.Medium,
+	} {
+		if URL != "" {
+			return Cover{URL: URL}, true, nil
+		}
+	}
+
+	return Cover{}, false, nil
+}
+
+func (anilistCoverProvider) VolumeCover(context.Context, Volume) (Cover, bool, error) {
+	return Cover{}, false, nil
+}
+
+// StaticCoverProvider uses the covers given by the user,
+// e.g. when downloading a single manga with the known good covers.
+//
+// Each cover is either http(s) URL or the path to the image file.
+type StaticCoverProvider struct {
+	// Manga is the cover of the manga. Empty means not set.
+	Manga string
+
+	// Volumes are covers by volume number
+	Volumes map[int]string
+
+	// FS to read cover files from. Nil means the OS filesystem
+	FS afero.Fs
+}
+
+func (s StaticCoverProvider) MangaCover(_ context.Context, _ Manga) (Cover, bool, error) {
+	return s.cover(s.Manga)
+}
+
+func (s StaticCoverProvider) VolumeCover(_ context.Context, volume Volume) (Cover, bool, error) {
+	return s.cover(s.Volumes[volume.Info().Number])
+}
+
+func (s StaticCoverProvider) cover(location string) (Cover, bool, error) {
+	if location == "" {
+		return Cover{}, false, nil
+	}
+
+	if strings.HasPrefix(location, "http://") || strings.HasPrefix(location, "https://") {
+		return Cover{URL: location}, true, nil
+	}
+
+	fs := s.FS
+	if fs == nil {
+		fs = afero.NewOsFs()
+	}
+
+	image, err := afero.ReadFile(fs, location)
+	if err != nil {
+		return Cover{}, false, err
+	}
+
+	return Cover{Image: image}, true, nil
+}
+
+// coverProviders returns providers to use with the given options
+func (c *Client) coverProviders(options DownloadOptions) []CoverProvider {
+	if options.CoverProviders != nil {
+		return options.CoverProviders
+	}
+
+	return []CoverProvider{
+		SourceCoverProvider(),
+		AnilistCoverProvider(c.Anilist()),
+	}
+}
+
+// findCover tries providers in order until the cover is found.
+// Errors of providers are returned only if none has found the cover.
+func (c *Client) findCover(
+	providers []CoverProvider,
+	find func(provider CoverProvider) (Cover, bool, error),
+) (Cover, bool, error) {
+	var errs []error
+	for _, provider := range providers {
+		cover, ok, err := find(provider)
+		if err != nil {
+			c.options.Log(fmt.Sprintf("Cover provider %T: %s", provider, err))
+			errs = append(errs, err)
+			continue
+		}
+
+		if ok {
+			return cover, true, nil
+		}
+	}
+
+	return Cover{}, false, errors.Join(errs...)
+}
+
+// writeCover writes the cover image, downloading it if needed
+func (c *Client) writeCover(ctx context.Context, manga Manga, cover Cover, out io.Writer) error {
+	if cover.URL == "" {
+		_, err := out.Write(cover.Image)
+		return err
+	}
+
+	c.options.Log(cover.URL)
+	return c.downloadMangaImage(ctx, manga, cover.URL, out)
+}
+
+// downloadCover will download cover of the manga
+func (c *Client) downloadCover(
+	ctx context.Context,
+	manga Manga,
+	providers []CoverProvider,
+	out io.Writer,
+) error {
+	c.options.Log("Downloading cover")
+
+	cover, ok, err := c.findCover(providers, func(provider CoverProvider) (Cover, bool, error) {
+		return provider.MangaCover(ctx, manga)
+	})
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return errors.New("cover not found")
+	}
+
+	return c.writeCover(ctx, manga, cover, out)
+}
+
+// downloadVolumeCover will download cover of the volume.
+// Falls back to the manga cover if volume has no cover.
+func (c *Client) downloadVolumeCover(
+	ctx context.Context,
+	volume Volume,
+	providers []CoverProvider,
+	out io.Writer,
+) error {
+	cover, ok, volumeErr := c.findCover(providers, func(provider CoverProvider) (Cover, bool, error) {
+		return provider.VolumeCover(ctx, volume)
+	})
+
+	if ok {
+		c.options.Log("Downloading volume cover")
+		return c.writeCover(ctx, volume.Manga(), cover, out)
+	}
+
+	if err := c.downloadCover(ctx, volume.Manga(), providers, out); err != nil {
+		return errors.Join(volumeErr, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package libmangal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	mangaDexAPIURL     = "https://api.mangadex.org"
+	mangaDexUploadsURL = "https://uploads.mangadex.org"
+)
+
+// MangaDexCoverOptions configures the MangaDex cover provider
+type MangaDexCoverOptions struct {
+	// HTTPClient to use for MangaDex API requests
+	HTTPClient *http.Client
+
+	// Locales of the volume covers in the order of preference,
+	// e.g. "ja" for the original edition. Covers of any locale
+	// are used if none matches.
+	Locales []string
+}
+
+// DefaultMangaDexCoverOptions constructs default MangaDexCoverOptions
+func DefaultMangaDexCoverOptions() MangaDexCoverOptions {
+	return MangaDexCoverOptions{
+		HTTPClient: &http.Client{
+			Timeout: time.Minute,
+		},
+		Locales: []string{"ja"},
+	}
+}
+
+// MangaDexCoverProvider uses covers of the MangaDex covers API,
+// which has the full-size covers of individual volumes.
+// Manga is found by its title.
+func MangaDexCoverProvider(options MangaDexCoverOptions) CoverProvider {
+	return &mangaDexCoverProvider{
+		options: options,
+		covers:  make(map[string]mangaDexCovers),
+	}
+}
+
+type mangaDexCover struct {
+	volume string
+	locale string
+	URL    string
+}
+
+// mangaDexCovers are covers of the single manga
+type mangaDexCovers struct {
+	// main is the URL of the cover MangaDex shows for the manga.
+	// Empty if the manga isn't found.
+	main    string
+	volumes []mangaDexCover
+}
+
+type mangaDexCoverProvider struct {
+	options MangaDexCoverOptions
+
+	mu sync.Mutex
+	// covers are cached by manga title
+	covers map[string]mangaDexCovers
+}
+
+func (m *mangaDexCoverProvider) MangaCover(ctx context.Context, manga Manga) (Cover, bool, error) {
+	covers, err := m.mangaCovers(ctx, manga)
+	if err != nil || covers.main == "" {
+		return Cover{}, false, err
+	}
+
+	return Cover{URL: covers.main}, true, nil
+}
+
+func (m *mangaDexCoverProvider) VolumeCover(ctx context.Context, volume Volume) (Cover, bool, error) {
+	covers, err := m.mangaCovers(ctx, volume.Manga())
+	if err != nil {
+		return Cover{}, false, err
+	}
+
+	number := strconv.Itoa(volume.Info().Number)
+
+	var found []mangaDexCover
+	for _, cover := range covers.volumes {
+		if cover.volume == number {
+			found = append(found, cover)
+		}
+	}
+
+	if len(found) == 0 {
+		return Cover{}, false, nil
+	}
+
+	for _, locale := range m.options.Locales {
+		for _, cover := range found {
+			if cover.locale == locale {
+				return Cover{URL: cover.URL}, true, nil
+			}
+		}
+	}
+
+	return Cover{URL: found[0].URL}, true, nil
+}
+
+func (m *mangaDexCoverProvider) mangaCovers(ctx context.Context, manga Manga) (mangaDexCovers, error) {
+	title := manga.Info().Title
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if covers, ok := m.covers[title]; ok {
+		return covers, nil
+	}
+
+	covers, err := m.fetchCovers(ctx, title)
+	if err != nil {
+		return mangaDexCovers{}, err
+	}
+
+	m.covers[title] = covers
+	return covers, nil
+}
+
+func (m *mangaDexCoverProvider) fetchCovers(ctx context.Context, title string) (mangaDexCovers, error) {
+	var mangas struct {
+		Data []struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				Title     map[string]string   `json:"title"`
+				AltTitles []map[string]string `json:"altTitles"`
+			} `json:"attributes"`
+			Relationships []struct {
+				Type       string `json:"type"`
+				Attributes struct {
+					FileName string `json:"fileName"`
+				} `json:"attributes"`
+			} `json:"relationships"`
+		} `json:"data"`
+	}
+
+	query := url.Values{}
+	query.Set("title", title)
+	query.Set("limit", "5")
+	query.Add("includes[]", "cover_art")
+
+	if err := m.get(ctx, "/manga", query, &mangas); err != nil {
+		return mangaDexCovers{}, err
+	}
+
+	if len(mangas.Data) == 0 {
+		return mangaDexCovers{}, nil
+	}
+
+	// search is fuzzy, prefer the exact match
+	found := mangas.Data[0]
+search:
+	for _, manga := range mangas.Data {
+		titles := []map[string]string{manga.Attributes.Title}
+		titles = append(titles, manga.Attributes.AltTitles...)
+
+		for _, localized := range titles {
+			for _, t := range localized {
+				if strings.EqualFold(t, title) {
+					found = manga
+					break search
+				}
+			}
+		}
+	}
+
+	covers := mangaDexCovers{}
+	for _, relationship := range found.Relationships {
+		if relationship.Type == "cover_art" && relationship.Attributes.FileName != "" {
+			covers.main = m.coverURL(found.ID, relationship.Attributes.FileName)
+		}
+	}
+
+	var volumes struct {
+		Data []struct {
+			Attributes struct {
+				Volume   string `json:"volume"`
+				FileName string `json:"fileName"`
+				Locale   string `json:"locale"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+
+	query = url.Values{}
+	query.Add("manga[]", found.ID)
+	query.Set("limit", "100")
+	query.Set("order[volume]", "asc")
+
+	if err := m.get(ctx, "/cover", query, &volumes); err != nil {
+		return mangaDexCovers{}, err
+	}
+
+	for _, cover := range volumes.Data {
+		covers.volumes = append(covers.volumes, mangaDexCover{
+			volume: cover.Attributes.Volume,
+			locale: cover.Attributes.Locale,
+			URL:    m.coverURL(found.ID, cover.Attributes.FileName),
+		})
+	}
+
+	return covers, nil
+}
+
+func (m *mangaDexCoverProvider) coverURL(mangaID, fileName string) string {
+	return fmt.Sprintf("%s/covers/%s/%s", mangaDexUploadsURL, mangaID, fileName)
+}
+
+func (m *mangaDexCoverProvider) get(ctx context.Context, path string, query url.Values, v any) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, mangaDexAPIURL+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Accept", "application/json")
+
+	response, err := m.options.HTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return HTTPStatusError{
+			StatusCode: response.StatusCode,
+			Status:     response.Status,
+			RetryAfter: parseRetryAfter(response.Header),
+		}
+	}
+
+	return json.NewDecoder(response.Body).Decode(v)
+}
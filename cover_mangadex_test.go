@@ -0,0 +1,122 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// fakeMangaDexAPI serves the fuzzy search with the exact match second
+// and the volume covers of the matched manga
+func fakeMangaDexAPI(t *testing.T, requests *int) *http.Client {
+	return &http.Client{Transport: roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		*requests++
+
+		var body string
+		switch request.URL.Path {
+		case "/manga":
+			if title := request.URL.Query().Get("title"); title != "Fake Manga" {
+				t.Errorf("searched for %q", title)
+			}
+
+			body = `{"data": [
+				{"id": "other", "attributes": {"title": {"en": "Fake Manga Doujinshi"}}},
+				{
+					"id": "fake-id",
+					"attributes": {"title": {"ja-ro": "Nise Manga"}, "altTitles": [{"en": "fake manga"}]},
+					"relationships": [{"type": "cover_art", "attributes": {"fileName": "main.jpg"}}]
+				}
+			]}`
+		case "/cover":
+			if id := request.URL.Query().Get("manga[]"); id != "fake-id" {
+				t.Errorf("got covers of %q, want the exact match", id)
+			}
+
+			body = `{"data": [
+				{"attributes": {"volume": "1", "fileName": "1-en.jpg", "locale": "en"}},
+				{"attributes": {"volume": "1", "fileName": "1-ja.jpg", "locale": "ja"}},
+				{"attributes": {"volume": "2", "fileName": "2-en.jpg", "locale": "en"}}
+			]}`
+		default:
+			t.Errorf("unexpected request to %s", request.URL)
+		}
+
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(body)),
+			Request:    request,
+		}, nil
+	})}
+}
+
+func TestMangaDexCoverProvider(t *testing.T) {
+	var requests int
+
+	options := DefaultMangaDexCoverOptions()
+	options.HTTPClient = fakeMangaDexAPI(t, &requests)
+	provider := MangaDexCoverProvider(options)
+
+	fake := newFakeProvider(t, 1, 1)
+	manga := fake.manga()
+	ctx := context.Background()
+
+	cover, ok, err := provider.MangaCover(ctx, manga)
+	if err != nil || !ok {
+		t.Fatalf("manga cover not found: %v", err)
+	}
+
+	if want := mangaDexUploadsURL + "/covers/fake-id/main.jpg"; cover.URL != want {
+		t.Errorf("got manga cover %s, want %s", cover.URL, want)
+	}
+
+	for _, test := range []struct {
+		number int
+		want   string
+	}{
+		{1, "1-ja.jpg"},
+		{2, "2-en.jpg"},
+		{3, ""},
+	} {
+		cover, ok, err := provider.VolumeCover(ctx, fakeVolume{number: test.number, manga: manga})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch {
+		case test.want == "" && ok:
+			t.Errorf("volume %d: got cover %s, want none", test.number, cover.URL)
+		case test.want != "" && !strings.HasSuffix(cover.URL, "/fake-id/"+test.want):
+			t.Errorf("volume %d: got cover %q, want %s", test.number, cover.URL, test.want)
+		}
+	}
+
+	// covers are fetched once per manga
+	if requests != 2 {
+		t.Errorf("made %d requests, want 2", requests)
+	}
+}
+
+func TestMangaDexCoverProviderStatus(t *testing.T) {
+	options := DefaultMangaDexCoverOptions()
+	options.HTTPClient = &http.Client{Transport: roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusServiceUnavailable,
+			Status:     "503 Service Unavailable",
+			Header:     http.Header{"Retry-After": {"10"}},
+			Body:       io.NopCloser(strings.NewReader("")),
+			Request:    request,
+		}, nil
+	})}
+
+	provider := MangaDexCoverProvider(options)
+
+	_, _, err := provider.MangaCover(context.Background(), newFakeProvider(t, 1, 1).manga())
+
+	var statusErr HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("got error %v, want the status error", err)
+	}
+}
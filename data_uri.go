@@ -0,0 +1,61 @@
+package libmangal
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// decodeDataURI decodes the contents of RFC 2397 data URI.
+// E.g. "data:image/png;base64,iVBORw0KGgo..."
+//
+// Both base64 and percent-encoded payloads are supported.
+// Decoded data larger than limit will result in SizeLimitError.
+func decodeDataURI(uri string, limit int64) ([]byte, error) {
+	const scheme = "data:"
+
+	if !strings.HasPrefix(uri, scheme) {
+		return nil, errors.New("data uri must start with " + scheme)
+	}
+
+	header, payload, found := strings.Cut(uri[len(scheme):], ",")
+	if !found {
+		return nil, errors.New("data uri is missing a comma")
+	}
+
+	if strings.HasSuffix(header, ";base64") {
+		// check the size before decoding to avoid allocating huge buffers,
+		// padding doesn't count
+		size := base64.RawStdEncoding.DecodedLen(len(strings.TrimRight(payload, "=")))
+		if err := checkSizeLimit(int64(size), limit); err != nil {
+			return nil, err
+		}
+
+		// some sources strip padding
+		encoding := base64.StdEncoding
+		if len(payload)%4 != 0 {
+			encoding = base64.RawStdEncoding
+		}
+
+		data, err := encoding.DecodeString(payload)
+		if err != nil {
+			return nil, fmt.Errorf("data uri: %w", err)
+		}
+
+		return data, nil
+	}
+
+	// percent-encoded payload is never larger than its decoded form
+	if err := checkSizeLimit(int64(len(payload)), limit); err != nil {
+		return nil, err
+	}
+
+	data, err := url.PathUnescape(payload)
+	if err != nil {
+		return nil, fmt.Errorf("data uri: %w", err)
+	}
+
+	return []byte(data), nil
+}
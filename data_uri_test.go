@@ -0,0 +1,92 @@
+package libmangal
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestDecodeDataURI(t *testing.T) {
+	for _, test := range []struct {
+		uri  string
+		want string
+	}{
+		{"data:image/png;base64,aGVsbG8=", "hello"},
+		{"data:image/png;base64,aGVsbG8", "hello"},
+		{"data:;base64,", ""},
+		{"data:text/plain,hello%20world", "hello world"},
+		{"data:,a,b", "a,b"},
+	} {
+		got, err := decodeDataURI(test.uri, 0)
+		if err != nil {
+			t.Errorf("%s: %s", test.uri, err)
+			continue
+		}
+
+		if string(got) != test.want {
+			t.Errorf("%s: got %q, want %q", test.uri, got, test.want)
+		}
+	}
+
+	for _, uri := range []string{
+		"https://example.com/page.png",
+		"data:image/png;base64",
+		"data:image/png;base64,!!!!",
+		"data:text/plain,%zz",
+	} {
+		if _, err := decodeDataURI(uri, 0); err == nil {
+			t.Errorf("%s: expected an error", uri)
+		}
+	}
+}
+
+func TestDecodeDataURISizeLimit(t *testing.T) {
+	payload := base64.StdEncoding.EncodeToString(make([]byte, 100))
+
+	for _, uri := range []string{
+		"data:image/png;base64," + payload,
+		"data:text/plain," + string(bytes.Repeat([]byte("a"), 100)),
+	} {
+		var sizeErr SizeLimitError
+		if _, err := decodeDataURI(uri, 99); !errors.As(err, &sizeErr) || sizeErr.Limit != 99 {
+			t.Errorf("got error %v, want SizeLimitError", err)
+		}
+
+		if _, err := decodeDataURI(uri, 100); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+// fakeDataURIPage delivers its image inline
+type fakeDataURIPage struct {
+	fakePage
+	uri string
+}
+
+func (p fakeDataURIPage) DataURI() string { return p.uri }
+
+func TestDownloadPageDataURI(t *testing.T) {
+	provider := newFakeProvider(t, 1, 1)
+	client := newTestClient(t, provider)
+
+	page := fakeDataURIPage{
+		fakePage: fakePage{index: 1, chapter: provider.chapterList()[0].(fakeChapter)},
+		uri:      "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(provider.image),
+	}
+
+	downloaded, err := client.DownloadPage(context.Background(), page)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(downloaded.GetImage(), provider.image) {
+		t.Error("decoded image differs")
+	}
+
+	if provider.requests.Load() != 0 {
+		t.Error("image was requested from the provider")
+	}
+}
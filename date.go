@@ -0,0 +1,47 @@
+package libmangal
+
+import (
+	"fmt"
+	"time"
+)
+
+// IsZero reports whether the date is unknown
+func (d Date) IsZero() bool {
+	return d.Year == 0
+}
+
+// String formats the date with its known parts, e.g. "March 2019" or "2019".
+// Empty string is returned for the unknown date.
+func (d Date) String() string {
+	switch {
+	case d.IsZero():
+		return ""
+	case d.Month >= 1 && d.Month <= 12:
+		return fmt.Sprintf("%s %d", time.Month(d.Month), d.Year)
+	default:
+		return fmt.Sprint(d.Year)
+	}
+}
+
+// PublicationRun formats the publication period of the series,
+// e.g. "March 2019 - December 2022" or "March 2019 - Present" if it's ongoing.
+//
+// Unknown dates are omitted, empty string is returned if the start is unknown
+// and there is nothing else to tell.
+func PublicationRun(start, end Date, ongoing bool) string {
+	from, to := start.String(), end.String()
+	if ongoing {
+		to = "Present"
+	}
+
+	switch {
+	case from == "" && (to == "" || ongoing):
+		return ""
+	case to == "" || to == from:
+		return from
+	case from == "":
+		return "- " + to
+	default:
+		return from + " - " + to
+	}
+}
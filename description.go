@@ -0,0 +1,89 @@
+package libmangal
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// DescriptionOptions cleans up descriptions written to the ComicInfo.xml Summary
+// and series.json. Anilist descriptions are HTML and often contain spoilers.
+//
+// Zero value keeps descriptions as is.
+type DescriptionOptions struct {
+	// StripHTML converts HTML to plain text.
+	// Line breaks and paragraphs are kept as newlines.
+	StripHTML bool
+
+	// CutSpoilers cuts the description at the first spoiler,
+	// marked as ~!spoiler!~ or with the markdown_spoiler span.
+	CutSpoilers bool
+
+	// MaxLength limits the length of the description in characters.
+	// Longer descriptions are cut at the word boundary ending with "…".
+	// Zero means no limit.
+	MaxLength int
+}
+
+var (
+	spoilerMarkerRegex = regexp.MustCompile(`~!|(?i:<span[^>]*markdown_spoiler)`)
+	htmlLineBreakRegex = regexp.MustCompile(`(?i)<br\s*/?>|</p>`)
+	htmlTagRegex       = regexp.MustCompile(`<[^>]*>`)
+	blankLinesRegex    = regexp.MustCompile(`\n{3,}`)
+	trailingBreakRegex = regexp.MustCompile(`(?i)(\s|<br\s*/?>)+$`)
+)
+
+// apply cleans up the description according to the options
+func (d DescriptionOptions) apply(description string) string {
+	if d.CutSpoilers {
+		if loc := spoilerMarkerRegex.FindStringIndex(description); loc != nil {
+			description = trailingBreakRegex.ReplaceAllString(description[:loc[0]], "")
+		}
+	}
+
+	if d.StripHTML {
+		description = stripHTML(description)
+	}
+
+	if d.MaxLength > 0 {
+		description = truncateDescription(description, d.MaxLength)
+	}
+
+	return strings.TrimSpace(description)
+}
+
+// stripHTML converts HTML to plain text
+func stripHTML(text string) string {
+	text = htmlLineBreakRegex.ReplaceAllString(text, "\n")
+	text = htmlTagRegex.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+
+	text = strings.Join(lines, "\n")
+	text = blankLinesRegex.ReplaceAllString(text, "\n\n")
+
+	return strings.TrimSpace(text)
+}
+
+// truncateDescription cuts the text to the length in characters
+// at the last word boundary, ending it with "…"
+func truncateDescription(text string, length int) string {
+	if utf8.RuneCountInString(text) <= length {
+		return text
+	}
+
+	runes := []rune(text)
+	cut := string(runes[:length-1])
+
+	// don't leave a partial word if there is a boundary at all
+	if i := strings.LastIndexAny(cut, " \n"); i > 0 {
+		cut = cut[:i]
+	}
+
+	return strings.TrimRight(cut, " \n.,;:") + "…"
+}
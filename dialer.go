@@ -0,0 +1,430 @@
+package libmangal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DialerOptions configures how connections are made, e.g. to work
+// around DNS blocks of manga sites. See NewHTTPClient
+type DialerOptions struct {
+	// HostOverrides maps hosts to the IPs to connect to,
+	// like the hosts file does. E.g. {"example.com": "93.184.216.34"}
+	HostOverrides map[string]string
+
+	// DNSServer is the address of the DNS server, e.g. "1.1.1.1:53".
+	// System resolver is used if empty.
+	DNSServer string
+
+	// DoHURL is the DNS-over-HTTPS endpoint with the JSON API,
+	// e.g. "https://1.1.1.1/dns-query". Takes precedence over DNSServer.
+	// Use the IP address in the URL, so that the endpoint itself
+	// doesn't need to be resolved. Answers are cached for their TTL.
+	DoHURL string
+
+	// ProxyURL routes connections through the proxy,
+	// e.g. "socks5://127.0.0.1:9050" for Tor or "http://proxy:8080".
+	// Hosts are resolved by SOCKS5 proxies, so onion services are reachable
+	// and HostOverrides, DNSServer and DoHURL only apply to the proxy itself.
+	//
+	// Since each Client has its own ClientOptions.HTTPClient,
+	// traffic of a single provider can be routed through the proxy
+	// while other traffic (e.g. Anilist) stays direct.
+	ProxyURL string
+
+	// Timeout is the maximum amount of time a dial will wait for a connect to complete
+	Timeout time.Duration
+
+	// FallbackDelay is the delay before falling back to IPv4
+	// when connecting to dual-stack hosts (Happy Eyeballs).
+	// Zero means the default of 300ms, negative disables the fallback.
+	FallbackDelay time.Duration
+}
+
+// DefaultDialerOptions constructs default DialerOptions
+func DefaultDialerOptions() DialerOptions {
+	return DialerOptions{
+		Timeout: 30 * time.Second,
+	}
+}
+
+// NewDialContext constructs the dial function for http.Transport
+// that applies the DialerOptions.
+//
+// DNS-over-HTTPS requests are made through the proxy from the environment,
+// see http.ProxyFromEnvironment. Use NewHTTPClient to apply DialerOptions.ProxyURL.
+func NewDialContext(options DialerOptions) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return newDialContext(options, http.ProxyFromEnvironment)
+}
+
+// newDialContext constructs the dial function, DoH requests are made through the proxy
+func newDialContext(
+	options DialerOptions,
+	proxy func(*http.Request) (*url.URL, error),
+) func(ctx context.Context, network, address string) (net.Conn, error) {
+	dialer := &net.Dialer{
+		Timeout:       options.Timeout,
+		FallbackDelay: options.FallbackDelay,
+		KeepAlive:     30 * time.Second,
+	}
+
+	if options.DNSServer != "" {
+		dialer.Resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, options.DNSServer)
+			},
+		}
+	}
+
+	// dial connects without DoH, the DoH endpoint itself is dialed with it
+	dial := func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		if ip, ok := options.HostOverrides[host]; ok {
+			return dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		}
+
+		return dialer.DialContext(ctx, network, address)
+	}
+
+	if options.DoHURL == "" {
+		return dial
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dial
+	transport.Proxy = proxy
+
+	doh := newDoHResolver(options.DoHURL, &http.Client{
+		Transport: transport,
+		Timeout:   options.Timeout,
+	})
+
+	fallbackDelay := options.FallbackDelay
+	if fallbackDelay == 0 {
+		fallbackDelay = defaultFallbackDelay
+	}
+
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := options.HostOverrides[host]; ok || net.ParseIP(host) != nil {
+			return dial(ctx, network, address)
+		}
+
+		ips, err := doh.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		primaries, fallbacks := partitionIPs(network, ips)
+		if len(primaries) == 0 {
+			return nil, &net.DNSError{Err: "no suitable address found", Name: host}
+		}
+
+		return dialParallel(ctx, dialer.DialContext, network, port, primaries, fallbacks, fallbackDelay)
+	}
+}
+
+// defaultFallbackDelay is the default DialerOptions.FallbackDelay
+const defaultFallbackDelay = 300 * time.Millisecond
+
+// partitionIPs filters the IPs suitable for the network and splits them,
+// like net.Dialer does, into the ones of the first IP family and the rest
+func partitionIPs(network string, ips []string) (primaries, fallbacks []string) {
+	var primaryIsIPv4 bool
+	for _, ip := range ips {
+		isIPv4 := net.ParseIP(ip).To4() != nil
+		if network == "tcp4" && !isIPv4 || network == "tcp6" && isIPv4 {
+			continue
+		}
+
+		if len(primaries) == 0 {
+			primaryIsIPv4 = isIPv4
+		}
+
+		if isIPv4 == primaryIsIPv4 {
+			primaries = append(primaries, ip)
+		} else {
+			fallbacks = append(fallbacks, ip)
+		}
+	}
+
+	return primaries, fallbacks
+}
+
+// dialParallel races dialing the primaries and the fallbacks (Happy Eyeballs),
+// the way net.Dialer does for the hosts it resolves itself.
+//
+// The fallbacks are dialed after the delay or as soon as the primaries fail.
+// Negative delay dials them only after the primaries fail.
+func dialParallel(
+	ctx context.Context,
+	dial func(ctx context.Context, network, address string) (net.Conn, error),
+	network, port string,
+	primaries, fallbacks []string,
+	delay time.Duration,
+) (net.Conn, error) {
+	dialSerial := func(ctx context.Context, ips []string) (net.Conn, error) {
+		var errs []error
+		for _, ip := range ips {
+			conn, err := dial(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, nil
+			}
+
+			errs = append(errs, err)
+
+			if ctx.Err() != nil {
+				break
+			}
+		}
+
+		return nil, errors.Join(errs...)
+	}
+
+	if len(fallbacks) == 0 {
+		return dialSerial(ctx, primaries)
+	}
+
+	if delay < 0 {
+		return dialSerial(ctx, append(primaries, fallbacks...))
+	}
+
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make(chan result)
+	race := func(ips []string) {
+		conn, err := dialSerial(ctx, ips)
+
+		select {
+		case results <- result{conn: conn, err: err}:
+		case <-ctx.Done():
+			// the other one won
+			if conn != nil {
+				_ = conn.Close()
+			}
+		}
+	}
+
+	go race(primaries)
+
+	fallbackTimer := time.NewTimer(delay)
+	defer fallbackTimer.Stop()
+
+	var (
+		errs            []error
+		pending         = 1
+		fallbackStarted bool
+	)
+
+	startFallback := func() {
+		fallbackStarted = true
+		pending++
+		go race(fallbacks)
+	}
+
+	for {
+		select {
+		case <-fallbackTimer.C:
+			if !fallbackStarted {
+				startFallback()
+			}
+		case res := <-results:
+			if res.err == nil {
+				return res.conn, nil
+			}
+
+			errs = append(errs, res.err)
+			pending--
+
+			// the primaries failed, don't wait for the delay
+			if !fallbackStarted {
+				startFallback()
+				continue
+			}
+
+			if pending == 0 {
+				return nil, errors.Join(errs...)
+			}
+		}
+	}
+}
+
+// NewHTTPClient constructs http client which connections are made
+// according to the DialerOptions.
+func NewHTTPClient(options DialerOptions) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if options.ProxyURL != "" {
+		proxyURL, err := url.Parse(options.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %w", err)
+		}
+
+		switch proxyURL.Scheme {
+		case "socks5", "http", "https":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme: %q", proxyURL.Scheme)
+		}
+
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	transport.DialContext = newDialContext(options, transport.Proxy)
+
+	return &http.Client{Transport: transport}, nil
+}
+
+// dohResolver resolves hosts with the DNS-over-HTTPS JSON API.
+// Answers are cached for their TTL.
+type dohResolver struct {
+	url    string
+	client *http.Client
+
+	mu    sync.Mutex
+	cache map[string]dohCacheEntry
+}
+
+type dohCacheEntry struct {
+	ips     []string
+	expires time.Time
+}
+
+func newDoHResolver(url string, client *http.Client) *dohResolver {
+	return &dohResolver{
+		url:    url,
+		client: client,
+		cache:  make(map[string]dohCacheEntry),
+	}
+}
+
+type dohResponse struct {
+	Status int         `json:"Status"`
+	Answer []dohAnswer `json:"Answer"`
+}
+
+type dohAnswer struct {
+	Type int    `json:"type"`
+	TTL  int    `json:"TTL"`
+	Data string `json:"data"`
+}
+
+const (
+	dnsTypeA    = 1
+	dnsTypeAAAA = 28
+)
+
+// lookup returns IPv4 addresses of the host followed by IPv6 ones
+func (d *dohResolver) lookup(ctx context.Context, host string) ([]string, error) {
+	d.mu.Lock()
+	entry, ok := d.cache[host]
+	d.mu.Unlock()
+
+	if ok && time.Now().Before(entry.expires) {
+		return entry.ips, nil
+	}
+
+	var (
+		ips []string
+		ttl = -1
+	)
+
+	for _, recordType := range []int{dnsTypeA, dnsTypeAAAA} {
+		found, foundTTL, err := d.query(ctx, host, recordType)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(found) > 0 && (ttl < 0 || foundTTL < ttl) {
+			ttl = foundTTL
+		}
+
+		ips = append(ips, found...)
+	}
+
+	if len(ips) == 0 {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	d.mu.Lock()
+	if ttl > 0 {
+		d.cache[host] = dohCacheEntry{
+			ips:     ips,
+			expires: time.Now().Add(time.Duration(ttl) * time.Second),
+		}
+	} else {
+		delete(d.cache, host)
+	}
+	d.mu.Unlock()
+
+	return ips, nil
+}
+
+// query returns the addresses of the record type and the smallest TTL of them in seconds
+func (d *dohResolver) query(ctx context.Context, host string, recordType int) ([]string, int, error) {
+	query := url.Values{}
+	query.Set("name", host)
+	query.Set("type", fmt.Sprint(recordType))
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, d.url+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	request.Header.Set("Accept", "application/dns-json")
+
+	response, err := d.client.Do(request)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("doh: unexpected http status: %s", response.Status)
+	}
+
+	var body dohResponse
+	if err := json.NewDecoder(response.Body).Decode(&body); err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		ips []string
+		ttl int
+	)
+
+	for _, answer := range body.Answer {
+		// CNAME records are followed by the server
+		if answer.Type == recordType && net.ParseIP(answer.Data) != nil {
+			if len(ips) == 0 || answer.TTL < ttl {
+				ttl = answer.TTL
+			}
+
+			ips = append(ips, answer.Data)
+		}
+	}
+
+	return ips, ttl, nil
+}
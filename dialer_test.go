@@ -0,0 +1,305 @@
+package libmangal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPartitionIPs(t *testing.T) {
+	ips := []string{"10.0.0.1", "::1", "10.0.0.2", "fe80::1"}
+
+	for _, test := range []struct {
+		network              string
+		primaries, fallbacks []string
+	}{
+		{"tcp", []string{"10.0.0.1", "10.0.0.2"}, []string{"::1", "fe80::1"}},
+		{"tcp4", []string{"10.0.0.1", "10.0.0.2"}, nil},
+		{"tcp6", []string{"::1", "fe80::1"}, nil},
+	} {
+		primaries, fallbacks := partitionIPs(test.network, ips)
+		if !reflect.DeepEqual(primaries, test.primaries) || !reflect.DeepEqual(fallbacks, test.fallbacks) {
+			t.Errorf("%s: got %v %v, want %v %v", test.network, primaries, fallbacks, test.primaries, test.fallbacks)
+		}
+	}
+}
+
+// fakeDial connects to the addresses with pipes.
+// Addresses in hang block until the dial is canceled, the ones in fail fail.
+func fakeDial(hang, fail []string, canceled chan<- string) func(context.Context, string, string) (net.Conn, error) {
+	contains := func(addresses []string, address string) bool {
+		for _, a := range addresses {
+			if net.JoinHostPort(a, "80") == address {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	return func(ctx context.Context, _, address string) (net.Conn, error) {
+		switch {
+		case contains(hang, address):
+			<-ctx.Done()
+			canceled <- address
+			return nil, ctx.Err()
+		case contains(fail, address):
+			return nil, fmt.Errorf("%s: connection refused", address)
+		}
+
+		conn, other := net.Pipe()
+		_ = other.Close()
+		return addressConn{Conn: conn, address: address}, nil
+	}
+}
+
+// addressConn records the address it was dialed to
+type addressConn struct {
+	net.Conn
+	address string
+}
+
+func TestDialParallel(t *testing.T) {
+	t.Run("fallback after the delay", func(t *testing.T) {
+		canceled := make(chan string, 1)
+		dial := fakeDial([]string{"10.0.0.1"}, nil, canceled)
+
+		conn, err := dialParallel(context.Background(), dial, "tcp", "80", []string{"10.0.0.1"}, []string{"::1"}, 10*time.Millisecond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		if address := conn.(addressConn).address; address != "[::1]:80" {
+			t.Errorf("connected to %s, want the fallback", address)
+		}
+
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Error("primary dial was not canceled")
+		}
+	})
+
+	t.Run("fallback as soon as the primaries fail", func(t *testing.T) {
+		dial := fakeDial(nil, []string{"10.0.0.1"}, nil)
+
+		started := time.Now()
+		conn, err := dialParallel(context.Background(), dial, "tcp", "80", []string{"10.0.0.1"}, []string{"::1"}, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		if time.Since(started) > time.Second {
+			t.Error("fallback waited for the delay")
+		}
+	})
+
+	t.Run("primary wins", func(t *testing.T) {
+		dial := fakeDial(nil, nil, nil)
+
+		conn, err := dialParallel(context.Background(), dial, "tcp", "80", []string{"10.0.0.1"}, []string{"::1"}, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		if address := conn.(addressConn).address; address != "10.0.0.1:80" {
+			t.Errorf("connected to %s, want the primary", address)
+		}
+	})
+
+	t.Run("all fail", func(t *testing.T) {
+		dial := fakeDial(nil, []string{"10.0.0.1", "10.0.0.2", "::1"}, nil)
+
+		_, err := dialParallel(context.Background(), dial, "tcp", "80", []string{"10.0.0.1", "10.0.0.2"}, []string{"::1"}, time.Millisecond)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		for _, address := range []string{"10.0.0.1:80", "10.0.0.2:80", "[::1]:80"} {
+			if !strings.Contains(err.Error(), address) {
+				t.Errorf("error %q doesn't mention %s", err, address)
+			}
+		}
+	})
+
+	t.Run("serial without the delay", func(t *testing.T) {
+		dial := fakeDial(nil, []string{"10.0.0.1"}, nil)
+
+		conn, err := dialParallel(context.Background(), dial, "tcp", "80", []string{"10.0.0.1"}, []string{"::1"}, -1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		if address := conn.(addressConn).address; address != "[::1]:80" {
+			t.Errorf("connected to %s, want the fallback", address)
+		}
+	})
+}
+
+// fakeDoHServer answers A queries of the hosts with 127.0.0.1
+// and the given TTL, AAAA queries are answered with nothing
+func fakeDoHServer(ttls map[string]int, queries *atomic.Int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries.Add(1)
+
+		var response dohResponse
+
+		name := r.URL.Query().Get("name")
+		if ttl, ok := ttls[name]; ok && r.URL.Query().Get("type") == fmt.Sprint(dnsTypeA) {
+			response.Answer = append(response.Answer, dohAnswer{Type: dnsTypeA, TTL: ttl, Data: "127.0.0.1"})
+		}
+
+		w.Header().Set("Content-Type", "application/dns-json")
+		_ = json.NewEncoder(w).Encode(response)
+	})
+}
+
+func TestDialContextDoH(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer target.Close()
+
+	_, port, err := net.SplitHostPort(strings.TrimPrefix(target.URL, "http://"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var queries atomic.Int64
+	doh := httptest.NewServer(fakeDoHServer(map[string]int{
+		"manga.test":   60,
+		"nocache.test": 0,
+	}, &queries))
+	defer doh.Close()
+
+	options := DefaultDialerOptions()
+	options.DoHURL = doh.URL + "/dns-query"
+
+	transport := &http.Transport{
+		DialContext:       newDialContext(options, nil),
+		DisableKeepAlives: true,
+	}
+	client := &http.Client{Transport: transport}
+
+	get := func(host string) error {
+		response, err := client.Get(fmt.Sprintf("http://%s/", net.JoinHostPort(host, port)))
+		if err != nil {
+			return err
+		}
+
+		return response.Body.Close()
+	}
+
+	// A and AAAA queries once, then the answer is cached for its TTL
+	for i := 0; i < 3; i++ {
+		if err := get("manga.test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := queries.Load(); n != 2 {
+		t.Errorf("made %d queries, want 2", n)
+	}
+
+	queries.Store(0)
+	for i := 0; i < 2; i++ {
+		if err := get("nocache.test"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if n := queries.Load(); n != 4 {
+		t.Errorf("made %d queries for zero TTL, want 4", n)
+	}
+
+	var dnsErr *net.DNSError
+	if err := get("missing.test"); !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+		t.Errorf("got %v, want not found error", err)
+	}
+}
+
+func TestDialContextDoHProxy(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			_ = conn.Close()
+		}
+	}()
+
+	// the DoH endpoint is reachable through the proxy only
+	var queries, proxied atomic.Int64
+	doh := fakeDoHServer(map[string]int{"manga.test": 60}, &queries)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Host != "doh.test" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+
+		proxied.Add(1)
+		doh.ServeHTTP(w, r)
+	}))
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := DefaultDialerOptions()
+	options.DoHURL = "http://doh.test/dns-query"
+
+	dial := newDialContext(options, http.ProxyURL(proxyURL))
+
+	conn, err := dial(context.Background(), "tcp", net.JoinHostPort("manga.test", fmt.Sprint(listener.Addr().(*net.TCPAddr).Port)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = conn.Close()
+
+	if proxied.Load() == 0 {
+		t.Error("DoH requests were not proxied")
+	}
+}
+
+func TestNewHTTPClientProxyURL(t *testing.T) {
+	for _, tc := range []struct {
+		proxyURL string
+		wantErr  bool
+	}{
+		{proxyURL: "socks5://127.0.0.1:9050"},
+		{proxyURL: "http://proxy:8080"},
+		{proxyURL: "ftp://proxy:21", wantErr: true},
+		{proxyURL: "://", wantErr: true},
+	} {
+		options := DefaultDialerOptions()
+		options.ProxyURL = tc.proxyURL
+
+		if _, err := NewHTTPClient(options); (err != nil) != tc.wantErr {
+			t.Errorf("%q: got error %v, want error %v", tc.proxyURL, err, tc.wantErr)
+		}
+	}
+}
@@ -0,0 +1,335 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DownloadPriority is the priority of the DownloadJob.
+// Jobs with the higher priority are started first.
+type DownloadPriority int
+
+const (
+	// PriorityBackground is for automatic downloads, e.g. by the Watcher
+	PriorityBackground DownloadPriority = 0
+
+	// PriorityUser is for downloads requested by the user
+	PriorityUser DownloadPriority = 10
+)
+
+// DownloadJob is the chapter download queued in the DownloadManager
+type DownloadJob struct {
+	// Client to download the chapter with
+	Client *Client
+
+	Chapter  Chapter
+	Options  DownloadOptions
+	Priority DownloadPriority
+}
+
+// series identifies the manga of the job for fair scheduling
+func (d DownloadJob) series() string {
+	manga := d.Chapter.Volume().Manga()
+	return mangaKey(d.Client.Info().ID, manga)
+}
+
+// DownloadManagerOptions configures the DownloadManager
+type DownloadManagerOptions struct {
+	// Concurrency is the number of chapters downloaded at once
+	Concurrency int
+
+	// ProviderConcurrency caps the number of chapters downloaded
+	// at once from the single provider. Zero means no cap.
+	ProviderConcurrency int
+
+	// OnDone is called after each job is finished.
+	// It's called from the worker goroutines.
+	OnDone func(job DownloadJob, result DownloadResult, err error)
+
+	// PauseOnCaptcha pauses the queue when the job fails with
+	// CaptchaRequiredError, so that the remaining jobs don't fail
+	// the same way. The job is queued again and the queue
+	// continues after Resume is called, e.g. once the user has solved it.
+	PauseOnCaptcha bool
+
+	// OnCaptcha is called when the queue is paused by PauseOnCaptcha
+	OnCaptcha func(job DownloadJob, err CaptchaRequiredError)
+}
+
+// DefaultDownloadManagerOptions constructs default DownloadManagerOptions
+func DefaultDownloadManagerOptions() DownloadManagerOptions {
+	return DownloadManagerOptions{
+		Concurrency:         4,
+		ProviderConcurrency: 2,
+		OnDone:              func(DownloadJob, DownloadResult, error) {},
+		PauseOnCaptcha:      false,
+		OnCaptcha:           func(DownloadJob, CaptchaRequiredError) {},
+	}
+}
+
+type queuedDownloadJob struct {
+	DownloadJob
+	series string
+}
+
+// DownloadManager is the queue of chapter downloads.
+//
+// Jobs are started by priority, so that user requests are not
+// starved by the background ones. Jobs of the same priority are
+// started round-robin across series, so that a single long batch
+// doesn't block the others. Per-provider concurrency is capped.
+//
+// It is safe for concurrent use by multiple goroutines.
+type DownloadManager struct {
+	options DownloadManagerOptions
+
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	queue   []queuedDownloadJob
+	running map[string]int
+	closed  bool
+	paused  bool
+
+	// served maps series with the queued jobs
+	// to the turn they were served last
+	served map[string]uint64
+	turn   uint64
+}
+
+// NewDownloadManager constructs new DownloadManager
+func NewDownloadManager(options DownloadManagerOptions) *DownloadManager {
+	manager := &DownloadManager{
+		options: options,
+		running: make(map[string]int),
+		served:  make(map[string]uint64),
+	}
+
+	manager.cond = sync.NewCond(&manager.mu)
+
+	return manager
+}
+
+// Enqueue adds the job to the queue.
+// It returns an error if the manager is closed.
+func (d *DownloadManager) Enqueue(job DownloadJob) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.closed {
+		return errors.New("download manager is closed")
+	}
+
+	d.queue = append(d.queue, queuedDownloadJob{
+		DownloadJob: job,
+		series:      job.series(),
+	})
+
+	d.cond.Signal()
+	return nil
+}
+
+// Pending returns the number of queued jobs that are not started yet
+func (d *DownloadManager) Pending() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return len(d.queue)
+}
+
+// Pause stops starting new jobs. Running ones are not interrupted.
+func (d *DownloadManager) Pause() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.paused = true
+}
+
+// Resume continues starting jobs after Pause or PauseOnCaptcha
+func (d *DownloadManager) Resume() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.paused = false
+	d.cond.Broadcast()
+}
+
+// Paused reports whether the queue is paused
+func (d *DownloadManager) Paused() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.paused
+}
+
+// Close stops accepting new jobs. Run returns after the queued ones are done.
+func (d *DownloadManager) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.closed = true
+	d.cond.Broadcast()
+}
+
+// Run downloads queued jobs until the context is canceled
+// or the manager is closed and its queue is drained.
+// The paused queue is not drained until Resume is called.
+func (d *DownloadManager) Run(ctx context.Context) error {
+	concurrency := d.options.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	// wake up the waiting workers on cancellation
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		select {
+		case <-ctx.Done():
+			d.mu.Lock()
+			d.cond.Broadcast()
+			d.mu.Unlock()
+		case <-done:
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			d.work(ctx)
+		}()
+	}
+
+	wg.Wait()
+	return ctx.Err()
+}
+
+func (d *DownloadManager) work(ctx context.Context) {
+	for {
+		d.mu.Lock()
+
+		var (
+			job queuedDownloadJob
+			ok  bool
+		)
+
+		for {
+			if ctx.Err() != nil {
+				d.mu.Unlock()
+				return
+			}
+
+			if !d.paused {
+				if job, ok = d.next(); ok {
+					break
+				}
+			}
+
+			if d.closed && len(d.queue) == 0 {
+				d.mu.Unlock()
+				return
+			}
+
+			d.cond.Wait()
+		}
+
+		provider := job.Client.Info().ID
+		d.running[provider]++
+		d.mu.Unlock()
+
+		result, err := job.Client.DownloadChapter(ctx, job.Chapter, job.Options)
+		if err != nil {
+			err = fmt.Errorf("%s: %w", job.Chapter, err)
+		}
+
+		var captcha CaptchaRequiredError
+		if d.options.PauseOnCaptcha && errors.As(err, &captcha) && ctx.Err() == nil {
+			d.mu.Lock()
+			d.running[provider]--
+			d.paused = true
+			// the job is retried once resumed
+			d.queue = append([]queuedDownloadJob{job}, d.queue...)
+			d.mu.Unlock()
+
+			if d.options.OnCaptcha != nil {
+				d.options.OnCaptcha(job.DownloadJob, captcha)
+			}
+
+			continue
+		}
+
+		d.mu.Lock()
+		d.running[provider]--
+		d.cond.Broadcast()
+		d.mu.Unlock()
+
+		if d.options.OnDone != nil {
+			d.options.OnDone(job.DownloadJob, result, err)
+		}
+	}
+}
+
+// next removes and returns the job to start next.
+// Must be called with the lock held.
+func (d *DownloadManager) next() (queuedDownloadJob, bool) {
+	best := -1
+	for i, job := range d.queue {
+		if limit := d.options.ProviderConcurrency; limit > 0 && d.running[job.Client.Info().ID] >= limit {
+			continue
+		}
+
+		if best < 0 {
+			best = i
+			continue
+		}
+
+		current := d.queue[best]
+		switch {
+		case job.Priority != current.Priority:
+			if job.Priority > current.Priority {
+				best = i
+			}
+		case job.series != current.series:
+			// the series served longest ago goes first,
+			// jobs of the same series keep their order
+			if d.served[job.series] < d.served[current.series] {
+				best = i
+			}
+		}
+	}
+
+	if best < 0 {
+		return queuedDownloadJob{}, false
+	}
+
+	job := d.queue[best]
+	d.queue = append(d.queue[:best], d.queue[best+1:]...)
+
+	d.turn++
+	d.served[job.series] = d.turn
+
+	// series are forgotten once they have no queued jobs,
+	// so that the map doesn't grow for the manager lifetime
+	if !d.queued(job.series) {
+		delete(d.served, job.series)
+	}
+
+	return job, true
+}
+
+// queued reports whether there are queued jobs of the series.
+// Must be called with the lock held.
+func (d *DownloadManager) queued(series string) bool {
+	for _, job := range d.queue {
+		if job.series == series {
+			return true
+		}
+	}
+
+	return false
+}
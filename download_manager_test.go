@@ -0,0 +1,280 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// providerWithID is the fakeProvider with another ID
+type providerWithID struct {
+	*fakeProvider
+	id string
+}
+
+func (p *providerWithID) Info() ProviderInfo {
+	info := fakeProviderInfo
+	info.ID = p.id
+	return info
+}
+
+func (p *providerWithID) Load(context.Context) (Provider, error) {
+	return p, nil
+}
+
+// seriesChapter constructs the chapter of the manga with the given ID
+func seriesChapter(mangaID string, number int) Chapter {
+	return fakeChapter{
+		info: ChapterInfo{
+			Title:  fmt.Sprintf("%s %d", mangaID, number),
+			Number: float32(number),
+		},
+		volume: fakeVolume{
+			number: 1,
+			manga:  fakeManga{info: MangaInfo{Title: mangaID, ID: mangaID}},
+		},
+	}
+}
+
+func TestDownloadManagerOrder(t *testing.T) {
+	client := newTestClient(t, newFakeProvider(t, 0, 1))
+
+	var order []string
+	options := DefaultDownloadManagerOptions()
+	options.Concurrency = 1
+	options.OnDone = func(job DownloadJob, _ DownloadResult, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+
+		order = append(order, job.Chapter.String())
+	}
+
+	manager := NewDownloadManager(options)
+
+	for _, job := range []struct {
+		manga    string
+		number   int
+		priority DownloadPriority
+	}{
+		{"A", 1, PriorityBackground},
+		{"A", 2, PriorityBackground},
+		{"A", 3, PriorityBackground},
+		{"B", 1, PriorityBackground},
+		{"B", 2, PriorityBackground},
+		{"C", 1, PriorityUser},
+	} {
+		err := manager.Enqueue(DownloadJob{
+			Client:   client,
+			Chapter:  seriesChapter(job.manga, job.number),
+			Options:  testDownloadOptions(),
+			Priority: job.priority,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager.Close()
+	if err := manager.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// user job first, then series take turns keeping their order
+	want := []string{"C 1", "A 1", "B 1", "A 2", "B 2", "A 3"}
+	if fmt.Sprint(order) != fmt.Sprint(want) {
+		t.Errorf("got order %v, want %v", order, want)
+	}
+
+	if len(manager.served) != 0 {
+		t.Errorf("served series are kept: %v", manager.served)
+	}
+
+	if err := manager.Enqueue(DownloadJob{Client: client, Chapter: seriesChapter("A", 4)}); err == nil {
+		t.Error("job was enqueued to the closed manager")
+	}
+}
+
+// concurrencyProvider tracks the number of concurrent page downloads
+type concurrencyProvider struct {
+	*providerWithID
+
+	running, max atomic.Int64
+}
+
+func (c *concurrencyProvider) Load(context.Context) (Provider, error) {
+	return c, nil
+}
+
+func (c *concurrencyProvider) GetPageImage(ctx context.Context, log LogFunc, page Page) ([]byte, error) {
+	running := c.running.Add(1)
+	defer c.running.Add(-1)
+
+	for {
+		max := c.max.Load()
+		if running <= max || c.max.CompareAndSwap(max, running) {
+			break
+		}
+	}
+
+	// give the other workers the chance to overlap
+	time.Sleep(time.Millisecond)
+
+	return c.fakeProvider.GetPageImage(ctx, log, page)
+}
+
+func TestDownloadManagerProviderConcurrency(t *testing.T) {
+	const limit = 2
+
+	options := DefaultDownloadManagerOptions()
+	options.Concurrency = 8
+	options.ProviderConcurrency = limit
+
+	var mu sync.Mutex
+	options.OnDone = func(_ DownloadJob, _ DownloadResult, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
+	manager := NewDownloadManager(options)
+
+	var providers []*concurrencyProvider
+	for _, id := range []string{"first", "second"} {
+		provider := &concurrencyProvider{
+			providerWithID: &providerWithID{fakeProvider: newFakeProvider(t, 0, 1), id: id},
+		}
+		providers = append(providers, provider)
+
+		client, err := NewClient(context.Background(), provider, testClientOptions())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for i := 1; i <= 8; i++ {
+			err := manager.Enqueue(DownloadJob{
+				Client:  client,
+				Chapter: seriesChapter(fmt.Sprint(id, i), i),
+				Options: testDownloadOptions(),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	manager.Close()
+	if err := manager.Run(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, provider := range providers {
+		if max := provider.max.Load(); max > limit {
+			t.Errorf("%s: got %d concurrent downloads, want at most %d", provider.id, max, limit)
+		}
+	}
+}
+
+func TestDownloadManagerNextSkipsCappedProviders(t *testing.T) {
+	options := DefaultDownloadManagerOptions()
+	options.ProviderConcurrency = 1
+	manager := NewDownloadManager(options)
+
+	capped := newTestClient(t, newFakeProvider(t, 0, 1))
+
+	other, err := NewClient(context.Background(), &providerWithID{
+		fakeProvider: newFakeProvider(t, 0, 1),
+		id:           "other",
+	}, testClientOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, job := range []DownloadJob{
+		{Client: capped, Chapter: seriesChapter("A", 1), Priority: PriorityUser},
+		{Client: other, Chapter: seriesChapter("B", 1)},
+	} {
+		if err := manager.Enqueue(job); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager.running[capped.Info().ID] = 1
+
+	job, ok := manager.next()
+	if !ok || job.Client != other {
+		t.Fatalf("got the job of the capped provider")
+	}
+
+	if _, ok := manager.next(); ok {
+		t.Error("capped provider job was started")
+	}
+
+	manager.running[capped.Info().ID] = 0
+	if job, ok := manager.next(); !ok || job.Client != capped {
+		t.Error("job was not started after the provider was released")
+	}
+}
+
+func TestDownloadManagerPauseOnCaptcha(t *testing.T) {
+	provider := newFakeProvider(t, 0, 1)
+	provider.pageErr = CaptchaRequiredError{URL: "https://example.com"}
+	provider.pageErrIndex = 1
+
+	client := newTestClient(t, provider)
+
+	captcha := make(chan struct{})
+	done := make(chan error, 1)
+
+	options := DefaultDownloadManagerOptions()
+	options.PauseOnCaptcha = true
+	options.OnCaptcha = func(DownloadJob, CaptchaRequiredError) {
+		close(captcha)
+	}
+	options.OnDone = func(_ DownloadJob, _ DownloadResult, err error) {
+		done <- err
+	}
+
+	manager := NewDownloadManager(options)
+	if err := manager.Enqueue(DownloadJob{
+		Client:  client,
+		Chapter: seriesChapter("A", 1),
+		Options: testDownloadOptions(),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	manager.Close()
+
+	run := make(chan error)
+	go func() { run <- manager.Run(context.Background()) }()
+
+	<-captcha
+
+	if !manager.Paused() {
+		t.Error("queue is not paused")
+	}
+
+	if pending := manager.Pending(); pending != 1 {
+		t.Errorf("got %d pending jobs, want the failed job queued again", pending)
+	}
+
+	// captcha is solved
+	provider.pageErr = nil
+
+	manager.Resume()
+
+	if err := <-done; err != nil {
+		t.Errorf("resumed job failed: %s", err)
+	}
+
+	if err := <-run; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,126 @@
+package libmangal
+
+import (
+	"fmt"
+)
+
+// DownloadProfile is a set of DownloadOptions overrides
+// attached to a manga, e.g. to download this series as CBZ
+// and that one as PDF during library updates.
+//
+// Unlike DownloadOptions it's serializable, so it can be
+// saved to the ClientOptions.ProfileStore.
+// Nil fields are not overridden.
+type DownloadProfile struct {
+	Format            *Format      `json:"format,omitempty"`
+	Directory         *string      `json:"directory,omitempty"`
+	CreateMangaDir    *bool        `json:"createMangaDir,omitempty"`
+	CreateVolumeDir   *bool        `json:"createVolumeDir,omitempty"`
+	WriteSeriesJson   *bool        `json:"writeSeriesJson,omitempty"`
+	WriteComicInfoXml *bool        `json:"writeComicInfoXml,omitempty"`
+	EmbedCover        *bool        `json:"embedCover,omitempty"`
+	ImagePolicy       *ImagePolicy `json:"imagePolicy,omitempty"`
+	MaxFileSize       *int64       `json:"maxFileSize,omitempty"`
+}
+
+// Apply returns a copy of the options with the profile overrides.
+// ImagePolicy is applied to the resulting format.
+func (p DownloadProfile) Apply(options DownloadOptions) DownloadOptions {
+	if p.Format != nil {
+		options.Format = *p.Format
+	}
+
+	if p.Directory != nil {
+		options.Directory = *p.Directory
+	}
+
+	if p.CreateMangaDir != nil {
+		options.CreateMangaDir = *p.CreateMangaDir
+	}
+
+	if p.CreateVolumeDir != nil {
+		options.CreateVolumeDir = *p.CreateVolumeDir
+	}
+
+	if p.WriteSeriesJson != nil {
+		options.WriteSeriesJson = *p.WriteSeriesJson
+	}
+
+	if p.WriteComicInfoXml != nil {
+		options.WriteComicInfoXml = *p.WriteComicInfoXml
+	}
+
+	if p.EmbedCover != nil {
+		options.EmbedCover = *p.EmbedCover
+	}
+
+	if p.MaxFileSize != nil {
+		options.MaxFileSize = *p.MaxFileSize
+	}
+
+	if p.ImagePolicy != nil {
+		// don't modify the original map
+		policies := make(map[Format]ImagePolicy, len(options.ImagePolicies)+1)
+		for format, policy := range options.ImagePolicies {
+			policies[format] = policy
+		}
+
+		policies[options.Format] = *p.ImagePolicy
+		options.ImagePolicies = policies
+	}
+
+	return options
+}
+
+func mangaKey(provider string, manga Manga) string {
+	return fmt.Sprintf("%s/%s", provider, manga.Info().ID)
+}
+
+// SetDownloadProfile attaches the profile to the manga.
+// It returns an error if ClientOptions.ProfileStore is nil.
+func (c *Client) SetDownloadProfile(manga Manga, profile DownloadProfile) error {
+	store := c.options.ProfileStore
+	if store == nil {
+		return fmt.Errorf("profile store is not configured")
+	}
+
+	return store.Set(mangaKey(c.Info().ID, manga), profile)
+}
+
+// DownloadProfile returns the profile attached to the manga
+func (c *Client) DownloadProfile(manga Manga) (DownloadProfile, bool, error) {
+	store := c.options.ProfileStore
+	if store == nil {
+		return DownloadProfile{}, false, nil
+	}
+
+	var profile DownloadProfile
+	found, err := store.Get(mangaKey(c.Info().ID, manga), &profile)
+	return profile, found, err
+}
+
+// RemoveDownloadProfile detaches the profile from the manga
+func (c *Client) RemoveDownloadProfile(manga Manga) error {
+	store := c.options.ProfileStore
+	if store == nil {
+		return nil
+	}
+
+	return store.Delete(mangaKey(c.Info().ID, manga))
+}
+
+// MangaDownloadOptions returns options to download chapters
+// of the manga with, that is, the base options with the
+// manga's DownloadProfile applied if there is one.
+func (c *Client) MangaDownloadOptions(manga Manga, base DownloadOptions) (DownloadOptions, error) {
+	profile, found, err := c.DownloadProfile(manga)
+	if err != nil {
+		return DownloadOptions{}, err
+	}
+
+	if !found {
+		return base, nil
+	}
+
+	return profile.Apply(base), nil
+}
@@ -0,0 +1,92 @@
+package libmangal
+
+import "time"
+
+// DownloadResult describes the outcome of the chapter download
+type DownloadResult struct {
+	// Path of the chapter joined with DownloadOptions.Directory
+	Path string `json:"path"`
+
+	// Format of the chapter
+	Format Format `json:"format"`
+
+	// Parts are the paths of the chapter parts if it was split
+	// because of DownloadOptions.MaxFileSize. Path doesn't exist in that case.
+	Parts []string `json:"parts"`
+
+	// Skipped is true if the chapter was not downloaded because
+	// it already existed and DownloadOptions.SkipIfExists was set
+	Skipped bool `json:"skipped"`
+
+	// Updated is true if only the pages appended to the existing
+	// chapter were downloaded. See DownloadOptions.RedownloadIfChanged
+	Updated bool `json:"updated"`
+
+	// PageCount is the number of the chapter pages
+	PageCount int `json:"pageCount"`
+
+	// Fingerprint of the downloaded chapter. Empty if Skipped
+	Fingerprint ChapterFingerprint `json:"fingerprint"`
+
+	// BytesWritten is the total size of the written files
+	// including metadata
+	BytesWritten int64 `json:"bytesWritten"`
+
+	// Duration of the whole download
+	Duration time.Duration `json:"duration"`
+
+	// SeriesJSONWritten is true if series.json was written
+	SeriesJSONWritten bool `json:"seriesJsonWritten"`
+
+	// VolumeJSONWritten is true if volume.json was written
+	VolumeJSONWritten bool `json:"volumeJsonWritten"`
+
+	// ChaptersJSONWritten is true if chapters.json was written
+	ChaptersJSONWritten bool `json:"chaptersJsonWritten"`
+
+	// CoverWritten is true if the manga cover was written
+	CoverWritten bool `json:"coverWritten"`
+
+	// VolumeCoverWritten is true if the volume cover was written
+	VolumeCoverWritten bool `json:"volumeCoverWritten"`
+
+	// BannerWritten is true if the manga banner was written
+	BannerWritten bool `json:"bannerWritten"`
+
+	// ComicInfoXMLWritten is true if ComicInfo.xml was
+	// written with the actual metadata
+	ComicInfoXMLWritten bool `json:"comicInfoXmlWritten"`
+
+	// LongStrip is true if the chapter was treated as a long strip.
+	// See DownloadOptions.LongStrip
+	LongStrip bool `json:"longStrip"`
+
+	// SpreadsSplit is the number of double-page spreads split in two.
+	// See DownloadOptions.Spreads
+	SpreadsSplit int `json:"spreadsSplit"`
+
+	// TextWritten is true if the ChapterText sidecar was written.
+	// See DownloadOptions.TextExtractor
+	TextWritten bool `json:"textWritten"`
+
+	// DryRun is true if nothing was actually downloaded.
+	// See DownloadOptions.DryRun
+	DryRun bool `json:"dryRun"`
+
+	// EstimatedBytes is the estimated total size of the page images.
+	// Sizes of the images that can't be known without downloading them
+	// are averaged from the previous downloads, see Client.UsageStats.
+	// Only set for DryRun.
+	EstimatedBytes int64 `json:"estimatedBytes"`
+
+	// PlannedFiles are the files that would be written. Only set for DryRun.
+	PlannedFiles []string `json:"plannedFiles"`
+
+	// Warnings are the non-fatal errors that occurred
+	// during the download, e.g. failed metadata when DownloadOptions.Strict is false
+	Warnings []string `json:"warnings"`
+}
+
+func (d *DownloadResult) warn(err error) {
+	d.Warnings = append(d.Warnings, err.Error())
+}
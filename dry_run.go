@@ -0,0 +1,110 @@
+package libmangal
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"strings"
+)
+
+// planChapterDownload is the DownloadOptions.DryRun version of DownloadChapter.
+// It resolves pages and computes paths without downloading images or writing anything.
+func (c *Client) planChapterDownload(
+	ctx context.Context,
+	chapter Chapter,
+	options DownloadOptions,
+) (DownloadResult, error) {
+	chapterPath, mangaDir := c.chapterPath(chapter, options)
+
+	result := DownloadResult{
+		Path:   chapterPath,
+		Format: options.Format,
+		DryRun: true,
+	}
+
+	state, err := c.chapterState(ctx, chapter, chapterPath, options, c.options.FS)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+
+	if state.excluded {
+		return DownloadResult{}, ChapterExcludedError{Chapter: chapter}
+	}
+
+	if !state.shouldDownload(options) {
+		result.Skipped = true
+
+		if state.indexedPath != "" {
+			result.Path = state.indexedPath
+		}
+	} else {
+		pages, err := c.ChapterPages(ctx, chapter)
+		if err != nil {
+			return DownloadResult{}, err
+		}
+
+		averageSize, err := c.averageImageSize(chapter.Volume().Manga())
+		if err != nil {
+			return DownloadResult{}, err
+		}
+
+		result.PageCount = len(pages)
+		for _, page := range pages {
+			size, ok := c.knownPageSize(page)
+			if !ok {
+				size = averageSize
+			}
+
+			result.EstimatedBytes += size
+		}
+
+		result.PlannedFiles = append(result.PlannedFiles, chapterPath)
+	}
+
+	for _, metadata := range []struct {
+		enabled bool
+		path    string
+	}{
+		{options.WriteSeriesJson, filepath.Join(mangaDir, filenameSeriesJSON)},
+		{options.WriteChaptersJson, filepath.Join(mangaDir, filenameChaptersJSON)},
+		{options.DownloadMangaCover, filepath.Join(mangaDir, filenameCoverJPG)},
+		{options.WriteVolumeJson && options.CreateVolumeDir, filepath.Join(filepath.Dir(chapterPath), filenameVolumeJSON)},
+		{options.DownloadVolumeCover && options.CreateVolumeDir, filepath.Join(filepath.Dir(chapterPath), filenameCoverJPG)},
+		{options.DownloadMangaBanner, filepath.Join(mangaDir, filenameBannerJPG)},
+	} {
+		if !metadata.enabled {
+			continue
+		}
+
+		path := metadata.path
+		exists, err := afero.Exists(c.options.FS, path)
+		if err != nil {
+			return DownloadResult{}, err
+		}
+
+		if !exists {
+			result.PlannedFiles = append(result.PlannedFiles, path)
+		}
+	}
+
+	return result, nil
+}
+
+// knownPageSize returns the size of the page image
+// if it's known without downloading it, e.g. it's in the page cache
+func (c *Client) knownPageSize(page Page) (int64, bool) {
+	switch page := page.(type) {
+	case PageWithImage:
+		return int64(len(page.GetImage())), true
+	case PageWithDataURI:
+		_, payload, _ := strings.Cut(page.DataURI(), ",")
+		return int64(base64.StdEncoding.DecodedLen(len(payload))), true
+	}
+
+	if cached, ok := c.cachedPage(page); ok {
+		return int64(len(cached.GetImage())), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,163 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"github.com/philippgille/gokv/syncmap"
+	"github.com/spf13/afero"
+	"testing"
+)
+
+func TestPlanChapterDownload(t *testing.T) {
+	provider := newFakeProvider(t, 1, 3)
+	chapter := provider.chapterList()[0]
+
+	tests := []struct {
+		name    string
+		prepare func(t *testing.T, client *Client, options *DownloadOptions, path string)
+		skipped bool
+		path    string
+	}{
+		{
+			name:    "new",
+			prepare: func(*testing.T, *Client, *DownloadOptions, string) {},
+		},
+		{
+			name: "exists",
+			prepare: func(t *testing.T, client *Client, _ *DownloadOptions, path string) {
+				if err := afero.WriteFile(client.FS(), path, nil, modeFile); err != nil {
+					t.Fatal(err)
+				}
+			},
+			skipped: true,
+		},
+		{
+			name: "split",
+			prepare: func(t *testing.T, client *Client, options *DownloadOptions, path string) {
+				options.MaxFileSize = 1 << 20
+				if err := afero.WriteFile(client.FS(), chapterPartPath(path, 1, options.Format), nil, modeFile); err != nil {
+					t.Fatal(err)
+				}
+			},
+			skipped: true,
+		},
+		{
+			name: "indexed",
+			prepare: func(t *testing.T, client *Client, _ *DownloadOptions, _ string) {
+				client.options.ChapterIndex = NewChapterIndex(syncmap.NewStore(syncmap.DefaultOptions))
+				if err := client.options.ChapterIndex.Set(client.Info().ID, chapter, "/moved.cbz"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			skipped: true,
+			path:    "/moved.cbz",
+		},
+	}
+
+	for _, test := range tests {
+		client := newTestClient(t, provider)
+		options := testDownloadOptions()
+		options.DryRun = true
+
+		path, err := client.ChapterPath(chapter, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		test.prepare(t, client, &options, path)
+
+		result, err := client.DownloadChapter(context.Background(), chapter, options)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		if result.Skipped != test.skipped {
+			t.Errorf("%s: got skipped %v, want %v", test.name, result.Skipped, test.skipped)
+		}
+
+		if test.path != "" && result.Path != test.path {
+			t.Errorf("%s: got path %q, want %q", test.name, result.Path, test.path)
+		}
+	}
+}
+
+func TestPlanChapterDownloadExcluded(t *testing.T) {
+	provider := newFakeProvider(t, 1, 1)
+	chapter := provider.chapterList()[0]
+
+	client := newTestClient(t, provider)
+	journal := NewFailureJournal(syncmap.NewStore(syncmap.DefaultOptions))
+	client.options.FailureJournal = journal
+
+	if err := journal.Record(client.Info().ID, chapter, errors.New("failed")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := journal.SetExcluded(chapterKey(client.Info().ID, chapter), true); err != nil {
+		t.Fatal(err)
+	}
+
+	options := testDownloadOptions()
+	options.DryRun = true
+
+	if _, err := client.DownloadChapter(context.Background(), chapter, options); !errors.As(err, &ChapterExcludedError{}) {
+		t.Errorf("got %v, want ChapterExcludedError", err)
+	}
+}
+
+func TestPlanChapterDownloadEstimatedBytes(t *testing.T) {
+	const pages = 3
+
+	provider := newFakeProvider(t, 2, pages)
+	chapters := provider.chapterList()
+	client := newTestClient(t, provider)
+
+	options := testDownloadOptions()
+	if _, err := client.DownloadChapter(context.Background(), chapters[0], options); err != nil {
+		t.Fatal(err)
+	}
+
+	options.DryRun = true
+	result, err := client.DownloadChapter(context.Background(), chapters[1], options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := int64(pages * len(provider.image)); result.EstimatedBytes != want {
+		t.Errorf("got %d estimated bytes, want %d", result.EstimatedBytes, want)
+	}
+}
+
+func TestPlanChapterDownloadPrefetched(t *testing.T) {
+	const pages = 3
+
+	provider := newFakeProvider(t, 1, pages)
+	chapter := provider.chapterList()[0]
+
+	clientOptions := testClientOptions()
+	clientOptions.PageCache = syncmap.NewStore(syncmap.DefaultOptions)
+	clientOptions.UsageStore = nil
+
+	client, err := NewClient(context.Background(), provider, clientOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.PrefetchChapter(context.Background(), chapter, 1).Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	options := testDownloadOptions()
+	options.DryRun = true
+
+	result, err := client.DownloadChapter(context.Background(), chapter, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// sizes of the other pages are not known without the usage
+	if want := int64(len(provider.image)); result.EstimatedBytes != want {
+		t.Errorf("got %d estimated bytes, want %d", result.EstimatedBytes, want)
+	}
+}
@@ -1,6 +1,10 @@
 package libmangal
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 type (
 	MetadataError struct {
@@ -10,8 +14,143 @@ type (
 	AnilistError struct {
 		error
 	}
+
+	// SizeLimitError is returned when a response or
+	// provider-returned data exceeds the configured size limit
+	SizeLimitError struct {
+		// Limit is the maximum allowed size in bytes
+		Limit int64
+	}
+
+	// ImageDecodeError is returned when page image can't be decoded,
+	// e.g. its format is not supported or the image is corrupted
+	ImageDecodeError struct {
+		error
+
+		// Page which image failed to decode
+		Page Page
+
+		// ContentType is the sniffed content type of the image data
+		ContentType string
+	}
+
+	// PageCorruptError is returned when the downloaded page data is not
+	// a valid image, e.g. the source served an HTML error page instead.
+	// See ClientOptions.PageValidation
+	PageCorruptError struct {
+		error
+
+		// Page which image is corrupt
+		Page Page
+
+		// ContentType is the sniffed content type of the data
+		ContentType string
+	}
+
+	// ChapterExcludedError is returned when downloading
+	// a chapter that was excluded in the FailureJournal
+	ChapterExcludedError struct {
+		Chapter Chapter
+	}
+
+	// CircuitOpenError is returned by CircuitBreakerTransport
+	// for requests to the host that failed too many times in a row
+	CircuitOpenError struct {
+		Host string
+
+		// RetryAt is the time the host will be tried again
+		RetryAt time.Time
+	}
+
+	// ProviderSignatureError is returned by the loader from NewVerifiedLoader
+	// for providers that are not signed by the trusted publisher
+	ProviderSignatureError struct {
+		// Provider is the ID of the provider
+		Provider string
+
+		// Reason why the provider is not trusted
+		Reason string
+	}
+
+	// HTTPStatusError is returned for unexpected HTTP response statuses.
+	// Providers may return it to have 5xx and 429 responses retried,
+	// see RetryPolicy
+	HTTPStatusError struct {
+		StatusCode int
+		Status     string
+
+		// RetryAfter is the delay requested by the Retry-After header
+		RetryAfter time.Duration
+	}
+
+	// CaptchaRequiredError is returned when the source responds with
+	// a CAPTCHA interstitial instead of the content.
+	// It has to be solved manually, e.g. by opening URL in the browser
+	// with the same IP. See ClientOptions.CaptchaSignatures
+	CaptchaRequiredError struct {
+		// URL to open to solve the CAPTCHA
+		URL string
+
+		// Signature that matched the response
+		Signature string
+	}
+
+	// ChecksumError is returned by Client.VerifyChapter
+	// for the chapter that doesn't match its ChecksumManifest
+	ChecksumError struct {
+		// Path of the chapter
+		Path string
+
+		// Corrupted are names of the corrupted, missing or unexpected pages,
+		// or the name of the chapter file if it's verified as a whole
+		Corrupted []string
+	}
 )
 
 func (a AnilistError) Error() string {
 	return fmt.Sprintf("anilist error: %s", a.error)
 }
+
+func (s SizeLimitError) Error() string {
+	return fmt.Sprintf("size limit of %d bytes exceeded", s.Limit)
+}
+
+func (c ChapterExcludedError) Error() string {
+	return fmt.Sprintf("chapter %q is excluded from downloading", c.Chapter)
+}
+
+func (i ImageDecodeError) Error() string {
+	return fmt.Sprintf("page %q: can't decode image (%s): %s", i.Page, i.ContentType, i.error)
+}
+
+func (i ImageDecodeError) Unwrap() error {
+	return i.error
+}
+
+func (p PageCorruptError) Error() string {
+	return fmt.Sprintf("page %q: corrupt image (%s): %s", p.Page, p.ContentType, p.error)
+}
+
+func (p PageCorruptError) Unwrap() error {
+	return p.error
+}
+
+func (c CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit open for %s until %s", c.Host, c.RetryAt.Format(time.RFC3339))
+}
+
+func (p ProviderSignatureError) Error() string {
+	return fmt.Sprintf("provider %q is not trusted: %s", p.Provider, p.Reason)
+}
+
+func (h HTTPStatusError) Error() string {
+	return fmt.Sprintf("unexpected http status: %s", h.Status)
+}
+
+func (c CaptchaRequiredError) Error() string {
+	return fmt.Sprintf("captcha required, solve it at %s (matched %q)", c.URL, c.Signature)
+}
+
+func (c ChecksumError) Error() string {
+	return fmt.Sprintf("%s: checksum mismatch: %s", c.Path, strings.Join(c.Corrupted, ", "))
+}
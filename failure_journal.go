@@ -0,0 +1,205 @@
+package libmangal
+
+import (
+	"fmt"
+	"github.com/philippgille/gokv"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Failure is a record of the chapter that failed to download
+type Failure struct {
+	// Key uniquely identifies the failed chapter
+	Key string `json:"key"`
+
+	// Provider is the ID of the provider the chapter belongs to
+	Provider string `json:"provider"`
+
+	// Manga is the title of the chapter's manga
+	Manga string `json:"manga"`
+
+	// Chapter is the title of the chapter
+	Chapter string `json:"chapter"`
+
+	// Number of the chapter
+	Number float32 `json:"number"`
+
+	// URL of the chapter web page
+	URL string `json:"url"`
+
+	// Reason is the error message of the last failure
+	Reason string `json:"reason"`
+
+	// Attempts is the number of failed attempts
+	Attempts int `json:"attempts"`
+
+	// FirstFailedAt is the time of the first failure
+	FirstFailedAt time.Time `json:"firstFailedAt"`
+
+	// LastFailedAt is the time of the last failure
+	LastFailedAt time.Time `json:"lastFailedAt"`
+
+	// Excluded chapters are skipped by the downloader
+	Excluded bool `json:"excluded"`
+}
+
+// FailureJournal keeps track of chapters that failed to download,
+// so that they can be listed, retried or excluded from further downloads.
+// It is safe for concurrent use by multiple goroutines.
+type FailureJournal struct {
+	store gokv.Store
+	index *storeIndex
+	mu    sync.Mutex
+}
+
+// NewFailureJournal constructs new FailureJournal backed by the given store
+func NewFailureJournal(store gokv.Store) *FailureJournal {
+	return &FailureJournal{
+		store: store,
+		index: newStoreIndex(store),
+	}
+}
+
+// chapterKey returns the key that identifies chapter within the provider.
+// Versions of the chapter by different scanlators or in different languages
+// have different keys, e.g. "provider/manga/10.5?lang=en&scanlator=Group".
+// The plain key is used if both are empty.
+func chapterKey(provider string, chapter Chapter) string {
+	info := chapter.Info()
+
+	key := fmt.Sprintf(
+		"%s/%s/%s",
+		provider,
+		chapter.Volume().Manga().Info().ID,
+		strconv.FormatFloat(float64(info.Number), 'f', -1, 32),
+	)
+
+	version := url.Values{}
+	if info.Scanlator != "" {
+		version.Set("scanlator", info.Scanlator)
+	}
+
+	if info.Language != "" {
+		version.Set("lang", info.Language)
+	}
+
+	if len(version) == 0 {
+		return key
+	}
+
+	return key + "?" + version.Encode()
+}
+
+// Record records the failure of the chapter with the given reason.
+// Previous record of the same chapter is updated.
+func (f *FailureJournal) Record(provider string, chapter Chapter, reason error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := chapterKey(provider, chapter)
+
+	var failure Failure
+	found, err := f.store.Get(key, &failure)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	if !found {
+		info := chapter.Info()
+		failure = Failure{
+			Key:           key,
+			Provider:      provider,
+			Manga:         chapter.Volume().Manga().Info().Title,
+			Chapter:       info.Title,
+			Number:        info.Number,
+			URL:           info.URL,
+			FirstFailedAt: now,
+		}
+	}
+
+	failure.Reason = reason.Error()
+	failure.Attempts++
+	failure.LastFailedAt = now
+
+	return f.index.set(key, failure)
+}
+
+// Get returns the failure with the given key
+func (f *FailureJournal) Get(key string) (Failure, bool, error) {
+	var failure Failure
+	found, err := f.store.Get(key, &failure)
+	return failure, found, err
+}
+
+// Failures returns all recorded failures
+// sorted by the time of the last failure, most recent first.
+func (f *FailureJournal) Failures() ([]Failure, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	keys, err := f.index.keys()
+	if err != nil {
+		return nil, err
+	}
+
+	failures := make([]Failure, 0, len(keys))
+	for _, key := range keys {
+		failure, found, err := f.Get(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			failures = append(failures, failure)
+		}
+	}
+
+	sort.SliceStable(failures, func(i, j int) bool {
+		return failures[i].LastFailedAt.After(failures[j].LastFailedAt)
+	})
+
+	return failures, nil
+}
+
+// SetExcluded marks the failed chapter as excluded or not.
+// Excluded chapters are skipped by the downloader.
+func (f *FailureJournal) SetExcluded(key string, excluded bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	failure, found, err := f.Get(key)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("failure %q not found", key)
+	}
+
+	failure.Excluded = excluded
+	return f.index.set(key, failure)
+}
+
+// IsExcluded checks whether the chapter is excluded from downloading
+func (f *FailureJournal) IsExcluded(provider string, chapter Chapter) (bool, error) {
+	failure, found, err := f.Get(chapterKey(provider, chapter))
+	if err != nil {
+		return false, err
+	}
+
+	return found && failure.Excluded, nil
+}
+
+// Remove removes the failure record so the chapter will be retried
+// as if it had never failed. It's no-op if the record doesn't exist.
+func (f *FailureJournal) Remove(key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.index.delete(key)
+}
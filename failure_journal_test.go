@@ -0,0 +1,125 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"github.com/philippgille/gokv/syncmap"
+	"testing"
+)
+
+func TestChapterKey(t *testing.T) {
+	provider := newFakeProvider(t, 1, 0)
+	chapter := provider.chapterList()[0].(fakeChapter)
+
+	if got, want := chapterKey("fake", chapter), "fake/fake-manga/1"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	keys := make(map[string]bool)
+	for _, version := range []struct{ scanlator, language string }{
+		{"", ""},
+		{"Group A", ""},
+		{"Group B", ""},
+		{"Group A", "en"},
+		{"", "en"},
+	} {
+		versioned := chapter
+		versioned.info.Scanlator = version.scanlator
+		versioned.info.Language = version.language
+
+		key := chapterKey("fake", versioned)
+		if keys[key] {
+			t.Errorf("%+v: duplicate key %q", version, key)
+		}
+
+		keys[key] = true
+	}
+}
+
+func TestFailureJournal(t *testing.T) {
+	journal := NewFailureJournal(syncmap.NewStore(syncmap.DefaultOptions))
+	provider := newFakeProvider(t, 2, 0)
+	chapters := provider.chapterList()
+
+	for _, chapter := range chapters {
+		if err := journal.Record(fakeProviderInfo.ID, chapter, errors.New("failed")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := journal.Record(fakeProviderInfo.ID, chapters[0], errors.New("failed again")); err != nil {
+		t.Fatal(err)
+	}
+
+	failures, err := journal.Failures()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(failures) != 2 {
+		t.Fatalf("got %d failures, want 2", len(failures))
+	}
+
+	if failures[0].Attempts != 2 || failures[0].Reason != "failed again" {
+		t.Errorf("most recent failure is %+v", failures[0])
+	}
+
+	if err := journal.Remove(failures[0].Key); err != nil {
+		t.Fatal(err)
+	}
+
+	failures, err = journal.Failures()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(failures) != 1 {
+		t.Errorf("got %d failures after removal, want 1", len(failures))
+	}
+}
+
+func TestDownloadChapterFailureJournal(t *testing.T) {
+	provider := newFakeProvider(t, 1, 2)
+	provider.pageErr = errors.New("page is gone")
+	provider.pageErrIndex = 2
+
+	client := newTestClient(t, provider)
+	chapter := provider.chapterList()[0]
+	journal := client.FailureJournal()
+
+	if _, err := client.DownloadChapter(context.Background(), chapter, testDownloadOptions()); err == nil {
+		t.Fatal("download didn't fail")
+	}
+
+	failure, found, err := journal.Get(chapterKey(fakeProviderInfo.ID, chapter))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !found || failure.Attempts != 1 {
+		t.Fatalf("got failure %+v, found %v", failure, found)
+	}
+
+	if err := journal.SetExcluded(failure.Key, true); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.DownloadChapter(context.Background(), chapter, testDownloadOptions())
+	if !errors.As(err, &ChapterExcludedError{}) {
+		t.Fatalf("got %v, want ChapterExcludedError", err)
+	}
+
+	if err := journal.SetExcluded(failure.Key, false); err != nil {
+		t.Fatal(err)
+	}
+
+	provider.pageErr = nil
+	if _, err := client.DownloadChapter(context.Background(), chapter, testDownloadOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	// successful download removes the record
+	if _, found, err := journal.Get(failure.Key); err != nil || found {
+		t.Errorf("failure is still recorded: %v", err)
+	}
+}
@@ -0,0 +1,228 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+)
+
+// FallbackPolicy controls fetching chapters that the provider lacks
+// from the secondary providers. See ClientOptions.Fallback
+type FallbackPolicy struct {
+	// Clients are the secondary clients tried in the given order.
+	// Empty list disables fallback.
+	Clients []*Client
+}
+
+var (
+	_ Volume  = (*fallbackVolume)(nil)
+	_ Chapter = (*fallbackChapter)(nil)
+)
+
+// fallbackVolume is the volume of the secondary provider
+// attached to the manga of the primary one
+type fallbackVolume struct {
+	Volume
+	manga Manga
+}
+
+func (f fallbackVolume) Manga() Manga {
+	return f.manga
+}
+
+// fallbackChapter is the chapter of the secondary provider
+// attached to the manga of the primary one,
+// so that naming and metadata stay consistent.
+type fallbackChapter struct {
+	Chapter
+	volume fallbackVolume
+}
+
+func (f fallbackChapter) Volume() Volume {
+	return f.volume
+}
+
+// FallbackChapter searches for the chapter of the manga with the given number
+// in the ClientOptions.Fallback clients. Mangas of the secondary providers
+// are matched by Anilist ID of the given manga.
+//
+// Found chapter is attached to the given manga, so it will be
+// named the same way as chapters of this client.
+// It must be downloaded with the returned client.
+func (c *Client) FallbackChapter(
+	ctx context.Context,
+	manga Manga,
+	number float32,
+) (Chapter, *Client, bool, error) {
+	if len(c.options.Fallback.Clients) == 0 {
+		return nil, nil, false, nil
+	}
+
+	resolver, err := c.newFallbackResolver(ctx, manga)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	chapter, fallback, ok := resolver.chapter(ctx, number)
+	return chapter, fallback, ok, nil
+}
+
+// fallbackResolver finds chapters of the manga on the fallback clients.
+// Manga and its chapters are resolved once per fallback client.
+type fallbackResolver struct {
+	client    *Client
+	manga     Manga
+	query     string
+	anilistID int
+
+	// chapters are the chapters of the manga on the fallback clients
+	// by their index. Nil if not resolved yet.
+	chapters []map[float32]Chapter
+}
+
+func (c *Client) newFallbackResolver(ctx context.Context, manga Manga) (*fallbackResolver, error) {
+	mangaWithAnilist, ok, err := c.Anilist().MakeMangaWithAnilist(ctx, manga)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, fmt.Errorf("manga %q was not found on anilist", manga)
+	}
+
+	query := manga.Info().AnilistSearch
+	if query == "" {
+		query = manga.Info().Title
+	}
+
+	return &fallbackResolver{
+		client:    c,
+		manga:     manga,
+		query:     query,
+		anilistID: mangaWithAnilist.Anilist.ID,
+		chapters:  make([]map[float32]Chapter, len(c.options.Fallback.Clients)),
+	}, nil
+}
+
+// chapter returns the chapter with the given number
+// of the first fallback client that has it
+func (f *fallbackResolver) chapter(ctx context.Context, number float32) (Chapter, *Client, bool) {
+	for i, fallback := range f.client.options.Fallback.Clients {
+		if f.chapters[i] == nil {
+			f.chapters[i] = f.resolve(ctx, fallback)
+		}
+
+		chapter, ok := f.chapters[i][number]
+		if !ok {
+			continue
+		}
+
+		return fallbackChapter{
+			Chapter: chapter,
+			volume: fallbackVolume{
+				Volume: chapter.Volume(),
+				manga:  f.manga,
+			},
+		}, fallback, true
+	}
+
+	return nil, nil, false
+}
+
+// resolve returns chapters of the manga on the fallback client by their number.
+// Failures are logged, so that the other clients are tried.
+func (f *fallbackResolver) resolve(ctx context.Context, fallback *Client) map[float32]Chapter {
+	f.client.options.Log(fmt.Sprintf("Searching %q on %s", f.manga, fallback))
+
+	chapters, err := fallback.chaptersByAnilistID(ctx, f.query, f.anilistID)
+	if err != nil {
+		f.client.options.Log(fmt.Sprintf("Fallback %s failed: %s", fallback, err))
+	}
+
+	byNumber := make(map[float32]Chapter, len(chapters))
+	for _, chapter := range chapters {
+		number := chapter.Info().Number
+		if _, ok := byNumber[number]; !ok {
+			byNumber[number] = chapter
+		}
+	}
+
+	return byNumber
+}
+
+// chaptersByAnilistID returns chapters of the first manga
+// found by the query that has the given Anilist ID
+func (c *Client) chaptersByAnilistID(
+	ctx context.Context,
+	query string,
+	anilistID int,
+) ([]Chapter, error) {
+	mangas, err := c.SearchMangas(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, manga := range mangas {
+		mangaWithAnilist, ok, err := c.Anilist().MakeMangaWithAnilist(ctx, manga)
+		if err != nil {
+			return nil, err
+		}
+
+		if !ok || mangaWithAnilist.Anilist.ID != anilistID {
+			continue
+		}
+
+		return c.mangaChapters(ctx, manga)
+	}
+
+	return nil, nil
+}
+
+// DownloadMissingChapters downloads chapters that are missing
+// on this provider (see MissingChapters) from the fallback providers.
+//
+// The manga's DownloadProfile is applied to the options.
+// It returns results of the downloaded chapters.
+func (c *Client) DownloadMissingChapters(
+	ctx context.Context,
+	manga Manga,
+	options DownloadOptions,
+) ([]DownloadResult, error) {
+	options, err := c.MangaDownloadOptions(manga, options)
+	if err != nil {
+		return nil, err
+	}
+
+	missing, err := c.MissingChapters(ctx, manga, options)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(missing.NotOnProvider) == 0 || len(c.options.Fallback.Clients) == 0 {
+		return nil, nil
+	}
+
+	resolver, err := c.newFallbackResolver(ctx, manga)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []DownloadResult
+	for _, chapterRange := range missing.NotOnProvider {
+		for number := chapterRange.From; number <= chapterRange.To; number++ {
+			chapter, fallback, ok := resolver.chapter(ctx, float32(number))
+			if !ok {
+				c.options.Log(fmt.Sprintf("Chapter %d of %q was not found on fallback providers", number, manga))
+				continue
+			}
+
+			result, err := fallback.DownloadChapter(ctx, chapter, options)
+			if err != nil {
+				return results, err
+			}
+
+			results = append(results, result)
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,58 @@
+package libmangal
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+)
+
+// searchCountingProvider counts searches of the mangas
+type searchCountingProvider struct {
+	*fakeProvider
+	searches atomic.Int64
+}
+
+func (s *searchCountingProvider) Load(context.Context) (Provider, error) {
+	return s, nil
+}
+
+func (s *searchCountingProvider) SearchMangas(ctx context.Context, log LogFunc, query string) ([]Manga, error) {
+	s.searches.Add(1)
+	return s.fakeProvider.SearchMangas(ctx, log, query)
+}
+
+func TestDownloadMissingChapters(t *testing.T) {
+	const chapters = 3
+
+	primary := newFakeProvider(t, 0, 1)
+	fallbackProvider := &searchCountingProvider{fakeProvider: newFakeProvider(t, chapters, 1)}
+
+	client := newTestClient(t, primary)
+
+	options := testClientOptions()
+	options.FS = client.options.FS
+
+	fallback, err := NewClient(context.Background(), fallbackProvider, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client.options.Fallback.Clients = []*Client{fallback}
+
+	anilistManga := AnilistManga{ID: 1, Chapters: chapters}
+	seedAnilist(t, client, anilistManga)
+	seedAnilist(t, fallback, anilistManga)
+
+	results, err := client.DownloadMissingChapters(context.Background(), primary.manga(), testDownloadOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(results) != chapters {
+		t.Fatalf("got %d results, want %d", len(results), chapters)
+	}
+
+	if got := fallbackProvider.searches.Load(); got != 1 {
+		t.Errorf("fallback manga was searched %d times, want once", got)
+	}
+}
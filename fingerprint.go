@@ -0,0 +1,104 @@
+package libmangal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+)
+
+// ChapterFingerprint identifies the contents of the chapter.
+// It's used to detect that a source has replaced chapter pages,
+// e.g. with the fixed scans.
+type ChapterFingerprint struct {
+	// Hash of the whole chapter derived from the Pages hashes
+	Hash string `json:"hash"`
+
+	// Pages are the hashes of each chapter page in order
+	Pages []string `json:"pages"`
+}
+
+// pageFingerprint hashes the page.
+// Images are hashed if they are available without downloading,
+// otherwise page string representation (usually URL) is used.
+func pageFingerprint(page Page) string {
+	hash := sha256.New()
+
+	switch page := page.(type) {
+	case PageWithImage:
+		_, _ = hash.Write(page.GetImage())
+	case PageWithDataURI:
+		_, _ = io.WriteString(hash, page.DataURI())
+	default:
+		_, _ = io.WriteString(hash, page.String())
+		_, _ = io.WriteString(hash, page.GetExtension())
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+func newChapterFingerprint(pages []Page) ChapterFingerprint {
+	fingerprint := ChapterFingerprint{
+		Pages: make([]string, len(pages)),
+	}
+
+	hash := sha256.New()
+	for i, page := range pages {
+		fingerprint.Pages[i] = pageFingerprint(page)
+		_, _ = io.WriteString(hash, fingerprint.Pages[i])
+	}
+
+	fingerprint.Hash = hex.EncodeToString(hash.Sum(nil))
+
+	return fingerprint
+}
+
+// ChapterFingerprint computes the current fingerprint of the chapter.
+// It fetches chapter pages but doesn't download their images.
+func (c *Client) ChapterFingerprint(ctx context.Context, chapter Chapter) (ChapterFingerprint, error) {
+	pages, err := c.ChapterPages(ctx, chapter)
+	if err != nil {
+		return ChapterFingerprint{}, err
+	}
+
+	return newChapterFingerprint(pages), nil
+}
+
+// StoredChapterFingerprint returns the fingerprint of the chapter
+// recorded during its last download.
+func (c *Client) StoredChapterFingerprint(chapter Chapter) (ChapterFingerprint, bool, error) {
+	store := c.options.FingerprintStore
+	if store == nil {
+		return ChapterFingerprint{}, false, nil
+	}
+
+	var fingerprint ChapterFingerprint
+	found, err := store.Get(chapterKey(c.Info().ID, chapter), &fingerprint)
+	return fingerprint, found, err
+}
+
+func (c *Client) storeChapterFingerprint(chapter Chapter, fingerprint ChapterFingerprint) error {
+	store := c.options.FingerprintStore
+	if store == nil {
+		return nil
+	}
+
+	return store.Set(chapterKey(c.Info().ID, chapter), fingerprint)
+}
+
+// ChapterChanged checks whether the chapter contents has changed
+// since its last download. Chapters without stored
+// fingerprint are considered unchanged.
+func (c *Client) ChapterChanged(ctx context.Context, chapter Chapter) (bool, error) {
+	stored, found, err := c.StoredChapterFingerprint(chapter)
+	if err != nil || !found {
+		return false, err
+	}
+
+	current, err := c.ChapterFingerprint(ctx, chapter)
+	if err != nil {
+		return false, err
+	}
+
+	return current.Hash != stored.Hash, nil
+}
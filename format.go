@@ -1,8 +1,12 @@
 package libmangal
 
-//go:generate enumer -type=Format -trimprefix=Format -json -yaml -text
+// Marshaling is not generated, as it must know the custom formats,
+// see format_registry.go
+//
+//go:generate enumer -type=Format -trimprefix=Format
 
-// Format is the format for saving chapters
+// Format is the format for saving chapters.
+// Custom formats can be added with RegisterFormat
 type Format uint8
 
 const (
@@ -25,6 +29,19 @@ const (
 
 	// FormatZIP save chapter images as zip archive
 	FormatZIP
+
+	// FormatHTML saves chapter as a single HTML file with embedded
+	// images and a simple reader, so it can be read in any browser
+	FormatHTML
+
+	// FormatCB7 saves chapter as CB7 archive.
+	// CB7 stands for Comic Book 7z format.
+	// Images are stored without compression, as they are already compressed
+	FormatCB7
+
+	// FormatCBT saves chapter as CBT archive.
+	// CBT stands for Comic Book Tar format
+	FormatCBT
 )
 
 // Extension returns extension of the format with the leading dot.
@@ -40,7 +57,17 @@ func (f Format) Extension() string {
 		return ".tar.gz"
 	case FormatZIP:
 		return ".zip"
+	case FormatHTML:
+		return ".html"
+	case FormatCB7:
+		return ".cb7"
+	case FormatCBT:
+		return ".cbt"
 	default:
+		if custom, ok := getCustomFormat(f); ok {
+			return custom.info.Extension
+		}
+
 		return ""
 	}
 }
@@ -0,0 +1,231 @@
+package libmangal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// FormatSaver saves downloaded chapter pages in the custom format.
+// See RegisterFormat
+type FormatSaver func(out io.Writer, pages []PageWithImage, chapter Chapter) error
+
+// FormatInfo describes the format
+type FormatInfo struct {
+	// Format is the format value
+	Format Format `json:"format"`
+
+	// Name of the format, e.g. "CBZ"
+	Name string `json:"name"`
+
+	// Extension of the format with the leading dot.
+	// Empty for the formats that produce directories.
+	Extension string `json:"extension"`
+
+	// Custom is true for the formats registered with RegisterFormat
+	Custom bool `json:"custom"`
+}
+
+type customFormat struct {
+	info  FormatInfo
+	saver FormatSaver
+}
+
+// formatCustomStart is the first value assigned to the custom formats.
+// It leaves room for the built-in formats to be added.
+const formatCustomStart Format = 128
+
+var (
+	customFormatsMu sync.RWMutex
+	customFormats   = make(map[Format]customFormat)
+)
+
+// RegisterFormat registers the custom format that is saved with the given saver.
+// Extension must start with the dot. Name must be unique among all formats.
+//
+// Returned Format can be used in DownloadOptions as any built-in one.
+func RegisterFormat(name, extension string, saver FormatSaver) (Format, error) {
+	if name == "" {
+		return 0, fmt.Errorf("format name must be non-empty")
+	}
+
+	if !strings.HasPrefix(extension, ".") {
+		return 0, fmt.Errorf("format extension must start with the dot: %q", extension)
+	}
+
+	if saver == nil {
+		return 0, fmt.Errorf("format saver must be non-nil")
+	}
+
+	customFormatsMu.Lock()
+	defer customFormatsMu.Unlock()
+
+	if _, ok := lookupFormat(name); ok {
+		return 0, fmt.Errorf("format %q is already registered", name)
+	}
+
+	if len(customFormats) > int(^Format(0)-formatCustomStart) {
+		return 0, fmt.Errorf("too many custom formats")
+	}
+
+	format := formatCustomStart + Format(len(customFormats))
+	customFormats[format] = customFormat{
+		info: FormatInfo{
+			Format:    format,
+			Name:      name,
+			Extension: extension,
+			Custom:    true,
+		},
+		saver: saver,
+	}
+
+	return format, nil
+}
+
+// lookupFormat finds format by its case-insensitive name.
+// customFormatsMu must be held.
+func lookupFormat(name string) (Format, bool) {
+	if format, err := FormatString(name); err == nil {
+		return format, true
+	}
+
+	for format, custom := range customFormats {
+		if strings.EqualFold(custom.info.Name, name) {
+			return format, true
+		}
+	}
+
+	return 0, false
+}
+
+// ParseFormat finds built-in or custom format by its case-insensitive name
+func ParseFormat(name string) (Format, error) {
+	customFormatsMu.RLock()
+	defer customFormatsMu.RUnlock()
+
+	format, ok := lookupFormat(name)
+	if !ok {
+		return 0, fmt.Errorf("unknown format: %q", name)
+	}
+
+	return format, nil
+}
+
+// Formats lists all the built-in and custom formats
+func Formats() []FormatInfo {
+	var formats []FormatInfo
+	for _, format := range FormatValues() {
+		formats = append(formats, FormatInfo{
+			Format:    format,
+			Name:      format.String(),
+			Extension: format.Extension(),
+		})
+	}
+
+	customFormatsMu.RLock()
+	defer customFormatsMu.RUnlock()
+
+	for i := 0; i < len(customFormats); i++ {
+		formats = append(formats, customFormats[formatCustomStart+Format(i)].info)
+	}
+
+	return formats
+}
+
+// getCustomFormat returns the custom format registered with RegisterFormat
+func getCustomFormat(format Format) (customFormat, bool) {
+	customFormatsMu.RLock()
+	defer customFormatsMu.RUnlock()
+
+	custom, ok := customFormats[format]
+	return custom, ok
+}
+
+// IsValid checks whether the format is a built-in or a registered custom format
+func (f Format) IsValid() bool {
+	if f.IsAFormat() {
+		return true
+	}
+
+	_, ok := getCustomFormat(f)
+	return ok
+}
+
+// Name returns the name of the format.
+// Unlike String it also knows the names of the custom formats.
+func (f Format) Name() string {
+	if custom, ok := getCustomFormat(f); ok {
+		return custom.info.Name
+	}
+
+	return f.String()
+}
+
+// marshalName returns the name of the format to be persisted.
+// Numeric values are never persisted, as values of the custom formats
+// depend on the order of registration.
+func (f Format) marshalName() (string, error) {
+	if !f.IsValid() {
+		return "", fmt.Errorf("unknown format: %d", f)
+	}
+
+	return f.Name(), nil
+}
+
+// MarshalJSON implements the json.Marshaler interface for Format
+func (f Format) MarshalJSON() ([]byte, error) {
+	name, err := f.marshalName()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(name)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface for Format
+func (f *Format) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return fmt.Errorf("Format should be a string, got %s", data)
+	}
+
+	var err error
+	*f, err = ParseFormat(name)
+	return err
+}
+
+// MarshalText implements the encoding.TextMarshaler interface for Format
+func (f Format) MarshalText() ([]byte, error) {
+	name, err := f.marshalName()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(name), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface for Format
+func (f *Format) UnmarshalText(text []byte) error {
+	var err error
+	*f, err = ParseFormat(string(text))
+	return err
+}
+
+// MarshalYAML implements a YAML Marshaler for Format
+func (f Format) MarshalYAML() (interface{}, error) {
+	return f.marshalName()
+}
+
+// UnmarshalYAML implements a YAML Unmarshaler for Format
+func (f *Format) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var name string
+	if err := unmarshal(&name); err != nil {
+		return err
+	}
+
+	var err error
+	*f, err = ParseFormat(name)
+	return err
+}
@@ -0,0 +1,112 @@
+package libmangal
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+)
+
+// testCustomFormat registers the custom format once per test binary
+func testCustomFormat(t *testing.T) Format {
+	t.Helper()
+
+	if format, err := ParseFormat("TestFormat"); err == nil {
+		return format
+	}
+
+	format, err := RegisterFormat("TestFormat", ".test", func(io.Writer, []PageWithImage, Chapter) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return format
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	formats := append(FormatValues(), testCustomFormat(t))
+
+	for _, format := range formats {
+		t.Run(format.Name(), func(t *testing.T) {
+			marshalled, err := json.Marshal(struct{ Format Format }{format})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := `{"Format":"` + format.Name() + `"}`
+			if string(marshalled) != want {
+				t.Errorf("json: got %s, want %s", marshalled, want)
+			}
+
+			var fromJSON struct{ Format Format }
+			if err := json.Unmarshal(marshalled, &fromJSON); err != nil {
+				t.Fatal(err)
+			}
+
+			if fromJSON.Format != format {
+				t.Errorf("json: got %v, want %v", fromJSON.Format, format)
+			}
+
+			text, err := format.MarshalText()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var fromText Format
+			if err := fromText.UnmarshalText(text); err != nil {
+				t.Fatal(err)
+			}
+
+			if fromText != format {
+				t.Errorf("text: got %v, want %v", fromText, format)
+			}
+
+			yaml, err := format.MarshalYAML()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if yaml != format.Name() {
+				t.Errorf("yaml: got %v, want %s", yaml, format.Name())
+			}
+
+			var fromYAML Format
+			if err := fromYAML.UnmarshalYAML(func(v interface{}) error {
+				*v.(*string) = yaml.(string)
+				return nil
+			}); err != nil {
+				t.Fatal(err)
+			}
+
+			if fromYAML != format {
+				t.Errorf("yaml: got %v, want %v", fromYAML, format)
+			}
+		})
+	}
+}
+
+func TestFormatMarshalUnknown(t *testing.T) {
+	unknown := Format(250)
+
+	if _, err := json.Marshal(unknown); err == nil {
+		t.Error("json: expected error")
+	}
+
+	if _, err := unknown.MarshalText(); err == nil {
+		t.Error("text: expected error")
+	}
+
+	if _, err := unknown.MarshalYAML(); err == nil {
+		t.Error("yaml: expected error")
+	}
+
+	var format Format
+	if err := json.Unmarshal([]byte(`128`), &format); err == nil {
+		t.Error("numeric value was unmarshalled")
+	}
+
+	if err := format.UnmarshalText([]byte("NotAFormat")); err == nil {
+		t.Error("unknown name was unmarshalled")
+	}
+}
@@ -3,6 +3,7 @@ package libmangal
 import (
 	"github.com/spf13/afero"
 	"io"
+	"os"
 	"path/filepath"
 )
 
@@ -47,23 +48,69 @@ func mergeDirectories(
 			}
 		}
 
-		srcFile, err := srcFS.Open(srcFilePath)
-		if err != nil {
+		if err := copyFile(dstFS, dstFilePath, srcFS, srcFilePath); err != nil {
 			return err
 		}
+	}
+
+	return nil
+}
+
+// partialFileSuffix is appended to the names of files being copied.
+// Leftovers of interrupted copies are removed by Client.CleanCaches,
+// so the suffix must not match files of the other tools.
+const partialFileSuffix = ".libmangal-part"
+
+// copyFile copies a single file from one filesystem to another.
+// Destination file is replaced if it exists.
+//
+// File is copied under the temporary name first,
+// so that interrupted copy doesn't leave truncated file.
+func copyFile(
+	dstFS afero.Fs, dstPath string,
+	srcFS afero.Fs, srcPath string,
+) error {
+	srcFile, err := srcFS.Open(srcPath)
+	if err != nil {
+		return err
+	}
+	defer srcFile.Close()
+
+	partialPath := dstPath + partialFileSuffix
+
+	dstFile, err := dstFS.Create(partialPath)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(dstFile, srcFile)
+	if closeErr := dstFile.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		_ = dstFS.Remove(partialPath)
+		return err
+	}
+
+	return dstFS.Rename(partialPath, dstPath)
+}
+
+// dirSize returns the total size of all files in the directory recursively
+func dirSize(fs afero.Fs, dir string) (int64, error) {
+	var size int64
 
-		dstFile, err := dstFS.Create(dstFilePath)
+	err := afero.Walk(fs, dir, func(_ string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if _, err := io.Copy(dstFile, srcFile); err != nil {
-			return err
+		if !info.IsDir() {
+			size += info.Size()
 		}
 
-		_ = srcFile.Close()
-		_ = dstFile.Close()
-	}
+		return nil
+	})
 
-	return nil
+	return size, err
 }
@@ -3,13 +3,16 @@ module github.com/mangalorg/libmangal
 go 1.20
 
 require (
+	github.com/nwaples/rardecode v1.1.3
 	github.com/pdfcpu/pdfcpu v0.4.1
 	github.com/philippgille/gokv v0.6.0
 	github.com/philippgille/gokv/syncmap v0.6.0
 	github.com/skratchdot/open-golang v0.0.0-20200116055534-eef842397966
 	github.com/spf13/afero v1.9.5
+	golang.org/x/image v0.8.0
 	golang.org/x/mod v0.10.0
 	golang.org/x/sync v0.2.0
+	google.golang.org/protobuf v1.30.0
 )
 
 require (
@@ -20,7 +23,6 @@ require (
 	github.com/philippgille/gokv/util v0.6.0 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/rivo/uniseg v0.4.4 // indirect
-	golang.org/x/image v0.8.0 // indirect
 	golang.org/x/text v0.10.0 // indirect
 	gopkg.in/check.v1 v1.0.0-20190902080502-41f04d3bba15 // indirect
 	gopkg.in/yaml.v2 v2.4.0 // indirect
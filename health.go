@@ -0,0 +1,89 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ProviderWithHealthCheck is a Provider that can perform
+// a lightweight check of whether it still works,
+// e.g. fetch the homepage and verify selectors.
+type ProviderWithHealthCheck interface {
+	Provider
+
+	// HealthCheck returns non-nil error if the provider is broken.
+	//
+	// Implementation should utilize given LogFunc
+	HealthCheck(ctx context.Context, log LogFunc) error
+}
+
+// HealthReport is the result of the provider health check
+type HealthReport struct {
+	// Healthy is true if the check passed
+	Healthy bool `json:"healthy"`
+
+	// Error is the reason the check failed
+	Error string `json:"error,omitempty"`
+
+	// Latency is the time the check took
+	Latency time.Duration `json:"latency"`
+
+	// CheckedAt is the time the check was started
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// HealthCheck checks whether the provider works.
+//
+// If the provider implements ProviderWithHealthCheck, its own check is used.
+// Otherwise, ProviderInfo.Website is requested.
+func (c *Client) HealthCheck(ctx context.Context) HealthReport {
+	report := HealthReport{
+		CheckedAt: time.Now(),
+	}
+
+	var err error
+	if withHealthCheck, ok := c.provider.(ProviderWithHealthCheck); ok {
+		err = withHealthCheck.HealthCheck(ctx, c.options.Log)
+	} else {
+		err = c.checkWebsite(ctx)
+	}
+
+	report.Latency = time.Since(report.CheckedAt)
+	report.Healthy = err == nil
+
+	if err != nil {
+		report.Error = err.Error()
+	}
+
+	return report
+}
+
+// checkWebsite checks that the provider website is reachable
+func (c *Client) checkWebsite(ctx context.Context) error {
+	website := c.Info().Website
+	if website == "" {
+		return errors.New("provider has neither health check nor website")
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, website, nil)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("User-Agent", c.userAgent(request.URL.Host))
+
+	response, err := c.options.HTTPClient.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("unexpected http status: %s", response.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,95 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// providerWithWebsite is the fakeProvider with the website
+type providerWithWebsite struct {
+	*fakeProvider
+	website string
+}
+
+func (p *providerWithWebsite) Info() ProviderInfo {
+	info := fakeProviderInfo
+	info.Website = p.website
+	return info
+}
+
+func (p *providerWithWebsite) Load(context.Context) (Provider, error) {
+	return p, nil
+}
+
+// providerWithHealthCheck is the fakeProvider with its own health check
+type providerWithHealthCheck struct {
+	*fakeProvider
+	err error
+}
+
+func (p *providerWithHealthCheck) HealthCheck(context.Context, LogFunc) error {
+	return p.err
+}
+
+func (p *providerWithHealthCheck) Load(context.Context) (Provider, error) {
+	return p, nil
+}
+
+func TestHealthCheckWebsite(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if userAgent := r.Header.Get("User-Agent"); userAgent != "test-agent" {
+			t.Errorf("got User-Agent %q", userAgent)
+		}
+
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	options := testClientOptions()
+	options.UserAgent = StaticUserAgent("test-agent")
+
+	ctx := context.Background()
+	client, err := NewClient(ctx, &providerWithWebsite{newFakeProvider(t, 1, 1), server.URL}, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := client.HealthCheck(ctx)
+	if !report.Healthy || report.Error != "" || report.CheckedAt.IsZero() {
+		t.Errorf("got %+v, want healthy", report)
+	}
+
+	status = http.StatusServiceUnavailable
+	if report := client.HealthCheck(ctx); report.Healthy || report.Error == "" {
+		t.Errorf("got %+v, want unhealthy", report)
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	ctx := context.Background()
+
+	// fakeProvider has neither
+	client := newTestClient(t, newFakeProvider(t, 1, 1))
+	if report := client.HealthCheck(ctx); report.Healthy {
+		t.Error("provider without website is healthy")
+	}
+
+	provider := &providerWithHealthCheck{fakeProvider: newFakeProvider(t, 1, 1)}
+	client, err := NewClient(ctx, provider, testClientOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report := client.HealthCheck(ctx); !report.Healthy {
+		t.Errorf("got %+v, want healthy", report)
+	}
+
+	provider.err = errors.New("selectors changed")
+	if report := client.HealthCheck(ctx); report.Healthy || report.Error != provider.err.Error() {
+		t.Errorf("got %+v, want the health check error", report)
+	}
+}
@@ -0,0 +1,200 @@
+package libmangal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/afero"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"text/template"
+)
+
+// Hook is called after the chapter was downloaded to the path
+// on the given filesystem. See DownloadOptions.AfterDownload
+//
+// It returns the path of the chapter after the hook,
+// e.g. if it was moved or converted, or the given path if it's unchanged.
+type Hook func(ctx context.Context, fs afero.Fs, path string, chapter Chapter) (string, error)
+
+// hookData is the data available for the hook templates
+// and the payload of the WebhookHook
+type hookData struct {
+	Path    string  `json:"path"`
+	Dir     string  `json:"dir"`
+	Name    string  `json:"name"`
+	Manga   string  `json:"manga"`
+	Volume  int     `json:"volume"`
+	Chapter string  `json:"chapter"`
+	Number  float32 `json:"number"`
+	URL     string  `json:"url"`
+}
+
+func newHookData(path string, chapter Chapter) hookData {
+	info := chapter.Info()
+	volume := chapter.Volume()
+
+	return hookData{
+		Path:    path,
+		Dir:     filepath.Dir(path),
+		Name:    filepath.Base(path),
+		Manga:   volume.Manga().Info().Title,
+		Volume:  volume.Info().Number,
+		Chapter: info.Title,
+		Number:  info.Number,
+		URL:     info.URL,
+	}
+}
+
+// CommandHook runs an external command after the chapter is downloaded.
+// Arguments are text/template templates with the following fields available:
+//
+//	.Path .Dir .Name .Manga .Volume .Chapter .Number .URL
+//
+// E.g. CommandHook("kcc-c2e", "--output", "{{ .Dir }}", "{{ .Path }}")
+//
+// Error is returned if any of the arguments is not a valid template.
+//
+// Note, that filesystem must be mapped with OsFs
+// in order for the command to access the chapter.
+func CommandHook(name string, args ...string) (Hook, error) {
+	templates := make([]*template.Template, len(args))
+	for i, arg := range args {
+		tmpl, err := template.New("arg").Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("argument #%d: %w", i+1, err)
+		}
+
+		templates[i] = tmpl
+	}
+
+	return func(ctx context.Context, _ afero.Fs, path string, chapter Chapter) (string, error) {
+		data := newHookData(path, chapter)
+
+		executed := make([]string, len(templates))
+		for i, tmpl := range templates {
+			var buffer bytes.Buffer
+			if err := tmpl.Execute(&buffer, data); err != nil {
+				return "", err
+			}
+
+			executed[i] = buffer.String()
+		}
+
+		output, err := exec.CommandContext(ctx, name, executed...).CombinedOutput()
+		if err != nil {
+			return "", fmt.Errorf("%s: %w: %s", name, err, output)
+		}
+
+		return path, nil
+	}, nil
+}
+
+// MoveHook moves downloaded chapter to the dstDir on the dstFS.
+// Useful for uploading to the remote filesystems.
+//
+// The moved chapter is reported, so it's the one recorded
+// in the ChapterIndex and chapters.json.
+func MoveHook(dstFS afero.Fs, dstDir string) Hook {
+	return func(_ context.Context, srcFS afero.Fs, path string, _ Chapter) (string, error) {
+		dstPath := filepath.Join(dstDir, filepath.Base(path))
+
+		isDir, err := afero.IsDir(srcFS, path)
+		if err != nil {
+			return "", err
+		}
+
+		if isDir {
+			if err := mergeDirectories(dstFS, dstPath, srcFS, path); err != nil {
+				return "", err
+			}
+
+			return dstPath, srcFS.RemoveAll(path)
+		}
+
+		if err := dstFS.MkdirAll(dstDir, modeDir); err != nil {
+			return "", err
+		}
+
+		if err := copyFile(dstFS, dstPath, srcFS, path); err != nil {
+			return "", err
+		}
+
+		return dstPath, srcFS.Remove(path)
+	}
+}
+
+// WebhookHook sends POST request with JSON description
+// of the downloaded chapter to the given URL.
+// Nil client means http.DefaultClient
+func WebhookHook(client *http.Client, URL string) Hook {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return func(ctx context.Context, _ afero.Fs, path string, chapter Chapter) (string, error) {
+		body, err := json.Marshal(newHookData(path, chapter))
+		if err != nil {
+			return "", err
+		}
+
+		request, err := http.NewRequestWithContext(ctx, http.MethodPost, URL, bytes.NewReader(body))
+		if err != nil {
+			return "", err
+		}
+
+		request.Header.Set("Content-Type", "application/json")
+
+		response, err := client.Do(request)
+		if err != nil {
+			return "", err
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode < 200 || response.StatusCode >= 300 {
+			return "", fmt.Errorf("unexpected http status: %s", response.Status)
+		}
+
+		return path, nil
+	}
+}
+
+// runHooks runs DownloadOptions.AfterDownload hooks for the downloaded chapter
+// or for each of its parts if it was split, see DownloadOptions.MaxFileSize.
+// Paths of the result are replaced with the ones reported by the hooks.
+func (c *Client) runHooks(ctx context.Context, chapter Chapter, hooks []Hook, result *DownloadResult) error {
+	run := func(path string) (string, error) {
+		for i, hook := range hooks {
+			var err error
+			path, err = hook(ctx, c.options.FS, path, chapter)
+			if err != nil {
+				return "", fmt.Errorf("after download hook #%d: %w", i+1, err)
+			}
+		}
+
+		return path, nil
+	}
+
+	if len(result.Parts) == 0 {
+		path, err := run(result.Path)
+		if err != nil {
+			return err
+		}
+
+		result.Path = path
+		return nil
+	}
+
+	for i, part := range result.Parts {
+		path, err := run(part)
+		if err != nil {
+			return err
+		}
+
+		result.Parts[i] = path
+	}
+
+	return nil
+}
@@ -0,0 +1,180 @@
+package libmangal
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/philippgille/gokv/syncmap"
+	"github.com/spf13/afero"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// renameHook renames the chapter adding the suffix and records the paths it was called with
+func renameHook(called *[]string) Hook {
+	return func(_ context.Context, fs afero.Fs, path string, _ Chapter) (string, error) {
+		*called = append(*called, path)
+
+		renamed := path + ".renamed"
+		return renamed, fs.Rename(path, renamed)
+	}
+}
+
+func TestAfterDownloadHooks(t *testing.T) {
+	provider := newFakeProvider(t, 1, 2)
+	client := newTestClient(t, provider)
+	client.options.ChapterIndex = NewChapterIndex(syncmap.NewStore(syncmap.DefaultOptions))
+
+	var called []string
+	options := testDownloadOptions()
+	options.AfterDownload = []Hook{renameHook(&called)}
+
+	chapter := provider.chapterList()[0]
+
+	result, err := client.DownloadChapter(context.Background(), chapter, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(called) != 1 {
+		t.Fatalf("hook was called %d times, want 1", len(called))
+	}
+
+	if want := called[0] + ".renamed"; result.Path != want {
+		t.Errorf("got path %q, want the reported %q", result.Path, want)
+	}
+
+	indexed, ok, err := client.ChapterIndex().Get(provider.Info().ID, chapter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !ok || indexed != result.Path {
+		t.Errorf("indexed path is %q, want %q", indexed, result.Path)
+	}
+
+	// the renamed chapter is indexed, so it's skipped
+	result, err = client.DownloadChapter(context.Background(), chapter, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.Skipped {
+		t.Fatal("chapter was not skipped")
+	}
+
+	if len(called) != 1 {
+		t.Errorf("hook was called for the skipped chapter")
+	}
+}
+
+func TestAfterDownloadHooksSplitChapter(t *testing.T) {
+	provider := newFakeProvider(t, 1, 4)
+	client := newTestClient(t, provider)
+
+	var called []string
+	options := testDownloadOptions()
+	options.MaxFileSize = int64(len(provider.image)*2 + 1)
+	options.AfterDownload = []Hook{renameHook(&called)}
+
+	result, err := client.DownloadChapter(context.Background(), provider.chapterList()[0], options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Parts) != 2 {
+		t.Fatalf("got %d parts, want 2", len(result.Parts))
+	}
+
+	if len(called) != len(result.Parts) {
+		t.Fatalf("hook was called %d times, want once per part", len(called))
+	}
+
+	for i, part := range result.Parts {
+		if !strings.HasSuffix(part, ".renamed") {
+			t.Errorf("part %d: got %q, want the reported path", i+1, part)
+		}
+
+		exists, err := afero.Exists(client.FS(), part)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !exists {
+			t.Errorf("part %d: %q doesn't exist", i+1, part)
+		}
+	}
+}
+
+func TestCommandHook(t *testing.T) {
+	if _, err := CommandHook("true", "{{ .Path"); err == nil {
+		t.Error("expected an error for the invalid template")
+	}
+
+	if _, err := exec.LookPath("test"); err != nil {
+		t.Skip("test command is not available")
+	}
+
+	provider := newFakeProvider(t, 1, 0)
+	chapter := provider.chapterList()[0]
+
+	// test exits with non-zero status if the strings differ
+	hook, err := CommandHook("test", "{{ .Manga }}/{{ .Name }}", "=", "Fake Manga/Chapter 1.cbz")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := hook(context.Background(), afero.NewMemMapFs(), "/library/Fake Manga/Chapter 1.cbz", chapter)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if path != "/library/Fake Manga/Chapter 1.cbz" {
+		t.Errorf("got path %q, want it unchanged", path)
+	}
+
+	if _, err := hook(context.Background(), afero.NewMemMapFs(), "/library/Fake Manga/Chapter 2.cbz", chapter); err == nil {
+		t.Error("expected an error for the failed command")
+	}
+}
+
+func TestWebhookHook(t *testing.T) {
+	var received hookData
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil || r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if received.Number == 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	provider := newFakeProvider(t, 2, 0)
+	chapters := provider.chapterList()
+
+	// nil client means http.DefaultClient
+	hook := WebhookHook(nil, server.URL)
+
+	path, err := hook(context.Background(), afero.NewMemMapFs(), "/library/Fake Manga/Chapter 1.cbz", chapters[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if path != "/library/Fake Manga/Chapter 1.cbz" {
+		t.Errorf("got path %q, want it unchanged", path)
+	}
+
+	want := newHookData(path, chapters[0])
+	if received != want {
+		t.Errorf("received %+v, want %+v", received, want)
+	}
+
+	if _, err := hook(context.Background(), afero.NewMemMapFs(), "/library/Fake Manga/Chapter 2.cbz", chapters[1]); err == nil {
+		t.Error("expected an error for the failed request")
+	}
+}
@@ -0,0 +1,118 @@
+package libmangal
+
+import (
+	"encoding/base64"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+)
+
+// htmlReaderTemplate is a minimal self-contained reader.
+// Arrow keys, space, clicks on the page halves and swipes turn pages.
+var htmlReaderTemplate = template.Must(template.New("reader").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{ .Title }}</title>
+<style>
+html, body { margin: 0; height: 100%; background: #111; color: #eee; font-family: sans-serif; }
+#page { display: block; max-width: 100%; max-height: calc(100% - 2em); margin: 0 auto; }
+#status { height: 2em; line-height: 2em; text-align: center; user-select: none; }
+img.hidden { display: none; }
+</style>
+</head>
+<body>
+<div id="status"></div>
+{{ range $i, $src := .Pages }}<img class="hidden" id="page-{{ $i }}" src="{{ $src }}" alt="">
+{{ end }}<script>
+(function () {
+	var title = {{ .Title }};
+	var count = {{ len .Pages }};
+	var current = 0;
+
+	function show(index) {
+		if (index < 0 || index >= count) {
+			return;
+		}
+
+		document.getElementById("page-" + current).className = "hidden";
+		current = index;
+
+		var page = document.getElementById("page-" + current);
+		page.className = "";
+		document.getElementById("status").textContent = title + " — " + (current + 1) + "/" + count;
+		window.scrollTo(0, 0);
+		location.hash = String(current + 1);
+	}
+
+	document.addEventListener("keydown", function (event) {
+		switch (event.key) {
+		case "ArrowRight": case "ArrowDown": case " ": case "l": case "j":
+			show(current + 1); event.preventDefault(); break;
+		case "ArrowLeft": case "ArrowUp": case "h": case "k":
+			show(current - 1); event.preventDefault(); break;
+		case "Home": show(0); break;
+		case "End": show(count - 1); break;
+		}
+	});
+
+	document.addEventListener("click", function (event) {
+		show(event.clientX < window.innerWidth / 2 ? current - 1 : current + 1);
+	});
+
+	var touchX = null;
+	document.addEventListener("touchstart", function (event) {
+		touchX = event.changedTouches[0].clientX;
+	});
+
+	document.addEventListener("touchend", function (event) {
+		if (touchX === null) {
+			return;
+		}
+
+		var delta = event.changedTouches[0].clientX - touchX;
+		touchX = null;
+
+		if (Math.abs(delta) > 50) {
+			show(delta < 0 ? current + 1 : current - 1);
+		}
+	});
+
+	var start = parseInt(location.hash.slice(1), 10);
+	show(start > 0 && start <= count ? start - 1 : 0);
+})();
+</script>
+</body>
+</html>
+`))
+
+// saveHTML saves pages in FormatHTML
+func (c *Client) saveHTML(
+	chapter Chapter,
+	pages []PageWithImage,
+	out io.Writer,
+) error {
+	c.options.Log(fmt.Sprintf("Saving %d pages as HTML", len(pages)))
+
+	sources := make([]template.URL, len(pages))
+	for i, page := range pages {
+		image := page.GetImage()
+
+		// data URIs are safe here, the content type is detected from the image itself
+		sources[i] = template.URL(fmt.Sprintf(
+			"data:%s;base64,%s",
+			http.DetectContentType(image),
+			base64.StdEncoding.EncodeToString(image),
+		))
+	}
+
+	return htmlReaderTemplate.Execute(out, struct {
+		Title string
+		Pages []template.URL
+	}{
+		Title: chapter.String(),
+		Pages: sources,
+	})
+}
@@ -0,0 +1,86 @@
+package libmangal
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"github.com/spf13/afero"
+	"html"
+	"image/jpeg"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestDownloadChapterHTML(t *testing.T) {
+	provider := newFakeProvider(t, 1, 3)
+	client := newTestClient(t, provider)
+
+	options := testDownloadOptions()
+	options.Format = FormatHTML
+
+	result, err := client.DownloadChapter(context.Background(), provider.chapterList()[0], options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasSuffix(result.Path, ".html") {
+		t.Errorf("saved to %s, want the .html file", result.Path)
+	}
+
+	document, err := afero.ReadFile(client.options.FS, result.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// images are embedded, the reader doesn't need the network
+	sources := regexp.MustCompile(`src="data:image/jpeg;base64,([^"]+)"`).FindAllSubmatch(document, -1)
+	if len(sources) != 3 {
+		t.Fatalf("got %d embedded pages, want 3", len(sources))
+	}
+
+	for i, source := range sources {
+		// attribute values are HTML-escaped
+		image, err := base64.StdEncoding.DecodeString(html.UnescapeString(string(source[1])))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		config, err := jpeg.DecodeConfig(bytes.NewReader(image))
+		if err != nil || config.Width != 200 || config.Height != 300 {
+			t.Errorf("page #%d is not the page image: %v", i+1, err)
+		}
+	}
+
+	for _, id := range []string{`id="page-0"`, `id="page-1"`, `id="page-2"`} {
+		if !bytes.Contains(document, []byte(id)) {
+			t.Errorf("%s is missing", id)
+		}
+	}
+
+	if !bytes.Contains(document, []byte("var count =  3 ;")) {
+		t.Error("page count is missing")
+	}
+}
+
+func TestSaveHTMLEscapesTitle(t *testing.T) {
+	provider := newFakeProvider(t, 1, 1)
+	client := newTestClient(t, provider)
+
+	chapter := provider.chapterList()[0].(fakeChapter)
+	chapter.info.Title = `</script><b>"Chapter" & 1</b>`
+
+	var buffer bytes.Buffer
+	if err := client.saveHTML(chapter, testPages(1, provider.image), &buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	document := buffer.String()
+	if strings.Contains(document, "<b>") {
+		t.Error("title is not escaped")
+	}
+
+	if !strings.Contains(document, "<title>&lt;/script&gt;&lt;b&gt;&#34;Chapter&#34; &amp; 1&lt;/b&gt;</title>") {
+		t.Error("title is missing")
+	}
+}
@@ -0,0 +1,140 @@
+package libmangal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+)
+
+// ImageConverter converts page images of particular formats,
+// e.g. WebP or AVIF which some readers can't open inside CBZ.
+//
+// See DownloadOptions.ImageConverters
+type ImageConverter interface {
+	// Convert returns the converted image and its extension with the leading dot.
+	// False is returned if the image is left as is.
+	Convert(page PageWithImage) (converted []byte, extension string, ok bool, err error)
+}
+
+// FormatConverter converts images of the given formats to the encoding
+type FormatConverter struct {
+	// From are the formats to convert as named by the image package,
+	// e.g. "webp", or "avif" which is detected by its header.
+	//
+	// Decoders of the formats other than JPEG, PNG, GIF and WebP
+	// must be registered by importing a package that calls image.RegisterFormat.
+	// Images without the registered decoder are left as is.
+	From []string
+
+	// To is the encoding of the converted images.
+	// ImageEncodingOriginal means ImageEncodingPNG
+	To ImageEncoding
+
+	// JPEGQuality is the quality of the JPEG images from 1 to 100.
+	// Zero means jpeg.DefaultQuality
+	JPEGQuality int
+}
+
+// WebPToJPEG converts WebP images to JPEG of the given quality
+func WebPToJPEG(quality int) FormatConverter {
+	return FormatConverter{
+		From:        []string{"webp"},
+		To:          ImageEncodingJPEG,
+		JPEGQuality: quality,
+	}
+}
+
+func (f FormatConverter) Convert(page PageWithImage) ([]byte, string, bool, error) {
+	data := page.GetImage()
+	format := detectImageFormat(data)
+
+	var matches bool
+	for _, from := range f.From {
+		if from == format {
+			matches = true
+			break
+		}
+	}
+
+	if !matches {
+		return nil, "", false, nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", false, ImageDecodeError{
+			Page:        page,
+			ContentType: format,
+			error:       fmt.Errorf("%w (is the %s decoder registered?)", err, format),
+		}
+	}
+
+	encoding := f.To
+	if encoding == ImageEncodingOriginal {
+		encoding = ImageEncodingPNG
+	}
+
+	converted, err := encodeImage(img, encoding, f.JPEGQuality)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	return converted, encoding.Extension(), true, nil
+}
+
+// detectImageFormat returns the format name of the image data
+// as named by the image package. Formats without registered decoders
+// are detected by their headers: "avif" and "heic".
+// Empty string is returned if the format is unknown.
+func detectImageFormat(data []byte) string {
+	if _, format, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		return format
+	}
+
+	// ISO BMFF: size, "ftyp", major brand
+	if len(data) >= 12 && string(data[4:8]) == "ftyp" {
+		switch string(data[8:12]) {
+		case "avif", "avis":
+			return "avif"
+		case "heic", "heix", "mif1":
+			return "heic"
+		}
+	}
+
+	return ""
+}
+
+// convertImages applies converters to the pages in order.
+// Converted pages get the extension of their new encoding.
+//
+// Pages which format has no registered decoder are left as is.
+func convertImages(pages []PageWithImage, converters []ImageConverter, log LogFunc) error {
+	for i, page := range pages {
+		for _, converter := range converters {
+			converted, extension, ok, err := converter.Convert(page)
+			if errors.Is(err, image.ErrFormat) {
+				log(fmt.Sprintf("Leaving page %q as is: %s", page, err))
+				continue
+			}
+
+			if err != nil {
+				return fmt.Errorf("page %q: %w", page, err)
+			}
+
+			if !ok {
+				continue
+			}
+
+			page.SetImage(converted)
+			page = pageWithExtension{
+				PageWithImage: page,
+				extension:     extension,
+			}
+		}
+
+		pages[i] = page
+	}
+
+	return nil
+}
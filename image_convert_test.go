@@ -0,0 +1,89 @@
+package libmangal
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+)
+
+// testAVIF is the AVIF header without the image, there is no AVIF decoder anyway
+var testAVIF = append([]byte("\x00\x00\x00\x1cftypavif\x00\x00\x00\x00"), make([]byte, 16)...)
+
+func TestConvertImagesUnsupportedFormat(t *testing.T) {
+	pages := testPages(2, testAVIF)
+
+	converters := []ImageConverter{FormatConverter{
+		From: []string{"avif"},
+		To:   ImageEncodingPNG,
+	}}
+
+	var logged []string
+	if err := convertImages(pages, converters, func(msg string) { logged = append(logged, msg) }); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, page := range pages {
+		if !bytes.Equal(page.GetImage(), testAVIF) {
+			t.Errorf("page %q was changed", page)
+		}
+
+		if page.GetExtension() != ".jpg" {
+			t.Errorf("page %q was renamed to %s", page, page.GetExtension())
+		}
+	}
+
+	if len(logged) != len(pages) {
+		t.Errorf("got %d log messages, want %d", len(logged), len(pages))
+	}
+}
+
+func TestConvertImages(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := gif.Encode(&buffer, testImage(8, 8), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	pages := testPages(1, buffer.Bytes())
+	converters := []ImageConverter{FormatConverter{From: []string{"gif"}}}
+
+	if err := convertImages(pages, converters, func(string) {}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := detectImageFormat(pages[0].GetImage()); got != "png" {
+		t.Errorf("got %q image, want png", got)
+	}
+
+	if got := pages[0].GetExtension(); got != ".png" {
+		t.Errorf("got %s extension, want .png", got)
+	}
+}
+
+func TestPDFCompatibleImage(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := gif.Encode(&buffer, testImage(8, 8), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		image  []byte
+		format string
+	}{
+		{name: "jpeg", image: testJPEG(t, 8, 8), format: "jpeg"},
+		{name: "gif", image: buffer.Bytes(), format: "png"},
+		{name: "unsupported", image: testAVIF, format: "avif"},
+	}
+
+	for _, test := range tests {
+		image, err := pdfCompatibleImage(testPages(1, test.image)[0])
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		if got := detectImageFormat(image); got != test.format {
+			t.Errorf("%s: got %q image, want %q", test.name, got, test.format)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package libmangal
+
+import (
+	"bytes"
+	"errors"
+	_ "golang.org/x/image/webp"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"net/http"
+	"strings"
+)
+
+// Images are decoded with the image package, which
+// tries every registered decoder by matching its magic bytes.
+// Formats not supported out of the box (e.g. AVIF) can be added
+// by importing a package that calls image.RegisterFormat.
+
+// decodeImage decodes page image with the registered decoders
+func decodeImage(page PageWithImage) (image.Image, string, error) {
+	data := page.GetImage()
+
+	img, format, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", ImageDecodeError{
+			Page:        page,
+			ContentType: http.DetectContentType(data),
+			error:       err,
+		}
+	}
+
+	return img, format, nil
+}
+
+// imageFormat detects format of the page image without decoding it fully
+func imageFormat(page PageWithImage) (string, error) {
+	data := page.GetImage()
+
+	_, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", ImageDecodeError{
+			Page:        page,
+			ContentType: http.DetectContentType(data),
+			error:       err,
+		}
+	}
+
+	return format, nil
+}
+
+// PageValidation defines how downloaded page images are checked,
+// so that error pages served with image content types don't end up
+// in archives as corrupt pages. See PageCorruptError
+type PageValidation uint8
+
+const (
+	// PageValidationHeader checks the image header (magic bytes and dimensions).
+	// Data of unsupported formats passes if it's sniffed as an image.
+	PageValidationHeader PageValidation = iota
+
+	// PageValidationDecode decodes the whole image.
+	// It also catches truncated images, but is slower.
+	PageValidationDecode
+
+	// PageValidationNone skips validation
+	PageValidationNone
+)
+
+// validatePageImage returns PageCorruptError if the image data is not valid
+func validatePageImage(page Page, data []byte, validation PageValidation) error {
+	if validation == PageValidationNone {
+		return nil
+	}
+
+	contentType := http.DetectContentType(data)
+
+	_, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		// formats without registered decoders can't be checked further
+		if errors.Is(err, image.ErrFormat) && strings.HasPrefix(contentType, "image/") {
+			return nil
+		}
+
+		return PageCorruptError{Page: page, ContentType: contentType, error: err}
+	}
+
+	if validation == PageValidationDecode {
+		if _, _, err := image.Decode(bytes.NewReader(data)); err != nil {
+			return PageCorruptError{Page: page, ContentType: contentType, error: err}
+		}
+	}
+
+	return nil
+}
+
+// pdfCompatibleImage returns page image in the format supported by PDF.
+// JPEG and PNG images are returned as is, others are converted to PNG.
+//
+// Images which format has no registered decoder are returned as is too,
+// e.g. if they are in the format that PDF importer handles on its own.
+func pdfCompatibleImage(page PageWithImage) ([]byte, error) {
+	format, err := imageFormat(page)
+	if errors.Is(err, image.ErrFormat) {
+		return page.GetImage(), nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if format == "jpeg" || format == "png" {
+		return page.GetImage(), nil
+	}
+
+	img, _, err := decodeImage(page)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
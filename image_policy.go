@@ -0,0 +1,144 @@
+package libmangal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+)
+
+// ImageEncoding is the encoding of the images to save
+type ImageEncoding uint8
+
+const (
+	// ImageEncodingOriginal keeps the original image encoding
+	ImageEncodingOriginal ImageEncoding = iota
+
+	// ImageEncodingJPEG encodes images as JPEG
+	ImageEncodingJPEG
+
+	// ImageEncodingPNG encodes images as PNG
+	ImageEncodingPNG
+)
+
+// Extension returns extension of the encoding with the leading dot.
+// Returns empty string for ImageEncodingOriginal.
+func (i ImageEncoding) Extension() string {
+	switch i {
+	case ImageEncodingJPEG:
+		return ".jpg"
+	case ImageEncodingPNG:
+		return ".png"
+	default:
+		return ""
+	}
+}
+
+// ImagePolicy describes how images are normalized before saving.
+// See DownloadOptions.ImagePolicies
+type ImagePolicy struct {
+	// Encoding of the saved images
+	Encoding ImageEncoding
+
+	// JPEGQuality is the quality of the JPEG images from 1 to 100.
+	// Zero means jpeg.DefaultQuality
+	JPEGQuality int
+
+	// MaxWidth downscales images wider than this.
+	// Zero means no limit.
+	MaxWidth int
+}
+
+var _ PageWithImage = (*pageWithExtension)(nil)
+
+// pageWithExtension overrides extension of the page,
+// e.g. after its image was re-encoded
+type pageWithExtension struct {
+	PageWithImage
+	extension string
+}
+
+func (p pageWithExtension) GetExtension() string {
+	return p.extension
+}
+
+// apply normalizes the page image according to the policy.
+// Images that already satisfy the policy are left untouched.
+func (i ImagePolicy) apply(page PageWithImage) (PageWithImage, error) {
+	if i.Encoding == ImageEncodingOriginal && i.MaxWidth <= 0 {
+		return page, nil
+	}
+
+	img, format, err := decodeImage(page)
+	if errors.Is(err, image.ErrFormat) {
+		// no decoder for this format, keep the image as is
+		return page, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	resized := false
+	if i.MaxWidth > 0 && img.Bounds().Dx() > i.MaxWidth {
+		img = resizeToWidth(img, i.MaxWidth)
+		resized = true
+	}
+
+	// original encoding is kept unless the image had to be resized,
+	// e.g. WebP is not re-encoded as PNG
+	if !resized && i.Encoding == ImageEncodingOriginal {
+		return page, nil
+	}
+
+	encoding := i.Encoding
+	if encoding == ImageEncodingOriginal {
+		// resized images of the formats without encoders become PNG
+		if format == "jpeg" {
+			encoding = ImageEncodingJPEG
+		} else {
+			encoding = ImageEncodingPNG
+		}
+	}
+
+	// avoid lossy re-encoding when nothing changes
+	if !resized && ((encoding == ImageEncodingJPEG && format == "jpeg") || (encoding == ImageEncodingPNG && format == "png")) {
+		return page, nil
+	}
+
+	encoded, err := encodeImage(img, encoding, i.JPEGQuality)
+	if err != nil {
+		return nil, fmt.Errorf("page %q: %w", page, err)
+	}
+
+	page.SetImage(encoded)
+
+	return pageWithExtension{
+		PageWithImage: page,
+		extension:     encoding.Extension(),
+	}, nil
+}
+
+// encodeImage encodes the image as JPEG or PNG.
+// Zero quality means jpeg.DefaultQuality
+func encodeImage(img image.Image, encoding ImageEncoding, quality int) ([]byte, error) {
+	var (
+		buffer bytes.Buffer
+		err    error
+	)
+
+	switch encoding {
+	case ImageEncodingJPEG:
+		if quality <= 0 {
+			quality = jpeg.DefaultQuality
+		}
+
+		err = jpeg.Encode(&buffer, img, &jpeg.Options{Quality: quality})
+	default:
+		err = png.Encode(&buffer, img)
+	}
+
+	return buffer.Bytes(), err
+}
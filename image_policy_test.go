@@ -0,0 +1,67 @@
+package libmangal
+
+import (
+	"bytes"
+	"image/gif"
+	"testing"
+)
+
+func TestImagePolicy(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := gif.Encode(&buffer, testImage(100, 50), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	gifImage := buffer.Bytes()
+	jpegImage := testJPEG(t, 100, 50)
+
+	tests := []struct {
+		name      string
+		policy    ImagePolicy
+		image     []byte
+		unchanged bool
+		format    string
+		width     int
+	}{
+		{name: "original", policy: ImagePolicy{}, image: gifImage, unchanged: true},
+		{name: "original narrow", policy: ImagePolicy{MaxWidth: 200}, image: gifImage, unchanged: true},
+		{name: "original resized", policy: ImagePolicy{MaxWidth: 50}, image: gifImage, format: "png", width: 50},
+		{name: "original resized jpeg", policy: ImagePolicy{MaxWidth: 50}, image: jpegImage, format: "jpeg", width: 50},
+		{name: "jpeg as jpeg", policy: ImagePolicy{Encoding: ImageEncodingJPEG}, image: jpegImage, unchanged: true},
+		{name: "gif as jpeg", policy: ImagePolicy{Encoding: ImageEncodingJPEG}, image: gifImage, format: "jpeg", width: 100},
+		{name: "jpeg as png", policy: ImagePolicy{Encoding: ImageEncodingPNG}, image: jpegImage, format: "png", width: 100},
+		{name: "unsupported", policy: ImagePolicy{Encoding: ImageEncodingJPEG}, image: testAVIF, unchanged: true},
+	}
+
+	for _, test := range tests {
+		page := testPages(1, test.image)[0]
+
+		applied, err := test.policy.apply(page)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		if test.unchanged {
+			if !bytes.Equal(applied.GetImage(), test.image) || applied.GetExtension() != page.GetExtension() {
+				t.Errorf("%s: image was changed", test.name)
+			}
+
+			continue
+		}
+
+		img, format, err := decodeImage(applied)
+		if err != nil {
+			t.Errorf("%s: %s", test.name, err)
+			continue
+		}
+
+		if format != test.format {
+			t.Errorf("%s: got %s image, want %s", test.name, format, test.format)
+		}
+
+		if width := img.Bounds().Dx(); width != test.width {
+			t.Errorf("%s: got width %d, want %d", test.name, width, test.width)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package libmangal
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+type imageSizeLimitKey struct{}
+
+// contextWithImageSizeLimit records the maximum image size for
+// the provider calls, see ImageSizeLimit
+func contextWithImageSizeLimit(ctx context.Context, limit int64) context.Context {
+	if limit <= 0 {
+		return ctx
+	}
+
+	return context.WithValue(ctx, imageSizeLimitKey{}, limit)
+}
+
+// ImageSizeLimit returns ClientOptions.MaxImageSize passed to
+// Provider.GetPageImage in its context. Providers should stop reading
+// images exceeding it, see NewImageSizeLimitReader and NewImageSizeLimitTransport.
+//
+// Returns false if there is no limit.
+func ImageSizeLimit(ctx context.Context) (int64, bool) {
+	limit, ok := ctx.Value(imageSizeLimitKey{}).(int64)
+	return limit, ok
+}
+
+// NewImageSizeLimitReader wraps the reader, so that it returns SizeLimitError
+// as soon as more than ImageSizeLimit bytes are read.
+// The reader is returned as is if the context has no limit.
+func NewImageSizeLimitReader(ctx context.Context, reader io.Reader) io.Reader {
+	limit, ok := ImageSizeLimit(ctx)
+	if !ok {
+		return reader
+	}
+
+	return &sizeLimitReader{
+		reader:    reader,
+		limit:     limit,
+		remaining: limit,
+	}
+}
+
+// sizeLimitReader reads up to the limit and fails
+// instead of reading past it
+type sizeLimitReader struct {
+	reader           io.Reader
+	limit, remaining int64
+}
+
+func (s *sizeLimitReader) Read(p []byte) (int, error) {
+	if s.remaining < 0 {
+		return 0, SizeLimitError{Limit: s.limit}
+	}
+
+	// read one extra byte to detect that the limit was exceeded
+	if int64(len(p)) > s.remaining+1 {
+		p = p[:s.remaining+1]
+	}
+
+	n, err := s.reader.Read(p)
+	s.remaining -= int64(n)
+
+	if s.remaining < 0 {
+		return n - 1, SizeLimitError{Limit: s.limit}
+	}
+
+	return n, err
+}
+
+var _ http.RoundTripper = (*ImageSizeLimitTransport)(nil)
+
+// ImageSizeLimitTransport limits response bodies of the requests
+// with ImageSizeLimit in their context, so that oversized images
+// are not buffered before ClientOptions.MaxImageSize is checked.
+//
+// Providers should use it for the image requests made with the context
+// passed to Provider.GetPageImage.
+type ImageSizeLimitTransport struct {
+	next http.RoundTripper
+}
+
+// NewImageSizeLimitTransport wraps the transport.
+// Nil next means http.DefaultTransport
+func NewImageSizeLimitTransport(next http.RoundTripper) *ImageSizeLimitTransport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &ImageSizeLimitTransport{next: next}
+}
+
+// RoundTrip implements http.RoundTripper
+func (i *ImageSizeLimitTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	response, err := i.next.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, ok := ImageSizeLimit(request.Context())
+	if !ok {
+		return response, nil
+	}
+
+	// fail early if the server announces an oversized body
+	if err := checkSizeLimit(response.ContentLength, limit); err != nil {
+		_ = response.Body.Close()
+		return nil, err
+	}
+
+	response.Body = struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: NewImageSizeLimitReader(request.Context(), response.Body),
+		Closer: response.Body,
+	}
+
+	return response, nil
+}
@@ -0,0 +1,155 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// streamingProvider downloads page images from the server
+// the way providers are expected to
+type streamingProvider struct {
+	*fakeProvider
+	client *http.Client
+	url    string
+}
+
+func (s *streamingProvider) Load(context.Context) (Provider, error) {
+	return s, nil
+}
+
+func (s *streamingProvider) GetPageImage(ctx context.Context, _ LogFunc, _ Page) ([]byte, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	return io.ReadAll(response.Body)
+}
+
+func TestDownloadPageImageSizeLimit(t *testing.T) {
+	const limit = 1 << 10
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// endless image without the content length
+		chunk := []byte(strings.Repeat("x", 512))
+		for r.Context().Err() == nil {
+			if _, err := w.Write(chunk); err != nil {
+				return
+			}
+
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer server.Close()
+
+	provider := &streamingProvider{
+		fakeProvider: newFakeProvider(t, 1, 1),
+		client:       &http.Client{Transport: NewImageSizeLimitTransport(nil)},
+		url:          server.URL,
+	}
+
+	options := testClientOptions()
+	options.MaxImageSize = limit
+
+	client, err := NewClient(context.Background(), provider, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chapters := provider.chapterList()
+	pages, err := provider.ChapterPages(context.Background(), nil, chapters[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.DownloadPage(context.Background(), pages[0])
+
+	var sizeErr SizeLimitError
+	if !errors.As(err, &sizeErr) {
+		t.Fatalf("got %v, want SizeLimitError", err)
+	}
+
+	if sizeErr.Limit != limit {
+		t.Errorf("got limit %d, want %d", sizeErr.Limit, limit)
+	}
+}
+
+func TestImageSizeLimitTransportContentLength(t *testing.T) {
+	var closed bool
+	transport := NewImageSizeLimitTransport(roundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode:    http.StatusOK,
+			ContentLength: 100,
+			Body: struct {
+				io.Reader
+				io.Closer
+			}{
+				Reader: strings.NewReader(strings.Repeat("x", 100)),
+				Closer: closerFunc(func() error { closed = true; return nil }),
+			},
+		}, nil
+	}))
+
+	ctx := contextWithImageSizeLimit(context.Background(), 10)
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := transport.RoundTrip(request); !errors.As(err, &SizeLimitError{}) {
+		t.Errorf("got %v, want SizeLimitError", err)
+	}
+
+	if !closed {
+		t.Error("body of the oversized response was not closed")
+	}
+}
+
+func TestImageSizeLimitReader(t *testing.T) {
+	ctx := contextWithImageSizeLimit(context.Background(), 10)
+
+	data, err := io.ReadAll(NewImageSizeLimitReader(ctx, strings.NewReader(strings.Repeat("x", 10))))
+	if err != nil || len(data) != 10 {
+		t.Errorf("got %d bytes and %v for the image of the limit size", len(data), err)
+	}
+
+	data, err = io.ReadAll(NewImageSizeLimitReader(ctx, strings.NewReader(strings.Repeat("x", 11))))
+	if !errors.As(err, &SizeLimitError{}) {
+		t.Errorf("got %v, want SizeLimitError", err)
+	}
+
+	if len(data) > 10 {
+		t.Errorf("read %d bytes past the limit", len(data)-10)
+	}
+
+	reader := strings.NewReader("x")
+	if NewImageSizeLimitReader(context.Background(), reader) != io.Reader(reader) {
+		t.Error("reader without the limit was wrapped")
+	}
+}
+
+type closerFunc func() error
+
+func (c closerFunc) Close() error {
+	return c()
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+var errTestUnreachable = errors.New("unreachable")
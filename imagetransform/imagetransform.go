@@ -0,0 +1,304 @@
+// Package imagetransform provides composable image transformations
+// that can be assigned to libmangal.DownloadOptions.ImageTransformer:
+//
+//	options.ImageTransformer = imagetransform.New(
+//		imagetransform.TrimBorders(16),
+//		imagetransform.MaxWidth(1200),
+//		imagetransform.Grayscale(),
+//	)
+//
+// Images are decoded once, transformed by each Op in order
+// and encoded back in their original format.
+package imagetransform
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+)
+
+// JPEGQuality is the quality of the re-encoded JPEG images
+const JPEGQuality = 90
+
+// Transformer transforms the image contents.
+// It's the type of libmangal.DownloadOptions.ImageTransformer
+type Transformer func([]byte) ([]byte, error)
+
+// Op is a single transformation of the decoded image
+type Op func(image.Image) (image.Image, error)
+
+// New constructs the Transformer that applies ops in order.
+//
+// Images of formats without a registered decoder or without
+// an encoder in the standard library (e.g. WebP) are left as is,
+// so that their contents keep matching the page extension.
+func New(ops ...Op) Transformer {
+	return func(data []byte) ([]byte, error) {
+		img, format, err := image.Decode(bytes.NewReader(data))
+		if errors.Is(err, image.ErrFormat) {
+			return data, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		switch format {
+		case "jpeg", "png", "gif":
+		default:
+			return data, nil
+		}
+
+		for _, op := range ops {
+			img, err = op(img)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return encode(img, format)
+	}
+}
+
+// Chain composes transformers, applying them in order
+func Chain(transformers ...Transformer) Transformer {
+	return func(data []byte) ([]byte, error) {
+		var err error
+		for _, transformer := range transformers {
+			data, err = transformer(data)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return data, nil
+	}
+}
+
+func encode(img image.Image, format string) ([]byte, error) {
+	var buffer bytes.Buffer
+
+	var err error
+	switch format {
+	case "jpeg":
+		err = jpeg.Encode(&buffer, img, &jpeg.Options{Quality: JPEGQuality})
+	case "png":
+		err = png.Encode(&buffer, img)
+	case "gif":
+		err = gif.Encode(&buffer, img, nil)
+	default:
+		err = fmt.Errorf("unsupported format: %s", format)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// Grayscale converts the image to grayscale
+func Grayscale() Op {
+	return func(src image.Image) (image.Image, error) {
+		if _, ok := src.(*image.Gray); ok {
+			return src, nil
+		}
+
+		bounds := src.Bounds()
+		dst := image.NewGray(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		draw.Draw(dst, dst.Bounds(), src, bounds.Min, draw.Src)
+
+		return dst, nil
+	}
+}
+
+// TrimBorders crops white margins around the content.
+// Pixels which channels all differ from white by no more
+// than tolerance (0-255) are considered white, which accounts
+// for the scan noise and compression artifacts.
+//
+// Blank pages are left as is.
+func TrimBorders(tolerance uint8) Op {
+	threshold := uint32(255-tolerance) * 0x101
+
+	isWhite := func(c color.Color) bool {
+		r, g, b, _ := c.RGBA()
+		return r >= threshold && g >= threshold && b >= threshold
+	}
+
+	return func(src image.Image) (image.Image, error) {
+		bounds := src.Bounds()
+
+		rowIsWhite := func(y int) bool {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				if !isWhite(src.At(x, y)) {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		columnIsWhite := func(x, minY, maxY int) bool {
+			for y := minY; y < maxY; y++ {
+				if !isWhite(src.At(x, y)) {
+					return false
+				}
+			}
+
+			return true
+		}
+
+		minY, maxY := bounds.Min.Y, bounds.Max.Y
+		for minY < maxY && rowIsWhite(minY) {
+			minY++
+		}
+
+		if minY == maxY {
+			return src, nil
+		}
+
+		for rowIsWhite(maxY - 1) {
+			maxY--
+		}
+
+		minX, maxX := bounds.Min.X, bounds.Max.X
+		for columnIsWhite(minX, minY, maxY) {
+			minX++
+		}
+
+		for columnIsWhite(maxX-1, minY, maxY) {
+			maxX--
+		}
+
+		return crop(src, image.Rect(minX, minY, maxX, maxY)), nil
+	}
+}
+
+func crop(src image.Image, rect image.Rectangle) image.Image {
+	if rect == src.Bounds() {
+		return src
+	}
+
+	if sub, ok := src.(interface {
+		SubImage(image.Rectangle) image.Image
+	}); ok {
+		return sub.SubImage(rect)
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(dst, dst.Bounds(), src, rect.Min, draw.Src)
+
+	return dst
+}
+
+// MaxWidth downscales images wider than width preserving aspect ratio.
+// Each resulting pixel is the average of the source pixels it covers.
+func MaxWidth(width int) Op {
+	return func(src image.Image) (image.Image, error) {
+		if width <= 0 {
+			return nil, fmt.Errorf("invalid width: %d", width)
+		}
+
+		if src.Bounds().Dx() <= width {
+			return src, nil
+		}
+
+		return resize(src, width), nil
+	}
+}
+
+func resize(src image.Image, width int) image.Image {
+	bounds := src.Bounds()
+
+	height := bounds.Dy() * width / bounds.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*bounds.Dy()/height
+		y1 := bounds.Min.Y + (y+1)*bounds.Dy()/height
+		if y1 <= y0 {
+			y1 = y0 + 1
+		}
+
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*bounds.Dx()/width
+			x1 := bounds.Min.X + (x+1)*bounds.Dx()/width
+			if x1 <= x0 {
+				x1 = x0 + 1
+			}
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+
+			dst.SetRGBA(x, y, color.RGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+
+	return dst
+}
+
+// Rotate rotates the image clockwise by degrees,
+// which must be a multiple of 90, e.g. -90 or 180
+func Rotate(degrees int) Op {
+	return func(src image.Image) (image.Image, error) {
+		if degrees%90 != 0 {
+			return nil, fmt.Errorf("rotation must be a multiple of 90 degrees, got %d", degrees)
+		}
+
+		turns := (degrees/90%4 + 4) % 4
+		if turns == 0 {
+			return src, nil
+		}
+
+		bounds := src.Bounds()
+		w, h := bounds.Dx(), bounds.Dy()
+
+		dstBounds := image.Rect(0, 0, h, w)
+		if turns == 2 {
+			dstBounds = image.Rect(0, 0, w, h)
+		}
+
+		dst := image.NewRGBA(dstBounds)
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				c := src.At(bounds.Min.X+x, bounds.Min.Y+y)
+
+				switch turns {
+				case 1:
+					dst.Set(h-1-y, x, c)
+				case 2:
+					dst.Set(w-1-x, h-1-y, c)
+				case 3:
+					dst.Set(y, w-1-x, c)
+				}
+			}
+		}
+
+		return dst, nil
+	}
+}
@@ -0,0 +1,285 @@
+package imagetransform
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// testPNG encodes the white image of the given size with
+// the red and blue pixels at the given points
+func testPNG(tb testing.TB, width, height int, red, blue image.Point) []byte {
+	tb.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.White)
+		}
+	}
+
+	img.Set(red.X, red.Y, color.RGBA{R: 255, A: 255})
+	img.Set(blue.X, blue.Y, color.RGBA{B: 255, A: 255})
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		tb.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func testPhoto(tb testing.TB, width, height int) []byte {
+	tb.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, img, nil); err != nil {
+		tb.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func decode(tb testing.TB, data []byte) image.Image {
+	tb.Helper()
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return img
+}
+
+func isColor(c color.Color, r, g, b uint32) bool {
+	cr, cg, cb, _ := c.RGBA()
+	return cr>>8 == r && cg>>8 == g && cb>>8 == b
+}
+
+func TestTrimBordersAndRotate(t *testing.T) {
+	data := testPNG(t, 10, 6, image.Pt(2, 1), image.Pt(5, 3))
+
+	transformed, err := New(TrimBorders(10), Rotate(90))(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	img := decode(t, transformed)
+	bounds := img.Bounds()
+
+	// content is 4x3, rotated it's 3x4
+	if bounds.Dx() != 3 || bounds.Dy() != 4 {
+		t.Fatalf("got %v, want 3x4", bounds)
+	}
+
+	if !isColor(img.At(bounds.Max.X-1, bounds.Min.Y), 255, 0, 0) {
+		t.Error("red pixel is not in the top right corner")
+	}
+
+	if !isColor(img.At(bounds.Min.X, bounds.Max.Y-1), 0, 0, 255) {
+		t.Error("blue pixel is not in the bottom left corner")
+	}
+}
+
+func TestTrimBordersBlankPage(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+
+	trimmed, err := TrimBorders(0)(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if trimmed.Bounds() != img.Bounds() {
+		t.Errorf("blank page was cropped to %v", trimmed.Bounds())
+	}
+}
+
+func TestRotate(t *testing.T) {
+	tests := []struct {
+		degrees       int
+		width, height int
+		wantErr       bool
+	}{
+		{degrees: 0, width: 4, height: 2},
+		{degrees: 90, width: 2, height: 4},
+		{degrees: -90, width: 2, height: 4},
+		{degrees: 180, width: 4, height: 2},
+		{degrees: 270, width: 2, height: 4},
+		{degrees: 360, width: 4, height: 2},
+		{degrees: 45, wantErr: true},
+	}
+
+	src := image.NewRGBA(image.Rect(0, 0, 4, 2))
+	for _, test := range tests {
+		rotated, err := Rotate(test.degrees)(src)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("Rotate(%d): expected error", test.degrees)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("Rotate(%d): %s", test.degrees, err)
+			continue
+		}
+
+		if got := rotated.Bounds(); got.Dx() != test.width || got.Dy() != test.height {
+			t.Errorf("Rotate(%d): got %v, want %dx%d", test.degrees, got, test.width, test.height)
+		}
+	}
+}
+
+func TestMaxWidth(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 100, 50))
+
+	resized, err := MaxWidth(40)(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := resized.Bounds(); got.Dx() != 40 || got.Dy() != 20 {
+		t.Errorf("got %v, want 40x20", got)
+	}
+
+	kept, err := MaxWidth(200)(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if kept != image.Image(src) {
+		t.Error("narrower image was resized")
+	}
+
+	if _, err := MaxWidth(0)(src); err == nil {
+		t.Error("expected error for zero width")
+	}
+}
+
+func TestGrayscale(t *testing.T) {
+	transformed, err := New(Grayscale())(testPhoto(t, 8, 8))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := decode(t, transformed).(*image.Gray); !ok {
+		t.Errorf("got %T, want *image.Gray", decode(t, transformed))
+	}
+}
+
+func TestNewKeepsUnknownFormats(t *testing.T) {
+	data := []byte("not an image")
+
+	transformed, err := New(Grayscale())(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(transformed, data) {
+		t.Error("unknown format was changed")
+	}
+}
+
+func TestChain(t *testing.T) {
+	var calls []int
+	transformer := func(n int) Transformer {
+		return func(data []byte) ([]byte, error) {
+			calls = append(calls, n)
+			return data, nil
+		}
+	}
+
+	if _, err := Chain(transformer(1), transformer(2))(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(calls) != 2 || calls[0] != 1 || calls[1] != 2 {
+		t.Errorf("got calls %v, want [1 2]", calls)
+	}
+}
+
+func BenchmarkGrayscale(b *testing.B) {
+	benchmarkTransformer(b, New(Grayscale()))
+}
+
+func BenchmarkTrimBorders(b *testing.B) {
+	benchmarkTransformer(b, New(TrimBorders(16)))
+}
+
+func BenchmarkMaxWidth(b *testing.B) {
+	benchmarkTransformer(b, New(MaxWidth(400)))
+}
+
+func BenchmarkRotate(b *testing.B) {
+	benchmarkTransformer(b, New(Rotate(90)))
+}
+
+// benchmarkTransformer runs the transformer on the 800x1200 JPEG page
+func benchmarkTransformer(b *testing.B, transformer Transformer) {
+	data := testPhoto(b, 800, 1200)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := transformer(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// performanceBudget is the upper bound of allocations per operation of the benchmark.
+// Budgets are about twice the measured values, so that only regressions fail them.
+type performanceBudget struct {
+	name      string
+	benchmark func(*testing.B)
+	allocs    int64
+	bytes     int64
+}
+
+func TestPerformanceBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("performance budget is not checked in short mode")
+	}
+
+	if raceEnabled {
+		t.Skip("performance budget is not checked with the race detector")
+	}
+
+	budgets := []performanceBudget{
+		{name: "Grayscale", benchmark: BenchmarkGrayscale, allocs: 64, bytes: 5_000_000},
+		{name: "TrimBorders", benchmark: BenchmarkTrimBorders, allocs: 64, bytes: 3_500_000},
+		{name: "MaxWidth", benchmark: BenchmarkMaxWidth, allocs: 1_500_000, bytes: 11_000_000},
+		{name: "Rotate", benchmark: BenchmarkRotate, allocs: 3_000_000, bytes: 26_000_000},
+	}
+
+	for _, budget := range budgets {
+		result := testing.Benchmark(budget.benchmark)
+		if result.N == 0 {
+			t.Errorf("%s: benchmark failed", budget.name)
+			continue
+		}
+
+		if allocs := result.AllocsPerOp(); allocs > budget.allocs {
+			t.Errorf("%s: %d allocs/op exceeds the budget of %d", budget.name, allocs, budget.allocs)
+		}
+
+		if bytes := result.AllocedBytesPerOp(); bytes > budget.bytes {
+			t.Errorf("%s: %d B/op exceeds the budget of %d", budget.name, bytes, budget.bytes)
+		}
+	}
+}
@@ -0,0 +1,5 @@
+//go:build !race
+
+package imagetransform
+
+const raceEnabled = false
@@ -0,0 +1,247 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ImportOptions configures Client.ImportArchive
+type ImportOptions struct {
+	// Title of the manga. Guessed from the archive name if empty.
+	// It's used to match the manga on Anilist.
+	Title string
+
+	// Number of the chapter. Guessed from the archive name if zero.
+	Number float32
+
+	// Volume number of the chapter
+	Volume int
+
+	// DownloadOptions define the library layout, format and metadata of
+	// the imported chapter the same way as for the downloaded ones.
+	DownloadOptions DownloadOptions
+}
+
+// DefaultImportOptions constructs default ImportOptions
+func DefaultImportOptions() ImportOptions {
+	options := DefaultDownloadOptions()
+	options.Format = FormatCBZ
+	options.WriteComicInfoXml = true
+	options.Strict = false
+
+	return ImportOptions{
+		Volume:          1,
+		DownloadOptions: options,
+	}
+}
+
+var (
+	// importChapterRegex matches chapter numbers like "Chapter 12", "Ch. 12.5" or "c012"
+	importChapterRegex = regexp.MustCompile(`(?i)(?:chapter|ch\.?|\bc)\s*(\d+(?:\.\d+)?)`)
+
+	// importVolumeRegex matches volume numbers like "Vol. 2" or "v02"
+	importVolumeRegex = regexp.MustCompile(`(?i)(?:volume|vol\.?|\bv)\s*\d+`)
+
+	// importNumberRegex matches any number
+	importNumberRegex = regexp.MustCompile(`\d+(?:\.\d+)?`)
+
+	// importTagRegex matches tags like "[Group]" or "(Digital)"
+	importTagRegex = regexp.MustCompile(`\[[^\]]*\]|\([^)]*\)`)
+)
+
+// guessImportName guesses manga title and chapter number from the archive name,
+// e.g. "Berserk - Chapter 12.cbz" or "Berserk v01 c012.cbz"
+func guessImportName(path string) (title string, number float32) {
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	// tags may contain numbers, e.g. "[v2]"
+	name = importTagRegex.ReplaceAllString(name, "")
+
+	var numberIndex []int
+	if match := importChapterRegex.FindStringSubmatchIndex(name); match != nil {
+		numberIndex = []int{match[0], match[2], match[3]}
+	} else if matches := importNumberRegex.FindAllStringIndex(name, -1); matches != nil {
+		last := matches[len(matches)-1]
+		numberIndex = []int{last[0], last[0], last[1]}
+	}
+
+	if numberIndex == nil {
+		return strings.Trim(name, " -_#"), 0
+	}
+
+	parsed, _ := strconv.ParseFloat(name[numberIndex[1]:numberIndex[2]], 32)
+
+	title = importVolumeRegex.ReplaceAllString(name[:numberIndex[0]], "")
+	title = strings.Trim(title, " -_#")
+
+	return title, float32(parsed)
+}
+
+// ImportArchive imports an existing chapter archive (e.g. downloaded elsewhere)
+// into the library. The chapter is matched with Anilist, named and saved
+// with the fresh metadata the same way as the downloaded chapters are.
+//
+// Supported archives are CBZ, ZIP, CBR and RAR.
+func (c *Client) ImportArchive(
+	ctx context.Context,
+	path string,
+	options ImportOptions,
+) (DownloadResult, error) {
+	title, number := guessImportName(path)
+	if options.Title != "" {
+		title = options.Title
+	}
+
+	if options.Number != 0 {
+		number = options.Number
+	}
+
+	if title == "" {
+		return DownloadResult{}, fmt.Errorf("can't guess manga title of %s", path)
+	}
+
+	c.options.Log(fmt.Sprintf("Importing %s as %q chapter %v", path, title, number))
+
+	images, err := readArchiveImages(c.options.FS, path)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+
+	if len(images) == 0 {
+		return DownloadResult{}, fmt.Errorf("no images found in %s", path)
+	}
+
+	manga := &importedManga{info: MangaInfo{
+		Title: title,
+		ID:    title,
+	}}
+
+	volume := &importedVolume{
+		info:  VolumeInfo{Number: options.Volume},
+		manga: manga,
+	}
+
+	chapter := &importedChapter{
+		info: ChapterInfo{
+			Title:  fmt.Sprintf("Chapter %v", number),
+			Number: number,
+		},
+		volume: volume,
+	}
+
+	for _, image := range images {
+		chapter.pages = append(chapter.pages, &pageWithImage{
+			Page: &importedPage{
+				extension: strings.ToLower(filepath.Ext(image.name)),
+				chapter:   chapter,
+			},
+			image: image.image,
+		})
+	}
+
+	// imported chapter is served by the provider
+	// that reads it from the archive
+	importClient := Client{
+		provider:   importProvider{Provider: c.provider},
+		options:    c.options,
+		log:        c.log,
+		pageCache:  c.pageCache,
+		usage:      c.usage,
+		categories: c.categories,
+
+		chaptersJSONMu: c.chaptersJSONMu,
+	}
+
+	return importClient.DownloadChapter(ctx, chapter, options.DownloadOptions)
+}
+
+// importProvider serves pages of the imported chapters.
+// Info is the same as of the client's provider to keep naming consistent.
+type importProvider struct {
+	Provider
+}
+
+func (importProvider) ChapterPages(_ context.Context, _ LogFunc, chapter Chapter) ([]Page, error) {
+	imported, ok := chapter.(*importedChapter)
+	if !ok {
+		return nil, fmt.Errorf("chapter %q is not imported", chapter)
+	}
+
+	return imported.pages, nil
+}
+
+var (
+	_ Provider = importProvider{}
+	_ Manga    = (*importedManga)(nil)
+	_ Volume   = (*importedVolume)(nil)
+	_ Chapter  = (*importedChapter)(nil)
+	_ Page     = (*importedPage)(nil)
+)
+
+type importedManga struct {
+	info MangaInfo
+}
+
+func (m *importedManga) String() string {
+	return m.info.Title
+}
+
+func (m *importedManga) Info() MangaInfo {
+	return m.info
+}
+
+type importedVolume struct {
+	info  VolumeInfo
+	manga *importedManga
+}
+
+func (v *importedVolume) String() string {
+	return fmt.Sprintf("Volume %d", v.info.Number)
+}
+
+func (v *importedVolume) Info() VolumeInfo {
+	return v.info
+}
+
+func (v *importedVolume) Manga() Manga {
+	return v.manga
+}
+
+type importedChapter struct {
+	info   ChapterInfo
+	volume *importedVolume
+	pages  []Page
+}
+
+func (c *importedChapter) String() string {
+	return c.info.Title
+}
+
+func (c *importedChapter) Info() ChapterInfo {
+	return c.info
+}
+
+func (c *importedChapter) Volume() Volume {
+	return c.volume
+}
+
+type importedPage struct {
+	extension string
+	chapter   *importedChapter
+}
+
+func (p *importedPage) String() string {
+	return fmt.Sprintf("%s page", p.chapter)
+}
+
+func (p *importedPage) GetExtension() string {
+	return p.extension
+}
+
+func (p *importedPage) Chapter() Chapter {
+	return p.chapter
+}
@@ -0,0 +1,139 @@
+package libmangal
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"github.com/spf13/afero"
+	"strings"
+	"testing"
+)
+
+func TestGuessImportName(t *testing.T) {
+	for _, test := range []struct {
+		path   string
+		title  string
+		number float32
+	}{
+		{"/downloads/Berserk - Chapter 12.cbz", "Berserk", 12},
+		{"Berserk v01 c012.cbz", "Berserk", 12},
+		{"One Piece Ch. 1000.5.zip", "One Piece", 1000.5},
+		{"[Group] Spy x Family 045.cbz", "Spy x Family", 45},
+		{"Vinland Saga (Digital) - Chapter 3 [Group].cbz", "Vinland Saga", 3},
+		{"Spy x Family 045 [v2].cbz", "Spy x Family", 45},
+		{"Oneshot.cbz", "Oneshot", 0},
+		{"Oneshot (Digital).cbz", "Oneshot", 0},
+	} {
+		title, number := guessImportName(test.path)
+		if title != test.title || number != test.number {
+			t.Errorf("%s: got %q %v, want %q %v", test.path, title, number, test.title, test.number)
+		}
+	}
+}
+
+// writeTestZIP writes the archive with the files in order
+func writeTestZIP(t *testing.T, fs afero.Fs, path string, names []string, files [][]byte) {
+	t.Helper()
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+
+	for i, name := range names {
+		entry, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := entry.Write(files[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := afero.WriteFile(fs, path, buffer.Bytes(), modeFile); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadArchiveImages(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	// pages come sorted, other files are skipped
+	writeTestZIP(t, fs, "/chapter.CBZ", []string{
+		"002.PNG",
+		"001.jpg",
+		"ComicInfo.xml",
+		"notes.txt",
+		"__MACOSX/._001.jpg",
+		"pages/.hidden.jpg",
+	}, [][]byte{[]byte("2"), []byte("1"), nil, nil, nil, nil})
+
+	images, err := readArchiveImages(fs, "/chapter.CBZ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, image := range images {
+		names = append(names, image.name+"="+string(image.image))
+	}
+
+	if got := strings.Join(names, " "); got != "001.jpg=1 002.PNG=2" {
+		t.Errorf("got %s, want 001.jpg=1 002.PNG=2", got)
+	}
+
+	if _, err := readArchiveImages(fs, "/chapter.7z"); err == nil {
+		t.Error("expected an error for unsupported archive")
+	}
+}
+
+func TestImportArchive(t *testing.T) {
+	provider := newFakeProvider(t, 0, 0)
+	client := newTestClient(t, provider)
+
+	page := testJPEG(t, 100, 100)
+	writeTestZIP(t, client.options.FS, "/downloads/Berserk - Chapter 12.cbz",
+		[]string{"b.jpg", "a.jpg", "readme.txt"},
+		[][]byte{provider.image, page, []byte("downloaded elsewhere")},
+	)
+
+	options := DefaultImportOptions()
+	options.DownloadOptions = testDownloadOptions()
+	options.Volume = 3
+
+	result, err := client.ImportArchive(context.Background(), "/downloads/Berserk - Chapter 12.cbz", options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(result.Path, "/library/") || !strings.Contains(result.Path, "Berserk") {
+		t.Errorf("imported to %s, want the library manga directory", result.Path)
+	}
+
+	if result.PageCount != 2 || provider.requests.Load() != 0 {
+		t.Errorf("got %d pages and %d provider requests, want 2 pages from the archive", result.PageCount, provider.requests.Load())
+	}
+
+	images, err := readArchiveImages(client.options.FS, result.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(images) != 2 || !bytes.Equal(images[0].image, page) || !bytes.Equal(images[1].image, provider.image) {
+		t.Errorf("got %d imported pages, want a.jpg and b.jpg in order", len(images))
+	}
+
+	// empty archives and unknown titles are rejected
+	writeTestZIP(t, client.options.FS, "/downloads/Empty - Chapter 1.cbz", []string{"readme.txt"}, [][]byte{nil})
+
+	if _, err := client.ImportArchive(context.Background(), "/downloads/Empty - Chapter 1.cbz", options); err == nil {
+		t.Error("expected an error for the archive without images")
+	}
+
+	if _, err := client.ImportArchive(context.Background(), "/downloads/012.cbz", options); err == nil {
+		t.Error("expected an error for the archive without the title")
+	}
+}
@@ -0,0 +1,34 @@
+package libmangal
+
+import "io"
+
+// copyLimited copies from src to dst until either EOF is reached
+// or more than limit bytes were read, in which case SizeLimitError is returned.
+// Limit of zero or less means no limit.
+func copyLimited(dst io.Writer, src io.Reader, limit int64) (int64, error) {
+	if limit <= 0 {
+		return io.Copy(dst, src)
+	}
+
+	// read one extra byte to detect that the limit was exceeded
+	n, err := io.Copy(dst, io.LimitReader(src, limit+1))
+	if err != nil {
+		return n, err
+	}
+
+	if n > limit {
+		return n, SizeLimitError{Limit: limit}
+	}
+
+	return n, nil
+}
+
+// checkSizeLimit returns SizeLimitError if size exceeds limit.
+// Limit of zero or less means no limit.
+func checkSizeLimit(size, limit int64) error {
+	if limit > 0 && size > limit {
+		return SizeLimitError{Limit: limit}
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package libmangaltest
+
+import (
+	"github.com/spf13/afero"
+	"os"
+	"syscall"
+)
+
+// ErrDiskFull is returned by the files of Fs when the failure is injected
+var ErrDiskFull error = syscall.ENOSPC
+
+// Fs is the afero.Fs wrapper that injects disk full errors into writes
+type Fs struct {
+	afero.Fs
+
+	// Chance decides whether faults are injected.
+	// Shared random source is used if nil.
+	Chance *Chance
+
+	// DiskFullProbability is the probability of ErrDiskFull
+	// for each write or file creation
+	DiskFullProbability float64
+}
+
+// NewFs wraps the fs
+func NewFs(fs afero.Fs, diskFullProbability float64) *Fs {
+	return &Fs{
+		Fs:                  fs,
+		DiskFullProbability: diskFullProbability,
+	}
+}
+
+func (f *Fs) diskFull() bool {
+	return chanceOrDefault(f.Chance).Roll(f.DiskFullProbability)
+}
+
+func (f *Fs) Create(name string) (afero.File, error) {
+	if f.diskFull() {
+		return nil, &os.PathError{Op: "create", Path: name, Err: ErrDiskFull}
+	}
+
+	file, err := f.Fs.Create(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return &faultFile{File: file, fs: f}, nil
+}
+
+func (f *Fs) OpenFile(name string, flag int, perm os.FileMode) (afero.File, error) {
+	if flag&os.O_CREATE != 0 && f.diskFull() {
+		return nil, &os.PathError{Op: "open", Path: name, Err: ErrDiskFull}
+	}
+
+	file, err := f.Fs.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+
+	return &faultFile{File: file, fs: f}, nil
+}
+
+func (f *Fs) Name() string {
+	return "libmangaltest.Fs(" + f.Fs.Name() + ")"
+}
+
+type faultFile struct {
+	afero.File
+	fs *Fs
+}
+
+func (f *faultFile) Write(b []byte) (int, error) {
+	if f.fs.diskFull() {
+		return 0, &os.PathError{Op: "write", Path: f.Name(), Err: ErrDiskFull}
+	}
+
+	return f.File.Write(b)
+}
+
+func (f *faultFile) WriteAt(b []byte, off int64) (int, error) {
+	if f.fs.diskFull() {
+		return 0, &os.PathError{Op: "write", Path: f.Name(), Err: ErrDiskFull}
+	}
+
+	return f.File.WriteAt(b, off)
+}
+
+func (f *faultFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
@@ -0,0 +1,50 @@
+// Package libmangaltest provides failure injection utilities
+// for testing libmangal and the applications built on it,
+// e.g. to verify their retry and resume behavior.
+package libmangaltest
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Chance decides whether a fault should be injected.
+// It's safe for concurrent use.
+type Chance struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewChance constructs new Chance with the given seed,
+// so that injected faults are reproducible.
+func NewChance(seed int64) *Chance {
+	return &Chance{rand: rand.New(rand.NewSource(seed))}
+}
+
+// Roll returns true with the given probability from 0 to 1
+func (c *Chance) Roll(probability float64) bool {
+	if probability <= 0 {
+		return false
+	}
+
+	if probability >= 1 {
+		return true
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.rand.Float64() < probability
+}
+
+// defaultChance is used when no Chance is specified
+var defaultChance = NewChance(time.Now().UnixNano())
+
+func chanceOrDefault(chance *Chance) *Chance {
+	if chance == nil {
+		return defaultChance
+	}
+
+	return chance
+}
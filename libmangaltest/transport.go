@@ -0,0 +1,134 @@
+package libmangaltest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrTimeout is returned by Transport when the timeout is injected.
+// It implements net.Error.
+var ErrTimeout error = timeoutError{}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "libmangaltest: injected timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// ErrPartialBody is returned when reading response body
+// that was cut by Transport.
+var ErrPartialBody = errors.New("libmangaltest: injected partial body")
+
+// Transport is the http.RoundTripper that injects failures
+// into the responses of the Base transport.
+//
+// Probabilities are from 0 to 1 and checked in the order
+// of the fields declaration, at most one fault is injected per request.
+type Transport struct {
+	// Base is the underlying transport.
+	// http.DefaultTransport is used if nil.
+	Base http.RoundTripper
+
+	// Chance decides whether faults are injected.
+	// Shared random source is used if nil.
+	Chance *Chance
+
+	// TimeoutProbability is the probability of ErrTimeout
+	TimeoutProbability float64
+
+	// TooManyRequestsProbability is the probability of
+	// the 429 Too Many Requests response without calling Base
+	TooManyRequestsProbability float64
+
+	// RetryAfter is the value of the Retry-After header of 429 responses
+	RetryAfter time.Duration
+
+	// PartialBodyProbability is the probability of the response
+	// which body ends with ErrPartialBody after the half of it is read
+	PartialBodyProbability float64
+}
+
+func (t *Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	chance := chanceOrDefault(t.Chance)
+
+	if chance.Roll(t.TimeoutProbability) {
+		return nil, &urlError{request: request, err: ErrTimeout}
+	}
+
+	if chance.Roll(t.TooManyRequestsProbability) {
+		header := make(http.Header)
+		header.Set("Retry-After", strconv.Itoa(int(t.RetryAfter.Seconds())))
+
+		return &http.Response{
+			Status:     fmt.Sprintf("%d %s", http.StatusTooManyRequests, http.StatusText(http.StatusTooManyRequests)),
+			StatusCode: http.StatusTooManyRequests,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     header,
+			Body:       io.NopCloser(bytes.NewReader(nil)),
+			Request:    request,
+		}, nil
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	response, err := base.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if chance.Roll(t.PartialBodyProbability) {
+		body, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		response.Body = &partialBody{
+			Reader: bytes.NewReader(body[:len(body)/2]),
+		}
+	}
+
+	return response, nil
+}
+
+// urlError mimics the error returned by http.Client on timeouts
+type urlError struct {
+	request *http.Request
+	err     error
+}
+
+func (u *urlError) Error() string {
+	return fmt.Sprintf("%s %q: %s", u.request.Method, u.request.URL, u.err)
+}
+
+func (u *urlError) Unwrap() error   { return u.err }
+func (u *urlError) Timeout() bool   { return true }
+func (u *urlError) Temporary() bool { return true }
+
+// partialBody returns ErrPartialBody instead of io.EOF
+type partialBody struct {
+	*bytes.Reader
+}
+
+func (p *partialBody) Read(b []byte) (int, error) {
+	n, err := p.Reader.Read(b)
+	if errors.Is(err, io.EOF) {
+		return n, ErrPartialBody
+	}
+
+	return n, err
+}
+
+func (p *partialBody) Close() error {
+	return nil
+}
@@ -0,0 +1,445 @@
+package libmangal
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"github.com/spf13/afero"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// LibraryDocument is the indexed chapter of the local library
+type LibraryDocument struct {
+	// Path of the chapter
+	Path string `json:"path"`
+
+	// Series is the manga title
+	Series string `json:"series"`
+
+	// Chapter is the chapter title
+	Chapter string `json:"chapter"`
+
+	// Number of the chapter, if known
+	Number float32 `json:"number"`
+
+	// Summary of the series, if known
+	Summary string `json:"summary"`
+
+	// Text of the pages from the ChapterText sidecar, if present
+	Text string `json:"text"`
+}
+
+// LibrarySearchResult is the document matching the search query
+type LibrarySearchResult struct {
+	Document LibraryDocument
+
+	// Score is the number of query terms occurrences
+	Score int
+}
+
+// LibraryIndex is the full-text index of the local library,
+// so that it can be searched without re-reading every ComicInfo.xml.
+// It is safe for concurrent use by multiple goroutines.
+//
+// See Client.IndexLibrary and Client.LoadLibraryIndex
+type LibraryIndex struct {
+	mu        sync.RWMutex
+	documents []LibraryDocument
+
+	// postings map terms to document indexes and term frequencies
+	postings map[string]map[int]int
+}
+
+// NewLibraryIndex constructs empty LibraryIndex
+func NewLibraryIndex() *LibraryIndex {
+	return &LibraryIndex{
+		postings: make(map[string]map[int]int),
+	}
+}
+
+// searchTerms splits text into lowercase words
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// Add adds the document to the index
+func (l *LibraryIndex) Add(document LibraryDocument) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	id := len(l.documents)
+	l.documents = append(l.documents, document)
+
+	for _, field := range []string{
+		document.Series,
+		document.Chapter,
+		document.Summary,
+		document.Text,
+	} {
+		for _, term := range searchTerms(field) {
+			postings, ok := l.postings[term]
+			if !ok {
+				postings = make(map[int]int)
+				l.postings[term] = postings
+			}
+
+			postings[id]++
+		}
+	}
+}
+
+// Len returns the number of indexed documents
+func (l *LibraryIndex) Len() int {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return len(l.documents)
+}
+
+// Search returns documents containing all the query words,
+// most relevant first.
+func (l *LibraryIndex) Search(query string) []LibrarySearchResult {
+	terms := searchTerms(query)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	scores := make(map[int]int)
+	for id, frequency := range l.postings[terms[0]] {
+		scores[id] = frequency
+	}
+
+	for _, term := range terms[1:] {
+		postings := l.postings[term]
+		for id := range scores {
+			frequency, ok := postings[id]
+			if !ok {
+				delete(scores, id)
+				continue
+			}
+
+			scores[id] += frequency
+		}
+	}
+
+	results := make([]LibrarySearchResult, 0, len(scores))
+	for id, score := range scores {
+		results = append(results, LibrarySearchResult{
+			Document: l.documents[id],
+			Score:    score,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+
+		return results[i].Document.Path < results[j].Document.Path
+	})
+
+	return results
+}
+
+// filenameLibraryIndexJSON is the file in the library root
+// where IndexLibrary persists the index, see LoadLibraryIndex
+const filenameLibraryIndexJSON = ".libmangal-index.json"
+
+// libraryIndexSnapshot is the persisted LibraryIndex
+type libraryIndexSnapshot struct {
+	Documents []LibraryDocument `json:"documents"`
+
+	// Files are the indexed files, so that unchanged ones are not read again
+	Files map[string]libraryFileStat `json:"files"`
+}
+
+// libraryFileStat identifies the version of the indexed file
+type libraryFileStat struct {
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"modTime"`
+}
+
+// unchanged reports whether the file was indexed with the same size and
+// modification time, returning the document it was indexed into
+func (l libraryIndexSnapshot) unchanged(path, documentPath string, info os.FileInfo) (LibraryDocument, bool) {
+	stat, ok := l.Files[path]
+	if !ok || stat.Size != info.Size() || !stat.ModTime.Equal(info.ModTime()) {
+		return LibraryDocument{}, false
+	}
+
+	for _, document := range l.Documents {
+		if document.Path == documentPath {
+			return document, true
+		}
+	}
+
+	return LibraryDocument{}, false
+}
+
+func (l libraryIndexSnapshot) index() *LibraryIndex {
+	index := NewLibraryIndex()
+	for _, document := range l.Documents {
+		index.Add(document)
+	}
+
+	return index
+}
+
+// readLibraryIndex reads the index persisted in the directory.
+// False is returned if there is none.
+func (c *Client) readLibraryIndex(dir string) (libraryIndexSnapshot, bool, error) {
+	contents, err := afero.ReadFile(c.options.FS, filepath.Join(dir, filenameLibraryIndexJSON))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return libraryIndexSnapshot{}, false, nil
+		}
+
+		return libraryIndexSnapshot{}, false, err
+	}
+
+	var snapshot libraryIndexSnapshot
+	if err := json.Unmarshal(contents, &snapshot); err != nil {
+		return libraryIndexSnapshot{}, false, err
+	}
+
+	return snapshot, true, nil
+}
+
+func (c *Client) writeLibraryIndex(dir string, snapshot libraryIndexSnapshot) error {
+	marshalled, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(c.options.FS, filepath.Join(dir, filenameLibraryIndexJSON), marshalled, modeFile)
+}
+
+// LoadLibraryIndex returns the index of the directory persisted by IndexLibrary
+// without walking the library. False is returned if it was never indexed.
+//
+// The index may be outdated, IndexLibrary refreshes it.
+func (c *Client) LoadLibraryIndex(dir string) (*LibraryIndex, bool, error) {
+	snapshot, found, err := c.readLibraryIndex(dir)
+	if err != nil || !found {
+		return nil, false, err
+	}
+
+	return snapshot.index(), true, nil
+}
+
+// IndexLibrary indexes chapters downloaded to the directory.
+//
+// Metadata is taken from ComicInfo.xml of CBZ chapters, otherwise from the paths.
+// Text extracted with DownloadOptions.TextExtractor is indexed too.
+//
+// The index is persisted in the directory, see LoadLibraryIndex.
+// Files unchanged since the last indexing are not read again.
+func (c *Client) IndexLibrary(ctx context.Context, dir string) (*LibraryIndex, error) {
+	c.options.Log(fmt.Sprintf("Indexing library at %s", dir))
+
+	previous, _, err := c.readLibraryIndex(dir)
+	if err != nil {
+		c.options.Log(fmt.Sprintf("Can't read library index, rebuilding it: %s", err))
+	}
+
+	extensions := make(map[string]Format)
+	for _, format := range Formats() {
+		if format.Extension != "" {
+			extensions[format.Extension] = format.Format
+		}
+	}
+
+	snapshot := libraryIndexSnapshot{
+		Files: make(map[string]libraryFileStat),
+	}
+
+	var reused int
+	byPath := make(map[string]int)
+
+	err = afero.Walk(c.options.FS, dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		stat := libraryFileStat{Size: info.Size(), ModTime: info.ModTime()}
+
+		if chapterPath, ok := strings.CutSuffix(path, suffixTextJSON); ok {
+			document, ok := previous.unchanged(path, chapterPath, info)
+			if ok {
+				reused++
+			} else {
+				text, err := c.ReadChapterText(chapterPath)
+				if err != nil {
+					return err
+				}
+
+				var pages []string
+				for _, page := range text.Pages {
+					pages = append(pages, page.Text)
+				}
+
+				document = LibraryDocument{
+					Path:    chapterPath,
+					Series:  text.Manga,
+					Chapter: text.Chapter,
+					Number:  text.Number,
+					Text:    strings.Join(pages, "\n"),
+				}
+			}
+
+			snapshot.Files[path] = stat
+
+			// chapter may be a directory of images, so it's not visited otherwise
+			i, ok := byPath[chapterPath]
+			if !ok {
+				i = len(snapshot.Documents)
+				byPath[chapterPath] = i
+				snapshot.Documents = append(snapshot.Documents, LibraryDocument{
+					Path:    chapterPath,
+					Series:  document.Series,
+					Chapter: document.Chapter,
+					Number:  document.Number,
+				})
+			}
+
+			snapshot.Documents[i].Text = document.Text
+			return nil
+		}
+
+		format, ok := libraryFileFormat(path, extensions)
+		if !ok {
+			return nil
+		}
+
+		document, ok := previous.unchanged(path, path, info)
+		if ok {
+			reused++
+		} else {
+			document = LibraryDocument{
+				Path:    path,
+				Series:  filepath.Base(filepath.Dir(path)),
+				Chapter: strings.TrimSuffix(filepath.Base(path), format.Extension()),
+			}
+
+			if format == FormatCBZ {
+				if err := c.readLibraryComicInfo(path, info.Size(), &document); err != nil {
+					c.options.Log(fmt.Sprintf("Can't read ComicInfo.xml of %s: %s", path, err))
+				}
+			}
+		}
+
+		snapshot.Files[path] = stat
+
+		// text comes from the sidecar only
+		document.Text = ""
+		if i, ok := byPath[path]; ok {
+			document.Text = snapshot.Documents[i].Text
+			snapshot.Documents[i] = document
+		} else {
+			byPath[path] = len(snapshot.Documents)
+			snapshot.Documents = append(snapshot.Documents, document)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.writeLibraryIndex(dir, snapshot); err != nil {
+		c.options.Log(fmt.Sprintf("Can't save library index: %s", err))
+	}
+
+	index := snapshot.index()
+
+	c.options.Log(fmt.Sprintf("Indexed %d chapters, %d files unchanged", index.Len(), reused))
+
+	return index, nil
+}
+
+// libraryFileFormat guesses the format of the chapter file by its extension
+func libraryFileFormat(path string, extensions map[string]Format) (Format, bool) {
+	// extensions may have multiple dots, e.g. .tar.gz
+	name := filepath.Base(path)
+	for i := strings.Index(name, "."); i >= 0; {
+		if format, ok := extensions[name[i:]]; ok {
+			return format, true
+		}
+
+		next := strings.Index(name[i+1:], ".")
+		if next < 0 {
+			break
+		}
+
+		i += next + 1
+	}
+
+	return 0, false
+}
+
+// readLibraryComicInfo fills the document from ComicInfo.xml inside the CBZ
+func (c *Client) readLibraryComicInfo(path string, size int64, document *LibraryDocument) error {
+	file, err := c.options.FS.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	archive, err := zip.NewReader(file, size)
+	if err != nil {
+		return err
+	}
+
+	entry, err := archive.Open(filenameComicInfoXML)
+	if err != nil {
+		return err
+	}
+	defer entry.Close()
+
+	contents, err := io.ReadAll(entry)
+	if err != nil {
+		return err
+	}
+
+	var comicInfo comicInfoXMLWrapper
+	if err := xml.Unmarshal(contents, &comicInfo); err != nil {
+		return err
+	}
+
+	if comicInfo.Series != "" {
+		document.Series = comicInfo.Series
+	}
+
+	if comicInfo.Title != "" {
+		document.Chapter = comicInfo.Title
+	}
+
+	document.Number = comicInfo.Number
+	document.Summary = comicInfo.Summary
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package libmangal
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLibraryIndexSearch(t *testing.T) {
+	index := NewLibraryIndex()
+	index.Add(LibraryDocument{Path: "/a", Series: "Blue Sea", Chapter: "Pirates"})
+	index.Add(LibraryDocument{Path: "/b", Series: "Blue Sky", Summary: "Pirates, pirates everywhere"})
+	index.Add(LibraryDocument{Path: "/c", Series: "Red Sea", Text: "no one here"})
+
+	if index.Len() != 3 {
+		t.Fatalf("Len() = %d, want 3", index.Len())
+	}
+
+	for _, test := range []struct {
+		query string
+		want  []string
+	}{
+		// most occurrences first
+		{"pirates", []string{"/b", "/a"}},
+		// every word must match, regardless of the case
+		{"BLUE sea", []string{"/a"}},
+		// equal scores are ordered by path
+		{"sea", []string{"/a", "/c"}},
+		{"green", nil},
+		{"  ,. ", nil},
+	} {
+		var got []string
+		for _, result := range index.Search(test.query) {
+			got = append(got, result.Document.Path)
+		}
+
+		if strings.Join(got, " ") != strings.Join(test.want, " ") {
+			t.Errorf("Search(%q) = %v, want %v", test.query, got, test.want)
+		}
+	}
+}
+
+// openCountingFs counts opened chapter archives
+type openCountingFs struct {
+	afero.Fs
+	opened *atomic.Int64
+}
+
+func (o openCountingFs) Open(name string) (afero.File, error) {
+	if strings.HasSuffix(name, FormatCBZ.Extension()) {
+		o.opened.Add(1)
+	}
+
+	return o.Fs.Open(name)
+}
+
+// writeLibraryCBZ writes the CBZ chapter with the ComicInfo.xml
+func writeLibraryCBZ(t *testing.T, fs afero.Fs, path string, comicInfo ComicInfoXML) {
+	t.Helper()
+
+	marshalled, err := xml.Marshal(comicInfo.wrapper(DefaultComicInfoOptions()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+
+	entry, err := archive.Create(filenameComicInfoXML)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := entry.Write(marshalled); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := afero.WriteFile(fs, path, buffer.Bytes(), modeFile); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIndexLibrary(t *testing.T) {
+	var opened atomic.Int64
+	fs := afero.NewMemMapFs()
+
+	options := testClientOptions()
+	options.FS = openCountingFs{Fs: fs, opened: &opened}
+
+	client, err := NewClient(context.Background(), newFakeProvider(t, 0, 0), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join("/library", "Blue Sea")
+	chapter1 := filepath.Join(dir, "Chapter 1.cbz")
+	chapter2 := filepath.Join(dir, "Chapter 2.cbz")
+	chapter3 := filepath.Join(dir, "Chapter 3")
+
+	writeLibraryCBZ(t, fs, chapter1, ComicInfoXML{Series: "Blue Sea", Title: "Departure", Number: 1, Summary: "Pirates"})
+	writeLibraryCBZ(t, fs, chapter2, ComicInfoXML{Series: "Blue Sea", Title: "Storm", Number: 2})
+
+	// directory chapters are indexed by their text only
+	text, err := json.Marshal(ChapterText{
+		Manga:   "Blue Sea",
+		Chapter: "Island",
+		Number:  3,
+		Pages:   []PageText{{Page: 1, Text: "treasure map"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := afero.WriteFile(fs, ChapterTextPath(chapter3), text, modeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(index *LibraryIndex, query string) []LibraryDocument {
+		var documents []LibraryDocument
+		for _, result := range index.Search(query) {
+			documents = append(documents, result.Document)
+		}
+
+		return documents
+	}
+
+	if _, found, err := client.LoadLibraryIndex("/library"); err != nil || found {
+		t.Fatalf("LoadLibraryIndex() before indexing = %t, %v", found, err)
+	}
+
+	index, err := client.IndexLibrary(context.Background(), "/library")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if index.Len() != 3 {
+		t.Fatalf("indexed %d chapters, want 3", index.Len())
+	}
+
+	if got := search(index, "pirates"); len(got) != 1 || got[0].Path != chapter1 || got[0].Chapter != "Departure" || got[0].Number != 1 {
+		t.Errorf("Search(pirates) = %+v", got)
+	}
+
+	if got := search(index, "treasure"); len(got) != 1 || got[0].Path != chapter3 || got[0].Chapter != "Island" {
+		t.Errorf("Search(treasure) = %+v", got)
+	}
+
+	if opened.Load() != 2 {
+		t.Errorf("opened %d archives, want 2", opened.Load())
+	}
+
+	// the persisted index is loaded without reading the library
+	opened.Store(0)
+
+	loaded, found, err := client.LoadLibraryIndex("/library")
+	if err != nil || !found {
+		t.Fatalf("LoadLibraryIndex() = %t, %v", found, err)
+	}
+
+	if got := search(loaded, "storm"); len(got) != 1 || got[0].Path != chapter2 {
+		t.Errorf("loaded Search(storm) = %+v", got)
+	}
+
+	if opened.Load() != 0 {
+		t.Errorf("loading opened %d archives", opened.Load())
+	}
+
+	// only the changed chapter is read again
+	writeLibraryCBZ(t, fs, chapter2, ComicInfoXML{Series: "Blue Sea", Title: "Calm", Number: 2})
+	if err := fs.Chtimes(chapter2, time.Now(), time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	index, err = client.IndexLibrary(context.Background(), "/library")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if opened.Load() != 1 {
+		t.Errorf("reindexing opened %d archives, want 1", opened.Load())
+	}
+
+	if got := search(index, "storm"); len(got) != 0 {
+		t.Errorf("outdated chapter found: %+v", got)
+	}
+
+	if got := search(index, "calm"); len(got) != 1 || got[0].Path != chapter2 {
+		t.Errorf("Search(calm) = %+v", got)
+	}
+
+	if got := search(index, "pirates treasure"); len(got) != 0 {
+		t.Errorf("Search(pirates treasure) = %+v", got)
+	}
+
+	if index.Len() != 3 {
+		t.Errorf("reindexed %d chapters, want 3", index.Len())
+	}
+}
@@ -0,0 +1,80 @@
+package libmangal
+
+import "context"
+
+// LogFields is the structured context of the log message,
+// so that sinks can tell apart messages of concurrent downloads.
+// See ClientOptions.StructuredLog
+type LogFields struct {
+	// Provider is the ID of the provider
+	Provider string
+
+	// MangaID is the ID of the manga. Empty if none.
+	MangaID string
+
+	// Chapter is the number of the chapter. Valid if HasChapter
+	Chapter    float32
+	HasChapter bool
+
+	// Page is the 1-based index of the page. Zero if none.
+	Page int
+}
+
+// StructuredLogFunc receives log messages along with their context
+type StructuredLogFunc = func(msg string, fields LogFields)
+
+type pageIndexKey struct{}
+
+// contextWithPageIndex records the 1-based index of the page
+// for the log messages of its download
+func contextWithPageIndex(ctx context.Context, index int) context.Context {
+	return context.WithValue(ctx, pageIndexKey{}, index)
+}
+
+func pageIndexFromContext(ctx context.Context) int {
+	index, _ := ctx.Value(pageIndexKey{}).(int)
+	return index
+}
+
+func mangaLogFields(manga Manga) LogFields {
+	return LogFields{
+		MangaID: manga.Info().ID,
+	}
+}
+
+func chapterLogFields(chapter Chapter) LogFields {
+	fields := mangaLogFields(chapter.Volume().Manga())
+	fields.Chapter = chapter.Info().Number
+	fields.HasChapter = true
+	return fields
+}
+
+// pageLogFields returns fields of the page with the index
+// recorded by contextWithPageIndex, if any
+func pageLogFields(ctx context.Context, page Page) LogFields {
+	fields := chapterLogFields(page.Chapter())
+	fields.Page = pageIndexFromContext(ctx)
+	return fields
+}
+
+// logWith returns the log function that attaches
+// the fields to the messages passed to ClientOptions.StructuredLog.
+// Provider field is always set.
+//
+// Messages are passed to ClientOptions.Log as is.
+func (c *Client) logWith(fields LogFields) LogFunc {
+	fields.Provider = c.Info().ID
+
+	var (
+		log        = c.log
+		structured = c.options.StructuredLog
+	)
+
+	return func(msg string) {
+		(*log.Load())(msg)
+
+		if structured != nil {
+			structured(msg, fields)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package libmangal
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+)
+
+// LongStripMode defines how vertical long-strip (webtoon) chapters are handled
+type LongStripMode uint8
+
+const (
+	// LongStripOff saves pages as is
+	LongStripOff LongStripMode = iota
+
+	// LongStripAuto detects long strips by the pages aspect ratio
+	// or ReadingDirectionVertical of the manga
+	LongStripAuto
+
+	// LongStripOn treats every chapter as a long strip
+	LongStripOn
+)
+
+// defaultLongStripAspectRatio is the height to width ratio above which
+// a page is considered a part of the long strip
+const defaultLongStripAspectRatio = 2.5
+
+// LongStripOptions configures handling of long-strip (webtoon) chapters.
+//
+// Long strips are written with ComicInfo Manga=No so that readers
+// don't apply right-to-left paging to them.
+type LongStripOptions struct {
+	// Mode of the long-strip detection
+	Mode LongStripMode
+
+	// MinAspectRatio is the height to width ratio above which
+	// a page is considered tall. Chapter is a long strip if most
+	// of its pages are tall.
+	//
+	// Zero means 2.5
+	MinAspectRatio float64
+
+	// SliceHeight stitches pages of the long strip together and
+	// cuts them into slices of this height in pixels, e.g. the screen height.
+	// PDF pages take the size of their images, so this also defines PDF page size.
+	//
+	// Zero keeps pages as is.
+	SliceHeight int
+}
+
+// DefaultLongStripOptions constructs default LongStripOptions
+func DefaultLongStripOptions() LongStripOptions {
+	return LongStripOptions{
+		Mode:           LongStripAuto,
+		MinAspectRatio: defaultLongStripAspectRatio,
+		SliceHeight:    0,
+	}
+}
+
+// isLongStrip reports whether the pages form a long strip
+func (l LongStripOptions) isLongStrip(pages []PageWithImage, direction ReadingDirection) bool {
+	switch l.Mode {
+	case LongStripOn:
+		return true
+	case LongStripAuto:
+		if direction == ReadingDirectionVertical {
+			return true
+		}
+	default:
+		return false
+	}
+
+	ratio := l.MinAspectRatio
+	if ratio <= 0 {
+		ratio = defaultLongStripAspectRatio
+	}
+
+	var tall, known int
+	for _, page := range pages {
+		config, _, err := image.DecodeConfig(bytes.NewReader(page.GetImage()))
+		if err != nil || config.Width == 0 {
+			continue
+		}
+
+		known++
+		if float64(config.Height)/float64(config.Width) >= ratio {
+			tall++
+		}
+	}
+
+	return known > 0 && tall*2 > known
+}
+
+// sliceLongStrip stitches pages together and cuts them
+// into slices of the given height.
+//
+// Pages are scaled to the width of the first one. Slices are encoded
+// as JPEG if all pages were JPEG and as PNG otherwise.
+func sliceLongStrip(pages []PageWithImage, height int) ([]PageWithImage, error) {
+	if len(pages) == 0 || height <= 0 {
+		return pages, nil
+	}
+
+	var (
+		images      = make([]image.Image, len(pages))
+		width       int
+		totalHeight int
+		allJPEG     = true
+	)
+
+	for i, page := range pages {
+		img, format, err := decodeImage(page)
+		if err != nil {
+			return nil, err
+		}
+
+		if i == 0 {
+			width = img.Bounds().Dx()
+		} else if img.Bounds().Dx() != width {
+			img = resizeToWidth(img, width)
+		}
+
+		if format != "jpeg" {
+			allJPEG = false
+		}
+
+		images[i] = img
+		totalHeight += img.Bounds().Dy()
+	}
+
+	// cut points, the short remainder is merged into the last slice
+	var bounds []int
+	for y := 0; y < totalHeight; y += height {
+		bounds = append(bounds, y)
+	}
+
+	if len(bounds) > 1 && totalHeight-bounds[len(bounds)-1] < height/4 {
+		bounds = bounds[:len(bounds)-1]
+	}
+
+	bounds = append(bounds, totalHeight)
+
+	extension := ".png"
+	if allJPEG {
+		extension = ".jpg"
+	}
+
+	slices := make([]PageWithImage, 0, len(bounds)-1)
+	for i := 0; i < len(bounds)-1; i++ {
+		top, bottom := bounds[i], bounds[i+1]
+		slice := image.NewRGBA(image.Rect(0, 0, width, bottom-top))
+
+		// slices may span several pages, the first one represents the slice
+		first := -1
+
+		// offset of the current image in the stitched strip
+		var offset int
+		for j, img := range images {
+			imgHeight := img.Bounds().Dy()
+			if offset+imgHeight > top && offset < bottom {
+				if first < 0 {
+					first = j
+				}
+
+				draw.Draw(
+					slice,
+					image.Rect(0, offset-top, width, offset-top+imgHeight),
+					img,
+					img.Bounds().Min,
+					draw.Src,
+				)
+			}
+
+			offset += imgHeight
+		}
+
+		var (
+			buffer bytes.Buffer
+			err    error
+		)
+
+		if allJPEG {
+			err = jpeg.Encode(&buffer, slice, &jpeg.Options{Quality: 95})
+		} else {
+			err = png.Encode(&buffer, slice)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("slice %d: %w", i+1, err)
+		}
+
+		slices = append(slices, pageWithExtension{
+			PageWithImage: &pageWithImage{
+				Page:  pages[first],
+				image: buffer.Bytes(),
+			},
+			extension: extension,
+		})
+	}
+
+	return slices, nil
+}
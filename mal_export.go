@@ -0,0 +1,132 @@
+package libmangal
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+)
+
+// MALStatus is the status of the manga in the MyAnimeList list
+type MALStatus string
+
+const (
+	MALStatusReading    MALStatus = "Reading"
+	MALStatusCompleted  MALStatus = "Completed"
+	MALStatusOnHold     MALStatus = "On-Hold"
+	MALStatusDropped    MALStatus = "Dropped"
+	MALStatusPlanToRead MALStatus = "Plan to Read"
+)
+
+// MALEntry is the manga entry of the MyAnimeList XML export
+type MALEntry struct {
+	// MangaDBID is the id of the manga on MyAnimeList
+	MangaDBID int `xml:"manga_mangadb_id"`
+
+	Title          string    `xml:"manga_title"`
+	ReadChapters   int       `xml:"my_read_chapters"`
+	ReadVolumes    int       `xml:"my_read_volumes"`
+	Status         MALStatus `xml:"my_status"`
+	UpdateOnImport int       `xml:"update_on_import"`
+}
+
+type malExportWrapper struct {
+	XMLName xml.Name `xml:"myanimelist"`
+	MyInfo  struct {
+		// UserExportType 2 means manga list
+		UserExportType int `xml:"user_export_type"`
+		TotalManga     int `xml:"user_total_manga"`
+	} `xml:"myinfo"`
+	Manga []MALEntry `xml:"manga"`
+}
+
+// ExportMALXML writes entries in the MyAnimeList XML backup format,
+// which can be imported to MyAnimeList, Anilist and other trackers.
+//
+// Entries without MangaDBID are skipped, since trackers can't match them.
+func ExportMALXML(out io.Writer, entries []MALEntry) error {
+	var wrapper malExportWrapper
+	wrapper.MyInfo.UserExportType = 2
+
+	for _, entry := range entries {
+		if entry.MangaDBID == 0 {
+			continue
+		}
+
+		entry.UpdateOnImport = 1
+		wrapper.Manga = append(wrapper.Manga, entry)
+	}
+
+	wrapper.MyInfo.TotalManga = len(wrapper.Manga)
+
+	marshalled, err := xml.MarshalIndent(wrapper, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+
+	_, err = out.Write(marshalled)
+	return err
+}
+
+// MALEntry makes the MyAnimeList export entry of the manga from
+// its Anilist binding and the read positions of its chapters.
+// See ExportMALXML
+//
+// Chapters are considered read when their ReadPosition is finished.
+// False is returned if the manga was not found on Anilist.
+func (c *Client) MALEntry(ctx context.Context, manga Manga) (MALEntry, bool, error) {
+	withAnilist, ok, err := c.Anilist().MakeMangaWithAnilist(ctx, manga)
+	if err != nil || !ok {
+		return MALEntry{}, false, err
+	}
+
+	anilist := withAnilist.Anilist
+
+	chapters, err := c.mangaChapters(ctx, manga)
+	if err != nil {
+		return MALEntry{}, false, err
+	}
+
+	var readChapters, readVolumes int
+	for _, chapter := range chapters {
+		position, found, err := c.ResumePosition(chapter)
+		if err != nil {
+			return MALEntry{}, false, err
+		}
+
+		if !found || !position.Finished() {
+			continue
+		}
+
+		if number := int(math.Trunc(float64(chapter.Info().Number))); number > readChapters {
+			readChapters = number
+		}
+
+		if number := chapter.Volume().Info().Number; number > readVolumes {
+			readVolumes = number
+		}
+	}
+
+	status := MALStatusReading
+	switch {
+	case readChapters == 0:
+		status = MALStatusPlanToRead
+	case anilist.Status == "FINISHED" && anilist.Chapters > 0 && readChapters >= anilist.Chapters:
+		status = MALStatusCompleted
+	}
+
+	c.options.Log(fmt.Sprintf("MAL entry of %q: %d chapters read", manga, readChapters))
+
+	return MALEntry{
+		MangaDBID:    anilist.IDMal,
+		Title:        anilist.String(),
+		ReadChapters: readChapters,
+		ReadVolumes:  readVolumes,
+		Status:       status,
+	}, true, nil
+}
@@ -0,0 +1,96 @@
+package libmangal
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+const testMALExportXML = `<?xml version="1.0" encoding="UTF-8"?>
+<myanimelist>
+  <myinfo>
+    <user_export_type>2</user_export_type>
+    <user_total_manga>1</user_total_manga>
+  </myinfo>
+  <manga>
+    <manga_mangadb_id>42</manga_mangadb_id>
+    <manga_title>Fake Manga</manga_title>
+    <my_read_chapters>12</my_read_chapters>
+    <my_read_volumes>2</my_read_volumes>
+    <my_status>Reading</my_status>
+    <update_on_import>1</update_on_import>
+  </manga>
+</myanimelist>`
+
+func TestExportMALXML(t *testing.T) {
+	var buffer bytes.Buffer
+	err := ExportMALXML(&buffer, []MALEntry{
+		{MangaDBID: 42, Title: "Fake Manga", ReadChapters: 12, ReadVolumes: 2, Status: MALStatusReading},
+		// not on MyAnimeList
+		{Title: "Other Manga", ReadChapters: 3, Status: MALStatusReading},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if buffer.String() != testMALExportXML {
+		t.Errorf("got\n%s\nwant\n%s", buffer.String(), testMALExportXML)
+	}
+}
+
+func TestMALEntry(t *testing.T) {
+	provider := newFakeProvider(t, 3, 1)
+	client := newTestClient(t, provider)
+	ctx := context.Background()
+
+	anilist := AnilistManga{ID: 1, IDMal: 42, Status: "FINISHED", Chapters: 3}
+	anilist.Title.English = "Fake Manga EN"
+	seedAnilist(t, client, anilist)
+
+	manga := provider.manga()
+	chapters := provider.chapterList()
+
+	entry := func() MALEntry {
+		t.Helper()
+
+		entry, ok, err := client.MALEntry(ctx, manga)
+		if err != nil || !ok {
+			t.Fatalf("no entry: %v", err)
+		}
+
+		return entry
+	}
+
+	if got := entry(); got.Status != MALStatusPlanToRead || got.ReadChapters != 0 {
+		t.Errorf("got %+v, want plan to read", got)
+	}
+
+	// unfinished chapters don't count
+	for i, page := range []int{9, 4} {
+		if err := client.SetReadPosition(chapters[i], page, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := MALEntry{
+		MangaDBID:    42,
+		Title:        "Fake Manga EN",
+		ReadChapters: 1,
+		ReadVolumes:  1,
+		Status:       MALStatusReading,
+	}
+
+	if got := entry(); got != want {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	for _, chapter := range chapters[1:] {
+		if err := client.SetReadPosition(chapter, 9, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := entry(); got.Status != MALStatusCompleted || got.ReadChapters != 3 {
+		t.Errorf("got %+v, want completed", got)
+	}
+}
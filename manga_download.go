@@ -0,0 +1,163 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ChapterDownloadStatus is the outcome of the chapter in DownloadManga
+type ChapterDownloadStatus uint8
+
+const (
+	// ChapterDownloaded means the chapter was downloaded or updated
+	ChapterDownloaded ChapterDownloadStatus = iota
+
+	// ChapterSkipped means the chapter already existed
+	// or was excluded by the FailureJournal
+	ChapterSkipped
+
+	// ChapterFailed means the chapter failed to download
+	ChapterFailed
+)
+
+// ChapterDownloadReport is the outcome of the single chapter in DownloadManga
+type ChapterDownloadReport struct {
+	Chapter Chapter
+	Status  ChapterDownloadStatus
+	Result  DownloadResult
+
+	// Error is non-nil for ChapterFailed
+	// and for chapters excluded by the FailureJournal
+	Error error
+}
+
+// MangaDownloadReport is the outcome of DownloadManga
+type MangaDownloadReport struct {
+	// Chapters are reports of the processed chapters in order
+	Chapters []ChapterDownloadReport
+}
+
+func (m MangaDownloadReport) filter(status ChapterDownloadStatus) []ChapterDownloadReport {
+	var reports []ChapterDownloadReport
+	for _, report := range m.Chapters {
+		if report.Status == status {
+			reports = append(reports, report)
+		}
+	}
+
+	return reports
+}
+
+// Downloaded returns reports of the downloaded chapters
+func (m MangaDownloadReport) Downloaded() []ChapterDownloadReport {
+	return m.filter(ChapterDownloaded)
+}
+
+// Skipped returns reports of the skipped chapters
+func (m MangaDownloadReport) Skipped() []ChapterDownloadReport {
+	return m.filter(ChapterSkipped)
+}
+
+// Failed returns reports of the failed chapters
+func (m MangaDownloadReport) Failed() []ChapterDownloadReport {
+	return m.filter(ChapterFailed)
+}
+
+// DownloadMangaOptions configures DownloadManga
+type DownloadMangaOptions struct {
+	// DownloadOptions are used for each chapter.
+	// The manga's DownloadProfile is applied to them.
+	DownloadOptions DownloadOptions
+
+	// StopOnError stops at the first failed chapter.
+	// Otherwise, failures are recorded in the report
+	// and the remaining chapters are downloaded.
+	StopOnError bool
+
+	// OnProgress is called after each chapter with
+	// the number of processed chapters and the total number
+	OnProgress func(report ChapterDownloadReport, done, total int)
+}
+
+// DefaultDownloadMangaOptions constructs default DownloadMangaOptions
+func DefaultDownloadMangaOptions() DownloadMangaOptions {
+	return DownloadMangaOptions{
+		DownloadOptions: DefaultDownloadOptions(),
+		StopOnError:     false,
+		OnProgress:      func(ChapterDownloadReport, int, int) {},
+	}
+}
+
+// DownloadManga downloads all chapters of the manga, picking one version
+// of each chapter by the preferred scanlator (see PreferredChapters).
+//
+// The report is returned along with the error, which is
+// non-nil if the context was canceled or StopOnError was set
+// and some chapter failed.
+func (c *Client) DownloadManga(
+	ctx context.Context,
+	manga Manga,
+	options DownloadMangaOptions,
+) (MangaDownloadReport, error) {
+	var report MangaDownloadReport
+
+	downloadOptions, err := c.MangaDownloadOptions(manga, options.DownloadOptions)
+	if err != nil {
+		return report, err
+	}
+
+	chapters, err := c.PreferredChapters(ctx, manga)
+	if err != nil {
+		return report, err
+	}
+
+	c.options.Log(fmt.Sprintf("Downloading %d chapters of %q", len(chapters), manga))
+
+	for i, chapter := range chapters {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		chapterReport := ChapterDownloadReport{Chapter: chapter}
+
+		result, err := c.DownloadChapter(ctx, chapter, downloadOptions)
+
+		var excluded ChapterExcludedError
+		switch {
+		case errors.As(err, &excluded):
+			chapterReport.Status = ChapterSkipped
+			chapterReport.Error = err
+		case err != nil:
+			chapterReport.Status = ChapterFailed
+			chapterReport.Error = err
+		case result.Skipped:
+			chapterReport.Status = ChapterSkipped
+		default:
+			chapterReport.Status = ChapterDownloaded
+		}
+
+		chapterReport.Result = result
+		report.Chapters = append(report.Chapters, chapterReport)
+
+		if options.OnProgress != nil {
+			options.OnProgress(chapterReport, i+1, len(chapters))
+		}
+
+		if chapterReport.Status != ChapterFailed {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return report, ctx.Err()
+		}
+
+		if options.StopOnError {
+			return report, fmt.Errorf("%s: %w", chapter, err)
+		}
+
+		c.options.Log(fmt.Sprintf("Chapter %q failed: %s", chapter, err))
+	}
+
+	return report, nil
+}
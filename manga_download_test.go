@@ -0,0 +1,98 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestDownloadManga(t *testing.T) {
+	provider := newFakeProvider(t, 3, 1)
+	client := newTestClient(t, provider)
+	ctx := context.Background()
+
+	options := DefaultDownloadMangaOptions()
+	options.DownloadOptions = testDownloadOptions()
+
+	// the first chapter exists, the others fail
+	if _, err := client.DownloadChapter(ctx, provider.chapterList()[0], options.DownloadOptions); err != nil {
+		t.Fatal(err)
+	}
+
+	provider.pageErr = errors.New("page is unavailable")
+	provider.pageErrIndex = 1
+
+	var progress []int
+	options.OnProgress = func(_ ChapterDownloadReport, done, total int) {
+		if total != 3 {
+			t.Errorf("got total %d, want 3", total)
+		}
+
+		progress = append(progress, done)
+	}
+
+	report, err := client.DownloadManga(ctx, provider.manga(), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Chapters) != 3 || len(report.Skipped()) != 1 || len(report.Failed()) != 2 {
+		t.Fatalf("got %d chapters, %d skipped, %d failed, want 3, 1, 2",
+			len(report.Chapters), len(report.Skipped()), len(report.Failed()))
+	}
+
+	for _, failed := range report.Failed() {
+		if !errors.Is(failed.Error, provider.pageErr) {
+			t.Errorf("%s: got error %v", failed.Chapter, failed.Error)
+		}
+	}
+
+	if len(progress) != 3 || progress[0] != 1 || progress[2] != 3 {
+		t.Errorf("got progress %v, want [1 2 3]", progress)
+	}
+
+	// StopOnError stops at the first failed chapter
+	options.StopOnError = true
+	options.OnProgress = nil
+
+	report, err = client.DownloadManga(ctx, provider.manga(), options)
+	if !errors.Is(err, provider.pageErr) {
+		t.Errorf("got error %v, want the page error", err)
+	}
+
+	if len(report.Chapters) != 2 {
+		t.Errorf("got %d chapters, want 2", len(report.Chapters))
+	}
+
+	// the failed chapters are downloaded once the pages are back
+	provider.pageErr = nil
+
+	report, err = client.DownloadManga(ctx, provider.manga(), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	downloaded := report.Downloaded()
+	if len(downloaded) != 2 || downloaded[0].Chapter.Info().Number != 2 || downloaded[1].Chapter.Info().Number != 3 {
+		t.Errorf("got %d downloaded chapters, want chapters 2 and 3", len(downloaded))
+	}
+}
+
+func TestDownloadMangaCanceled(t *testing.T) {
+	provider := newFakeProvider(t, 2, 1)
+	client := newTestClient(t, provider)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	options := DefaultDownloadMangaOptions()
+	options.DownloadOptions = testDownloadOptions()
+
+	if _, err := client.DownloadManga(ctx, provider.manga(), options); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+
+	if provider.requests.Load() != 0 {
+		t.Error("pages were downloaded after the cancellation")
+	}
+}
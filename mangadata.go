@@ -1,6 +1,9 @@
 package libmangal
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 type MangaInfo struct {
 	// Title of the manga
@@ -25,6 +28,36 @@ type MangaInfo struct {
 
 	// Banner is the banner image url.
 	Banner string `json:"banner"`
+
+	// ReadingDirection of the manga, e.g. ReadingDirectionLTR for manhua.
+	// Empty value means it's unknown, which is treated as ReadingDirectionRTL.
+	ReadingDirection ReadingDirection `json:"readingDirection"`
+}
+
+// ReadingDirection is the direction the manga pages are read in
+type ReadingDirection string
+
+const (
+	// ReadingDirectionRTL is right-to-left, e.g. Japanese manga
+	ReadingDirectionRTL ReadingDirection = "rtl"
+
+	// ReadingDirectionLTR is left-to-right, e.g. manhua and western comics
+	ReadingDirectionLTR ReadingDirection = "ltr"
+
+	// ReadingDirectionVertical is top-to-bottom long strip, e.g. manhwa (webtoons)
+	ReadingDirectionVertical ReadingDirection = "vertical"
+)
+
+// comicInfoManga returns the ComicInfo Manga value for the direction
+func (r ReadingDirection) comicInfoManga() string {
+	switch r {
+	case ReadingDirectionLTR:
+		return "Yes"
+	case ReadingDirectionVertical:
+		return "No"
+	default:
+		return "YesAndRightToLeft"
+	}
 }
 
 type Manga interface {
@@ -42,9 +75,38 @@ type MangaWithSeriesJSON interface {
 	SeriesJSON() (SeriesJSON, error)
 }
 
+// LatestChapter describes the freshness of the manga
+type LatestChapter struct {
+	// Number of the latest chapter
+	Number float32 `json:"number"`
+
+	// UpdatedAt is the time the latest chapter was released. Zero if unknown.
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// Thumbnail is the image url to show in the search results.
+	// May be empty, MangaInfo.Cover is used then.
+	Thumbnail string `json:"thumbnail"`
+}
+
+// MangaWithLatestChapter is the Manga which provider knows its
+// latest chapter already from the search results, so that search UIs
+// can show freshness without getting chapters of every manga.
+type MangaWithLatestChapter interface {
+	Manga
+
+	// LatestChapter returns the latest chapter info.
+	// False is returned if it's unknown for this manga.
+	//
+	// Implementation should not make any external requests.
+	LatestChapter() (LatestChapter, bool)
+}
+
 type VolumeInfo struct {
 	// Number of the volume. Must be greater than 0
 	Number int `json:"number"`
+
+	// Cover is the volume cover image url. May be empty.
+	Cover string `json:"cover"`
 }
 
 // Volume if a series is popular enough, its chapters
@@ -77,6 +139,20 @@ type ChapterInfo struct {
 	// Float type used in case of chapters that has numbers
 	// like this: 10.8 or 103.1.
 	Number float32 `json:"number"`
+
+	// Language of the chapter as ISO 639-1 code, e.g. "en". May be empty.
+	Language string `json:"language"`
+
+	// PublishedAt is the time the chapter was released. Zero if unknown.
+	//
+	// It allows Watcher to learn the release cadence of the series.
+	PublishedAt time.Time `json:"publishedAt"`
+
+	// Scanlator is the group that translated the chapter. May be empty.
+	//
+	// Providers may return several versions of the same chapter
+	// by different scanlators. See Client.ChapterVersions
+	Scanlator string `json:"scanlator"`
 }
 
 // Chapter is what Volume consists of. Each chapter is about 24–40 pages.
@@ -118,6 +194,31 @@ type Page interface {
 	Chapter() Chapter
 }
 
+// PageWithDataURI is a Page which image is delivered inline
+// as a data URI, e.g. "data:image/png;base64,iVBORw0KGgo...".
+//
+// Image of such page is decoded directly,
+// Provider.GetPageImage is not called for it.
+type PageWithDataURI interface {
+	Page
+
+	// DataURI returns the data URI containing the page image.
+	DataURI() string
+}
+
+// PageWithMirrors is a Page that is also served by alternative servers.
+// They are used as a fallback if downloading the page fails.
+type PageWithMirrors interface {
+	Page
+
+	// Mirrors returns pages with the same image served
+	// from alternative servers. They are tried in the given order.
+	//
+	// Implementation should not make any external requests
+	// nor be computationally heavy.
+	Mirrors() []Page
+}
+
 // PageWithImage is a Page with downloaded image
 type PageWithImage interface {
 	Page
@@ -130,6 +231,8 @@ type PageWithImage interface {
 	SetImage(newImage []byte)
 }
 
+var _ PageWithImage = (*pageWithImage)(nil)
+
 type pageWithImage struct {
 	Page
 	image []byte
@@ -1,9 +1,12 @@
 package libmangal
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
 	"fmt"
+	"image"
+	"reflect"
 	"strings"
 )
 
@@ -12,6 +15,10 @@ const (
 	filenameSeriesJSON   = "series.json"
 	filenameCoverJPG     = "cover.jpg"
 	filenameBannerJPG    = "banner.jpg"
+
+	// filenameEmbeddedCoverJPG is the name of the cover inside archives.
+	// It's sorted before the first page "0001.jpg" so readers use it as a cover.
+	filenameEmbeddedCoverJPG = "0000_cover.jpg"
 )
 
 // ComicInfoXML contains metadata information about a comic book.
@@ -24,6 +31,8 @@ type ComicInfoXML struct {
 	Series string
 	// Number of the book in the series.
 	Number float32
+	// Volume number of the book in the series.
+	Volume int
 	// Web a URL pointing to a reference website for the book.
 	Web string
 
@@ -39,6 +48,9 @@ type ComicInfoXML struct {
 	// Characters present in the book.
 	Characters []string
 
+	// Teams present in the book. Usually refer to super-hero teams (e.g. Avengers).
+	Teams []string
+
 	// Year of the book release
 	Year int
 
@@ -114,20 +126,68 @@ type ComicInfoXML struct {
 	// Notes a free text field, usually used to store information about
 	// the application that created the ComicInfo.xml file.
 	Notes string
+
+	// Manga whether the book is a manga and its reading direction:
+	// "Yes", "No" or "YesAndRightToLeft".
+	//
+	// Empty value means it's derived from MangaInfo.ReadingDirection.
+	Manga string
+}
+
+// ComicInfoXMLSource defines how ComicInfo.xml provided by the chapter
+// (see ChapterWithComicInfoXML) is combined with the Anilist one
+type ComicInfoXMLSource uint8
+
+const (
+	// ComicInfoXMLMerge uses the provider fields and fills the blank ones from Anilist
+	ComicInfoXMLMerge ComicInfoXMLSource = iota
+
+	// ComicInfoXMLMergeAnilistFirst uses the Anilist fields and fills the blank ones from the provider
+	ComicInfoXMLMergeAnilistFirst
+
+	// ComicInfoXMLProviderOnly uses the provider fields only
+	ComicInfoXMLProviderOnly
+
+	// ComicInfoXMLAnilistOnly uses the Anilist fields only
+	ComicInfoXMLAnilistOnly
+)
+
+// mergeComicInfoXML returns primary with its blank fields filled from secondary
+func mergeComicInfoXML(primary, secondary ComicInfoXML) ComicInfoXML {
+	merged := reflect.ValueOf(&primary).Elem()
+	fallback := reflect.ValueOf(secondary)
+
+	for i := 0; i < merged.NumField(); i++ {
+		field := merged.Field(i)
+
+		blank := field.IsZero()
+		if field.Kind() == reflect.Slice {
+			blank = field.Len() == 0
+		}
+
+		if blank {
+			field.Set(fallback.Field(i))
+		}
+	}
+
+	return primary
 }
 
 func (c ComicInfoXML) wrapper(options ComicInfoXMLOptions) comicInfoXMLWrapper {
 	wrapper := comicInfoXMLWrapper{
+		pageInfo:   options.PageInfo,
 		XmlnsXsd:   "http://www.w3.org/2001/XMLSchema",
 		XmlnsXsi:   "http://www.w3.org/2001/XMLSchema-instance",
 		Title:      c.Title,
 		Series:     c.Series,
 		Number:     c.Number,
+		Volume:     c.Volume,
 		Web:        c.Web,
 		Genre:      strings.Join(c.Genres, ","),
 		Summary:    c.Summary,
 		Count:      c.Count,
 		Characters: strings.Join(c.Characters, ","),
+		Teams:      strings.Join(c.Teams, ","),
 		Year:       c.Year,
 		Month:      c.Month,
 		Day:        c.Day,
@@ -142,7 +202,7 @@ func (c ComicInfoXML) wrapper(options ComicInfoXMLOptions) comicInfoXMLWrapper {
 			fmt.Sprintf("Downloaded with libmangal/%s", Version),
 			"https://github.com/mangalorg/libmangal",
 		}, "\n"),
-		Manga:           "YesAndRightToLeft",
+		Manga:           c.Manga,
 		StoryArc:        c.StoryArc,
 		StoryArcNumber:  c.StoryArcNumber,
 		ScanInformation: c.ScanInformation,
@@ -155,6 +215,18 @@ func (c ComicInfoXML) wrapper(options ComicInfoXMLOptions) comicInfoXMLWrapper {
 		Publisher:       c.Publisher,
 	}
 
+	if wrapper.Manga == "" {
+		wrapper.Manga = ReadingDirectionRTL.comicInfoManga()
+	}
+
+	if options.AgeRating != "" {
+		wrapper.AgeRating = options.AgeRating
+	}
+
+	if options.LanguageISO != "" {
+		wrapper.LanguageISO = options.LanguageISO
+	}
+
 	if !options.AddDate {
 		wrapper.Year = 0
 		wrapper.Month = 0
@@ -180,11 +252,13 @@ type comicInfoXMLWrapper struct {
 	Title           string  `xml:"Title,omitempty"`
 	Series          string  `xml:"Series,omitempty"`
 	Number          float32 `xml:"Number,omitempty"`
+	Volume          int     `xml:"Volume,omitempty"`
 	Web             string  `xml:"Web,omitempty"`
 	Genre           string  `xml:"Genre,omitempty"`
 	Summary         string  `xml:"Summary,omitempty"`
 	Count           int     `xml:"Count,omitempty"`
 	Characters      string  `xml:"Characters,omitempty"`
+	Teams           string  `xml:"Teams,omitempty"`
 	PageCount       int     `xml:"PageCount,omitempty"`
 	Year            int     `xml:"Year,omitempty"`
 	Month           int     `xml:"Month,omitempty"`
@@ -206,6 +280,96 @@ type comicInfoXMLWrapper struct {
 	Format          string  `xml:"Format,omitempty"`
 	LanguageISO     string  `xml:"LanguageISO,omitempty"`
 	Publisher       string  `xml:"Publisher,omitempty"`
+
+	// Pages are set by saveCBZ, see ComicInfoXMLOptions.PageInfo.
+	// Bookmarks of the chapters of the volume are set before.
+	Pages *comicInfoXMLPages `xml:"Pages,omitempty"`
+
+	// pageInfo is ComicInfoXMLOptions.PageInfo
+	pageInfo bool
+}
+
+type comicInfoXMLPages struct {
+	Page []comicInfoXMLPage `xml:"Page"`
+}
+
+// comicInfoXMLPage describes the page of the book.
+// Image is the index of the page starting from 0
+type comicInfoXMLPage struct {
+	Image       int    `xml:"Image,attr"`
+	Type        string `xml:"Type,attr,omitempty"`
+	DoublePage  bool   `xml:"DoublePage,attr,omitempty"`
+	ImageSize   int    `xml:"ImageSize,attr,omitempty"`
+	Bookmark    string `xml:"Bookmark,attr,omitempty"`
+	ImageWidth  int    `xml:"ImageWidth,attr,omitempty"`
+	ImageHeight int    `xml:"ImageHeight,attr,omitempty"`
+}
+
+// describePage describes the image at the given index of the archive
+func describePage(index int, data []byte) comicInfoXMLPage {
+	page := comicInfoXMLPage{
+		Image:     index,
+		ImageSize: len(data),
+	}
+
+	if config, _, err := image.DecodeConfig(bytes.NewReader(data)); err == nil {
+		page.ImageWidth = config.Width
+		page.ImageHeight = config.Height
+		page.DoublePage = config.Width > config.Height
+	}
+
+	return page
+}
+
+// describePages returns the Pages element for the archive
+// with the given images. Embedded cover is the first image,
+// so bookmarks set before are shifted past it.
+func (c comicInfoXMLWrapper) describePages(pages []PageWithImage, cover []byte) *comicInfoXMLPages {
+	var offset int
+	if len(cover) > 0 {
+		offset = 1
+	}
+
+	bookmarks := make(map[int]string)
+	if c.Pages != nil {
+		for _, page := range c.Pages.Page {
+			bookmarks[page.Image+offset] = page.Bookmark
+		}
+	}
+
+	var described []comicInfoXMLPage
+	if c.pageInfo {
+		if offset > 0 {
+			described = append(described, describePage(0, cover))
+		}
+
+		for i, page := range pages {
+			described = append(described, describePage(i+offset, page.GetImage()))
+		}
+
+		if len(described) > 0 {
+			described[0].Type = "FrontCover"
+		}
+
+		for i := range described {
+			described[i].Bookmark = bookmarks[described[i].Image]
+		}
+	} else {
+		if len(bookmarks) == 0 {
+			return nil
+		}
+
+		for _, page := range c.Pages.Page {
+			page.Image += offset
+			described = append(described, page)
+		}
+	}
+
+	if len(described) == 0 {
+		return nil
+	}
+
+	return &comicInfoXMLPages{Page: described}
 }
 
 func (c comicInfoXMLWrapper) marshal() ([]byte, error) {
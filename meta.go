@@ -1,6 +1,8 @@
 package libmangal
 
 const (
-	Version   = "0.7.0"
-	UserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/114.0.0.0 Safari/537.36"
+	Version = "0.7.0"
+
+	// UserAgent is the default User-Agent. See ClientOptions.UserAgent
+	UserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/118.0.0.0 Safari/537.36"
 )
@@ -0,0 +1,107 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// ChapterRange is an inclusive range of chapter numbers
+type ChapterRange struct {
+	From int `json:"from"`
+	To   int `json:"to"`
+}
+
+func (c ChapterRange) String() string {
+	if c.From == c.To {
+		return fmt.Sprint(c.From)
+	}
+
+	return fmt.Sprintf("%d-%d", c.From, c.To)
+}
+
+// MissingChapters is the difference between the chapters the manga
+// should have, the chapters the provider has and the chapters that are downloaded.
+type MissingChapters struct {
+	// Total is the number of chapters according to Anilist.
+	// Zero if unknown, e.g. the manga is still releasing.
+	Total int `json:"total"`
+
+	// NotOnProvider are the ranges of chapter numbers that the provider
+	// doesn't have. Chapters are expected to be numbered from 1
+	// up to Total or the latest chapter of the provider, whichever is greater.
+	NotOnProvider []ChapterRange `json:"notOnProvider"`
+
+	// NotDownloaded are the provider chapters that are not downloaded
+	// or have changed since (see DownloadOptions.RedownloadIfChanged).
+	// Chapters excluded in the FailureJournal are not listed.
+	NotDownloaded []Chapter `json:"-"`
+}
+
+// MissingChapters compares chapters of the manga available on the provider
+// with the chapters count on Anilist and with the chapters
+// already downloaded according to the DownloadOptions.
+//
+// It helps to find gaps in the provider and to pick alternative sources.
+func (c *Client) MissingChapters(
+	ctx context.Context,
+	manga Manga,
+	options DownloadOptions,
+) (MissingChapters, error) {
+	chapters, err := c.PreferredChapters(ctx, manga)
+	if err != nil {
+		return MissingChapters{}, err
+	}
+
+	var missing MissingChapters
+
+	mangaWithAnilist, ok, err := c.Anilist().MakeMangaWithAnilist(ctx, manga)
+	if err != nil {
+		return MissingChapters{}, err
+	}
+
+	if ok {
+		missing.Total = mangaWithAnilist.Anilist.Chapters
+	}
+
+	var (
+		available = make(map[int]struct{})
+		latest    = missing.Total
+	)
+
+	for _, chapter := range chapters {
+		// chapters like 10.5 are extras of the chapter 10
+		number := int(math.Trunc(float64(chapter.Info().Number)))
+		available[number] = struct{}{}
+
+		if number > latest {
+			latest = number
+		}
+
+		path, _ := c.chapterPath(chapter, options)
+		state, err := c.chapterState(ctx, chapter, path, options, c.options.FS)
+		if err != nil {
+			return MissingChapters{}, err
+		}
+
+		if !state.excluded && (!state.exists || state.changed) {
+			missing.NotDownloaded = append(missing.NotDownloaded, chapter)
+		}
+	}
+
+	for number := 1; number <= latest; number++ {
+		if _, ok := available[number]; ok {
+			continue
+		}
+
+		ranges := missing.NotOnProvider
+		if len(ranges) > 0 && ranges[len(ranges)-1].To == number-1 {
+			ranges[len(ranges)-1].To = number
+			continue
+		}
+
+		missing.NotOnProvider = append(ranges, ChapterRange{From: number, To: number})
+	}
+
+	return missing, nil
+}
@@ -0,0 +1,68 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"github.com/philippgille/gokv/syncmap"
+	"github.com/spf13/afero"
+	"testing"
+)
+
+// seedAnilist caches the Anilist manga for the fake manga title
+func seedAnilist(t *testing.T, client *Client, manga AnilistManga) {
+	t.Helper()
+
+	anilist := client.Anilist()
+	if err := anilist.cacheSetTitle(fakeProviderInfo.Name+" Manga", manga.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := anilist.cacheSetId(manga.ID, manga); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMissingChaptersNotDownloaded(t *testing.T) {
+	provider := newFakeProvider(t, 4, 1)
+	chapters := provider.chapterList()
+
+	client := newTestClient(t, provider)
+	seedAnilist(t, client, AnilistManga{ID: 1, Chapters: len(chapters)})
+
+	options := testDownloadOptions()
+	options.MaxFileSize = 1 << 20
+
+	// first chapter was split, second one is excluded,
+	// third one was moved by a hook
+	path, err := client.ChapterPath(chapters[0], options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := afero.WriteFile(client.FS(), chapterPartPath(path, 1, options.Format), nil, modeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	journal := client.options.FailureJournal
+	if err := journal.Record(client.Info().ID, chapters[1], errors.New("failed")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := journal.SetExcluded(chapterKey(client.Info().ID, chapters[1]), true); err != nil {
+		t.Fatal(err)
+	}
+
+	client.options.ChapterIndex = NewChapterIndex(syncmap.NewStore(syncmap.DefaultOptions))
+	if err := client.options.ChapterIndex.Set(client.Info().ID, chapters[2], "/moved.cbz"); err != nil {
+		t.Fatal(err)
+	}
+
+	missing, err := client.MissingChapters(context.Background(), provider.manga(), options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(missing.NotDownloaded) != 1 || missing.NotDownloaded[0].Info().Number != 4 {
+		t.Errorf("got %d chapters not downloaded, want only the fourth one", len(missing.NotDownloaded))
+	}
+}
@@ -0,0 +1,85 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+)
+
+// AnilistMangaDir defines how manga directories are named
+// after the matching Anilist manga. See ClientOptions.AnilistMangaDir
+type AnilistMangaDir uint8
+
+const (
+	// AnilistMangaDirNone names directories with ClientOptions.MangaNameTemplate
+	AnilistMangaDirNone AnilistMangaDir = iota
+
+	// AnilistMangaDirTitle names directory after the Anilist title
+	// (english, romaji or native, whichever is available first)
+	AnilistMangaDirTitle
+
+	// AnilistMangaDirTitleWithID names directory after the
+	// Anilist title followed by the Anilist ID, e.g. "Berserk [30002]"
+	AnilistMangaDirTitleWithID
+)
+
+// resolveAnilistMangaDir finds the manga on Anilist, so that its directory
+// is named after it. Mangas not found on Anilist are named
+// with ClientOptions.MangaNameTemplate.
+func (c *Client) resolveAnilistMangaDir(ctx context.Context, manga Manga) error {
+	if c.options.AnilistMangaDir == AnilistMangaDirNone {
+		return nil
+	}
+
+	_, ok, err := c.Anilist().MakeMangaWithAnilist(ctx, manga)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		c.options.Log(fmt.Sprintf("Manga %q was not found on anilist, naming it after its title", manga))
+	}
+
+	return nil
+}
+
+// anilistMangaFilename names the manga directory after the Anilist manga
+// resolved by resolveAnilistMangaDir. Only cached results are used,
+// since paths are computed without the context.
+func (c *Client) anilistMangaFilename(manga Manga) (string, bool) {
+	anilistManga, ok, err := c.Anilist().cachedManga(manga)
+	if err != nil {
+		c.options.Log(fmt.Sprintf("Failed to read anilist cache: %s", err))
+		return "", false
+	}
+
+	if !ok {
+		c.options.Log(fmt.Sprintf("Manga %q is not resolved on anilist, naming it with the template", manga))
+		return "", false
+	}
+
+	title := anilistManga.String()
+
+	switch c.options.AnilistMangaDir {
+	case AnilistMangaDirTitleWithID:
+		return sanitizePath(fmt.Sprintf("%s [%d]", title, anilistManga.ID)), true
+	default:
+		return sanitizePath(title), true
+	}
+}
+
+// cachedManga returns the closest Anilist manga found
+// by MakeMangaWithAnilist before without requesting Anilist
+func (a *Anilist) cachedManga(manga Manga) (AnilistManga, bool, error) {
+	title := manga.Info().AnilistSearch
+	if title == "" {
+		title = manga.Info().Title
+	}
+
+	found, id, err := a.cacheStatusTitle(title)
+	if err != nil || !found {
+		return AnilistManga{}, false, err
+	}
+
+	found, anilistManga, err := a.cacheStatusId(id)
+	return anilistManga, found, err
+}
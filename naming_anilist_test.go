@@ -0,0 +1,57 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestAnilistMangaDir(t *testing.T) {
+	provider := newFakeProvider(t, 1, 1)
+	chapter := provider.chapterList()[0]
+
+	client := newTestClient(t, provider)
+	client.options.AnilistMangaDir = AnilistMangaDirTitleWithID
+
+	anilistManga := AnilistManga{ID: 30002}
+	anilistManga.Title.English = "Berserk"
+	seedAnilist(t, client, anilistManga)
+
+	options := testDownloadOptions()
+	options.CreateMangaDir = true
+
+	result, err := client.DownloadChapter(context.Background(), chapter, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := filepath.Base(filepath.Dir(result.Path)), "Berserk [30002]"; got != want {
+		t.Errorf("got manga directory %q, want %q", got, want)
+	}
+}
+
+func TestAnilistMangaDirUnreachable(t *testing.T) {
+	provider := newFakeProvider(t, 1, 1)
+	chapter := provider.chapterList()[0]
+
+	client := newTestClient(t, provider)
+	client.options.AnilistMangaDir = AnilistMangaDirTitle
+	client.Anilist().options.HTTPClient = &http.Client{
+		Transport: roundTripperFunc(func(*http.Request) (*http.Response, error) {
+			return nil, errTestUnreachable
+		}),
+	}
+
+	options := testDownloadOptions()
+	options.CreateMangaDir = true
+
+	if _, err := client.DownloadChapter(context.Background(), chapter, options); !errors.As(err, &AnilistError{}) {
+		t.Errorf("got %v, want AnilistError", err)
+	}
+
+	if provider.requests.Load() != 0 {
+		t.Error("chapter was downloaded without the anilist manga")
+	}
+}
@@ -0,0 +1,132 @@
+package libmangal
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NamingPreset is the set of naming templates, e.g. replicating
+// the layout of another tool so that migrated libraries keep their names.
+//
+// Templates are text/template texts, see ChapterNameTemplateString.
+// Presets can be exported and imported as JSON.
+type NamingPreset struct {
+	Name    string `json:"name"`
+	Manga   string `json:"manga"`
+	Volume  string `json:"volume"`
+	Chapter string `json:"chapter"`
+
+	// CreateVolumeDir is whether the layout has volume directories.
+	// See DownloadOptions.CreateVolumeDir
+	CreateVolumeDir bool `json:"createVolumeDir"`
+}
+
+// builtinNamingPresets are the presets available by name
+var builtinNamingPresets = []NamingPreset{
+	{
+		Name:    "mangal",
+		Manga:   `{{ .Title }}`,
+		Volume:  `Vol. {{ .Number }}`,
+		Chapter: `[{{ printf "%06.1f" .Number }}] {{ .Title }}`,
+	},
+	{
+		// local source reads chapter numbers from the names
+		Name:    "tachiyomi",
+		Manga:   `{{ .Title }}`,
+		Volume:  `Vol.{{ .Number }}`,
+		Chapter: `{{ with .Scanlator }}{{ . }}_{{ end }}Ch. {{ printf "%g" .Number }}{{ with .Title }} - {{ . }}{{ end }}`,
+	},
+	{
+		// series name, volume and chapter numbers are parsed by Komga and Kavita
+		Name:    "komga",
+		Manga:   `{{ .Title }}`,
+		Volume:  `{{ .Manga }} v{{ pad 2 .Number }}`,
+		Chapter: `{{ .Manga }} v{{ pad 2 .Volume }} c{{ pad 3 .Number }}{{ with .Title }} - {{ . }}{{ end }}`,
+	},
+	{
+		Name:    "hakuneko",
+		Manga:   `{{ .Title }}`,
+		Volume:  `Vol.{{ pad 3 .Number }}`,
+		Chapter: `{{ default (printf "Ch.%s" (pad 3 .Number)) .Title }}`,
+	},
+	{
+		Name:    "fmd2",
+		Manga:   `{{ .Title }}`,
+		Volume:  `Volume {{ .Number }}`,
+		Chapter: `{{ pad 4 .Number }}{{ with .Title }} - {{ . }}{{ end }}`,
+	},
+}
+
+// NamingPresets returns names of the built-in presets
+func NamingPresets() []string {
+	names := make([]string, len(builtinNamingPresets))
+	for i, preset := range builtinNamingPresets {
+		names[i] = preset.Name
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// GetNamingPreset returns the built-in preset by its case-insensitive name.
+// Available presets: mangal, tachiyomi, komga, hakuneko, fmd2
+func GetNamingPreset(name string) (NamingPreset, error) {
+	for _, preset := range builtinNamingPresets {
+		if strings.EqualFold(preset.Name, name) {
+			return preset, nil
+		}
+	}
+
+	return NamingPreset{}, fmt.Errorf("unknown naming preset %q, available: %s", name, strings.Join(NamingPresets(), ", "))
+}
+
+// ParseNamingPreset imports the preset exported as JSON.
+// Templates are checked to be valid.
+func ParseNamingPreset(data []byte) (NamingPreset, error) {
+	var preset NamingPreset
+	if err := json.Unmarshal(data, &preset); err != nil {
+		return NamingPreset{}, err
+	}
+
+	var options ClientOptions
+	if err := preset.Apply(&options); err != nil {
+		return NamingPreset{}, err
+	}
+
+	return preset, nil
+}
+
+// Apply sets naming templates of the options.
+// Empty templates of the preset keep the current ones.
+func (p NamingPreset) Apply(options *ClientOptions) error {
+	if p.Manga != "" {
+		template, err := MangaNameTemplateString(p.Manga)
+		if err != nil {
+			return fmt.Errorf("preset %q: manga: %w", p.Name, err)
+		}
+
+		options.MangaNameTemplate = template
+	}
+
+	if p.Volume != "" {
+		template, err := VolumeNameTemplateString(p.Volume)
+		if err != nil {
+			return fmt.Errorf("preset %q: volume: %w", p.Name, err)
+		}
+
+		options.VolumeNameTemplate = template
+	}
+
+	if p.Chapter != "" {
+		template, err := ChapterNameTemplateString(p.Chapter)
+		if err != nil {
+			return fmt.Errorf("preset %q: chapter: %w", p.Name, err)
+		}
+
+		options.ChapterNameTemplate = template
+	}
+
+	return nil
+}
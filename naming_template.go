@@ -0,0 +1,291 @@
+package libmangal
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+)
+
+// MangaNameData is the data available for manga name templates
+type MangaNameData struct {
+	Provider string
+	Title    string
+	ID       string
+}
+
+// VolumeNameData is the data available for volume name templates
+type VolumeNameData struct {
+	Provider string
+	Manga    string
+	Number   int
+}
+
+// ChapterNameData is the data available for chapter name templates
+type ChapterNameData struct {
+	Provider  string
+	Manga     string
+	Volume    int
+	Number    float32
+	Title     string
+	Language  string
+	Scanlator string
+
+	// PublishedAt is zero if unknown
+	PublishedAt time.Time
+}
+
+// NamingFuncs returns functions available for naming templates:
+//
+//	pad N NUMBER      zero-pads the integer part, e.g. pad 3 10.5 is "010.5" and pad 3 -0.5 is "-000.5"
+//	slug TEXT         lowercase words joined with dashes, e.g. "chainsaw-man".
+//	                  Accented latin letters are transliterated, other scripts are kept
+//	truncate N TEXT   cuts the text to N characters ending it with "…" if N > 1
+//	date LAYOUT TIME  formats the time, see time.Time.Format. Empty for zero time
+//	title TEXT        title case, e.g. "Chainsaw Man"
+//	translit TEXT     replaces accented latin letters with ASCII ones
+//	roman NUMBER      roman numerals, e.g. roman 4 is "IV". Fails outside of 1-3999
+//	lower, upper, trim
+//	default VALUE X   returns VALUE if X is empty
+func NamingFuncs() template.FuncMap {
+	return template.FuncMap{
+		"pad":      namingPad,
+		"slug":     namingSlug,
+		"truncate": namingTruncate,
+		"date":     namingDate,
+		"title":    toTitleCase,
+		"translit": transliterate,
+		"roman":    namingRoman,
+		"lower":    strings.ToLower,
+		"upper":    strings.ToUpper,
+		"trim":     strings.TrimSpace,
+		"default":  namingDefault,
+	}
+}
+
+// MangaNameTemplateString constructs ClientOptions.MangaNameTemplate
+// from the text/template with MangaNameData, e.g. "{{ .Title | slug }}"
+func MangaNameTemplateString(text string) (func(provider string, manga Manga) string, error) {
+	tmpl, err := parseNamingTemplate("manga", text)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(provider string, manga Manga) string {
+		info := manga.Info()
+		return executeNamingTemplate(tmpl, MangaNameData{
+			Provider: provider,
+			Title:    info.Title,
+			ID:       info.ID,
+		}, info.Title)
+	}, nil
+}
+
+// VolumeNameTemplateString constructs ClientOptions.VolumeNameTemplate
+// from the text/template with VolumeNameData, e.g. "Vol. {{ pad 2 .Number }}"
+func VolumeNameTemplateString(text string) (func(provider string, volume Volume) string, error) {
+	tmpl, err := parseNamingTemplate("volume", text)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(provider string, volume Volume) string {
+		number := volume.Info().Number
+		return executeNamingTemplate(tmpl, VolumeNameData{
+			Provider: provider,
+			Manga:    volume.Manga().Info().Title,
+			Number:   number,
+		}, fmt.Sprintf("Vol. %d", number))
+	}, nil
+}
+
+// ChapterNameTemplateString constructs ClientOptions.ChapterNameTemplate
+// from the text/template with ChapterNameData,
+// e.g. "{{ .Manga }} - Ch. {{ pad 3 .Number }}{{ with .Title }} - {{ truncate 40 . }}{{ end }}"
+func ChapterNameTemplateString(text string) (func(provider string, chapter Chapter) string, error) {
+	tmpl, err := parseNamingTemplate("chapter", text)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(provider string, chapter Chapter) string {
+		info := chapter.Info()
+		volume := chapter.Volume()
+
+		return executeNamingTemplate(tmpl, ChapterNameData{
+			Provider:    provider,
+			Manga:       volume.Manga().Info().Title,
+			Volume:      volume.Info().Number,
+			Number:      info.Number,
+			Title:       info.Title,
+			Language:    info.Language,
+			Scanlator:   info.Scanlator,
+			PublishedAt: info.PublishedAt,
+		}, info.Title)
+	}, nil
+}
+
+func parseNamingTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(NamingFuncs()).Option("missingkey=error").Parse(text)
+}
+
+// executeNamingTemplate executes the template and sanitizes the result.
+// Fallback is used if the template fails or results in the empty name.
+func executeNamingTemplate(tmpl *template.Template, data any, fallback string) string {
+	var buffer bytes.Buffer
+	if err := tmpl.Execute(&buffer, data); err != nil || strings.TrimSpace(buffer.String()) == "" {
+		return sanitizePath(fallback)
+	}
+
+	return sanitizePath(strings.TrimSpace(buffer.String()))
+}
+
+func namingPad(width int, number any) (string, error) {
+	var formatted string
+	switch number := number.(type) {
+	case int:
+		formatted = strconv.FormatInt(int64(number), 10)
+	case int64:
+		formatted = strconv.FormatInt(number, 10)
+	case float32:
+		// formatted as float32 to avoid 0.50000001
+		formatted = strconv.FormatFloat(float64(number), 'f', -1, 32)
+	case float64:
+		formatted = strconv.FormatFloat(number, 'f', -1, 64)
+	default:
+		return "", fmt.Errorf("pad: unsupported type %T", number)
+	}
+
+	sign := ""
+	if strings.HasPrefix(formatted, "-") {
+		sign, formatted = "-", formatted[1:]
+	}
+
+	integer, fraction, _ := strings.Cut(formatted, ".")
+	if padding := width - len(integer); padding > 0 {
+		integer = strings.Repeat("0", padding) + integer
+	}
+
+	if fraction != "" {
+		fraction = "." + fraction
+	}
+
+	// -0 is not signed
+	if sign != "" && strings.Trim(integer+fraction, "0.") == "" {
+		sign = ""
+	}
+
+	return sign + integer + fraction, nil
+}
+
+func namingSlug(text string) string {
+	var (
+		b    strings.Builder
+		dash bool
+	)
+
+	for _, r := range strings.ToLower(transliterate(text)) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteRune('-')
+			}
+
+			dash = false
+			b.WriteRune(r)
+		} else {
+			dash = true
+		}
+	}
+
+	return b.String()
+}
+
+func namingTruncate(length int, text string) string {
+	runes := []rune(text)
+	if length <= 0 || len(runes) <= length {
+		return text
+	}
+
+	// no room for the ellipsis
+	if length == 1 {
+		return string(runes[:1])
+	}
+
+	return strings.TrimSpace(string(runes[:length-1])) + "…"
+}
+
+func namingDate(layout string, t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.Format(layout)
+}
+
+func namingDefault(value string, text string) string {
+	if strings.TrimSpace(text) == "" {
+		return value
+	}
+
+	return text
+}
+
+func namingRoman(number int) (string, error) {
+	if number < 1 || number > 3999 {
+		return "", fmt.Errorf("roman: %d is out of range 1-3999", number)
+	}
+
+	numerals := []struct {
+		value  int
+		symbol string
+	}{
+		{1000, "M"}, {900, "CM"}, {500, "D"}, {400, "CD"},
+		{100, "C"}, {90, "XC"}, {50, "L"}, {40, "XL"},
+		{10, "X"}, {9, "IX"}, {5, "V"}, {4, "IV"}, {1, "I"},
+	}
+
+	var b strings.Builder
+	for _, numeral := range numerals {
+		for number >= numeral.value {
+			b.WriteString(numeral.symbol)
+			number -= numeral.value
+		}
+	}
+
+	return b.String(), nil
+}
+
+// transliterations map accented latin letters to ASCII
+var transliterations = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a",
+	'À': "A", 'Á': "A", 'Â': "A", 'Ã': "A", 'Ä': "A", 'Å': "A", 'Ā': "A",
+	'æ': "ae", 'Æ': "AE", 'ç': "c", 'Ç': "C",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e",
+	'È': "E", 'É': "E", 'Ê': "E", 'Ë': "E", 'Ē': "E",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i",
+	'Ì': "I", 'Í': "I", 'Î': "I", 'Ï': "I", 'Ī': "I",
+	'ñ': "n", 'Ñ': "N",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o",
+	'Ò': "O", 'Ó': "O", 'Ô': "O", 'Õ': "O", 'Ö': "O", 'Ø': "O", 'Ō': "O",
+	'œ': "oe", 'Œ': "OE", 'ß': "ss",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u",
+	'Ù': "U", 'Ú': "U", 'Û': "U", 'Ü': "U", 'Ū': "U",
+	'ý': "y", 'ÿ': "y", 'Ý': "Y",
+	'’': "'", '‘': "'", '“': "\"", '”': "\"", '–': "-", '—': "-",
+}
+
+func transliterate(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		if replacement, ok := transliterations[r]; ok {
+			b.WriteString(replacement)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,146 @@
+package libmangal
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"text/template"
+	"time"
+)
+
+func TestNamingFuncs(t *testing.T) {
+	published := time.Date(2023, time.March, 7, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		text    string
+		data    any
+		want    string
+		wantErr bool
+	}{
+		{name: "pad int", text: `{{ pad 3 7 }}`, want: "007"},
+		{name: "pad wide int", text: `{{ pad 2 1234 }}`, want: "1234"},
+		{name: "pad float", text: `{{ pad 3 . }}`, data: float32(10.5), want: "010.5"},
+		{name: "pad float32 precision", text: `{{ pad 3 . }}`, data: float32(10.1), want: "010.1"},
+		{name: "pad float64", text: `{{ pad 2 . }}`, data: 3.25, want: "03.25"},
+		{name: "pad negative fraction", text: `{{ pad 3 . }}`, data: -0.5, want: "-000.5"},
+		{name: "pad negative", text: `{{ pad 3 . }}`, data: float32(-10.5), want: "-010.5"},
+		{name: "pad negative int", text: `{{ pad 3 -7 }}`, want: "-007"},
+		{name: "pad negative zero", text: `{{ pad 2 . }}`, data: math.Copysign(0, -1), want: "00"},
+		{name: "pad tiny fraction", text: `{{ pad 2 . }}`, data: 1.00001, want: "01.00001"},
+		{name: "pad tiny float32 fraction", text: `{{ pad 1 . }}`, data: float32(0.00001), want: "0.00001"},
+		{name: "pad zero width", text: `{{ pad 0 5 }}`, want: "5"},
+		{name: "pad int64", text: `{{ pad 4 . }}`, data: int64(42), want: "0042"},
+		{name: "pad unsupported", text: `{{ pad 3 "7" }}`, wantErr: true},
+
+		{name: "slug", text: `{{ slug "Chainsaw Man!" }}`, want: "chainsaw-man"},
+		{name: "slug accents", text: `{{ slug "Pokémon: Ça va" }}`, want: "pokemon-ca-va"},
+		{name: "slug separators", text: `{{ slug "  --Vol. 1 -- Part 2--  " }}`, want: "vol-1-part-2"},
+		{name: "slug non-latin", text: `{{ slug "チェンソーマン 第1話" }}`, want: "チェンソーマン-第1話"},
+		{name: "slug cyrillic", text: `{{ slug "Берсерк Том" }}`, want: "берсерк-том"},
+		{name: "slug empty", text: `{{ slug "!!!" }}`, want: ""},
+
+		{name: "truncate", text: `{{ truncate 5 "Chainsaw" }}`, want: "Chai…"},
+		{name: "truncate short", text: `{{ truncate 10 "Chainsaw" }}`, want: "Chainsaw"},
+		{name: "truncate exact", text: `{{ truncate 8 "Chainsaw" }}`, want: "Chainsaw"},
+		{name: "truncate one", text: `{{ truncate 1 "Chainsaw" }}`, want: "C"},
+		{name: "truncate two", text: `{{ truncate 2 "Chainsaw" }}`, want: "C…"},
+		{name: "truncate zero", text: `{{ truncate 0 "Chainsaw" }}`, want: "Chainsaw"},
+		{name: "truncate space", text: `{{ truncate 5 "Ab cd ef" }}`, want: "Ab c…"},
+		{name: "truncate trailing space", text: `{{ truncate 4 "Ab cd" }}`, want: "Ab…"},
+		{name: "truncate runes", text: `{{ truncate 3 "チェンソー" }}`, want: "チェ…"},
+
+		{name: "date", text: `{{ date "2006-01-02" . }}`, data: published, want: "2023-03-07"},
+		{name: "date zero", text: `{{ date "2006-01-02" . }}`, data: time.Time{}, want: ""},
+
+		{name: "title", text: `{{ title "chainsaw MAN" }}`, want: "Chainsaw Man"},
+		{name: "title spaces", text: `{{ title "  one   piece " }}`, want: "One Piece"},
+
+		{name: "translit", text: `{{ translit "Pokémon – Æon" }}`, want: "Pokemon - AEon"},
+		{name: "translit non-latin", text: `{{ translit "チェンソー" }}`, want: "チェンソー"},
+
+		{name: "roman", text: `{{ roman 4 }}`, want: "IV"},
+		{name: "roman one", text: `{{ roman 1 }}`, want: "I"},
+		{name: "roman large", text: `{{ roman 1994 }}`, want: "MCMXCIV"},
+		{name: "roman max", text: `{{ roman 3999 }}`, want: "MMMCMXCIX"},
+		{name: "roman zero", text: `{{ roman 0 }}`, wantErr: true},
+		{name: "roman negative", text: `{{ roman -3 }}`, wantErr: true},
+		{name: "roman too large", text: `{{ roman 4000 }}`, wantErr: true},
+
+		{name: "lower", text: `{{ lower "Chainsaw MAN" }}`, want: "chainsaw man"},
+		{name: "upper", text: `{{ upper "Chainsaw man" }}`, want: "CHAINSAW MAN"},
+		{name: "trim", text: `{{ trim "  Chainsaw  " }}`, want: "Chainsaw"},
+
+		{name: "default empty", text: `{{ default "Untitled" . }}`, data: "", want: "Untitled"},
+		{name: "default blank", text: `{{ default "Untitled" . }}`, data: "  ", want: "Untitled"},
+		{name: "default set", text: `{{ default "Untitled" . }}`, data: "Title", want: "Title"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tmpl, err := template.New(test.name).Funcs(NamingFuncs()).Parse(test.text)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var b strings.Builder
+			err = tmpl.Execute(&b, test.data)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("expected error, got %q", b.String())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := b.String(); got != test.want {
+				t.Errorf("got %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNamingFuncsAreTested(t *testing.T) {
+	tested := []string{
+		"pad", "slug", "truncate", "date", "title", "translit",
+		"roman", "lower", "upper", "trim", "default",
+	}
+
+	funcs := NamingFuncs()
+	if len(funcs) != len(tested) {
+		t.Errorf("NamingFuncs has %d functions, %d are tested", len(funcs), len(tested))
+	}
+
+	for _, name := range tested {
+		if _, ok := funcs[name]; !ok {
+			t.Errorf("%s is not in NamingFuncs", name)
+		}
+	}
+}
+
+func TestChapterNameTemplateString(t *testing.T) {
+	provider := newFakeProvider(t, 1, 0)
+	chapter := provider.chapterList()[0]
+
+	name, err := ChapterNameTemplateString(`{{ .Manga | slug }} - Ch. {{ pad 3 .Number }} - {{ roman .Volume }}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := name(fakeProviderInfo.ID, chapter), "fake-manga - Ch. 001 - I"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	failing, err := ChapterNameTemplateString(`{{ roman 0 }}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := failing(fakeProviderInfo.ID, chapter), chapter.Info().Title; got != want {
+		t.Errorf("failed template: got %q, want the fallback %q", got, want)
+	}
+}
@@ -0,0 +1,5 @@
+//go:build !race
+
+package libmangal
+
+const raceEnabled = false
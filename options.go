@@ -35,19 +35,56 @@ type DownloadOptions struct {
 	// However, metadata will still be created if needed.
 	SkipIfExists bool
 
+	// RedownloadIfChanged will download existing chapter again even if SkipIfExists is true
+	// when its ChapterFingerprint differs from the stored one,
+	// e.g. the source has replaced pages with the fixed scans.
+	//
+	// If pages were only appended, only the new ones are downloaded
+	// and saved along with the existing ones for FormatCBZ, FormatZIP
+	// and FormatImages.
+	RedownloadIfChanged bool
+
 	// DownloadMangaCover or not. Will not download cover again if its already downloaded.
 	DownloadMangaCover bool
 
+	// DownloadVolumeCover will download the volume cover (or the manga cover
+	// if there is none) into the volume directory. Requires CreateVolumeDir.
+	DownloadVolumeCover bool
+
+	// CoverProviders are tried in order to find covers for DownloadMangaCover,
+	// DownloadVolumeCover and EmbedCover, e.g. to prefer full-size volume covers
+	// from MangaDex over the Anilist ones.
+	//
+	// Nil value uses covers of the manga provider, then of Anilist.
+	// See SourceCoverProvider, AnilistCoverProvider, MangaDexCoverProvider
+	// and StaticCoverProvider
+	CoverProviders []CoverProvider
+
 	// DownloadMangaBanner or not. Will not download banner again if its already downloaded.
 	DownloadMangaBanner bool
 
 	// WriteSeriesJson write metadata series.json file in the manga directory
 	WriteSeriesJson bool
 
+	// WriteVolumeJson write volume.json with the volume metadata
+	// and its chapters to the volume directory. Requires CreateVolumeDir.
+	//
+	// DownloadVolume writes it next to the volume file, see VolumeJSONPath
+	WriteVolumeJson bool
+
+	// WriteChaptersJson write chapters.json index of the downloaded
+	// chapters to the manga directory, see ChaptersJSON
+	WriteChaptersJson bool
+
 	// WriteComicInfoXml write metadata ComicInfo.xml file to the .cbz archive when
 	// downloading with FormatCBZ
 	WriteComicInfoXml bool
 
+	// EmbedCover will put the volume cover (or the manga cover if there is none)
+	// inside the archive as the first file when downloading with FormatCBZ.
+	// Some readers ignore cover files in the series directory.
+	EmbedCover bool
+
 	// ReadAfter will open the chapter for reading after it was downloaded.
 	// It will use os default app for resulting mimetype.
 	//
@@ -66,10 +103,82 @@ type DownloadOptions struct {
 	// ComicInfoXMLOptions options to use for ComicInfo.xml when WriteComicInfoXml is true
 	ComicInfoXMLOptions ComicInfoXMLOptions
 
+	// Description cleans up the manga description written
+	// to the ComicInfo.xml Summary and series.json
+	Description DescriptionOptions
+
 	// ImageTransformer is applied for each image for the chapter.
 	//
-	// E.g. grayscale effect
+	// E.g. grayscale effect, see the imagetransform package
 	ImageTransformer func([]byte) ([]byte, error)
+
+	// ImageConverters convert images of particular formats, e.g. WebP
+	// which some readers can't open inside CBZ, renaming pages accordingly.
+	// Converters are applied after the ImageTransformer.
+	//
+	// See WebPToJPEG and FormatConverter
+	ImageConverters []ImageConverter
+
+	// ImagePolicies describe how images are normalized for each format.
+	// Policy is applied after the ImageTransformer and ImageConverters.
+	//
+	// E.g. {FormatPDF: {Encoding: ImageEncodingJPEG, JPEGQuality: 85}}
+	ImagePolicies map[Format]ImagePolicy
+
+	// Aliases are the rules for creating alternative views of the manga directory,
+	// e.g. named after its romaji title or grouped by genres.
+	// Requires CreateMangaDir and filesystem that supports symlinks.
+	//
+	// See AliasAnilistTitles and AliasGenres
+	Aliases []AliasRule
+
+	// AfterDownload hooks are called in order after the chapter is downloaded.
+	// E.g. for converting or uploading it somewhere else.
+	// They are not called for the skipped chapters.
+	// Each part of the split chapter is passed to them separately,
+	// see MaxFileSize.
+	//
+	// See CommandHook, MoveHook and WebhookHook
+	AfterDownload []Hook
+
+	// MaxFileSize splits chapters which images exceed this size in bytes
+	// into multiple files named "... - Part N", e.g. for FAT32 drives with
+	// 4GB limit or attachments. Doesn't apply to FormatImages.
+	//
+	// Zero means no limit.
+	MaxFileSize int64
+
+	// TextExtractor extracts text from the chapter pages (e.g. OCR)
+	// and saves it next to the chapter as a ChapterText JSON sidecar,
+	// enabling full-text search. See ChapterTextPath
+	//
+	// Nil value disables text extraction.
+	TextExtractor TextExtractor
+
+	// LongStrip configures handling of vertical long-strip (webtoon) chapters
+	LongStrip LongStripOptions
+
+	// Spreads configures handling of landscape double-page spreads
+	Spreads SpreadOptions
+
+	// Resume keeps the downloaded pages next to the chapter until it's saved,
+	// so that the download interrupted by a crash or cancellation
+	// continues from the last downloaded page instead of starting over.
+	Resume bool
+
+	// Checksums writes SHA256 checksums of the saved pages and the chapter
+	// file to the ChecksumManifest sidecar, so that corrupted files can be
+	// detected later with Client.VerifyChapter. See ChecksumsPath
+	Checksums bool
+
+	// Provenance defines where to record which provider and URL
+	// the chapter was downloaded from
+	Provenance ProvenanceOptions
+
+	// DryRun resolves chapter pages and computes resulting paths
+	// without downloading images or writing anything.
+	// See DownloadResult.PlannedFiles
+	DryRun bool
 }
 
 // DefaultDownloadOptions constructs default DownloadOptions
@@ -91,9 +200,31 @@ func DefaultDownloadOptions() DownloadOptions {
 			return img, nil
 		},
 		ComicInfoXMLOptions: DefaultComicInfoOptions(),
+		LongStrip:           DefaultLongStripOptions(),
+		Spreads:             DefaultSpreadOptions(),
+		Provenance:          DefaultProvenanceOptions(),
 	}
 }
 
+// RemoveOptions configures chapter removal
+type RemoveOptions struct {
+	// Trash will move removed chapters to the TrashDir
+	// instead of deleting them permanently.
+	Trash bool
+
+	// TrashDir is the directory where removed chapters are moved to.
+	// It's required if Trash is enabled.
+	//
+	// Chapters are moved by renaming, which fails across devices,
+	// so it should be on the same filesystem as the library, e.g. inside its root.
+	TrashDir string
+}
+
+// DefaultRemoveOptions constructs default RemoveOptions
+func DefaultRemoveOptions() RemoveOptions {
+	return RemoveOptions{}
+}
+
 // AnilistOptions is options for Anilist client
 type AnilistOptions struct {
 	// HTTPClient is a http client used for Anilist API
@@ -119,6 +250,48 @@ type AnilistOptions struct {
 
 	AccessTokenStore gokv.Store
 
+	// CompressRequests will gzip request bodies.
+	// Responses are always requested gzipped.
+	CompressRequests bool
+
+	// AddToListOnProgress will add manga to the user's list
+	// with the status CURRENT when setting progress for
+	// the manga that is not on the list yet.
+	// Otherwise, progress for such mangas is not tracked.
+	AddToListOnProgress bool
+
+	// PendingBindingsStore holds automatic title bindings with
+	// the confidence below BindingReviewThreshold for review.
+	// See Anilist.PendingBindings
+	//
+	// Nil value disables the review.
+	PendingBindingsStore gokv.Store
+
+	// BindingReviewThreshold is the confidence from 0 to 1
+	// below which automatic bindings are queued for review.
+	BindingReviewThreshold float64
+
+	// TitleSimilarity is used by Anilist.FindClosestManga to pick
+	// the search result closest to the title and to compute
+	// the binding confidence.
+	//
+	// E.g. LevenshteinSimilarity, JaroWinklerSimilarity or TokenSetSimilarity
+	TitleSimilarity TitleSimilarity
+
+	// MinTitleSimilarity is the confidence from 0 to 1 below which
+	// search results are not considered a match at all.
+	// Zero accepts the closest result whatever it is.
+	MinTitleSimilarity float64
+
+	// FullCredits makes Anilist.MakeMangaWithAnilist fetch complete
+	// lists of characters and staff instead of the main ones only,
+	// so that ComicInfo.xml credits are complete. It costs extra requests.
+	FullCredits bool
+
+	// MaxCreditPages bounds the number of pages of characters and staff
+	// fetched by Anilist.WithFullCredits. Zero means no bound.
+	MaxCreditPages int
+
 	// Log logs progress
 	Log LogFunc
 }
@@ -128,19 +301,30 @@ func DefaultAnilistOptions() AnilistOptions {
 	return AnilistOptions{
 		Log: func(string) {},
 
-		HTTPClient: &http.Client{},
+		HTTPClient: newAnilistHTTPClient(),
 
-		QueryToIDsStore:  syncmap.NewStore(syncmap.DefaultOptions),
-		TitleToIDStore:   syncmap.NewStore(syncmap.DefaultOptions),
-		IDToMangaStore:   syncmap.NewStore(syncmap.DefaultOptions),
-		AccessTokenStore: syncmap.NewStore(syncmap.DefaultOptions),
+		AddToListOnProgress:    true,
+		BindingReviewThreshold: 0.8,
+		TitleSimilarity:        LevenshteinSimilarity,
+		MinTitleSimilarity:     0,
+		MaxCreditPages:         4,
+
+		QueryToIDsStore:      syncmap.NewStore(syncmap.DefaultOptions),
+		TitleToIDStore:       syncmap.NewStore(syncmap.DefaultOptions),
+		IDToMangaStore:       syncmap.NewStore(syncmap.DefaultOptions),
+		AccessTokenStore:     syncmap.NewStore(syncmap.DefaultOptions),
+		PendingBindingsStore: syncmap.NewStore(syncmap.DefaultOptions),
 	}
 }
 
 // ClientOptions is options that client would use during its runtime.
 // See DefaultClientOptions
 type ClientOptions struct {
-	// HTTPClient is http client that client would use for requests
+	// HTTPClient is http client that client would use for requests.
+	// Wrap its transport with NewCircuitBreakerTransport
+	// to stop requesting hosts that keep failing.
+	//
+	// Use NewHTTPClient for custom DNS and host overrides
 	HTTPClient *http.Client
 
 	// FS is a file system abstraction
@@ -153,6 +337,15 @@ type ClientOptions struct {
 		manga Manga,
 	) string
 
+	// AnilistMangaDir names manga directories after the matching Anilist manga
+	// instead of MangaNameTemplate, so that the same series downloaded
+	// from different providers ends up in the same directory.
+	//
+	// DownloadChapter finds the manga on Anilist before downloading
+	// and fails if Anilist can't be reached. Mangas not found on Anilist
+	// are named with MangaNameTemplate.
+	AnilistMangaDir AnilistMangaDir
+
 	// ChapterNameTemplate defines how volumes filenames will look when downloaded.
 	// E.g. Vol. 1
 	VolumeNameTemplate func(
@@ -171,8 +364,132 @@ type ClientOptions struct {
 	// to serve as a progress writer
 	Log LogFunc
 
+	// StructuredLog receives the same messages as Log along with
+	// their context: provider, manga, chapter and page.
+	// Messages of the provider calls get the context too.
+	//
+	// Nil value disables it.
+	StructuredLog StructuredLogFunc
+
 	// Anilist is the Anilist client to use
 	Anilist *Anilist
+
+	// MaxImageSize is the maximum size in bytes of a single image
+	// (page, cover or banner) that the client will accept.
+	// Exceeding it will result in SizeLimitError.
+	//
+	// Providers get it with ImageSizeLimit to stop downloading
+	// oversized pages early, e.g. with NewImageSizeLimitTransport.
+	//
+	// Zero or negative value disables the limit.
+	MaxImageSize int64
+
+	// UserAgent provides User-Agent header values for
+	// the requests made by the client, e.g. for covers.
+	//
+	// See StaticUserAgent, NewRandomPerHostUserAgent and NewRotatingUserAgent
+	UserAgent UserAgentProvider
+
+	// FailureJournal records chapters that failed to download.
+	// Chapters excluded in it are not downloaded.
+	//
+	// Nil value disables the journal.
+	FailureJournal *FailureJournal
+
+	// FingerprintStore maps chapters to their ChapterFingerprint
+	// recorded during the download.
+	//
+	// Nil value disables fingerprints storing.
+	FingerprintStore gokv.Store
+
+	// ChapterIndex maps downloaded chapters to their paths
+	// to avoid filesystem lookups.
+	//
+	// Nil value disables the index.
+	ChapterIndex *ChapterIndex
+
+	// ReadPositionStore maps chapters to their last ReadPosition.
+	// See Client.SetReadPosition and Client.ResumePosition
+	//
+	// Nil value disables read positions tracking.
+	ReadPositionStore gokv.Store
+
+	// ProfileStore maps mangas to their DownloadProfile.
+	// See Client.SetDownloadProfile
+	//
+	// Nil value disables download profiles.
+	ProfileStore gokv.Store
+
+	// PageCache holds page images prefetched with Client.PrefetchChapter.
+	// See CleanPolicy.PageCacheMaxAge for pruning it.
+	//
+	// Nil value disables prefetching.
+	PageCache gokv.Store
+
+	// UsageStore accumulates downloaded bytes per manga across sessions.
+	// See Client.UsageStats
+	//
+	// Nil value disables usage tracking.
+	UsageStore gokv.Store
+
+	// CategoryStore keeps user-defined categories of mangas.
+	// See Client.SetCategory
+	//
+	// Nil value disables categories.
+	CategoryStore gokv.Store
+
+	// ChapterVersionSelector picks the preferred scanlator when the manga
+	// has chapters by several of them. See Client.PreferredChapters
+	//
+	// Nil value picks the first version of each chapter.
+	ChapterVersionSelector ChapterVersionSelector
+
+	// ScanlatorStore remembers the scanlator picked for each manga,
+	// so that ChapterVersionSelector is asked once per series.
+	//
+	// Nil value disables remembering.
+	ScanlatorStore gokv.Store
+
+	// TitleNormalizer cleans chapter titles before they are used
+	// for naming and ComicInfo.xml, and before translation.
+	//
+	// Nil value keeps titles as given by the provider.
+	TitleNormalizer *TitleNormalizer
+
+	// Translator translates manga and chapter titles before
+	// they are used for naming and metadata.
+	//
+	// Nil value disables translation.
+	Translator Translator
+
+	// TranslationStore caches translations.
+	// Nil value disables caching.
+	TranslationStore gokv.Store
+
+	// Fallback configures downloading chapters this provider lacks
+	// from the other providers. See Client.FallbackChapter
+	Fallback FallbackPolicy
+
+	// RetryPolicy configures retrying of failed page and image downloads
+	RetryPolicy RetryPolicy
+
+	// PageValidation defines how downloaded page images are checked.
+	// Invalid pages are retried and then fail with PageCorruptError
+	PageValidation PageValidation
+
+	// CaptchaSignatures are case-insensitive markers of CAPTCHA pages
+	// served instead of page images or covers. Matching responses fail
+	// with CaptchaRequiredError instead of being saved as pages.
+	//
+	// Nil value disables detection.
+	CaptchaSignatures []string
+
+	// PDFMemoryBudget is the size in bytes of page images imported
+	// into the PDF at once. Larger chapters and volumes are imported
+	// in chunks which are merged afterwards, keeping memory usage bounded.
+	//
+	// Zero means importing all pages at once.
+	PDFMemoryBudget int64
 }
 
 // DefaultClientOptions constructs default ClientOptions
@@ -192,8 +509,23 @@ func DefaultClientOptions() ClientOptions {
 		VolumeNameTemplate: func(_ string, volume Volume) string {
 			return sanitizePath(fmt.Sprintf("Vol. %d", volume.Info().Number))
 		},
-		Log:     func(string) {},
-		Anilist: &anilist,
+		Log:               func(string) {},
+		Anilist:           &anilist,
+		MaxImageSize:      64 << 20, // 64 MiB
+		UserAgent:         StaticUserAgent(UserAgent),
+		FailureJournal:    NewFailureJournal(syncmap.NewStore(syncmap.DefaultOptions)),
+		FingerprintStore:  syncmap.NewStore(syncmap.DefaultOptions),
+		ChapterIndex:      NewChapterIndex(syncmap.NewStore(syncmap.DefaultOptions)),
+		ReadPositionStore: syncmap.NewStore(syncmap.DefaultOptions),
+		ProfileStore:      syncmap.NewStore(syncmap.DefaultOptions),
+		TranslationStore:  syncmap.NewStore(syncmap.DefaultOptions),
+		UsageStore:        syncmap.NewStore(syncmap.DefaultOptions),
+		ScanlatorStore:    syncmap.NewStore(syncmap.DefaultOptions),
+		CategoryStore:     syncmap.NewStore(syncmap.DefaultOptions),
+		RetryPolicy:       DefaultRetryPolicy(),
+		PageValidation:    PageValidationHeader,
+		CaptchaSignatures: DefaultCaptchaSignatures(),
+		PDFMemoryBudget:   256 << 20, // 256 MiB
 	}
 }
 
@@ -204,11 +536,29 @@ type ComicInfoXMLOptions struct {
 
 	// AlternativeDate use other date
 	AlternativeDate *Date
+
+	// AgeRating overrides the age rating if non-empty.
+	// E.g. "Everyone", "Teen", "Mature 17+"
+	AgeRating string
+
+	// LanguageISO overrides the language code if non-empty.
+	// E.g. "en"
+	LanguageISO string
+
+	// Source defines where ComicInfo.xml fields come from
+	// for chapters implementing ChapterWithComicInfoXML
+	Source ComicInfoXMLSource
+
+	// PageInfo describes each image of the CBZ in the Pages element:
+	// dimensions, size, double-page spreads and the front cover.
+	// Readers like Kavita and Komga use it to detect spreads.
+	PageInfo bool
 }
 
 // DefaultComicInfoOptions constructs default ComicInfoXMLOptions
 func DefaultComicInfoOptions() ComicInfoXMLOptions {
 	return ComicInfoXMLOptions{
-		AddDate: true,
+		AddDate:  true,
+		PageInfo: true,
 	}
 }
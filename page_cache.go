@@ -0,0 +1,135 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+	"golang.org/x/sync/errgroup"
+)
+
+// pageCacheEntry is the cached page image
+type pageCacheEntry struct {
+	Image []byte `json:"image"`
+}
+
+// pageCacheKey identifies the page within the chapter of the provider,
+// since pages of different chapters may look the same
+func pageCacheKey(provider string, page Page) string {
+	return fmt.Sprintf("%s/%s", chapterKey(provider, page.Chapter()), pageFingerprint(page))
+}
+
+// cachedPage returns the page with the image from the ClientOptions.PageCache
+func (c *Client) cachedPage(page Page) (PageWithImage, bool) {
+	store := c.options.PageCache
+	if store == nil {
+		return nil, false
+	}
+
+	var entry pageCacheEntry
+	found, err := store.Get(pageCacheKey(c.Info().ID, page), &entry)
+	if err != nil || !found {
+		return nil, false
+	}
+
+	return &pageWithImage{
+		Page:  page,
+		image: entry.Image,
+	}, true
+}
+
+// Prefetch is the background download of the chapter pages.
+// See Client.PrefetchChapter
+type Prefetch struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error
+}
+
+// Cancel stops prefetching, e.g. when the user jumps to another chapter.
+// Pages that were already prefetched stay in the cache.
+func (p *Prefetch) Cancel() {
+	p.cancel()
+}
+
+// Wait waits for the prefetch to finish and returns its error
+func (p *Prefetch) Wait() error {
+	<-p.done
+	return p.err
+}
+
+// PrefetchChapter downloads the next n pages of the chapter into the
+// ClientOptions.PageCache in the background, so that streaming readers
+// can display them without waiting. Subsequent DownloadPage calls
+// return the cached pages.
+//
+// Pages after the ResumePosition of the chapter are prefetched,
+// or the first n pages if the chapter wasn't read yet.
+func (c *Client) PrefetchChapter(ctx context.Context, chapter Chapter, n int) *Prefetch {
+	ctx, cancel := context.WithCancel(ctx)
+
+	prefetch := &Prefetch{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer close(prefetch.done)
+		defer cancel()
+
+		prefetch.err = c.prefetchChapter(ctx, chapter, n)
+	}()
+
+	return prefetch
+}
+
+func (c *Client) prefetchChapter(ctx context.Context, chapter Chapter, n int) error {
+	if c.pageCache == nil {
+		return fmt.Errorf("page cache is not configured")
+	}
+
+	pages, err := c.ChapterPages(ctx, chapter)
+	if err != nil {
+		return err
+	}
+
+	from := 0
+	position, found, err := c.ResumePosition(chapter)
+	if err != nil {
+		return err
+	}
+
+	if found {
+		from = position.Page + 1
+	}
+
+	if from >= len(pages) {
+		return nil
+	}
+
+	to := from + n
+	if to > len(pages) {
+		to = len(pages)
+	}
+
+	c.options.Log(fmt.Sprintf("Prefetching pages #%d-#%d of %q", from+1, to, chapter))
+
+	g, ctx := errgroup.WithContext(ctx)
+	for _, page := range pages[from:to] {
+		page := page
+		g.Go(func() error {
+			if _, ok := c.cachedPage(page); ok {
+				return nil
+			}
+
+			downloaded, err := c.DownloadPage(ctx, page)
+			if err != nil {
+				return err
+			}
+
+			return c.pageCache.set(pageCacheKey(c.Info().ID, page), pageCacheEntry{
+				Image: downloaded.GetImage(),
+			})
+		})
+	}
+
+	return g.Wait()
+}
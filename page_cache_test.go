@@ -0,0 +1,56 @@
+package libmangal
+
+import (
+	"bytes"
+	"context"
+	"github.com/philippgille/gokv/syncmap"
+	"testing"
+)
+
+func TestPrefetchChapter(t *testing.T) {
+	provider := newFakeProvider(t, 2, 2)
+	chapters := provider.chapterList()
+
+	options := testClientOptions()
+	options.PageCache = syncmap.NewStore(syncmap.DefaultOptions)
+
+	client, err := NewClient(context.Background(), provider, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.PrefetchChapter(context.Background(), chapters[0], 2).Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	prefetched := provider.image
+	provider.image = testJPEG(t, 10, 10)
+	requests := provider.requests.Load()
+
+	// pages of both chapters are named the same
+	for _, test := range []struct {
+		chapter Chapter
+		image   []byte
+	}{
+		{chapter: chapters[0], image: prefetched},
+		{chapter: chapters[1], image: provider.image},
+	} {
+		pages, err := client.ChapterPages(context.Background(), test.chapter)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		page, err := client.DownloadPage(context.Background(), pages[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(page.GetImage(), test.image) {
+			t.Errorf("chapter %q: got the image of another chapter", test.chapter)
+		}
+	}
+
+	if got := provider.requests.Load() - requests; got != 1 {
+		t.Errorf("got %d requests after prefetching, want 1", got)
+	}
+}
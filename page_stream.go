@@ -0,0 +1,129 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// pageStreamWorkers is the number of pages PageStream downloads at once
+const pageStreamWorkers = 4
+
+type pageStreamResult struct {
+	page PageWithImage
+	err  error
+}
+
+// PageStream yields pages of the chapter in order as soon as they are downloaded,
+// so that readers can display the first page before the whole chapter is fetched.
+//
+// Pages are downloaded in the background, the earliest ones first.
+// It must be closed to stop the downloads.
+//
+// Next must not be called concurrently.
+// Close may be called from another goroutine to stop Next.
+type PageStream struct {
+	pages   []Page
+	results []chan pageStreamResult
+	next    int
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// ChapterPageStream starts downloading pages of the chapter
+// and returns the stream to receive them from.
+//
+// Unlike DownloadPagesInBatch, a failed page doesn't stop the others.
+func (c *Client) ChapterPageStream(ctx context.Context, chapter Chapter) (*PageStream, error) {
+	pages, err := c.ChapterPages(ctx, chapter)
+	if err != nil {
+		return nil, err
+	}
+
+	c.options.Log(fmt.Sprintf("Streaming %d pages", len(pages)))
+
+	ctx, cancel := context.WithCancel(ctx)
+
+	stream := &PageStream{
+		pages:   pages,
+		results: make([]chan pageStreamResult, len(pages)),
+		cancel:  cancel,
+	}
+
+	for i := range stream.results {
+		stream.results[i] = make(chan pageStreamResult, 1)
+	}
+
+	var (
+		mu      sync.Mutex
+		pending int
+	)
+
+	workers := pageStreamWorkers
+	if len(pages) < workers {
+		workers = len(pages)
+	}
+
+	for w := 0; w < workers; w++ {
+		stream.wg.Add(1)
+		go func() {
+			defer stream.wg.Done()
+
+			for {
+				mu.Lock()
+				i := pending
+				pending++
+				mu.Unlock()
+
+				if i >= len(pages) {
+					return
+				}
+
+				select {
+				case <-ctx.Done():
+					stream.results[i] <- pageStreamResult{err: ctx.Err()}
+					continue
+				default:
+				}
+
+				downloaded, err := c.DownloadPage(contextWithPageIndex(ctx, i+1), pages[i])
+				stream.results[i] <- pageStreamResult{page: downloaded, err: err}
+			}
+		}()
+	}
+
+	return stream, nil
+}
+
+// Len returns the number of pages of the chapter
+func (p *PageStream) Len() int {
+	return len(p.pages)
+}
+
+// Next waits for the next page to be downloaded and returns it.
+// False is returned when there are no pages left.
+//
+// Error of the failed page is returned along with true,
+// so that the stream can be continued with the next page.
+func (p *PageStream) Next(ctx context.Context) (PageWithImage, bool, error) {
+	if p.next >= len(p.results) {
+		return nil, false, nil
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	case result := <-p.results[p.next]:
+		p.next++
+		return result.page, true, result.err
+	}
+}
+
+// Close stops downloading the remaining pages.
+// It's safe to call it before the stream is exhausted.
+func (p *PageStream) Close() error {
+	p.cancel()
+	p.wg.Wait()
+	return nil
+}
@@ -0,0 +1,96 @@
+package libmangal
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestChapterPageStream(t *testing.T) {
+	provider := newFakeProvider(t, 1, 6)
+	provider.pageErr = errors.New("page is unavailable")
+	provider.pageErrIndex = 3
+
+	client := newTestClient(t, provider)
+	ctx := context.Background()
+
+	stream, err := client.ChapterPageStream(ctx, provider.chapterList()[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Close()
+
+	if stream.Len() != 6 {
+		t.Fatalf("got %d pages, want 6", stream.Len())
+	}
+
+	// pages come in order, the failed one doesn't stop the others
+	for i := 1; i <= 6; i++ {
+		page, ok, err := stream.Next(ctx)
+		if !ok {
+			t.Fatalf("stream ended at page %d", i)
+		}
+
+		if i == provider.pageErrIndex {
+			if !errors.Is(err, provider.pageErr) {
+				t.Errorf("page %d: got error %v, want the page error", i, err)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("page %d: %s", i, err)
+		}
+
+		if want := fmt.Sprintf("page %d", i); page.String() != want {
+			t.Errorf("got %s, want %s", page, want)
+		}
+
+		if !bytes.Equal(page.GetImage(), provider.image) {
+			t.Errorf("page %d image differs", i)
+		}
+	}
+
+	if _, ok, err := stream.Next(ctx); ok || err != nil {
+		t.Errorf("got %t, %v after the last page", ok, err)
+	}
+}
+
+func TestChapterPageStreamClose(t *testing.T) {
+	provider := newFakeProvider(t, 1, 20)
+	client := newTestClient(t, provider)
+	ctx := context.Background()
+
+	stream, err := client.ChapterPageStream(ctx, provider.chapterList()[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := stream.Next(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// closed streams don't download the remaining pages
+	if err := stream.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := provider.requests.Load()
+	for {
+		_, ok, err := stream.Next(ctx)
+		if !ok {
+			break
+		}
+
+		if err != nil && !errors.Is(err, context.Canceled) {
+			t.Fatal(err)
+		}
+	}
+
+	if provider.requests.Load() != requests {
+		t.Error("pages were downloaded after the stream was closed")
+	}
+}
@@ -0,0 +1,96 @@
+package libmangal
+
+import (
+	"fmt"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/spf13/afero"
+	"io"
+	"path/filepath"
+)
+
+// pdfChunks splits pages into chunks which images
+// fit into the budget in bytes. Each chunk has at least one page.
+// Zero budget means a single chunk.
+func pdfChunks(pages []PageWithImage, budget int64) [][]PageWithImage {
+	if budget <= 0 {
+		return [][]PageWithImage{pages}
+	}
+
+	var (
+		chunks [][]PageWithImage
+		chunk  []PageWithImage
+		size   int64
+	)
+
+	for _, page := range pages {
+		pageSize := int64(len(page.GetImage()))
+		if len(chunk) > 0 && size+pageSize > budget {
+			chunks = append(chunks, chunk)
+			chunk, size = nil, 0
+		}
+
+		chunk = append(chunk, page)
+		size += pageSize
+	}
+
+	return append(chunks, chunk)
+}
+
+// savePDFChunked imports each chunk of pages into its own document
+// and merges them, so that images of the whole volume are not
+// converted and imported at once.
+//
+// Intermediate documents are kept in the temporary directory
+// of the client filesystem instead of memory.
+func (c *Client) savePDFChunked(
+	chunks [][]PageWithImage,
+	out io.Writer,
+	properties map[string]string,
+) error {
+	c.options.Log(fmt.Sprintf("Assembling PDF from %d chunks", len(chunks)))
+
+	dir, err := afero.TempDir(c.options.FS, "", "libmangal-pdf-")
+	if err != nil {
+		return err
+	}
+	defer c.options.FS.RemoveAll(dir)
+
+	documents := make([]io.ReadSeeker, len(chunks))
+	for i, chunk := range chunks {
+		file, err := c.options.FS.Create(filepath.Join(dir, fmt.Sprintf("%04d.pdf", i+1)))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		if err := c.importPDFImages(chunk, file); err != nil {
+			return err
+		}
+
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+
+		documents[i] = file
+	}
+
+	if len(properties) == 0 {
+		return api.MergeRaw(documents, out, nil)
+	}
+
+	merged, err := c.options.FS.Create(filepath.Join(dir, "merged.pdf"))
+	if err != nil {
+		return err
+	}
+	defer merged.Close()
+
+	if err := api.MergeRaw(documents, merged, nil); err != nil {
+		return err
+	}
+
+	if _, err := merged.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	return api.AddProperties(merged, out, properties, nil)
+}
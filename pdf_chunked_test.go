@@ -0,0 +1,102 @@
+package libmangal
+
+import (
+	"bytes"
+	"context"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/spf13/afero"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPDFChunks(t *testing.T) {
+	sizes := func(chunks [][]PageWithImage) [][]int {
+		var got [][]int
+		for _, chunk := range chunks {
+			var chunkSizes []int
+			for _, page := range chunk {
+				chunkSizes = append(chunkSizes, len(page.GetImage()))
+			}
+
+			got = append(got, chunkSizes)
+		}
+
+		return got
+	}
+
+	var pages []PageWithImage
+	for _, size := range []int{4, 3, 5, 10, 1} {
+		pages = append(pages, testPages(1, make([]byte, size))...)
+	}
+
+	for _, test := range []struct {
+		budget int64
+		want   [][]int
+	}{
+		{0, [][]int{{4, 3, 5, 10, 1}}},
+		{100, [][]int{{4, 3, 5, 10, 1}}},
+		{8, [][]int{{4, 3}, {5}, {10}, {1}}},
+		// pages over the budget get their own chunk
+		{1, [][]int{{4}, {3}, {5}, {10}, {1}}},
+	} {
+		if got := sizes(pdfChunks(pages, test.budget)); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("budget %d: got %v, want %v", test.budget, got, test.want)
+		}
+	}
+}
+
+func TestSavePDFChunked(t *testing.T) {
+	provider := newFakeProvider(t, 1, 1)
+
+	options := testClientOptions()
+	options.PDFMemoryBudget = int64(2*len(provider.image) + 1)
+
+	client, err := NewClient(context.Background(), provider, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pages := testPages(5, provider.image)
+	if chunks := pdfChunks(pages, options.PDFMemoryBudget); len(chunks) != 3 {
+		t.Fatalf("got %d chunks, want 3", len(chunks))
+	}
+
+	properties := map[string]string{"Series": "Fake Manga"}
+
+	var buffer bytes.Buffer
+	if err := client.savePDF(pages, &buffer, properties); err != nil {
+		t.Fatal(err)
+	}
+
+	pageCount, err := api.PageCount(bytes.NewReader(buffer.Bytes()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pageCount != 5 {
+		t.Errorf("got %d pages, want 5", pageCount)
+	}
+
+	list, err := api.ListProperties(bytes.NewReader(buffer.Bytes()), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"Series = Fake Manga"}; !reflect.DeepEqual(list, want) {
+		t.Errorf("got properties %q, want %q", list, want)
+	}
+
+	// intermediate documents are removed
+	entries, err := afero.ReadDir(options.FS, os.TempDir())
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "libmangal-pdf-") {
+			t.Errorf("temporary directory %s is left", entry.Name())
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package libmangal
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	"math"
+)
+
+// protoField is a single decoded field of the protobuf message
+type protoField struct {
+	num     protowire.Number
+	typ     protowire.Type
+	varint  uint64
+	fixed32 uint32
+	fixed64 uint64
+	bytes   []byte
+}
+
+// protoFields calls fn for each field of the encoded protobuf message
+func protoFields(b []byte, fn func(field protoField) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+
+		b = b[n:]
+
+		field := protoField{num: num, typ: typ}
+		switch typ {
+		case protowire.VarintType:
+			field.varint, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed32Type:
+			field.fixed32, n = protowire.ConsumeFixed32(b)
+		case protowire.Fixed64Type:
+			field.fixed64, n = protowire.ConsumeFixed64(b)
+		case protowire.BytesType:
+			field.bytes, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+
+		b = b[n:]
+
+		if err := fn(field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p protoField) int64() int64 {
+	return int64(p.varint)
+}
+
+func (p protoField) int32() int32 {
+	return int32(p.varint)
+}
+
+func (p protoField) bool() bool {
+	return p.varint != 0
+}
+
+func (p protoField) string() string {
+	return string(p.bytes)
+}
+
+func (p protoField) float32() float32 {
+	return math.Float32frombits(p.fixed32)
+}
+
+// int64s returns values of the repeated integer field, which may be packed
+func (p protoField) int64s() ([]int64, error) {
+	if p.typ == protowire.VarintType {
+		return []int64{p.int64()}, nil
+	}
+
+	var values []int64
+	for b := p.bytes; len(b) > 0; {
+		value, n := protowire.ConsumeVarint(b)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+
+		values = append(values, int64(value))
+		b = b[n:]
+	}
+
+	return values, nil
+}
+
+// appendProtoVarint appends the integer field unless it's zero
+func appendProtoVarint(b []byte, num protowire.Number, value uint64) []byte {
+	if value == 0 {
+		return b
+	}
+
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, value)
+}
+
+// appendProtoBool appends the boolean field even if it's false
+func appendProtoBool(b []byte, num protowire.Number, value bool) []byte {
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, protowire.EncodeBool(value))
+}
+
+// appendProtoFloat32 appends the float field unless it's zero
+func appendProtoFloat32(b []byte, num protowire.Number, value float32) []byte {
+	if value == 0 {
+		return b
+	}
+
+	b = protowire.AppendTag(b, num, protowire.Fixed32Type)
+	return protowire.AppendFixed32(b, math.Float32bits(value))
+}
+
+// appendProtoString appends the string field unless it's empty
+func appendProtoString(b []byte, num protowire.Number, value string) []byte {
+	if value == "" {
+		return b
+	}
+
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, value)
+}
+
+// appendProtoMessage appends the encoded embedded message
+func appendProtoMessage(b []byte, num protowire.Number, message []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, message)
+}
@@ -0,0 +1,127 @@
+package libmangal
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/afero"
+	"strings"
+	"time"
+)
+
+// suffixProvenanceJSON is appended to the chapter path
+// to get the path of its Provenance sidecar
+const suffixProvenanceJSON = ".provenance.json"
+
+// ProvenanceOptions defines where the Provenance of the downloaded chapter is recorded
+type ProvenanceOptions struct {
+	// ArchiveComment writes it as the zip comment
+	// for FormatCBZ and FormatZIP
+	ArchiveComment bool
+
+	// PDFMetadata writes it to the document properties for FormatPDF
+	PDFMetadata bool
+
+	// Sidecar writes it as JSON next to the chapter. See ProvenancePath
+	Sidecar bool
+}
+
+// DefaultProvenanceOptions constructs default ProvenanceOptions
+func DefaultProvenanceOptions() ProvenanceOptions {
+	return ProvenanceOptions{
+		ArchiveComment: true,
+		PDFMetadata:    true,
+		Sidecar:        false,
+	}
+}
+
+// Provenance tells where the downloaded chapter came from,
+// so that it can be fetched again later, e.g. when the source fixes scans.
+type Provenance struct {
+	Provider        string    `json:"provider"`
+	ProviderVersion string    `json:"providerVersion"`
+	Manga           string    `json:"manga"`
+	MangaID         string    `json:"mangaId"`
+	Chapter         string    `json:"chapter"`
+	Number          float32   `json:"number"`
+	URL             string    `json:"url"`
+	DownloadedAt    time.Time `json:"downloadedAt"`
+	Libmangal       string    `json:"libmangal"`
+}
+
+// ProvenancePath returns the path of the Provenance sidecar
+// of the chapter downloaded at the given path
+func ProvenancePath(chapterPath string) string {
+	return chapterPath + suffixProvenanceJSON
+}
+
+func (c *Client) provenance(chapter Chapter) Provenance {
+	mangaInfo := chapter.Volume().Manga().Info()
+	chapterInfo := chapter.Info()
+
+	return Provenance{
+		Provider:        c.Info().ID,
+		ProviderVersion: c.Info().Version,
+		Manga:           mangaInfo.Title,
+		MangaID:         mangaInfo.ID,
+		Chapter:         chapterInfo.Title,
+		Number:          chapterInfo.Number,
+		URL:             chapterInfo.URL,
+		DownloadedAt:    time.Now().UTC(),
+		Libmangal:       Version,
+	}
+}
+
+// comment formats the provenance as the archive comment
+func (p Provenance) comment() string {
+	var b strings.Builder
+
+	for _, line := range [][2]string{
+		{"Provider", fmt.Sprintf("%s %s", p.Provider, p.ProviderVersion)},
+		{"Manga", p.Manga},
+		{"Chapter", fmt.Sprintf("%s (#%g)", p.Chapter, p.Number)},
+		{"URL", p.URL},
+		{"Downloaded", p.DownloadedAt.Format(time.RFC3339)},
+		{"Libmangal", p.Libmangal},
+	} {
+		fmt.Fprintf(&b, "%s: %s\n", line[0], line[1])
+	}
+
+	return b.String()
+}
+
+// pdfProperties formats the provenance as the PDF document properties
+func (p Provenance) pdfProperties() map[string]string {
+	return map[string]string{
+		"Title":           fmt.Sprintf("%s - %s", p.Manga, p.Chapter),
+		"Producer":        fmt.Sprintf("libmangal/%s", p.Libmangal),
+		"Provider":        p.Provider,
+		"ProviderVersion": p.ProviderVersion,
+		"SourceURL":       p.URL,
+		"DownloadedAt":    p.DownloadedAt.Format(time.RFC3339),
+	}
+}
+
+func (c *Client) writeProvenance(path string, provenance Provenance) error {
+	marshalled, err := json.MarshalIndent(provenance, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(c.options.FS, ProvenancePath(path), marshalled, modeFile)
+}
+
+// ReadProvenance reads the Provenance sidecar
+// of the chapter downloaded at the given path
+func (c *Client) ReadProvenance(chapterPath string) (Provenance, error) {
+	contents, err := afero.ReadFile(c.options.FS, ProvenancePath(chapterPath))
+	if err != nil {
+		return Provenance{}, err
+	}
+
+	var provenance Provenance
+	if err := json.Unmarshal(contents, &provenance); err != nil {
+		return Provenance{}, err
+	}
+
+	return provenance, nil
+}
@@ -0,0 +1,210 @@
+package libmangal
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"github.com/philippgille/gokv"
+	"sort"
+)
+
+// SignaturePolicy defines how the provider bundles without
+// a trusted signature are treated. See NewVerifiedLoader
+type SignaturePolicy uint8
+
+const (
+	// SignatureRequired refuses to load unsigned and untrusted providers
+	SignatureRequired SignaturePolicy = iota
+
+	// SignatureWarn loads unsigned and untrusted providers with a warning
+	SignatureWarn
+
+	// SignatureOptional loads unsigned and untrusted providers silently
+	SignatureOptional
+)
+
+// SignedProviderLoader is the ProviderLoader that can expose
+// the bundle it loads the provider from, e.g. the script contents,
+// along with its Ed25519 signature.
+type SignedProviderLoader interface {
+	ProviderLoader
+
+	// Bundle returns the contents the provider is loaded from
+	Bundle() ([]byte, error)
+
+	// Signature returns the Ed25519 signature of the bundle.
+	// Nil means the bundle is not signed.
+	Signature() ([]byte, error)
+
+	// LoadBundle loads the provider from the given bundle contents
+	// instead of reading them again, so that the provider is loaded
+	// from exactly the bundle which signature was verified.
+	LoadBundle(ctx context.Context, bundle []byte) (Provider, error)
+}
+
+// SignProviderBundle signs the provider bundle with the publisher key
+func SignProviderBundle(key ed25519.PrivateKey, bundle []byte) []byte {
+	return ed25519.Sign(key, bundle)
+}
+
+// TrustStore holds public keys of the trusted provider publishers.
+// It's safe for concurrent use if the store is.
+type TrustStore struct {
+	index *storeIndex
+}
+
+// NewTrustStore constructs new TrustStore backed by the given store
+func NewTrustStore(store gokv.Store) *TrustStore {
+	return &TrustStore{index: newStoreIndex(store)}
+}
+
+// Trust adds the publisher key to the store
+func (t *TrustStore) Trust(publisher string, key ed25519.PublicKey) error {
+	if len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid public key size: %d", len(key))
+	}
+
+	return t.index.set(publisher, base64.StdEncoding.EncodeToString(key))
+}
+
+// Revoke removes the publisher key from the store
+func (t *TrustStore) Revoke(publisher string) error {
+	return t.index.delete(publisher)
+}
+
+// Publishers returns names of the trusted publishers
+func (t *TrustStore) Publishers() ([]string, error) {
+	publishers, err := t.index.keys()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(publishers)
+	return publishers, nil
+}
+
+// Verify returns the trusted publisher who signed the bundle.
+// False is returned if none of them did.
+func (t *TrustStore) Verify(bundle, signature []byte) (string, bool, error) {
+	publishers, err := t.Publishers()
+	if err != nil {
+		return "", false, err
+	}
+
+	for _, publisher := range publishers {
+		var encoded string
+		found, err := t.index.store.Get(publisher, &encoded)
+		if err != nil {
+			return "", false, err
+		}
+
+		if !found {
+			continue
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			continue
+		}
+
+		if ed25519.Verify(key, bundle, signature) {
+			return publisher, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// verifiedLoader checks the bundle signature before loading the provider
+type verifiedLoader struct {
+	ProviderLoader
+
+	trust  *TrustStore
+	policy SignaturePolicy
+	log    LogFunc
+}
+
+// NewVerifiedLoader wraps the loader so that the provider is loaded only if
+// its bundle is signed by the trusted publisher, according to the policy.
+//
+// Loaders not implementing SignedProviderLoader are considered unsigned.
+// Nil log discards the messages.
+func NewVerifiedLoader(
+	loader ProviderLoader,
+	trust *TrustStore,
+	policy SignaturePolicy,
+	log LogFunc,
+) ProviderLoader {
+	if log == nil {
+		log = func(string) {}
+	}
+
+	return verifiedLoader{
+		ProviderLoader: loader,
+		trust:          trust,
+		policy:         policy,
+		log:            log,
+	}
+}
+
+func (v verifiedLoader) Load(ctx context.Context) (Provider, error) {
+	signed, ok := v.ProviderLoader.(SignedProviderLoader)
+	if !ok {
+		if err := v.untrusted(ProviderSignatureError{Provider: v.Info().ID, Reason: "not signed"}); err != nil {
+			return nil, err
+		}
+
+		return v.ProviderLoader.Load(ctx)
+	}
+
+	// bundle is read once, so that it can't change between verification and loading
+	bundle, err := signed.Bundle()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := v.verify(signed, bundle); err != nil {
+		return nil, err
+	}
+
+	return signed.LoadBundle(ctx, bundle)
+}
+
+func (v verifiedLoader) verify(signed SignedProviderLoader, bundle []byte) error {
+	provider := v.Info().ID
+
+	signature, err := signed.Signature()
+	if err != nil {
+		return err
+	}
+
+	if len(signature) == 0 {
+		return v.untrusted(ProviderSignatureError{Provider: provider, Reason: "not signed"})
+	}
+
+	publisher, trusted, err := v.trust.Verify(bundle, signature)
+	if err != nil {
+		return err
+	}
+
+	if !trusted {
+		return v.untrusted(ProviderSignatureError{Provider: provider, Reason: "not signed by a trusted publisher"})
+	}
+
+	v.log(fmt.Sprintf("Provider %q is signed by %q", provider, publisher))
+	return nil
+}
+
+// untrusted applies the policy to the provider that failed verification
+func (v verifiedLoader) untrusted(err ProviderSignatureError) error {
+	switch v.policy {
+	case SignatureWarn:
+		v.log(fmt.Sprintf("Warning: %s", err))
+		return nil
+	case SignatureOptional:
+		return nil
+	default:
+		return err
+	}
+}
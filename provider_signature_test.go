@@ -0,0 +1,139 @@
+package libmangal
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"github.com/philippgille/gokv/syncmap"
+	"testing"
+)
+
+// signedFakeLoader serves the provider from the bundle,
+// which is swapped for the tampered one after the first read
+type signedFakeLoader struct {
+	*fakeProvider
+
+	bundle, tampered []byte
+	signature        []byte
+	reads            int
+
+	// loaded is the bundle the provider was loaded from
+	loaded []byte
+}
+
+func (s *signedFakeLoader) Bundle() ([]byte, error) {
+	s.reads++
+	if s.reads > 1 {
+		return s.tampered, nil
+	}
+
+	return s.bundle, nil
+}
+
+func (s *signedFakeLoader) Signature() ([]byte, error) {
+	return s.signature, nil
+}
+
+func (s *signedFakeLoader) Load(ctx context.Context) (Provider, error) {
+	bundle, err := s.Bundle()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.LoadBundle(ctx, bundle)
+}
+
+func (s *signedFakeLoader) LoadBundle(_ context.Context, bundle []byte) (Provider, error) {
+	s.loaded = bundle
+	return s.fakeProvider, nil
+}
+
+func newTestTrustStore(t *testing.T) (*TrustStore, ed25519.PrivateKey) {
+	t.Helper()
+
+	public, private, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trust := NewTrustStore(syncmap.NewStore(syncmap.DefaultOptions))
+	if err := trust.Trust("publisher", public); err != nil {
+		t.Fatal(err)
+	}
+
+	return trust, private
+}
+
+func TestVerifiedLoaderLoadsVerifiedBundle(t *testing.T) {
+	trust, key := newTestTrustStore(t)
+
+	bundle := []byte("provider")
+	loader := &signedFakeLoader{
+		fakeProvider: newFakeProvider(t, 0, 0),
+		bundle:       bundle,
+		tampered:     []byte("tampered provider"),
+		signature:    SignProviderBundle(key, bundle),
+	}
+
+	verified := NewVerifiedLoader(loader, trust, SignatureRequired, func(string) {})
+	if _, err := verified.Load(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if string(loader.loaded) != string(bundle) {
+		t.Errorf("provider was loaded from %q, want the verified %q", loader.loaded, bundle)
+	}
+}
+
+func TestVerifiedLoaderRejectsUntrusted(t *testing.T) {
+	trust, _ := newTestTrustStore(t)
+	_, untrustedKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bundle := []byte("provider")
+	loader := &signedFakeLoader{
+		fakeProvider: newFakeProvider(t, 0, 0),
+		bundle:       bundle,
+		signature:    SignProviderBundle(untrustedKey, bundle),
+	}
+
+	verified := NewVerifiedLoader(loader, trust, SignatureRequired, func(string) {})
+
+	var signatureErr ProviderSignatureError
+	if _, err := verified.Load(context.Background()); !errors.As(err, &signatureErr) {
+		t.Errorf("got %v, want ProviderSignatureError", err)
+	}
+
+	if loader.loaded != nil {
+		t.Error("untrusted provider was loaded")
+	}
+}
+
+func TestVerifiedLoaderUnsigned(t *testing.T) {
+	trust, _ := newTestTrustStore(t)
+	provider := newFakeProvider(t, 0, 0)
+
+	var signatureErr ProviderSignatureError
+	required := NewVerifiedLoader(provider, trust, SignatureRequired, func(string) {})
+	if _, err := required.Load(context.Background()); !errors.As(err, &signatureErr) {
+		t.Errorf("required: got %v, want ProviderSignatureError", err)
+	}
+
+	var warnings int
+	warn := NewVerifiedLoader(provider, trust, SignatureWarn, func(string) { warnings++ })
+	if _, err := warn.Load(context.Background()); err != nil {
+		t.Errorf("warn: %s", err)
+	}
+
+	if warnings != 1 {
+		t.Errorf("warn: got %d warnings, want 1", warnings)
+	}
+
+	// nil log discards the warning
+	silent := NewVerifiedLoader(provider, trust, SignatureWarn, nil)
+	if _, err := silent.Load(context.Background()); err != nil {
+		t.Errorf("nil log: %s", err)
+	}
+}
@@ -0,0 +1,237 @@
+package libmangal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/spf13/afero"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeManga, fakeVolume, fakeChapter and fakePage are the in-memory
+// manga data served by fakeProvider
+type (
+	fakeManga struct {
+		info MangaInfo
+	}
+
+	fakeVolume struct {
+		number int
+		manga  fakeManga
+	}
+
+	fakeChapter struct {
+		info   ChapterInfo
+		volume fakeVolume
+	}
+
+	fakePage struct {
+		index   int
+		chapter fakeChapter
+	}
+)
+
+func (m fakeManga) String() string  { return m.info.Title }
+func (m fakeManga) Info() MangaInfo { return m.info }
+
+func (v fakeVolume) String() string   { return fmt.Sprintf("Vol. %d", v.number) }
+func (v fakeVolume) Info() VolumeInfo { return VolumeInfo{Number: v.number} }
+func (v fakeVolume) Manga() Manga     { return v.manga }
+
+func (c fakeChapter) String() string    { return c.info.Title }
+func (c fakeChapter) Info() ChapterInfo { return c.info }
+func (c fakeChapter) Volume() Volume    { return c.volume }
+
+// ComicInfoXML keeps CBZ downloads from querying Anilist
+func (c fakeChapter) ComicInfoXML() (ComicInfoXML, error) {
+	return ComicInfoXML{
+		Title:  c.info.Title,
+		Series: c.volume.manga.info.Title,
+		Number: c.info.Number,
+	}, nil
+}
+
+func (p fakePage) String() string       { return fmt.Sprintf("page %d", p.index) }
+func (p fakePage) GetExtension() string { return ".jpg" }
+func (p fakePage) Chapter() Chapter     { return p.chapter }
+
+// fakeProvider serves chapters with generated JPEG pages.
+// It's safe for concurrent use.
+type fakeProvider struct {
+	chapters int
+	pages    int
+
+	// image is served for every page
+	image []byte
+
+	// pageErr is returned for the page with this index if non-zero
+	pageErr      error
+	pageErrIndex int
+
+	// requests counts GetPageImage calls
+	requests atomic.Int64
+}
+
+var fakeProviderInfo = ProviderInfo{
+	ID:      "fake",
+	Name:    "Fake",
+	Version: "0.1.0",
+}
+
+func newFakeProvider(tb testing.TB, chapters, pages int) *fakeProvider {
+	tb.Helper()
+
+	return &fakeProvider{
+		chapters: chapters,
+		pages:    pages,
+		image:    testJPEG(tb, 200, 300),
+	}
+}
+
+func (f *fakeProvider) String() string     { return fakeProviderInfo.Name }
+func (f *fakeProvider) Info() ProviderInfo { return fakeProviderInfo }
+
+func (f *fakeProvider) Load(context.Context) (Provider, error) {
+	return f, nil
+}
+
+func (f *fakeProvider) manga() fakeManga {
+	return fakeManga{info: MangaInfo{
+		Title: "Fake Manga",
+		ID:    "fake-manga",
+		URL:   "https://example.com/manga",
+	}}
+}
+
+func (f *fakeProvider) volume() fakeVolume {
+	return fakeVolume{number: 1, manga: f.manga()}
+}
+
+func (f *fakeProvider) chapterList() []Chapter {
+	chapters := make([]Chapter, f.chapters)
+	for i := range chapters {
+		chapters[i] = fakeChapter{
+			info: ChapterInfo{
+				Title:  fmt.Sprintf("Chapter %d", i+1),
+				URL:    fmt.Sprintf("https://example.com/chapter/%d", i+1),
+				Number: float32(i + 1),
+			},
+			volume: f.volume(),
+		}
+	}
+
+	return chapters
+}
+
+func (f *fakeProvider) SearchMangas(context.Context, LogFunc, string) ([]Manga, error) {
+	return []Manga{f.manga()}, nil
+}
+
+func (f *fakeProvider) MangaVolumes(context.Context, LogFunc, Manga) ([]Volume, error) {
+	return []Volume{f.volume()}, nil
+}
+
+func (f *fakeProvider) VolumeChapters(context.Context, LogFunc, Volume) ([]Chapter, error) {
+	return f.chapterList(), nil
+}
+
+func (f *fakeProvider) ChapterPages(_ context.Context, _ LogFunc, chapter Chapter) ([]Page, error) {
+	// chapters from the fallback providers are wrapped
+	if fallback, ok := chapter.(fallbackChapter); ok {
+		chapter = fallback.Chapter
+	}
+
+	pages := make([]Page, f.pages)
+	for i := range pages {
+		pages[i] = fakePage{index: i + 1, chapter: chapter.(fakeChapter)}
+	}
+
+	return pages, nil
+}
+
+func (f *fakeProvider) GetPageImage(ctx context.Context, _ LogFunc, page Page) ([]byte, error) {
+	f.requests.Add(1)
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if f.pageErr != nil && page.(fakePage).index == f.pageErrIndex {
+		return nil, f.pageErr
+	}
+
+	return f.image, nil
+}
+
+// testJPEG encodes the gradient image of the given size
+func testJPEG(tb testing.TB, width, height int) []byte {
+	tb.Helper()
+
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, testImage(width, height), nil); err != nil {
+		tb.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func testImage(width, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	return img
+}
+
+// testClientOptions use the in-memory file system
+func testClientOptions() ClientOptions {
+	options := DefaultClientOptions()
+	options.FS = afero.NewMemMapFs()
+	return options
+}
+
+// newTestClient constructs the client of the provider
+// with the in-memory filesystem
+func newTestClient(tb testing.TB, provider *fakeProvider) *Client {
+	tb.Helper()
+
+	client, err := NewClient(context.Background(), provider, testClientOptions())
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	return client
+}
+
+// testDownloadOptions don't write metadata that requires Anilist
+func testDownloadOptions() DownloadOptions {
+	options := DefaultDownloadOptions()
+	options.Directory = "/library"
+	options.Format = FormatCBZ
+	options.Strict = false
+	options.SkipIfExists = true
+	return options
+}
+
+// testPages constructs n downloaded pages with the given image
+func testPages(n int, image []byte) []PageWithImage {
+	provider := &fakeProvider{chapters: 1}
+	chapter := provider.chapterList()[0].(fakeChapter)
+
+	pages := make([]PageWithImage, n)
+	for i := range pages {
+		pages[i] = &pageWithImage{
+			Page:  fakePage{index: i + 1, chapter: chapter},
+			image: image,
+		}
+	}
+
+	return pages
+}
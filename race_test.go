@@ -0,0 +1,7 @@
+//go:build race
+
+package libmangal
+
+// raceEnabled is true when tests are built with the race detector,
+// which inflates allocations
+const raceEnabled = true
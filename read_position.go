@@ -0,0 +1,63 @@
+package libmangal
+
+import (
+	"time"
+)
+
+// ReadPosition is the last read position inside the chapter
+type ReadPosition struct {
+	// Page is the index of the last read page starting from 0
+	Page int `json:"page"`
+
+	// Pages is the total number of pages in the chapter.
+	// It's 0 if unknown.
+	Pages int `json:"pages"`
+
+	// UpdatedAt is the time the position was recorded
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// Finished reports whether the last page of the chapter was reached
+func (r ReadPosition) Finished() bool {
+	return r.Pages > 0 && r.Page >= r.Pages-1
+}
+
+// SetReadPosition records the last read page of the chapter,
+// so reading can be resumed later. See ResumePosition.
+//
+// It does nothing if ClientOptions.ReadPositionStore is nil.
+func (c *Client) SetReadPosition(chapter Chapter, page, pages int) error {
+	store := c.options.ReadPositionStore
+	if store == nil {
+		return nil
+	}
+
+	return store.Set(chapterKey(c.Info().ID, chapter), ReadPosition{
+		Page:      page,
+		Pages:     pages,
+		UpdatedAt: time.Now(),
+	})
+}
+
+// ResumePosition returns the last recorded read position of the chapter.
+// False is returned if the chapter wasn't read before.
+func (c *Client) ResumePosition(chapter Chapter) (ReadPosition, bool, error) {
+	store := c.options.ReadPositionStore
+	if store == nil {
+		return ReadPosition{}, false, nil
+	}
+
+	var position ReadPosition
+	found, err := store.Get(chapterKey(c.Info().ID, chapter), &position)
+	return position, found, err
+}
+
+// ResetReadPosition removes the recorded read position of the chapter
+func (c *Client) ResetReadPosition(chapter Chapter) error {
+	store := c.options.ReadPositionStore
+	if store == nil {
+		return nil
+	}
+
+	return store.Delete(chapterKey(c.Info().ID, chapter))
+}
@@ -0,0 +1,104 @@
+package libmangal
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+)
+
+// ReadingListBook is an entry of the ReadingList
+type ReadingListBook struct {
+	Series string `xml:"Series,attr"`
+	Number string `xml:"Number,attr"`
+	Volume int    `xml:"Volume,attr,omitempty"`
+	Year   int    `xml:"Year,attr,omitempty"`
+}
+
+// ReadingList is the ComicRack reading list (.cbl).
+// Comic readers use it to keep the reading order
+// of chapters across series, e.g. of a story arc.
+type ReadingList struct {
+	Name  string
+	Books []ReadingListBook
+}
+
+// NewReadingList constructs ReadingList of the chapters in the given order.
+// Books are matched by readers using the same Series and Number
+// as written to ComicInfo.xml.
+func NewReadingList(name string, chapters []Chapter) ReadingList {
+	books := make([]ReadingListBook, len(chapters))
+	for i, chapter := range chapters {
+		volume := chapter.Volume()
+
+		books[i] = ReadingListBook{
+			Series: volume.Manga().Info().Title,
+			Number: strconv.FormatFloat(float64(chapter.Info().Number), 'f', -1, 32),
+			Volume: volume.Info().Number,
+		}
+	}
+
+	return ReadingList{
+		Name:  name,
+		Books: books,
+	}
+}
+
+type readingListWrapper struct {
+	// XMLName is a meta field that must be left unchanged
+	XMLName xml.Name `xml:"ReadingList"`
+	// XmlnsXsi is a meta field that must be left unchanged
+	XmlnsXsi string `xml:"xmlns:xsi,attr"`
+	// XmlnsXsd is a meta field that must be left unchanged.
+	XmlnsXsd string `xml:"xmlns:xsd,attr"`
+
+	Name  string            `xml:"Name"`
+	Books []ReadingListBook `xml:"Books>Book"`
+}
+
+// WriteCBL writes the reading list in the .cbl format
+func (r ReadingList) WriteCBL(out io.Writer) error {
+	marshalled, err := xml.MarshalIndent(readingListWrapper{
+		XmlnsXsi: "http://www.w3.org/2001/XMLSchema-instance",
+		XmlnsXsd: "http://www.w3.org/2001/XMLSchema",
+		Name:     r.Name,
+		Books:    r.Books,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.WriteString(out, xml.Header); err != nil {
+		return err
+	}
+
+	_, err = out.Write(marshalled)
+	return err
+}
+
+// SaveReadingList saves the reading list as "<name>.cbl"
+// in the library root, that is DownloadOptions.Directory.
+//
+// It returns the path of the saved file.
+func (c *Client) SaveReadingList(list ReadingList, options DownloadOptions) (string, error) {
+	path := filepath.Join(options.Directory, sanitizePath(list.Name)+".cbl")
+
+	c.options.Log(fmt.Sprintf("Saving reading list %q to %s", list.Name, path))
+
+	if err := c.options.FS.MkdirAll(options.Directory, modeDir); err != nil {
+		return "", err
+	}
+
+	file, err := c.options.FS.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if err := list.WriteCBL(file); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
@@ -0,0 +1,56 @@
+package libmangal
+
+import (
+	"bytes"
+	"github.com/spf13/afero"
+	"testing"
+)
+
+const testReadingListCBL = `<?xml version="1.0" encoding="UTF-8"?>
+<ReadingList xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns:xsd="http://www.w3.org/2001/XMLSchema">
+  <Name>Story Arc</Name>
+  <Books>
+    <Book Series="Fake Manga" Number="2" Volume="1"></Book>
+    <Book Series="Fake Manga" Number="1.5" Volume="1"></Book>
+  </Books>
+</ReadingList>`
+
+func TestReadingList(t *testing.T) {
+	provider := newFakeProvider(t, 2, 1)
+	chapters := provider.chapterList()
+
+	// the order of the chapters is kept
+	extra := chapters[0].(fakeChapter)
+	extra.info.Number = 1.5
+
+	list := NewReadingList("Story Arc", []Chapter{chapters[1], extra})
+
+	var buffer bytes.Buffer
+	if err := list.WriteCBL(&buffer); err != nil {
+		t.Fatal(err)
+	}
+
+	if buffer.String() != testReadingListCBL {
+		t.Errorf("got\n%s\nwant\n%s", buffer.String(), testReadingListCBL)
+	}
+
+	client := newTestClient(t, provider)
+
+	path, err := client.SaveReadingList(list, testDownloadOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if path != "/library/Story Arc.cbl" {
+		t.Errorf("saved to %s", path)
+	}
+
+	saved, err := afero.ReadFile(client.options.FS, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(saved) != testReadingListCBL {
+		t.Errorf("saved reading list differs:\n%s", saved)
+	}
+}
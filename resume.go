@@ -0,0 +1,139 @@
+package libmangal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/afero"
+	"golang.org/x/sync/errgroup"
+	"path/filepath"
+)
+
+const (
+	// suffixPartial is appended to the chapter path to get the directory
+	// where pages of the interrupted download are kept.
+	// Such directories are removed by Client.CleanCaches,
+	// so the suffix must not match directories of the other tools.
+	suffixPartial = ".libmangal-partial"
+
+	filenamePartialManifest = "manifest.json"
+)
+
+// partialPath returns the directory with the downloaded pages
+// of the chapter which download is not finished yet
+func partialPath(chapterPath string) string {
+	return chapterPath + suffixPartial
+}
+
+// partialManifest describes pages kept in the partial directory
+type partialManifest struct {
+	// Fingerprint of the chapter the pages belong to.
+	// Pages are discarded if the chapter has changed since.
+	Fingerprint ChapterFingerprint `json:"fingerprint"`
+}
+
+func partialPageName(index int) string {
+	return fmt.Sprintf("%04d", index+1)
+}
+
+// downloadPagesResumable downloads pages like DownloadPagesInBatch,
+// but keeps each downloaded page in the partial directory,
+// so that the interrupted download continues from where it stopped.
+//
+// The directory is removed after the chapter is saved, see downloadChapterToFS
+func (c *Client) downloadPagesResumable(
+	ctx context.Context,
+	fs afero.Fs,
+	dir string,
+	pages []Page,
+	fingerprint ChapterFingerprint,
+) ([]PageWithImage, error) {
+	downloadedPages := make([]PageWithImage, len(pages))
+
+	manifestPath := filepath.Join(dir, filenamePartialManifest)
+
+	var manifest partialManifest
+	if contents, err := afero.ReadFile(fs, manifestPath); err == nil {
+		if err := json.Unmarshal(contents, &manifest); err != nil {
+			c.options.Log(fmt.Sprintf("Discarding partial download: %s", err))
+		}
+	}
+
+	if manifest.Fingerprint.Hash == fingerprint.Hash {
+		for i, page := range pages {
+			image, err := afero.ReadFile(fs, filepath.Join(dir, partialPageName(i)))
+			if err != nil {
+				continue
+			}
+
+			downloadedPages[i] = &pageWithImage{
+				Page:  page,
+				image: image,
+			}
+		}
+	} else {
+		if err := fs.RemoveAll(dir); err != nil {
+			return nil, err
+		}
+
+		if err := fs.MkdirAll(dir, modeDir); err != nil {
+			return nil, err
+		}
+
+		marshalled, err := json.Marshal(partialManifest{Fingerprint: fingerprint})
+		if err != nil {
+			return nil, err
+		}
+
+		if err := afero.WriteFile(fs, manifestPath, marshalled, modeFile); err != nil {
+			return nil, err
+		}
+	}
+
+	var missing int
+	for _, page := range downloadedPages {
+		if page == nil {
+			missing++
+		}
+	}
+
+	if missing < len(pages) {
+		c.options.Log(fmt.Sprintf("Resuming download: %d of %d pages left", missing, len(pages)))
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	for i, page := range pages {
+		if downloadedPages[i] != nil {
+			continue
+		}
+
+		i, page := i, page
+		g.Go(func() error {
+			downloaded, err := c.DownloadPage(contextWithPageIndex(ctx, i+1), page)
+			if err != nil {
+				return err
+			}
+
+			// written under the temporary name first,
+			// so that a crash doesn't leave a truncated page
+			path := filepath.Join(dir, partialPageName(i))
+			if err := afero.WriteFile(fs, path+".tmp", downloaded.GetImage(), modeFile); err != nil {
+				return err
+			}
+
+			if err := fs.Rename(path+".tmp", path); err != nil {
+				return err
+			}
+
+			downloadedPages[i] = downloaded
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	return downloadedPages, nil
+}
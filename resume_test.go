@@ -0,0 +1,71 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"github.com/spf13/afero"
+	"testing"
+)
+
+func TestDownloadChapterResume(t *testing.T) {
+	const pages = 4
+
+	provider := newFakeProvider(t, 1, pages)
+	provider.pageErr = errors.New("connection lost")
+	provider.pageErrIndex = pages
+
+	client := newTestClient(t, provider)
+	chapter := provider.chapterList()[0]
+	fs := client.options.FS
+
+	// pages are not kept by default
+	options := testDownloadOptions()
+	if options.Resume {
+		t.Fatal("resume is enabled by default")
+	}
+
+	path, err := client.ChapterPath(chapter, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.DownloadChapter(context.Background(), chapter, options); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if exists, _ := afero.Exists(fs, partialPath(path)); exists {
+		t.Error("partial directory was created without Resume")
+	}
+
+	options.Resume = true
+	if _, err := client.DownloadChapter(context.Background(), chapter, options); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	entries, err := afero.ReadDir(fs, partialPath(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the manifest and the pages downloaded before the failure
+	kept := len(entries) - 1
+
+	provider.pageErr = nil
+	provider.requests.Store(0)
+
+	if _, err := client.DownloadChapter(context.Background(), chapter, options); err != nil {
+		t.Fatal(err)
+	}
+
+	if requests := provider.requests.Load(); requests != int64(pages-kept) {
+		t.Errorf("requested %d pages, want %d", requests, pages-kept)
+	}
+
+	if exists, _ := afero.Exists(fs, path); !exists {
+		t.Error("chapter was not saved")
+	}
+
+	if exists, _ := afero.Exists(fs, partialPath(path)); exists {
+		t.Error("partial directory was not removed")
+	}
+}
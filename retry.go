@@ -0,0 +1,123 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy configures retrying of page and image downloads,
+// so that a single transient error doesn't abort the whole chapter.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts.
+	// One or less disables retrying.
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry.
+	// It's doubled for each next one.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts,
+	// including the one requested by Retry-After.
+	MaxBackoff time.Duration
+
+	// Retryable reports whether the error is transient.
+	//
+	// Nil value retries 5xx and 429 responses (see HTTPStatusError),
+	// corrupt pages (see PageCorruptError), timeouts,
+	// connection resets and unexpected EOFs.
+	Retryable func(err error) bool
+}
+
+// DefaultRetryPolicy constructs default RetryPolicy
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+	}
+}
+
+// isRetryableError is the default RetryPolicy.Retryable
+func isRetryableError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var statusErr HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+
+	// sources serve error pages in place of images under load
+	var corruptErr PageCorruptError
+	if errors.As(err, &corruptErr) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// do calls fn until it succeeds, fails with the non-retryable
+// error or the attempts are exhausted
+func (r RetryPolicy) do(ctx context.Context, log LogFunc, what string, fn func() error) error {
+	retryable := r.Retryable
+	if retryable == nil {
+		retryable = isRetryableError
+	}
+
+	backoff := r.InitialBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= r.MaxAttempts || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		delay := backoff
+		var statusErr HTTPStatusError
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > delay {
+			delay = statusErr.RetryAfter
+		}
+
+		if r.MaxBackoff > 0 && delay > r.MaxBackoff {
+			delay = r.MaxBackoff
+		}
+
+		log(fmt.Sprintf("%s: %s, retrying in %s (%d/%d)", what, err, delay, attempt, r.MaxAttempts-1))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		backoff *= 2
+	}
+}
+
+// parseRetryAfter parses the Retry-After header given in seconds.
+// Zero is returned if it's missing or is an HTTP date.
+func parseRetryAfter(header http.Header) time.Duration {
+	seconds, err := strconv.Atoi(header.Get("Retry-After"))
+	if err != nil || seconds < 0 {
+		return 0
+	}
+
+	return time.Duration(seconds) * time.Second
+}
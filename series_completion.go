@@ -0,0 +1,105 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+	"math"
+)
+
+// IsSeriesComplete reports whether the series has ended on Anilist
+// and the provider has all of its chapters, i.e. no new chapters are expected.
+//
+// The result is recorded for the categorized mangas, see CategorizedManga.Completed.
+// Watcher stops polling completed series.
+func (c *Client) IsSeriesComplete(ctx context.Context, manga Manga) (bool, error) {
+	chapters, err := c.PreferredChapters(ctx, manga)
+	if err != nil {
+		return false, err
+	}
+
+	complete, err := c.seriesComplete(ctx, manga, chapters)
+	if err != nil {
+		return false, err
+	}
+
+	if err := c.setSeriesCompleted(manga.Info(), complete); err != nil {
+		return false, err
+	}
+
+	return complete, nil
+}
+
+// seriesComplete checks completion of the manga with the given provider chapters
+func (c *Client) seriesComplete(ctx context.Context, manga Manga, chapters []Chapter) (bool, error) {
+	mangaWithAnilist, ok, err := c.Anilist().MakeMangaWithAnilist(ctx, manga)
+	if err != nil {
+		return false, err
+	}
+
+	if !ok {
+		return false, nil
+	}
+
+	anilist := mangaWithAnilist.Anilist
+	if anilist.Status != "FINISHED" && anilist.Status != "CANCELLED" {
+		return false, nil
+	}
+
+	// chapters count may be unknown even for the finished series
+	if anilist.Chapters == 0 {
+		return len(chapters) > 0, nil
+	}
+
+	available := make(map[int]struct{}, len(chapters))
+	for _, chapter := range chapters {
+		// chapters like 10.5 are extras of the chapter 10
+		available[int(math.Trunc(float64(chapter.Info().Number)))] = struct{}{}
+	}
+
+	for number := 1; number <= anilist.Chapters; number++ {
+		if _, ok := available[number]; !ok {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// setSeriesCompleted records completion of the manga if it's categorized
+func (c *Client) setSeriesCompleted(info MangaInfo, completed bool) error {
+	if c.categories == nil {
+		return nil
+	}
+
+	store := c.categories
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	key := c.categorizedMangaKey(info)
+
+	var categorized CategorizedManga
+	found, err := store.index.store.Get(key, &categorized)
+	if err != nil || !found || categorized.Completed == completed {
+		return err
+	}
+
+	if completed {
+		c.options.Log(fmt.Sprintf("Series %q is complete", info.Title))
+	}
+
+	categorized.Completed = completed
+	return store.index.set(key, categorized)
+}
+
+// SeriesCompleted returns whether the categorized manga was found complete
+// by IsSeriesComplete or the Watcher
+func (c *Client) SeriesCompleted(info MangaInfo) (bool, error) {
+	if c.categories == nil {
+		return false, nil
+	}
+
+	var categorized CategorizedManga
+	_, err := c.categories.index.store.Get(c.categorizedMangaKey(info), &categorized)
+	return categorized.Completed, err
+}
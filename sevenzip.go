@@ -0,0 +1,196 @@
+package libmangal
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"unicode/utf16"
+)
+
+// 7z property IDs
+const (
+	sevenZipEnd             = 0x00
+	sevenZipHeader          = 0x01
+	sevenZipMainStreams     = 0x04
+	sevenZipFilesInfo       = 0x05
+	sevenZipPackInfo        = 0x06
+	sevenZipUnpackInfo      = 0x07
+	sevenZipSubStreams      = 0x08
+	sevenZipSize            = 0x09
+	sevenZipCRC             = 0x0A
+	sevenZipFolder          = 0x0B
+	sevenZipCodersUnpack    = 0x0C
+	sevenZipNumUnpackStream = 0x0D
+	sevenZipEmptyStream     = 0x0E
+	sevenZipEmptyFile       = 0x0F
+	sevenZipName            = 0x11
+)
+
+// sevenZipSignature starts every 7z archive
+var sevenZipSignature = []byte{'7', 'z', 0xBC, 0xAF, 0x27, 0x1C}
+
+// appendSevenZipNumber appends the variable length 7z number.
+// Leading ones of the first byte count the following bytes,
+// the rest of the first byte holds the highest bits.
+func appendSevenZipNumber(b []byte, v uint64) []byte {
+	if v < 0x80 {
+		return append(b, byte(v))
+	}
+
+	for n := 1; n <= 8; n++ {
+		if n < 8 && v >= 1<<(8*n+7-n) {
+			continue
+		}
+
+		first := byte(0xFF << (8 - n))
+		if n < 8 {
+			first |= byte(v >> (8 * n))
+		}
+
+		b = append(b, first)
+		for i := 0; i < n; i++ {
+			b = append(b, byte(v>>(8*i)))
+		}
+
+		return b
+	}
+
+	return b
+}
+
+// appendSevenZipBits appends the bit vector, most significant bit first
+func appendSevenZipBits(b []byte, bits []bool) []byte {
+	vector := make([]byte, (len(bits)+7)/8)
+	for i, bit := range bits {
+		if bit {
+			vector[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+
+	return append(b, vector...)
+}
+
+// write7z writes the 7z archive with the given files.
+// Files are stored as a single solid stream without compression.
+// Empty files have no stream and are marked with kEmptyStream.
+func write7z(out io.Writer, names []string, files [][]byte) error {
+	if len(files) == 0 {
+		return errors.New("7z archive must contain files")
+	}
+
+	var (
+		packSize uint64
+		streams  [][]byte
+		empty    = make([]bool, len(files))
+	)
+
+	for i, file := range files {
+		if len(file) == 0 {
+			empty[i] = true
+			continue
+		}
+
+		packSize += uint64(len(file))
+		streams = append(streams, file)
+	}
+
+	header := []byte{sevenZipHeader}
+
+	if len(streams) > 0 {
+		header = append(header, sevenZipMainStreams)
+
+		// one packed stream holding all files
+		header = append(header, sevenZipPackInfo)
+		header = appendSevenZipNumber(header, 0)
+		header = appendSevenZipNumber(header, 1)
+		header = append(header, sevenZipSize)
+		header = appendSevenZipNumber(header, packSize)
+		header = append(header, sevenZipEnd)
+
+		// one folder with the single copy coder
+		header = append(header, sevenZipUnpackInfo, sevenZipFolder)
+		header = appendSevenZipNumber(header, 1)
+		header = append(header, 0) // not external
+		header = appendSevenZipNumber(header, 1)
+		header = append(header, 0x01, 0x00) // simple coder with 1 byte id, copy method
+		header = append(header, sevenZipCodersUnpack)
+		header = appendSevenZipNumber(header, packSize)
+		header = append(header, sevenZipEnd)
+
+		// non-empty files are the substreams of the folder
+		header = append(header, sevenZipSubStreams, sevenZipNumUnpackStream)
+		header = appendSevenZipNumber(header, uint64(len(streams)))
+		header = append(header, sevenZipSize)
+		for _, stream := range streams[:len(streams)-1] {
+			header = appendSevenZipNumber(header, uint64(len(stream)))
+		}
+
+		header = append(header, sevenZipCRC, 1) // all defined
+		for _, stream := range streams {
+			header = binary.LittleEndian.AppendUint32(header, crc32.ChecksumIEEE(stream))
+		}
+
+		header = append(header, sevenZipEnd, sevenZipEnd)
+	}
+
+	// names are null-terminated UTF-16LE
+	var encodedNames []byte
+	for _, name := range names {
+		for _, r := range utf16.Encode([]rune(name)) {
+			encodedNames = binary.LittleEndian.AppendUint16(encodedNames, r)
+		}
+
+		encodedNames = append(encodedNames, 0, 0)
+	}
+
+	header = append(header, sevenZipFilesInfo)
+	header = appendSevenZipNumber(header, uint64(len(files)))
+
+	if len(streams) < len(files) {
+		emptyStream := appendSevenZipBits(nil, empty)
+		header = append(header, sevenZipEmptyStream)
+		header = appendSevenZipNumber(header, uint64(len(emptyStream)))
+		header = append(header, emptyStream...)
+
+		// empty streams are files, not directories
+		emptyFiles := make([]bool, len(files)-len(streams))
+		for i := range emptyFiles {
+			emptyFiles[i] = true
+		}
+
+		emptyFile := appendSevenZipBits(nil, emptyFiles)
+		header = append(header, sevenZipEmptyFile)
+		header = appendSevenZipNumber(header, uint64(len(emptyFile)))
+		header = append(header, emptyFile...)
+	}
+
+	header = append(header, sevenZipName)
+	header = appendSevenZipNumber(header, uint64(len(encodedNames)+1))
+	header = append(header, 0) // not external
+	header = append(header, encodedNames...)
+	header = append(header, sevenZipEnd, sevenZipEnd)
+
+	startHeader := make([]byte, 20)
+	binary.LittleEndian.PutUint64(startHeader[0:], packSize)
+	binary.LittleEndian.PutUint64(startHeader[8:], uint64(len(header)))
+	binary.LittleEndian.PutUint32(startHeader[16:], crc32.ChecksumIEEE(header))
+
+	signatureHeader := append([]byte{}, sevenZipSignature...)
+	signatureHeader = append(signatureHeader, 0, 4) // version 0.4
+	signatureHeader = binary.LittleEndian.AppendUint32(signatureHeader, crc32.ChecksumIEEE(startHeader))
+	signatureHeader = append(signatureHeader, startHeader...)
+
+	if _, err := out.Write(signatureHeader); err != nil {
+		return err
+	}
+
+	for _, stream := range streams {
+		if _, err := out.Write(stream); err != nil {
+			return err
+		}
+	}
+
+	_, err := out.Write(header)
+	return err
+}
@@ -0,0 +1,400 @@
+package libmangal
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"github.com/spf13/afero"
+	"hash/crc32"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+// sevenZipReader is the minimal 7z reader written after 7zFormat.txt
+// independently of write7z. It supports uncompressed archives only.
+type sevenZipReader struct {
+	b []byte
+}
+
+func (r *sevenZipReader) byte() byte {
+	if len(r.b) == 0 {
+		panic("unexpected end of header")
+	}
+
+	b := r.b[0]
+	r.b = r.b[1:]
+	return b
+}
+
+func (r *sevenZipReader) bytes(n uint64) []byte {
+	if uint64(len(r.b)) < n {
+		panic("unexpected end of header")
+	}
+
+	b := r.b[:n]
+	r.b = r.b[n:]
+	return b
+}
+
+// number reads REAL_UINT64 as described by ReadNumber in 7zFormat.txt
+func (r *sevenZipReader) number() uint64 {
+	first := r.byte()
+
+	var value uint64
+	mask := byte(0x80)
+	for i := 0; i < 8; i++ {
+		if first&mask == 0 {
+			high := uint64(first & (mask - 1))
+			return value | high<<(8*i)
+		}
+
+		value |= uint64(r.byte()) << (8 * i)
+		mask >>= 1
+	}
+
+	return value
+}
+
+func (r *sevenZipReader) expect(id byte) {
+	if got := r.byte(); got != id {
+		panic(fmt.Sprintf("got property 0x%02x, want 0x%02x", got, id))
+	}
+}
+
+func (r *sevenZipReader) bits(n int) []bool {
+	vector := r.bytes(uint64((n + 7) / 8))
+	bits := make([]bool, n)
+	for i := range bits {
+		bits[i] = vector[i/8]&(0x80>>(i%8)) != 0
+	}
+
+	return bits
+}
+
+func (r *sevenZipReader) digests(n int) []uint32 {
+	defined := make([]bool, n)
+	if allDefined := r.byte(); allDefined != 0 {
+		for i := range defined {
+			defined[i] = true
+		}
+	} else {
+		defined = r.bits(n)
+	}
+
+	digests := make([]uint32, n)
+	for i := range digests {
+		if defined[i] {
+			digests[i] = binary.LittleEndian.Uint32(r.bytes(4))
+		}
+	}
+
+	return digests
+}
+
+// sevenZipEntry is the file read from the archive
+type sevenZipEntry struct {
+	name string
+	data []byte
+}
+
+// read7z parses the archive, verifies its checksums and returns the files
+func read7z(archive []byte) (entries []sevenZipEntry, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("%v", recovered)
+		}
+	}()
+
+	if len(archive) < 32 || !bytes.Equal(archive[:6], sevenZipSignature) {
+		return nil, errors.New("not a 7z archive")
+	}
+
+	startHeader := archive[12:32]
+	if crc32.ChecksumIEEE(startHeader) != binary.LittleEndian.Uint32(archive[8:12]) {
+		return nil, errors.New("start header crc mismatch")
+	}
+
+	offset := binary.LittleEndian.Uint64(startHeader[0:])
+	size := binary.LittleEndian.Uint64(startHeader[8:])
+	if 32+offset+size != uint64(len(archive)) {
+		return nil, errors.New("header is not at the end")
+	}
+
+	header := archive[32+offset:]
+	if crc32.ChecksumIEEE(header) != binary.LittleEndian.Uint32(startHeader[16:]) {
+		return nil, errors.New("header crc mismatch")
+	}
+
+	r := &sevenZipReader{b: header}
+	r.expect(0x01) // kHeader
+
+	var (
+		packPos     uint64
+		packSizes   []uint64
+		unpackSize  uint64
+		streamSizes []uint64
+		streamCRCs  []uint32
+	)
+
+	id := r.byte()
+	if id == 0x04 { // kMainStreamsInfo
+		r.expect(0x06) // kPackInfo
+		packPos = r.number()
+		packSizes = make([]uint64, r.number())
+		r.expect(0x09) // kSize
+		for i := range packSizes {
+			packSizes[i] = r.number()
+		}
+		r.expect(0x00)
+
+		r.expect(0x07) // kUnpackInfo
+		r.expect(0x0B) // kFolder
+		if folders := r.number(); folders != 1 {
+			return nil, fmt.Errorf("%d folders", folders)
+		}
+		r.expect(0x00) // not external
+		if coders := r.number(); coders != 1 {
+			return nil, fmt.Errorf("%d coders", coders)
+		}
+		flags := r.byte()
+		if flags&0x30 != 0 {
+			return nil, errors.New("complex coder")
+		}
+		if codec := r.bytes(uint64(flags & 0x0F)); !bytes.Equal(codec, []byte{0x00}) {
+			return nil, fmt.Errorf("codec %x is not copy", codec)
+		}
+		r.expect(0x0C) // kCodersUnpackSize
+		unpackSize = r.number()
+		r.expect(0x00)
+
+		r.expect(0x08) // kSubStreamsInfo
+		streams := uint64(1)
+		property := r.byte()
+		if property == 0x0D { // kNumUnpackStream
+			streams = r.number()
+			property = r.byte()
+		}
+
+		streamSizes = make([]uint64, streams)
+		var sum uint64
+		if property == 0x09 { // kSize
+			for i := uint64(0); i+1 < streams; i++ {
+				streamSizes[i] = r.number()
+				sum += streamSizes[i]
+			}
+			property = r.byte()
+		}
+		streamSizes[streams-1] = unpackSize - sum
+
+		if property == 0x0A { // kCRC
+			streamCRCs = r.digests(int(streams))
+			property = r.byte()
+		}
+
+		if property != 0x00 {
+			return nil, fmt.Errorf("unexpected substreams property 0x%02x", property)
+		}
+		r.expect(0x00) // end of streams info
+
+		id = r.byte()
+	}
+
+	if id != 0x05 { // kFilesInfo
+		return nil, fmt.Errorf("got property 0x%02x, want files info", id)
+	}
+
+	files := int(r.number())
+	emptyStream := make([]bool, files)
+	var names []string
+
+	for {
+		property := r.byte()
+		if property == 0x00 {
+			break
+		}
+
+		data := &sevenZipReader{b: r.bytes(r.number())}
+		switch property {
+		case 0x0E: // kEmptyStream
+			emptyStream = data.bits(files)
+		case 0x0F: // kEmptyFile
+			var empty int
+			for _, isEmpty := range emptyStream {
+				if isEmpty {
+					empty++
+				}
+			}
+
+			for i, isFile := range data.bits(empty) {
+				if !isFile {
+					return nil, fmt.Errorf("empty stream #%d is a directory", i)
+				}
+			}
+		case 0x11: // kName
+			if external := data.byte(); external != 0 {
+				return nil, errors.New("external names")
+			}
+
+			var name []uint16
+			for len(data.b) > 0 {
+				char := binary.LittleEndian.Uint16(data.bytes(2))
+				if char == 0 {
+					names = append(names, string(utf16.Decode(name)))
+					name = nil
+					continue
+				}
+
+				name = append(name, char)
+			}
+		}
+	}
+	r.expect(0x00) // end of header
+
+	if len(names) != files {
+		return nil, fmt.Errorf("%d names for %d files", len(names), files)
+	}
+
+	var total uint64
+	for _, packSize := range packSizes {
+		total += packSize
+	}
+
+	if total != unpackSize || 32+packPos+total != 32+offset {
+		return nil, errors.New("pack and unpack sizes differ")
+	}
+
+	packed := archive[32+packPos : 32+packPos+total]
+
+	var stream int
+	for i := 0; i < files; i++ {
+		entry := sevenZipEntry{name: names[i], data: []byte{}}
+
+		if !emptyStream[i] {
+			if stream >= len(streamSizes) {
+				return nil, errors.New("more files than streams")
+			}
+
+			entry.data = packed[:streamSizes[stream]]
+			packed = packed[streamSizes[stream]:]
+
+			if crc32.ChecksumIEEE(entry.data) != streamCRCs[stream] {
+				return nil, fmt.Errorf("%s: crc mismatch", entry.name)
+			}
+
+			stream++
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if stream != len(streamSizes) {
+		return nil, errors.New("streams without files")
+	}
+
+	return entries, nil
+}
+
+func TestSevenZipNumber(t *testing.T) {
+	for _, v := range []uint64{
+		0, 1, 0x7F, 0x80, 0x3FFF, 0x4000, 0x1FFFFF, 0x200000,
+		1<<28 - 1, 1 << 28, 1<<35 - 1, 1 << 35, 1<<56 - 1, 1 << 56, 1<<64 - 1,
+	} {
+		r := &sevenZipReader{b: appendSevenZipNumber(nil, v)}
+		if got := r.number(); got != v || len(r.b) != 0 {
+			t.Errorf("%d decoded as %d with %d bytes left", v, got, len(r.b))
+		}
+	}
+}
+
+func TestWrite7z(t *testing.T) {
+	large := bytes.Repeat([]byte("0123456789"), 7000)
+
+	for _, test := range []struct {
+		name    string
+		entries []sevenZipEntry
+	}{
+		{"single file", []sevenZipEntry{{"0001.jpg", []byte("page")}}},
+		{"multi-byte sizes", []sevenZipEntry{
+			{"0001.jpg", large},
+			{"0002.jpg", large[:200]},
+			{"ページ.jpg", []byte("unicode name")},
+		}},
+		{"empty files", []sevenZipEntry{
+			{"0001.jpg", []byte{}},
+			{"0002.jpg", []byte("page")},
+			{"0003.jpg", []byte{}},
+			{"0004.jpg", []byte("last page")},
+		}},
+		{"only empty files", []sevenZipEntry{
+			{"0001.jpg", []byte{}},
+			{"0002.jpg", []byte{}},
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var names []string
+			var files [][]byte
+			for _, entry := range test.entries {
+				names = append(names, entry.name)
+				files = append(files, entry.data)
+			}
+
+			var buffer bytes.Buffer
+			if err := write7z(&buffer, names, files); err != nil {
+				t.Fatal(err)
+			}
+
+			entries, err := read7z(buffer.Bytes())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(entries, test.entries) {
+				t.Errorf("got %q, want %q", entries, test.entries)
+			}
+		})
+	}
+
+	if err := write7z(&bytes.Buffer{}, nil, nil); err == nil {
+		t.Error("expected an error for no files")
+	}
+}
+
+func TestDownloadChapterCB7(t *testing.T) {
+	provider := newFakeProvider(t, 1, 3)
+	client := newTestClient(t, provider)
+
+	options := testDownloadOptions()
+	options.Format = FormatCB7
+
+	result, err := client.DownloadChapter(context.Background(), provider.chapterList()[0], options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := afero.ReadFile(client.options.FS, result.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := read7z(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 3 {
+		t.Fatalf("got %d pages, want 3", len(entries))
+	}
+
+	for i, entry := range entries {
+		if want := fmt.Sprintf("%04d.jpg", i+1); entry.name != want {
+			t.Errorf("page #%d is named %q, want %q", i+1, entry.name, want)
+		}
+
+		if !bytes.Equal(entry.data, provider.image) {
+			t.Errorf("page #%d differs", i+1)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package libmangal
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// TitleSimilarity measures how similar two titles are, from 0 to 1.
+// It's used to pick the closest manga on Anilist, see AnilistOptions.TitleSimilarity
+type TitleSimilarity interface {
+	Similarity(a, b string) float64
+}
+
+// TitleSimilarityFunc is the function implementing TitleSimilarity
+type TitleSimilarityFunc func(a, b string) float64
+
+func (t TitleSimilarityFunc) Similarity(a, b string) float64 {
+	return t(a, b)
+}
+
+var (
+	// LevenshteinSimilarity is based on the edit distance of the titles
+	LevenshteinSimilarity TitleSimilarity = TitleSimilarityFunc(titleSimilarity)
+
+	// JaroWinklerSimilarity favors titles with the common prefix,
+	// e.g. the same title with a different subtitle
+	JaroWinklerSimilarity TitleSimilarity = TitleSimilarityFunc(jaroWinklerSimilarity)
+)
+
+// TokenSetSimilarity compares sets of the title words ignoring
+// their order and duplicates, so that "Attack on Titan: Before the Fall"
+// fully matches "Before the Fall Attack on Titan".
+type TokenSetSimilarity struct {
+	// Synonyms replace the words before comparing, e.g. "&" => "and".
+	// Keys must be lowercase.
+	Synonyms map[string]string
+}
+
+// NewTokenSetSimilarity constructs TokenSetSimilarity with the common synonyms
+func NewTokenSetSimilarity() TokenSetSimilarity {
+	return TokenSetSimilarity{
+		Synonyms: map[string]string{
+			"&":   "and",
+			"+":   "and",
+			"vol": "volume",
+			"ch":  "chapter",
+			"pt":  "part",
+		},
+	}
+}
+
+func (t TokenSetSimilarity) tokens(title string) []string {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '&' && r != '+'
+	})
+
+	set := make(map[string]struct{}, len(words))
+	for _, word := range words {
+		if synonym, ok := t.Synonyms[word]; ok {
+			word = synonym
+		}
+
+		set[word] = struct{}{}
+	}
+
+	tokens := make([]string, 0, len(set))
+	for token := range set {
+		tokens = append(tokens, token)
+	}
+
+	sort.Strings(tokens)
+	return tokens
+}
+
+// Similarity compares the common words of the titles with each
+// title words and returns the best Levenshtein similarity
+func (t TokenSetSimilarity) Similarity(a, b string) float64 {
+	x, y := t.tokens(a), t.tokens(b)
+
+	inY := make(map[string]struct{}, len(y))
+	for _, token := range y {
+		inY[token] = struct{}{}
+	}
+
+	var common, onlyX, onlyY []string
+	for _, token := range x {
+		if _, ok := inY[token]; ok {
+			common = append(common, token)
+			delete(inY, token)
+		} else {
+			onlyX = append(onlyX, token)
+		}
+	}
+
+	for _, token := range y {
+		if _, ok := inY[token]; ok {
+			onlyY = append(onlyY, token)
+		}
+	}
+
+	intersection := strings.Join(common, " ")
+	withX := strings.TrimSpace(intersection + " " + strings.Join(onlyX, " "))
+	withY := strings.TrimSpace(intersection + " " + strings.Join(onlyY, " "))
+
+	best := titleSimilarity(withX, withY)
+	if intersection == "" {
+		return best
+	}
+
+	for _, similarity := range []float64{
+		titleSimilarity(intersection, withX),
+		titleSimilarity(intersection, withY),
+	} {
+		if similarity > best {
+			best = similarity
+		}
+	}
+
+	return best
+}
+
+// jaroWinklerSimilarity returns the Jaro-Winkler similarity
+// of two titles ignoring case and surrounding spaces
+func jaroWinklerSimilarity(a, b string) float64 {
+	x := []rune(strings.ToLower(strings.TrimSpace(a)))
+	y := []rune(strings.ToLower(strings.TrimSpace(b)))
+
+	if len(x) == 0 && len(y) == 0 {
+		return 1
+	}
+
+	if len(x) == 0 || len(y) == 0 {
+		return 0
+	}
+
+	window := len(x)
+	if len(y) > window {
+		window = len(y)
+	}
+
+	window = window/2 - 1
+	if window < 0 {
+		window = 0
+	}
+
+	matchedX := make([]bool, len(x))
+	matchedY := make([]bool, len(y))
+
+	var matches int
+	for i := range x {
+		from, to := i-window, i+window+1
+		if from < 0 {
+			from = 0
+		}
+
+		if to > len(y) {
+			to = len(y)
+		}
+
+		for j := from; j < to; j++ {
+			if matchedY[j] || x[i] != y[j] {
+				continue
+			}
+
+			matchedX[i], matchedY[j] = true, true
+			matches++
+			break
+		}
+	}
+
+	if matches == 0 {
+		return 0
+	}
+
+	var transpositions, j int
+	for i := range x {
+		if !matchedX[i] {
+			continue
+		}
+
+		for !matchedY[j] {
+			j++
+		}
+
+		if x[i] != y[j] {
+			transpositions++
+		}
+
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(x)) + m/float64(len(y)) + (m-float64(transpositions)/2)/m) / 3
+
+	// common prefix up to 4 characters
+	var prefix int
+	for prefix < len(x) && prefix < len(y) && prefix < 4 && x[prefix] == y[prefix] {
+		prefix++
+	}
+
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
@@ -0,0 +1,54 @@
+package libmangal
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTitleSimilarity(t *testing.T) {
+	for _, test := range []struct {
+		similarity TitleSimilarity
+		a, b       string
+		want       float64
+	}{
+		{LevenshteinSimilarity, "Berserk", "berserk ", 1},
+		{LevenshteinSimilarity, "kitten", "sitting", 1 - 3.0/7},
+		{LevenshteinSimilarity, "", "", 1},
+		{LevenshteinSimilarity, "abc", "", 0},
+
+		// reference values of the Jaro-Winkler similarity
+		{JaroWinklerSimilarity, "MARTHA", "MARHTA", 0.9611},
+		{JaroWinklerSimilarity, "DWAYNE", "DUANE", 0.84},
+		{JaroWinklerSimilarity, "DIXON", "DICKSONX", 0.8133},
+		{JaroWinklerSimilarity, "", "", 1},
+		{JaroWinklerSimilarity, "abc", "", 0},
+		{JaroWinklerSimilarity, "abc", "xyz", 0},
+
+		// word order, duplicates and synonyms are ignored
+		{NewTokenSetSimilarity(), "Attack on Titan: Before the Fall", "Before the Fall Attack on Titan", 1},
+		{NewTokenSetSimilarity(), "Spy x Family Spy", "spy x family", 1},
+		{NewTokenSetSimilarity(), "Romeo & Juliet", "Romeo and Juliet", 1},
+		{NewTokenSetSimilarity(), "One Piece", "Naruto", titleSimilarity("one piece", "naruto")},
+	} {
+		got := test.similarity.Similarity(test.a, test.b)
+		if math.Abs(got-test.want) > 0.0001 {
+			t.Errorf("%T(%q, %q) = %.4f, want %.4f", test.similarity, test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestTokenSetSimilarityPartialMatch(t *testing.T) {
+	similarity := NewTokenSetSimilarity()
+
+	// the shared words weigh more than the extra subtitle
+	subtitle := similarity.Similarity("Attack on Titan", "Attack on Titan: No Regrets")
+	other := similarity.Similarity("Attack on Titan", "Attack of the Killer Tomatoes")
+
+	if subtitle != 1 {
+		t.Errorf("subtitle similarity is %.4f, want 1", subtitle)
+	}
+
+	if other >= subtitle {
+		t.Errorf("unrelated title similarity %.4f is not below %.4f", other, subtitle)
+	}
+}
@@ -0,0 +1,43 @@
+package libmangal
+
+import (
+	"fmt"
+	"strings"
+)
+
+// splitPages splits pages into consecutive parts so that the total
+// size of images in each part doesn't exceed maxSize.
+// A single page larger than maxSize gets a part of its own.
+func splitPages(pages []PageWithImage, maxSize int64) [][]PageWithImage {
+	var (
+		parts [][]PageWithImage
+		part  []PageWithImage
+		size  int64
+	)
+
+	for _, page := range pages {
+		pageSize := int64(len(page.GetImage()))
+
+		if len(part) > 0 && size+pageSize > maxSize {
+			parts = append(parts, part)
+			part = nil
+			size = 0
+		}
+
+		part = append(part, page)
+		size += pageSize
+	}
+
+	if len(part) > 0 {
+		parts = append(parts, part)
+	}
+
+	return parts
+}
+
+// chapterPartPath returns the path of the n-th part of the split chapter.
+// E.g. "[001] Chapter.cbz" becomes "[001] Chapter - Part 2.cbz"
+func chapterPartPath(path string, n int, format Format) string {
+	extension := format.Extension()
+	return fmt.Sprintf("%s - Part %d%s", strings.TrimSuffix(path, extension), n, extension)
+}
@@ -0,0 +1,149 @@
+package libmangal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+)
+
+// SpreadMode defines how landscape double-page spreads are handled
+type SpreadMode uint8
+
+const (
+	// SpreadKeep saves spreads as is
+	SpreadKeep SpreadMode = iota
+
+	// SpreadSplit splits each spread into two pages in the reading order:
+	// right half first for ReadingDirectionRTL, left half first for ReadingDirectionLTR.
+	// Long strips are never split.
+	SpreadSplit
+)
+
+// defaultSpreadAspectRatio is the width to height ratio above which
+// a page is considered a double-page spread
+const defaultSpreadAspectRatio = 1.2
+
+// SpreadOptions configures handling of double-page spreads,
+// which e-readers with small portrait screens show poorly.
+type SpreadOptions struct {
+	// Mode of the spreads handling
+	Mode SpreadMode
+
+	// MinAspectRatio is the width to height ratio above which
+	// a page is considered a spread.
+	//
+	// Zero means 1.2
+	MinAspectRatio float64
+}
+
+// DefaultSpreadOptions constructs default SpreadOptions
+func DefaultSpreadOptions() SpreadOptions {
+	return SpreadOptions{
+		Mode:           SpreadKeep,
+		MinAspectRatio: defaultSpreadAspectRatio,
+	}
+}
+
+// isSpread reports whether the image of the given dimensions is a spread
+func (s SpreadOptions) isSpread(width, height int) bool {
+	ratio := s.MinAspectRatio
+	if ratio <= 0 {
+		ratio = defaultSpreadAspectRatio
+	}
+
+	return height > 0 && float64(width)/float64(height) >= ratio
+}
+
+// splitSpreads splits spreads among pages into halves ordered by the reading direction.
+// Other pages and pages which can't be decoded are kept as is.
+//
+// Halves are encoded as JPEG if the spread was JPEG and as PNG otherwise.
+// It returns the resulting pages and the number of split spreads.
+func (s SpreadOptions) splitSpreads(pages []PageWithImage, direction ReadingDirection) ([]PageWithImage, int, error) {
+	var (
+		split  = make([]PageWithImage, 0, len(pages))
+		spread int
+	)
+
+	for _, page := range pages {
+		config, _, err := image.DecodeConfig(bytes.NewReader(page.GetImage()))
+		if err != nil || !s.isSpread(config.Width, config.Height) {
+			split = append(split, page)
+			continue
+		}
+
+		halves, err := splitSpread(page, direction)
+		if errors.Is(err, image.ErrFormat) {
+			split = append(split, page)
+			continue
+		}
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		split = append(split, halves...)
+		spread++
+	}
+
+	return split, spread, nil
+}
+
+// splitSpread splits the page into two halves ordered by the reading direction
+func splitSpread(page PageWithImage, direction ReadingDirection) ([]PageWithImage, error) {
+	img, format, err := decodeImage(page)
+	if err != nil {
+		return nil, err
+	}
+
+	subImager, ok := img.(interface {
+		SubImage(image.Rectangle) image.Image
+	})
+	if !ok {
+		return nil, fmt.Errorf("page %q: can't crop %T", page, img)
+	}
+
+	bounds := img.Bounds()
+	middle := bounds.Min.X + bounds.Dx()/2
+
+	left := subImager.SubImage(image.Rect(bounds.Min.X, bounds.Min.Y, middle, bounds.Max.Y))
+	right := subImager.SubImage(image.Rect(middle, bounds.Min.Y, bounds.Max.X, bounds.Max.Y))
+
+	halves := []image.Image{right, left}
+	if direction == ReadingDirectionLTR {
+		halves = []image.Image{left, right}
+	}
+
+	extension := ".png"
+	if format == "jpeg" {
+		extension = ".jpg"
+	}
+
+	pages := make([]PageWithImage, len(halves))
+	for i, half := range halves {
+		var buffer bytes.Buffer
+
+		if format == "jpeg" {
+			err = jpeg.Encode(&buffer, half, &jpeg.Options{Quality: 95})
+		} else {
+			err = png.Encode(&buffer, half)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("page %q: %w", page, err)
+		}
+
+		pages[i] = pageWithExtension{
+			PageWithImage: &pageWithImage{
+				Page:  page,
+				image: buffer.Bytes(),
+			},
+			extension: extension,
+		}
+	}
+
+	return pages, nil
+}
@@ -0,0 +1,162 @@
+package libmangal
+
+import (
+	"fmt"
+	"github.com/philippgille/gokv"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+const (
+	// storeIndexShardPrefix prefixes the keys under which storeIndex keeps
+	// the lists of keys, since gokv.Store can't enumerate them.
+	// Keys are split into shards, so that adding a key doesn't rewrite all of them.
+	storeIndexShardPrefix = "__index__/"
+
+	// storeIndexShards is the number of the key lists
+	storeIndexShards = 64
+
+	// storeIndexWrittenPrefix prefixes the keys of the write times of the entries
+	storeIndexWrittenPrefix = "__written__/"
+)
+
+// storeIndex tracks keys of the store with their last write time,
+// so that stale entries can be pruned.
+//
+// Writes of the existing keys only read their shard.
+// Several indexes may share the store, nothing is cached in memory.
+type storeIndex struct {
+	store gokv.Store
+	mu    sync.Mutex
+}
+
+func newStoreIndex(store gokv.Store) *storeIndex {
+	return &storeIndex{store: store}
+}
+
+func storeIndexShardKey(shard uint32) string {
+	return fmt.Sprintf("%s%02d", storeIndexShardPrefix, shard)
+}
+
+// shardKeyOf returns the key of the shard that lists the key
+func shardKeyOf(key string) string {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(key))
+	return storeIndexShardKey(hash.Sum32() % storeIndexShards)
+}
+
+func (s *storeIndex) shard(shardKey string) (map[string]struct{}, error) {
+	shard := make(map[string]struct{})
+	if _, err := s.store.Get(shardKey, &shard); err != nil {
+		return nil, err
+	}
+
+	return shard, nil
+}
+
+// set sets the value and records the key write time
+func (s *storeIndex) set(key string, value any) error {
+	if err := s.store.Set(key, value); err != nil {
+		return err
+	}
+
+	if err := s.store.Set(storeIndexWrittenPrefix+key, time.Now()); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shardKey := shardKeyOf(key)
+	shard, err := s.shard(shardKey)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := shard[key]; ok {
+		return nil
+	}
+
+	shard[key] = struct{}{}
+	return s.store.Set(shardKey, shard)
+}
+
+// delete deletes the value and forgets the key
+func (s *storeIndex) delete(key string) error {
+	if err := s.store.Delete(key); err != nil {
+		return err
+	}
+
+	if err := s.store.Delete(storeIndexWrittenPrefix + key); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	shardKey := shardKeyOf(key)
+	shard, err := s.shard(shardKey)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := shard[key]; !ok {
+		return nil
+	}
+
+	delete(shard, key)
+	return s.store.Set(shardKey, shard)
+}
+
+// keys returns the tracked keys
+func (s *storeIndex) keys() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var keys []string
+	for i := uint32(0); i < storeIndexShards; i++ {
+		shard, err := s.shard(storeIndexShardKey(i))
+		if err != nil {
+			return nil, err
+		}
+
+		for key := range shard {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys, nil
+}
+
+// prune deletes entries written more than maxAge ago.
+// Entries without the write time are not known and kept.
+//
+// It returns the number of deleted entries.
+func (s *storeIndex) prune(maxAge time.Duration) (int, error) {
+	keys, err := s.keys()
+	if err != nil {
+		return 0, err
+	}
+
+	var pruned int
+	for _, key := range keys {
+		var writtenAt time.Time
+		found, err := s.store.Get(storeIndexWrittenPrefix+key, &writtenAt)
+		if err != nil {
+			return pruned, err
+		}
+
+		if !found || time.Since(writtenAt) < maxAge {
+			continue
+		}
+
+		if err := s.delete(key); err != nil {
+			return pruned, err
+		}
+
+		pruned++
+	}
+
+	return pruned, nil
+}
@@ -0,0 +1,151 @@
+package libmangal
+
+import (
+	"fmt"
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/syncmap"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingStore counts writes of the storeIndex shards
+type countingStore struct {
+	gokv.Store
+	shardWrites atomic.Int64
+}
+
+func (c *countingStore) Set(key string, value any) error {
+	if strings.HasPrefix(key, storeIndexShardPrefix) {
+		c.shardWrites.Add(1)
+	}
+
+	return c.Store.Set(key, value)
+}
+
+func TestStoreIndex(t *testing.T) {
+	store := &countingStore{Store: syncmap.NewStore(syncmap.DefaultOptions)}
+	index := newStoreIndex(store)
+
+	const n = 200
+	for i := 0; i < n; i++ {
+		if err := index.set(fmt.Sprint(i), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := store.shardWrites.Load(); got != n {
+		t.Errorf("got %d shard writes for new keys, want %d", got, n)
+	}
+
+	// rewrites of the known keys don't touch the shards
+	for i := 0; i < n; i++ {
+		if err := index.set(fmt.Sprint(i), i+1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := store.shardWrites.Load(); got != n {
+		t.Errorf("got %d shard writes after rewrites, want %d", got, n)
+	}
+
+	// another index of the same store sees the keys
+	keys, err := newStoreIndex(store).keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != n {
+		t.Fatalf("got %d keys, want %d", len(keys), n)
+	}
+
+	if err := index.delete("0"); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err = index.keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(keys)
+	if len(keys) != n-1 || keys[0] != "1" {
+		t.Errorf("deleted key is listed")
+	}
+
+	var value int
+	if found, _ := store.Get("0", &value); found {
+		t.Error("deleted value is in the store")
+	}
+}
+
+func TestStoreIndexSharedStore(t *testing.T) {
+	store := syncmap.NewStore(syncmap.DefaultOptions)
+	first, second := newStoreIndex(store), newStoreIndex(store)
+
+	if err := first.set("key", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// deleted by another index of the same store
+	if err := second.delete("key"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := first.set("key", 2); err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := second.keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != 1 || keys[0] != "key" {
+		t.Errorf("got keys %v, want [key]", keys)
+	}
+}
+
+func TestStoreIndexPrune(t *testing.T) {
+	store := syncmap.NewStore(syncmap.DefaultOptions)
+	index := newStoreIndex(store)
+
+	for _, key := range []string{"old", "new"} {
+		if err := index.set(key, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := store.Set(storeIndexWrittenPrefix+"old", time.Now().Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	pruned, err := index.prune(time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pruned != 1 {
+		t.Errorf("pruned %d entries, want 1", pruned)
+	}
+
+	var value string
+	if found, _ := store.Get("old", &value); found {
+		t.Error("old entry was not pruned")
+	}
+
+	if found, _ := store.Get("new", &value); !found {
+		t.Error("new entry was pruned")
+	}
+
+	keys, err := index.keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != 1 || keys[0] != "new" {
+		t.Errorf("got keys %v, want [new]", keys)
+	}
+}
@@ -13,3 +13,51 @@ func sanitizePath(path string) string {
 	// replace two or more consecutive underscores with one underscore
 	return regexp.MustCompile(`_+`).ReplaceAllString(path, "_")
 }
+
+// titleSimilarity returns the similarity of two titles from 0 to 1
+// based on the Levenshtein distance, ignoring case and surrounding spaces.
+func titleSimilarity(a, b string) float64 {
+	x := []rune(strings.ToLower(strings.TrimSpace(a)))
+	y := []rune(strings.ToLower(strings.TrimSpace(b)))
+
+	longest := len(x)
+	if len(y) > longest {
+		longest = len(y)
+	}
+
+	if longest == 0 {
+		return 1
+	}
+
+	// two rows of the distance matrix are enough
+	previous := make([]int, len(y)+1)
+	current := make([]int, len(y)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(x); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+
+			current[j] = previous[j] + 1
+			if insertion := current[j-1] + 1; insertion < current[j] {
+				current[j] = insertion
+			}
+
+			if substitution := previous[j-1] + cost; substitution < current[j] {
+				current[j] = substitution
+			}
+		}
+
+		previous, current = current, previous
+	}
+
+	return 1 - float64(previous[len(y)])/float64(longest)
+}
@@ -0,0 +1,497 @@
+package libmangal
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"google.golang.org/protobuf/encoding/protowire"
+	"io"
+)
+
+// TachiyomiTrackerAnilist is the TachiyomiTracking.SyncID of Anilist
+const TachiyomiTrackerAnilist int32 = 2
+
+// TachiyomiBackup is the backup of the Tachiyomi (or Mihon) library.
+// Only the library related parts of the backup are kept.
+type TachiyomiBackup struct {
+	Mangas     []TachiyomiManga    `json:"mangas"`
+	Categories []TachiyomiCategory `json:"categories"`
+	Sources    []TachiyomiSource   `json:"sources"`
+}
+
+// SourceName returns name of the source with the given id
+func (t TachiyomiBackup) SourceName(id int64) (string, bool) {
+	for _, source := range t.Sources {
+		if source.ID == id {
+			return source.Name, true
+		}
+	}
+
+	return "", false
+}
+
+// CategoryName returns name of the category with the given order
+func (t TachiyomiBackup) CategoryName(order int64) (string, bool) {
+	for _, category := range t.Categories {
+		if category.Order == order {
+			return category.Name, true
+		}
+	}
+
+	return "", false
+}
+
+// TachiyomiCategory is the library category
+type TachiyomiCategory struct {
+	Name  string `json:"name"`
+	Order int64  `json:"order"`
+	Flags int64  `json:"flags"`
+}
+
+// TachiyomiSource is the extension source
+type TachiyomiSource struct {
+	Name string `json:"name"`
+	ID   int64  `json:"id"`
+}
+
+// TachiyomiManga is the library entry
+type TachiyomiManga struct {
+	// Source is the TachiyomiSource.ID
+	Source       int64    `json:"source"`
+	URL          string   `json:"url"`
+	Title        string   `json:"title"`
+	Artist       string   `json:"artist"`
+	Author       string   `json:"author"`
+	Description  string   `json:"description"`
+	Genres       []string `json:"genres"`
+	Status       int32    `json:"status"`
+	ThumbnailURL string   `json:"thumbnailUrl"`
+
+	// DateAdded is the unix time in milliseconds
+	DateAdded int64 `json:"dateAdded"`
+
+	Chapters []TachiyomiChapter `json:"chapters"`
+
+	// Categories are the TachiyomiCategory.Order values
+	Categories []int64             `json:"categories"`
+	Tracking   []TachiyomiTracking `json:"tracking"`
+	Favorite   bool                `json:"favorite"`
+	History    []TachiyomiHistory  `json:"history"`
+}
+
+// AnilistID returns the Anilist manga ID if the entry is tracked there
+func (t TachiyomiManga) AnilistID() (int, bool) {
+	for _, tracking := range t.Tracking {
+		if tracking.SyncID == TachiyomiTrackerAnilist && tracking.MediaID > 0 {
+			return int(tracking.MediaID), true
+		}
+	}
+
+	return 0, false
+}
+
+// LastChapterRead returns the highest number of the chapters marked as read
+func (t TachiyomiManga) LastChapterRead() float32 {
+	var last float32
+	for _, chapter := range t.Chapters {
+		if chapter.Read && chapter.Number > last {
+			last = chapter.Number
+		}
+	}
+
+	for _, tracking := range t.Tracking {
+		if tracking.LastChapterRead > last {
+			last = tracking.LastChapterRead
+		}
+	}
+
+	return last
+}
+
+// TachiyomiChapter is the chapter of the library entry
+type TachiyomiChapter struct {
+	URL          string  `json:"url"`
+	Name         string  `json:"name"`
+	Scanlator    string  `json:"scanlator"`
+	Read         bool    `json:"read"`
+	Bookmark     bool    `json:"bookmark"`
+	LastPageRead int64   `json:"lastPageRead"`
+	DateFetch    int64   `json:"dateFetch"`
+	DateUpload   int64   `json:"dateUpload"`
+	Number       float32 `json:"number"`
+	SourceOrder  int64   `json:"sourceOrder"`
+}
+
+// TachiyomiTracking is the tracker entry of the manga
+type TachiyomiTracking struct {
+	// SyncID identifies the tracker, see TachiyomiTrackerAnilist
+	SyncID          int32   `json:"syncId"`
+	LibraryID       int64   `json:"libraryId"`
+	TrackingURL     string  `json:"trackingUrl"`
+	Title           string  `json:"title"`
+	LastChapterRead float32 `json:"lastChapterRead"`
+	TotalChapters   int32   `json:"totalChapters"`
+	Score           float32 `json:"score"`
+	Status          int32   `json:"status"`
+	StartedReading  int64   `json:"startedReading"`
+	FinishedReading int64   `json:"finishedReading"`
+	MediaID         int64   `json:"mediaId"`
+}
+
+// TachiyomiHistory is the reading history of the chapter
+type TachiyomiHistory struct {
+	// URL is the TachiyomiChapter.URL
+	URL string `json:"url"`
+
+	// LastRead is the unix time in milliseconds
+	LastRead int64 `json:"lastRead"`
+
+	// ReadDuration in milliseconds
+	ReadDuration int64 `json:"readDuration"`
+}
+
+// ParseTachiyomiBackup parses the Tachiyomi backup (.tachibk or .proto.gz).
+// Both gzip compressed and raw protobuf backups are accepted.
+func ParseTachiyomiBackup(r io.Reader) (TachiyomiBackup, error) {
+	buffered := bufio.NewReader(r)
+
+	magic, err := buffered.Peek(2)
+	if err != nil && err != io.EOF {
+		return TachiyomiBackup{}, err
+	}
+
+	var reader io.Reader = buffered
+	if bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gzipReader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return TachiyomiBackup{}, err
+		}
+		defer gzipReader.Close()
+
+		reader = gzipReader
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return TachiyomiBackup{}, err
+	}
+
+	var backup TachiyomiBackup
+	if err := backup.unmarshal(data); err != nil {
+		return TachiyomiBackup{}, fmt.Errorf("tachiyomi backup: %w", err)
+	}
+
+	return backup, nil
+}
+
+func (t *TachiyomiBackup) unmarshal(b []byte) error {
+	return protoFields(b, func(field protoField) error {
+		switch field.num {
+		case 1:
+			var manga TachiyomiManga
+			if err := manga.unmarshal(field.bytes); err != nil {
+				return err
+			}
+
+			t.Mangas = append(t.Mangas, manga)
+		case 2:
+			var category TachiyomiCategory
+			if err := category.unmarshal(field.bytes); err != nil {
+				return err
+			}
+
+			t.Categories = append(t.Categories, category)
+		case 101:
+			var source TachiyomiSource
+			if err := source.unmarshal(field.bytes); err != nil {
+				return err
+			}
+
+			t.Sources = append(t.Sources, source)
+		}
+
+		return nil
+	})
+}
+
+func (t *TachiyomiCategory) unmarshal(b []byte) error {
+	return protoFields(b, func(field protoField) error {
+		switch field.num {
+		case 1:
+			t.Name = field.string()
+		case 2:
+			t.Order = field.int64()
+		case 100:
+			t.Flags = field.int64()
+		}
+
+		return nil
+	})
+}
+
+func (t *TachiyomiSource) unmarshal(b []byte) error {
+	return protoFields(b, func(field protoField) error {
+		switch field.num {
+		case 1:
+			t.Name = field.string()
+		case 2:
+			t.ID = field.int64()
+		}
+
+		return nil
+	})
+}
+
+func (t *TachiyomiManga) unmarshal(b []byte) error {
+	// favorite is true unless stated otherwise
+	t.Favorite = true
+
+	return protoFields(b, func(field protoField) error {
+		switch field.num {
+		case 1:
+			t.Source = field.int64()
+		case 2:
+			t.URL = field.string()
+		case 3:
+			t.Title = field.string()
+		case 4:
+			t.Artist = field.string()
+		case 5:
+			t.Author = field.string()
+		case 6:
+			t.Description = field.string()
+		case 7:
+			t.Genres = append(t.Genres, field.string())
+		case 8:
+			t.Status = field.int32()
+		case 9:
+			t.ThumbnailURL = field.string()
+		case 13:
+			t.DateAdded = field.int64()
+		case 16:
+			var chapter TachiyomiChapter
+			if err := chapter.unmarshal(field.bytes); err != nil {
+				return err
+			}
+
+			t.Chapters = append(t.Chapters, chapter)
+		case 17:
+			categories, err := field.int64s()
+			if err != nil {
+				return err
+			}
+
+			t.Categories = append(t.Categories, categories...)
+		case 18:
+			var tracking TachiyomiTracking
+			if err := tracking.unmarshal(field.bytes); err != nil {
+				return err
+			}
+
+			t.Tracking = append(t.Tracking, tracking)
+		case 100:
+			t.Favorite = field.bool()
+		case 104:
+			var history TachiyomiHistory
+			if err := history.unmarshal(field.bytes); err != nil {
+				return err
+			}
+
+			t.History = append(t.History, history)
+		}
+
+		return nil
+	})
+}
+
+func (t *TachiyomiChapter) unmarshal(b []byte) error {
+	return protoFields(b, func(field protoField) error {
+		switch field.num {
+		case 1:
+			t.URL = field.string()
+		case 2:
+			t.Name = field.string()
+		case 3:
+			t.Scanlator = field.string()
+		case 4:
+			t.Read = field.bool()
+		case 5:
+			t.Bookmark = field.bool()
+		case 6:
+			t.LastPageRead = field.int64()
+		case 7:
+			t.DateFetch = field.int64()
+		case 8:
+			t.DateUpload = field.int64()
+		case 9:
+			t.Number = field.float32()
+		case 10:
+			t.SourceOrder = field.int64()
+		}
+
+		return nil
+	})
+}
+
+func (t *TachiyomiTracking) unmarshal(b []byte) error {
+	return protoFields(b, func(field protoField) error {
+		switch field.num {
+		case 1:
+			t.SyncID = field.int32()
+		case 2:
+			t.LibraryID = field.int64()
+		case 3:
+			// deprecated int32 media id, superseded by field 100
+			if t.MediaID == 0 {
+				t.MediaID = int64(field.int32())
+			}
+		case 4:
+			t.TrackingURL = field.string()
+		case 5:
+			t.Title = field.string()
+		case 6:
+			t.LastChapterRead = field.float32()
+		case 7:
+			t.TotalChapters = field.int32()
+		case 8:
+			t.Score = field.float32()
+		case 9:
+			t.Status = field.int32()
+		case 10:
+			t.StartedReading = field.int64()
+		case 11:
+			t.FinishedReading = field.int64()
+		case 100:
+			t.MediaID = field.int64()
+		}
+
+		return nil
+	})
+}
+
+func (t *TachiyomiHistory) unmarshal(b []byte) error {
+	return protoFields(b, func(field protoField) error {
+		switch field.num {
+		case 1:
+			t.URL = field.string()
+		case 2:
+			t.LastRead = field.int64()
+		case 3:
+			t.ReadDuration = field.int64()
+		}
+
+		return nil
+	})
+}
+
+func (t TachiyomiBackup) marshal() []byte {
+	var b []byte
+	for _, manga := range t.Mangas {
+		b = appendProtoMessage(b, 1, manga.marshal())
+	}
+
+	for _, category := range t.Categories {
+		b = appendProtoMessage(b, 2, category.marshal())
+	}
+
+	for _, source := range t.Sources {
+		b = appendProtoMessage(b, 101, source.marshal())
+	}
+
+	return b
+}
+
+func (t TachiyomiCategory) marshal() []byte {
+	var b []byte
+	b = appendProtoString(b, 1, t.Name)
+	b = appendProtoVarint(b, 2, uint64(t.Order))
+	b = appendProtoVarint(b, 100, uint64(t.Flags))
+	return b
+}
+
+func (t TachiyomiSource) marshal() []byte {
+	var b []byte
+	b = appendProtoString(b, 1, t.Name)
+	b = appendProtoVarint(b, 2, uint64(t.ID))
+	return b
+}
+
+func (t TachiyomiManga) marshal() []byte {
+	var b []byte
+	b = appendProtoVarint(b, 1, uint64(t.Source))
+	b = appendProtoString(b, 2, t.URL)
+	b = appendProtoString(b, 3, t.Title)
+	b = appendProtoString(b, 4, t.Artist)
+	b = appendProtoString(b, 5, t.Author)
+	b = appendProtoString(b, 6, t.Description)
+
+	for _, genre := range t.Genres {
+		b = appendProtoString(b, 7, genre)
+	}
+
+	b = appendProtoVarint(b, 8, uint64(t.Status))
+	b = appendProtoString(b, 9, t.ThumbnailURL)
+	b = appendProtoVarint(b, 13, uint64(t.DateAdded))
+
+	for _, chapter := range t.Chapters {
+		b = appendProtoMessage(b, 16, chapter.marshal())
+	}
+
+	for _, category := range t.Categories {
+		// category order may be zero
+		b = protowire.AppendTag(b, 17, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(category))
+	}
+
+	for _, tracking := range t.Tracking {
+		b = appendProtoMessage(b, 18, tracking.marshal())
+	}
+
+	b = appendProtoBool(b, 100, t.Favorite)
+
+	for _, history := range t.History {
+		b = appendProtoMessage(b, 104, history.marshal())
+	}
+
+	return b
+}
+
+func (t TachiyomiChapter) marshal() []byte {
+	var b []byte
+	b = appendProtoString(b, 1, t.URL)
+	b = appendProtoString(b, 2, t.Name)
+	b = appendProtoString(b, 3, t.Scanlator)
+	b = appendProtoVarint(b, 4, protowire.EncodeBool(t.Read))
+	b = appendProtoVarint(b, 5, protowire.EncodeBool(t.Bookmark))
+	b = appendProtoVarint(b, 6, uint64(t.LastPageRead))
+	b = appendProtoVarint(b, 7, uint64(t.DateFetch))
+	b = appendProtoVarint(b, 8, uint64(t.DateUpload))
+	b = appendProtoFloat32(b, 9, t.Number)
+	b = appendProtoVarint(b, 10, uint64(t.SourceOrder))
+	return b
+}
+
+func (t TachiyomiTracking) marshal() []byte {
+	var b []byte
+	b = appendProtoVarint(b, 1, uint64(t.SyncID))
+	b = appendProtoVarint(b, 2, uint64(t.LibraryID))
+	b = appendProtoString(b, 4, t.TrackingURL)
+	b = appendProtoString(b, 5, t.Title)
+	b = appendProtoFloat32(b, 6, t.LastChapterRead)
+	b = appendProtoVarint(b, 7, uint64(t.TotalChapters))
+	b = appendProtoFloat32(b, 8, t.Score)
+	b = appendProtoVarint(b, 9, uint64(t.Status))
+	b = appendProtoVarint(b, 10, uint64(t.StartedReading))
+	b = appendProtoVarint(b, 11, uint64(t.FinishedReading))
+	b = appendProtoVarint(b, 100, uint64(t.MediaID))
+	return b
+}
+
+func (t TachiyomiHistory) marshal() []byte {
+	var b []byte
+	b = appendProtoString(b, 1, t.URL)
+	b = appendProtoVarint(b, 2, uint64(t.LastRead))
+	b = appendProtoVarint(b, 3, uint64(t.ReadDuration))
+	return b
+}
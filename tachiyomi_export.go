@@ -0,0 +1,138 @@
+package libmangal
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+// Tachiyomi Anilist tracking statuses
+const (
+	tachiyomiAnilistReading   int32 = 1
+	tachiyomiAnilistCompleted int32 = 2
+	tachiyomiAnilistPlanning  int32 = 5
+)
+
+// ExportTachiyomiBackup writes the backup in the gzip compressed
+// protobuf format that Tachiyomi (and its forks) restore from .tachibk files.
+func ExportTachiyomiBackup(out io.Writer, backup TachiyomiBackup) error {
+	gzipWriter := gzip.NewWriter(out)
+
+	if _, err := gzipWriter.Write(backup.marshal()); err != nil {
+		return err
+	}
+
+	return gzipWriter.Close()
+}
+
+// TachiyomiSource returns the Tachiyomi source representing this provider.
+//
+// Its ID is computed the way Tachiyomi does for extensions,
+// but there is no such extension, so entries of this source
+// are meant to be migrated to the real ones in the app.
+func (c *Client) TachiyomiSource() TachiyomiSource {
+	name := c.Info().Name
+	hash := md5.Sum([]byte(fmt.Sprintf("%s/all/1", strings.ToLower(name))))
+
+	return TachiyomiSource{
+		Name: name,
+		ID:   int64(binary.BigEndian.Uint64(hash[:8]) & math.MaxInt64),
+	}
+}
+
+// TachiyomiManga makes the Tachiyomi backup entry of the manga.
+// See ExportTachiyomiBackup
+//
+// Chapters are marked as read according to their ReadPosition,
+// which also makes up the history. If the manga is found on Anilist
+// the entry is tracked there.
+func (c *Client) TachiyomiManga(ctx context.Context, manga Manga) (TachiyomiManga, error) {
+	info := manga.Info()
+
+	entry := TachiyomiManga{
+		Source:       c.TachiyomiSource().ID,
+		URL:          info.URL,
+		Title:        info.Title,
+		ThumbnailURL: info.Cover,
+		Favorite:     true,
+	}
+
+	chapters, err := c.mangaChapters(ctx, manga)
+	if err != nil {
+		return TachiyomiManga{}, err
+	}
+
+	var lastChapterRead float32
+	for i, chapter := range chapters {
+		chapterInfo := chapter.Info()
+
+		backupChapter := TachiyomiChapter{
+			URL:       chapterInfo.URL,
+			Name:      chapterInfo.Title,
+			Scanlator: chapterInfo.Scanlator,
+			Number:    chapterInfo.Number,
+			// Tachiyomi lists the latest chapters first
+			SourceOrder: int64(len(chapters) - 1 - i),
+		}
+
+		position, found, err := c.ResumePosition(chapter)
+		if err != nil {
+			return TachiyomiManga{}, err
+		}
+
+		if found {
+			backupChapter.Read = position.Finished()
+			backupChapter.LastPageRead = int64(position.Page)
+
+			entry.History = append(entry.History, TachiyomiHistory{
+				URL:      chapterInfo.URL,
+				LastRead: position.UpdatedAt.UnixMilli(),
+			})
+
+			if backupChapter.Read && chapterInfo.Number > lastChapterRead {
+				lastChapterRead = chapterInfo.Number
+			}
+		}
+
+		entry.Chapters = append(entry.Chapters, backupChapter)
+	}
+
+	withAnilist, ok, err := c.Anilist().MakeMangaWithAnilist(ctx, manga)
+	if err != nil {
+		return TachiyomiManga{}, err
+	}
+
+	if ok {
+		anilist := withAnilist.Anilist
+
+		entry.Description = anilist.Description
+		entry.Genres = anilist.Genres
+
+		status := tachiyomiAnilistReading
+		switch {
+		case lastChapterRead == 0:
+			status = tachiyomiAnilistPlanning
+		case anilist.Status == "FINISHED" && anilist.Chapters > 0 && int(lastChapterRead) >= anilist.Chapters:
+			status = tachiyomiAnilistCompleted
+		}
+
+		entry.Tracking = append(entry.Tracking, TachiyomiTracking{
+			SyncID:          TachiyomiTrackerAnilist,
+			TrackingURL:     anilist.SiteURL,
+			Title:           anilist.String(),
+			LastChapterRead: lastChapterRead,
+			TotalChapters:   int32(anilist.Chapters),
+			Status:          status,
+			MediaID:         int64(anilist.ID),
+		})
+	}
+
+	c.options.Log(fmt.Sprintf("Tachiyomi entry of %q: %d chapters, %d in history", manga, len(entry.Chapters), len(entry.History)))
+
+	return entry, nil
+}
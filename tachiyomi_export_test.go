@@ -0,0 +1,247 @@
+package libmangal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"google.golang.org/protobuf/encoding/protowire"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestTachiyomiExportRoundTrip(t *testing.T) {
+	provider := newFakeProvider(t, 3, 1)
+	client := newTestClient(t, provider)
+	seedAnilist(t, client, AnilistManga{
+		ID:       30013,
+		Status:   "FINISHED",
+		Chapters: 3,
+		SiteURL:  "https://anilist.co/manga/30013",
+		Genres:   []string{"Action"},
+	})
+
+	chapters := provider.chapterList()
+	for _, chapter := range chapters[:2] {
+		if err := client.SetReadPosition(chapter, 9, 10); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manga, err := client.TachiyomiManga(context.Background(), provider.manga())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manga.Categories = []int64{0}
+
+	backup := TachiyomiBackup{
+		Mangas:     []TachiyomiManga{manga},
+		Categories: []TachiyomiCategory{{Name: "Reading"}},
+		Sources:    []TachiyomiSource{client.TachiyomiSource()},
+	}
+
+	var buffer bytes.Buffer
+	if err := ExportTachiyomiBackup(&buffer, backup); err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := ParseTachiyomiBackup(bytes.NewReader(buffer.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(parsed, backup) {
+		t.Fatalf("round trip:\ngot  %+v\nwant %+v", parsed, backup)
+	}
+
+	migration, err := client.ImportTachiyomiBackup(context.Background(), bytes.NewReader(buffer.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(migration.Entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(migration.Entries))
+	}
+
+	entry := migration.Entries[0]
+	if entry.Manga == nil || entry.Manga.Info().ID != provider.manga().info.ID {
+		t.Errorf("entry matched %v, want %v", entry.Manga, provider.manga())
+	}
+
+	if entry.AnilistID != 30013 {
+		t.Errorf("AnilistID = %d, want 30013", entry.AnilistID)
+	}
+
+	if entry.LastChapterRead != 2 {
+		t.Errorf("LastChapterRead = %v, want 2", entry.LastChapterRead)
+	}
+
+	if entry.Source != fakeProviderInfo.Name {
+		t.Errorf("Source = %q, want %q", entry.Source, fakeProviderInfo.Name)
+	}
+
+	if !reflect.DeepEqual(entry.Categories, []string{"Reading"}) {
+		t.Errorf("Categories = %v, want [Reading]", entry.Categories)
+	}
+}
+
+// tachiyomiSchema is the layout of the backup messages in Tachiyomi,
+// with the wire types of the fields and the schemas of embedded messages
+type tachiyomiSchema struct {
+	types    map[protowire.Number]protowire.Type
+	messages map[protowire.Number]tachiyomiSchema
+}
+
+var (
+	tachiyomiChapterSchema = tachiyomiSchema{types: map[protowire.Number]protowire.Type{
+		1: protowire.BytesType, 2: protowire.BytesType, 3: protowire.BytesType,
+		4: protowire.VarintType, 5: protowire.VarintType, 6: protowire.VarintType,
+		7: protowire.VarintType, 8: protowire.VarintType, 9: protowire.Fixed32Type,
+		10: protowire.VarintType,
+	}}
+
+	tachiyomiTrackingSchema = tachiyomiSchema{types: map[protowire.Number]protowire.Type{
+		1: protowire.VarintType, 2: protowire.VarintType, 4: protowire.BytesType,
+		5: protowire.BytesType, 6: protowire.Fixed32Type, 7: protowire.VarintType,
+		8: protowire.Fixed32Type, 9: protowire.VarintType, 10: protowire.VarintType,
+		11: protowire.VarintType, 100: protowire.VarintType,
+	}}
+
+	tachiyomiHistorySchema = tachiyomiSchema{types: map[protowire.Number]protowire.Type{
+		1: protowire.BytesType, 2: protowire.VarintType, 3: protowire.VarintType,
+	}}
+
+	tachiyomiMangaSchema = tachiyomiSchema{
+		types: map[protowire.Number]protowire.Type{
+			1: protowire.VarintType, 2: protowire.BytesType, 3: protowire.BytesType,
+			4: protowire.BytesType, 5: protowire.BytesType, 6: protowire.BytesType,
+			7: protowire.BytesType, 8: protowire.VarintType, 9: protowire.BytesType,
+			13: protowire.VarintType, 16: protowire.BytesType, 17: protowire.VarintType,
+			18: protowire.BytesType, 100: protowire.VarintType, 104: protowire.BytesType,
+		},
+		messages: map[protowire.Number]tachiyomiSchema{
+			16:  tachiyomiChapterSchema,
+			18:  tachiyomiTrackingSchema,
+			104: tachiyomiHistorySchema,
+		},
+	}
+
+	tachiyomiBackupSchema = tachiyomiSchema{
+		types: map[protowire.Number]protowire.Type{
+			1: protowire.BytesType, 2: protowire.BytesType, 101: protowire.BytesType,
+		},
+		messages: map[protowire.Number]tachiyomiSchema{
+			1: tachiyomiMangaSchema,
+			2: {types: map[protowire.Number]protowire.Type{
+				1: protowire.BytesType, 2: protowire.VarintType, 100: protowire.VarintType,
+			}},
+			101: {types: map[protowire.Number]protowire.Type{
+				1: protowire.BytesType, 2: protowire.VarintType,
+			}},
+		},
+	}
+)
+
+// check reports fields of the message that are not in the schema
+// or have the wrong wire type, and schema fields that are missing
+func (s tachiyomiSchema) check(t *testing.T, name string, message []byte) {
+	t.Helper()
+
+	seen := make(map[protowire.Number]bool)
+	err := protoFields(message, func(field protoField) error {
+		seen[field.num] = true
+
+		typ, ok := s.types[field.num]
+		if !ok {
+			t.Errorf("%s: unexpected field %d", name, field.num)
+			return nil
+		}
+
+		if field.typ != typ {
+			t.Errorf("%s: field %d has wire type %d, want %d", name, field.num, field.typ, typ)
+			return nil
+		}
+
+		if embedded, ok := s.messages[field.num]; ok {
+			embedded.check(t, fmt.Sprintf("%s.%d", name, field.num), field.bytes)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("%s: %s", name, err)
+	}
+
+	for num := range s.types {
+		if !seen[num] {
+			t.Errorf("%s: field %d is missing", name, num)
+		}
+	}
+}
+
+func TestExportTachiyomiBackupFieldNumbers(t *testing.T) {
+	// every field is set, so every field number is checked
+	backup := TachiyomiBackup{
+		Mangas: []TachiyomiManga{{
+			Source:       1,
+			URL:          "/manga",
+			Title:        "Title",
+			Artist:       "Artist",
+			Author:       "Author",
+			Description:  "Description",
+			Genres:       []string{"Action"},
+			Status:       1,
+			ThumbnailURL: "/cover.jpg",
+			DateAdded:    1,
+			Chapters: []TachiyomiChapter{{
+				URL:          "/chapter",
+				Name:         "Chapter",
+				Scanlator:    "Scanlator",
+				Read:         true,
+				Bookmark:     true,
+				LastPageRead: 1,
+				DateFetch:    1,
+				DateUpload:   1,
+				Number:       1.5,
+				SourceOrder:  1,
+			}},
+			Categories: []int64{1},
+			Tracking: []TachiyomiTracking{{
+				SyncID:          TachiyomiTrackerAnilist,
+				LibraryID:       1,
+				TrackingURL:     "/tracking",
+				Title:           "Title",
+				LastChapterRead: 1,
+				TotalChapters:   1,
+				Score:           1,
+				Status:          1,
+				StartedReading:  1,
+				FinishedReading: 1,
+				MediaID:         1,
+			}},
+			Favorite: true,
+			History:  []TachiyomiHistory{{URL: "/chapter", LastRead: 1, ReadDuration: 1}},
+		}},
+		Categories: []TachiyomiCategory{{Name: "Category", Order: 1, Flags: 1}},
+		Sources:    []TachiyomiSource{{Name: "Source", ID: 1}},
+	}
+
+	var buffer bytes.Buffer
+	if err := ExportTachiyomiBackup(&buffer, backup); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := gzip.NewReader(&buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tachiyomiBackupSchema.check(t, "Backup", message)
+}
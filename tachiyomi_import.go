@@ -0,0 +1,152 @@
+package libmangal
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// tachiyomiTitleSimilarity is the minimal titleSimilarity of the
+// provider manga to be matched with the backup entry by title
+const tachiyomiTitleSimilarity = 0.9
+
+// TachiyomiMigration is the plan of moving the Tachiyomi library to the provider
+type TachiyomiMigration struct {
+	// Categories are the names of the library categories
+	Categories []string `json:"categories"`
+
+	// Entries are the library entries. Mangas that are in the
+	// backup only because of the history are not included.
+	Entries []TachiyomiMigrationEntry `json:"entries"`
+}
+
+// Unmatched returns entries that were not found on the provider
+func (t TachiyomiMigration) Unmatched() []TachiyomiMigrationEntry {
+	var unmatched []TachiyomiMigrationEntry
+	for _, entry := range t.Entries {
+		if entry.Manga == nil {
+			unmatched = append(unmatched, entry)
+		}
+	}
+
+	return unmatched
+}
+
+// TachiyomiMigrationEntry is the library entry mapped to the provider
+type TachiyomiMigrationEntry struct {
+	// Backup is the entry as it is in the backup
+	Backup TachiyomiManga `json:"backup"`
+
+	// Source is the name of the Tachiyomi source of the entry
+	Source string `json:"source"`
+
+	// Categories are the names of the entry categories
+	Categories []string `json:"categories"`
+
+	// AnilistID is the Anilist manga ID. Zero if the entry is not tracked there.
+	AnilistID int `json:"anilistId"`
+
+	// LastChapterRead is the highest chapter number read
+	LastChapterRead float32 `json:"lastChapterRead"`
+
+	// Manga is the matching manga of the provider. Nil if not found.
+	Manga Manga `json:"-"`
+}
+
+// ImportTachiyomiBackup parses the Tachiyomi (or Mihon) backup and maps its
+// library to this provider, producing a migration plan.
+//
+// Entries tracked on Anilist are bound to their Anilist IDs
+// (see Anilist.BindTitleWithID) and matched by them,
+// others are matched by the title.
+func (c *Client) ImportTachiyomiBackup(ctx context.Context, r io.Reader) (TachiyomiMigration, error) {
+	backup, err := ParseTachiyomiBackup(r)
+	if err != nil {
+		return TachiyomiMigration{}, err
+	}
+
+	var migration TachiyomiMigration
+	for _, category := range backup.Categories {
+		migration.Categories = append(migration.Categories, category.Name)
+	}
+
+	for _, manga := range backup.Mangas {
+		if !manga.Favorite {
+			continue
+		}
+
+		entry := TachiyomiMigrationEntry{
+			Backup:          manga,
+			LastChapterRead: manga.LastChapterRead(),
+		}
+
+		entry.Source, _ = backup.SourceName(manga.Source)
+
+		for _, order := range manga.Categories {
+			if name, ok := backup.CategoryName(order); ok {
+				entry.Categories = append(entry.Categories, name)
+			}
+		}
+
+		if id, ok := manga.AnilistID(); ok {
+			entry.AnilistID = id
+
+			if err := c.Anilist().BindTitleWithID(manga.Title, id); err != nil {
+				return TachiyomiMigration{}, err
+			}
+		}
+
+		entry.Manga, err = c.matchTachiyomiManga(ctx, manga.Title, entry.AnilistID)
+		if err != nil {
+			return TachiyomiMigration{}, err
+		}
+
+		if entry.Manga == nil {
+			c.options.Log(fmt.Sprintf("Tachiyomi entry %q not found", manga.Title))
+		}
+
+		migration.Entries = append(migration.Entries, entry)
+	}
+
+	return migration, nil
+}
+
+// matchTachiyomiManga searches the provider for the manga
+// with the given Anilist ID or the similar title
+func (c *Client) matchTachiyomiManga(ctx context.Context, title string, anilistID int) (Manga, error) {
+	mangas, err := c.SearchMangas(ctx, title)
+	if err != nil {
+		return nil, err
+	}
+
+	if anilistID != 0 {
+		for _, manga := range mangas {
+			withAnilist, ok, err := c.Anilist().MakeMangaWithAnilist(ctx, manga)
+			if err != nil {
+				return nil, err
+			}
+
+			if ok && withAnilist.Anilist.ID == anilistID {
+				return manga, nil
+			}
+		}
+	}
+
+	var (
+		closest    Manga
+		similarity float64
+	)
+
+	for _, manga := range mangas {
+		current := titleSimilarity(title, manga.Info().Title)
+		if current > similarity {
+			closest, similarity = manga, current
+		}
+	}
+
+	if similarity < tachiyomiTitleSimilarity {
+		return nil, nil
+	}
+
+	return closest, nil
+}
@@ -0,0 +1,265 @@
+package libmangal
+
+import (
+	"bytes"
+	"compress/gzip"
+	"google.golang.org/protobuf/encoding/protowire"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// protoMessage builds protobuf fixtures field by field,
+// independently of the marshal methods under test
+type protoMessage []byte
+
+func (m protoMessage) varint(num protowire.Number, value uint64) protoMessage {
+	b := protowire.AppendTag(m, num, protowire.VarintType)
+	return protowire.AppendVarint(b, value)
+}
+
+func (m protoMessage) float(num protowire.Number, value float32) protoMessage {
+	b := protowire.AppendTag(m, num, protowire.Fixed32Type)
+	return protowire.AppendFixed32(b, math.Float32bits(value))
+}
+
+func (m protoMessage) bytes(num protowire.Number, value []byte) protoMessage {
+	b := protowire.AppendTag(m, num, protowire.BytesType)
+	return protowire.AppendBytes(b, value)
+}
+
+func (m protoMessage) string(num protowire.Number, value string) protoMessage {
+	return m.bytes(num, []byte(value))
+}
+
+// tachiyomiBackupFixture is a gzipped backup laid out the way Tachiyomi
+// writes it, including a packed categories field, the deprecated
+// tracking media id and fields unknown to the parser
+func tachiyomiBackupFixture(tb testing.TB) []byte {
+	tb.Helper()
+
+	var packedCategories []byte
+	packedCategories = protowire.AppendVarint(packedCategories, 1)
+	packedCategories = protowire.AppendVarint(packedCategories, 3)
+
+	chapter1 := protoMessage{}.
+		string(1, "/chapter/1").
+		string(2, "Chapter 1").
+		string(3, "Scans").
+		varint(4, 1).
+		varint(6, 19).
+		varint(7, 1690000000000).
+		varint(8, 1680000000000).
+		float(9, 1).
+		varint(10, 1)
+
+	chapter2 := protoMessage{}.
+		string(1, "/chapter/1.5").
+		string(2, "Chapter 1.5").
+		varint(5, 1).
+		float(9, 1.5)
+
+	tracking := protoMessage{}.
+		varint(1, uint64(TachiyomiTrackerAnilist)).
+		varint(2, 777).
+		varint(3, 30013).
+		string(4, "https://anilist.co/manga/30013").
+		string(5, "One Piece").
+		float(6, 1).
+		varint(7, 1000).
+		float(8, 8.5).
+		varint(9, 1).
+		varint(10, 1670000000000)
+
+	history := protoMessage{}.
+		string(1, "/chapter/1").
+		varint(2, 1690000000000).
+		varint(3, 600000)
+
+	favorite := protoMessage{}.
+		varint(1, 2499283573021220255).
+		string(2, "/manga/one-piece").
+		string(3, "One Piece").
+		string(4, "Oda Eiichiro").
+		string(5, "Oda Eiichiro").
+		string(6, "Pirates").
+		string(7, "Action").
+		string(7, "Adventure").
+		varint(8, 1).
+		string(9, "https://example.com/cover.jpg").
+		varint(13, 1660000000000).
+		bytes(16, chapter1).
+		bytes(16, chapter2).
+		bytes(17, packedCategories).
+		bytes(18, tracking).
+		bytes(104, history).
+		// unknown fields are skipped
+		string(99, "ignored")
+
+	removed := protoMessage{}.
+		varint(1, 1).
+		string(3, "Removed").
+		varint(17, 2).
+		varint(100, 0)
+
+	backup := protoMessage{}.
+		bytes(1, favorite).
+		bytes(1, removed).
+		bytes(2, protoMessage{}.string(1, "Reading").varint(2, 1).varint(100, 64)).
+		bytes(2, protoMessage{}.string(1, "Done").varint(2, 3)).
+		bytes(101, protoMessage{}.string(1, "MangaDex").varint(2, 2499283573021220255))
+
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(backup); err != nil {
+		tb.Fatal(err)
+	}
+
+	if err := writer.Close(); err != nil {
+		tb.Fatal(err)
+	}
+
+	return buffer.Bytes()
+}
+
+func TestParseTachiyomiBackup(t *testing.T) {
+	backup, err := ParseTachiyomiBackup(bytes.NewReader(tachiyomiBackupFixture(t)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(backup.Mangas) != 2 {
+		t.Fatalf("got %d mangas, want 2", len(backup.Mangas))
+	}
+
+	manga := backup.Mangas[0]
+	wantManga := TachiyomiManga{
+		Source:       2499283573021220255,
+		URL:          "/manga/one-piece",
+		Title:        "One Piece",
+		Artist:       "Oda Eiichiro",
+		Author:       "Oda Eiichiro",
+		Description:  "Pirates",
+		Genres:       []string{"Action", "Adventure"},
+		Status:       1,
+		ThumbnailURL: "https://example.com/cover.jpg",
+		DateAdded:    1660000000000,
+		Chapters: []TachiyomiChapter{
+			{
+				URL:          "/chapter/1",
+				Name:         "Chapter 1",
+				Scanlator:    "Scans",
+				Read:         true,
+				LastPageRead: 19,
+				DateFetch:    1690000000000,
+				DateUpload:   1680000000000,
+				Number:       1,
+				SourceOrder:  1,
+			},
+			{
+				URL:      "/chapter/1.5",
+				Name:     "Chapter 1.5",
+				Bookmark: true,
+				Number:   1.5,
+			},
+		},
+		Categories: []int64{1, 3},
+		Tracking: []TachiyomiTracking{{
+			SyncID:          TachiyomiTrackerAnilist,
+			LibraryID:       777,
+			TrackingURL:     "https://anilist.co/manga/30013",
+			Title:           "One Piece",
+			LastChapterRead: 1,
+			TotalChapters:   1000,
+			Score:           8.5,
+			Status:          1,
+			StartedReading:  1670000000000,
+			MediaID:         30013,
+		}},
+		Favorite: true,
+		History: []TachiyomiHistory{{
+			URL:          "/chapter/1",
+			LastRead:     1690000000000,
+			ReadDuration: 600000,
+		}},
+	}
+
+	if !reflect.DeepEqual(manga, wantManga) {
+		t.Errorf("manga:\ngot  %+v\nwant %+v", manga, wantManga)
+	}
+
+	if id, ok := manga.AnilistID(); !ok || id != 30013 {
+		t.Errorf("AnilistID() = %d, %t, want 30013", id, ok)
+	}
+
+	if last := manga.LastChapterRead(); last != 1 {
+		t.Errorf("LastChapterRead() = %v, want 1", last)
+	}
+
+	removed := backup.Mangas[1]
+	if removed.Favorite {
+		t.Error("explicit favorite=false was ignored")
+	}
+
+	if !reflect.DeepEqual(removed.Categories, []int64{2}) {
+		t.Errorf("unpacked categories = %v, want [2]", removed.Categories)
+	}
+
+	wantCategories := []TachiyomiCategory{
+		{Name: "Reading", Order: 1, Flags: 64},
+		{Name: "Done", Order: 3},
+	}
+	if !reflect.DeepEqual(backup.Categories, wantCategories) {
+		t.Errorf("categories = %+v, want %+v", backup.Categories, wantCategories)
+	}
+
+	for _, order := range manga.Categories {
+		if _, ok := backup.CategoryName(order); !ok {
+			t.Errorf("category %d not found", order)
+		}
+	}
+
+	if name, ok := backup.SourceName(manga.Source); !ok || name != "MangaDex" {
+		t.Errorf("SourceName() = %q, %t, want MangaDex", name, ok)
+	}
+}
+
+func TestParseTachiyomiBackupTrackingMediaID(t *testing.T) {
+	// field 100 supersedes the deprecated field 3 regardless of their order
+	for _, tracking := range []protoMessage{
+		protoMessage{}.varint(1, uint64(TachiyomiTrackerAnilist)).varint(3, 1).varint(100, 2),
+		protoMessage{}.varint(1, uint64(TachiyomiTrackerAnilist)).varint(100, 2).varint(3, 1),
+	} {
+		var got TachiyomiTracking
+		if err := got.unmarshal(tracking); err != nil {
+			t.Fatal(err)
+		}
+
+		if got.MediaID != 2 {
+			t.Errorf("MediaID = %d, want 2", got.MediaID)
+		}
+	}
+}
+
+func TestParseTachiyomiBackupUncompressed(t *testing.T) {
+	raw := protoMessage{}.bytes(1, protoMessage{}.string(3, "Title"))
+
+	backup, err := ParseTachiyomiBackup(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(backup.Mangas) != 1 || backup.Mangas[0].Title != "Title" || !backup.Mangas[0].Favorite {
+		t.Errorf("got %+v", backup.Mangas)
+	}
+}
+
+func TestParseTachiyomiBackupMalformed(t *testing.T) {
+	// the manga message is truncated
+	malformed := protoMessage{}.bytes(1, protoMessage{}.string(3, "Title"))
+	malformed = malformed[:len(malformed)-2]
+
+	if _, err := ParseTachiyomiBackup(bytes.NewReader(malformed)); err == nil {
+		t.Error("expected an error")
+	}
+}
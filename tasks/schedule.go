@@ -0,0 +1,212 @@
+package tasks
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule decides when the task runs
+type Schedule interface {
+	// Next returns the next run time after the given time.
+	// Zero time means the task will never run again.
+	Next(after time.Time) time.Time
+}
+
+// Every runs the task periodically with the given interval.
+// Interval must be positive, Scheduler.Add rejects the schedule otherwise.
+func Every(interval time.Duration) Schedule {
+	return every(interval)
+}
+
+type every time.Duration
+
+func (e every) Next(after time.Time) time.Time {
+	// never run instead of running continuously
+	if e <= 0 {
+		return time.Time{}
+	}
+
+	return after.Add(time.Duration(e))
+}
+
+// validateSchedule rejects the schedules that would run continuously
+func validateSchedule(schedule Schedule) error {
+	if e, ok := schedule.(every); ok && e <= 0 {
+		return fmt.Errorf("interval must be positive, got %s", time.Duration(e))
+	}
+
+	return nil
+}
+
+// cron is the parsed cron expression.
+// Each field is a set of allowed values.
+type cron struct {
+	minute, hour, dom, month, dow [64]bool
+
+	// domAny and dowAny are true for "*" fields.
+	// If both day fields are restricted, either of them must match.
+	domAny, dowAny bool
+}
+
+// cronField describes the bounds of the cron field
+type cronField struct {
+	name     string
+	min, max int
+}
+
+var cronFields = [...]cronField{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	// both 0 and 7 are Sunday
+	{"day of week", 0, 7},
+}
+
+// cronAliases are the shorthands for common expressions
+var cronAliases = map[string]string{
+	"@hourly":  "0 * * * *",
+	"@daily":   "0 0 * * *",
+	"@weekly":  "0 0 * * 0",
+	"@monthly": "0 0 1 * *",
+	"@yearly":  "0 0 1 1 *",
+}
+
+// ParseCron parses the standard 5-field cron expression
+// "minute hour day-of-month month day-of-week" in the local time.
+//
+// Fields support "*", numbers, ranges "1-5", lists "1,3,5" and steps "*/15".
+// Sunday is either 0 or 7 in the day of week field.
+// Aliases @hourly, @daily, @weekly, @monthly and @yearly are supported too.
+func ParseCron(expression string) (Schedule, error) {
+	if alias, ok := cronAliases[strings.TrimSpace(expression)]; ok {
+		expression = alias
+	}
+
+	fields := strings.Fields(expression)
+	if len(fields) != len(cronFields) {
+		return nil, fmt.Errorf("cron: expected %d fields, got %d", len(cronFields), len(fields))
+	}
+
+	var c cron
+	sets := []*[64]bool{&c.minute, &c.hour, &c.dom, &c.month, &c.dow}
+
+	for i, field := range fields {
+		if err := parseCronField(field, cronFields[i], sets[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if c.dow[7] {
+		c.dow[0] = true
+	}
+
+	c.domAny = fields[2] == "*"
+	c.dowAny = fields[4] == "*"
+
+	return &c, nil
+}
+
+// MustParseCron is like ParseCron but panics on error
+func MustParseCron(expression string) Schedule {
+	schedule, err := ParseCron(expression)
+	if err != nil {
+		panic(err)
+	}
+
+	return schedule
+}
+
+func parseCronField(field string, bounds cronField, set *[64]bool) error {
+	for _, part := range strings.Split(field, ",") {
+		step := 1
+		if rangePart, stepPart, ok := strings.Cut(part, "/"); ok {
+			parsed, err := strconv.Atoi(stepPart)
+			if err != nil || parsed <= 0 {
+				return fmt.Errorf("cron: invalid step in %s field: %q", bounds.name, part)
+			}
+
+			step = parsed
+			part = rangePart
+		}
+
+		from, to := bounds.min, bounds.max
+		if part != "*" {
+			var err error
+			if start, end, ok := strings.Cut(part, "-"); ok {
+				from, err = strconv.Atoi(start)
+				if err == nil {
+					to, err = strconv.Atoi(end)
+				}
+			} else {
+				from, err = strconv.Atoi(part)
+				to = from
+			}
+
+			if err != nil {
+				return fmt.Errorf("cron: invalid %s field: %q", bounds.name, part)
+			}
+		}
+
+		if from < bounds.min || to > bounds.max || from > to {
+			return fmt.Errorf("cron: %s out of range %d-%d: %q", bounds.name, bounds.min, bounds.max, part)
+		}
+
+		for value := from; value <= to; value += step {
+			set[value] = true
+		}
+	}
+
+	return nil
+}
+
+func (c *cron) dayMatches(t time.Time) bool {
+	dom := c.dom[t.Day()]
+	dow := c.dow[int(t.Weekday())]
+
+	switch {
+	case c.domAny && c.dowAny:
+		return true
+	case c.domAny:
+		return dow
+	case c.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+func (c *cron) Next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+
+	// impossible expressions like "0 0 30 2 *" never match
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if !c.month[int(t.Month())] {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !c.hour[t.Hour()] {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !c.minute[t.Minute()] {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
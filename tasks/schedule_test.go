@@ -0,0 +1,98 @@
+package tasks
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCron(t *testing.T) {
+	tests := []struct {
+		expression string
+		valid      bool
+	}{
+		{expression: "* * * * *", valid: true},
+		{expression: "0 0 1 1 0", valid: true},
+		{expression: "59 23 31 12 6", valid: true},
+		{expression: "0 0 * * 7", valid: true},
+		{expression: "*/15 1-5 1,15 */2 1-7", valid: true},
+		{expression: "@daily", valid: true},
+		{expression: " @hourly ", valid: true},
+		{expression: ""},
+		{expression: "* * * *"},
+		{expression: "* * * * * *"},
+		{expression: "60 * * * *"},
+		{expression: "* 24 * * *"},
+		{expression: "* * 0 * *"},
+		{expression: "* * 32 * *"},
+		{expression: "* * * 0 *"},
+		{expression: "* * * 13 *"},
+		{expression: "* * * * 8"},
+		{expression: "5-1 * * * *"},
+		{expression: "*/0 * * * *"},
+		{expression: "*/x * * * *"},
+		{expression: "a * * * *"},
+		{expression: "1-x * * * *"},
+		{expression: "@never"},
+	}
+
+	for _, test := range tests {
+		_, err := ParseCron(test.expression)
+		if valid := err == nil; valid != test.valid {
+			t.Errorf("%q: got valid %v, want %v (%v)", test.expression, valid, test.valid, err)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	// Wednesday
+	after := time.Date(2024, time.January, 10, 10, 30, 45, 0, time.UTC)
+
+	tests := []struct {
+		expression string
+		want       time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.January, 10, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.January, 10, 10, 45, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2024, time.January, 10, 11, 0, 0, 0, time.UTC)},
+		{"30 10 * * *", time.Date(2024, time.January, 11, 10, 30, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, time.January, 11, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 0", time.Date(2024, time.January, 14, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, time.January, 14, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 1-5", time.Date(2024, time.January, 11, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"@yearly", time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+
+		// either day field matches if both are restricted
+		{"0 0 15 * 5", time.Date(2024, time.January, 12, 0, 0, 0, 0, time.UTC)},
+
+		// never matches
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, test := range tests {
+		schedule, err := ParseCron(test.expression)
+		if err != nil {
+			t.Errorf("%q: %s", test.expression, err)
+			continue
+		}
+
+		if got := schedule.Next(after); !got.Equal(test.want) {
+			t.Errorf("%q: got %s, want %s", test.expression, got, test.want)
+		}
+	}
+}
+
+func TestEveryNext(t *testing.T) {
+	after := time.Date(2024, time.January, 10, 10, 30, 0, 0, time.UTC)
+
+	if got, want := Every(time.Hour).Next(after), after.Add(time.Hour); !got.Equal(want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if got := Every(interval).Next(after); !got.IsZero() {
+			t.Errorf("%s: got %s, want never", interval, got)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+// Package tasks provides a lightweight scheduler for periodic
+// library maintenance jobs, e.g. library updates, cache cleaning
+// (see libmangal.Client.CleanCaches) or tracker sync.
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Job is the work of the task
+type Job func(ctx context.Context) error
+
+// EventKind is the kind of the task Event
+type EventKind uint8
+
+const (
+	// EventStarted is emitted when the task starts
+	EventStarted EventKind = iota + 1
+
+	// EventFinished is emitted when the task finishes successfully
+	EventFinished
+
+	// EventFailed is emitted when the task returns an error
+	EventFailed
+
+	// EventSkipped is emitted when the task is due
+	// but its previous run is still in progress
+	EventSkipped
+)
+
+func (e EventKind) String() string {
+	switch e {
+	case EventStarted:
+		return "started"
+	case EventFinished:
+		return "finished"
+	case EventFailed:
+		return "failed"
+	case EventSkipped:
+		return "skipped"
+	default:
+		return fmt.Sprintf("EventKind(%d)", e)
+	}
+}
+
+// Event describes what happened to the task
+type Event struct {
+	Task string
+	Kind EventKind
+	Time time.Time
+
+	// Duration of the run for EventFinished and EventFailed
+	Duration time.Duration
+
+	// Err is the error returned by the job for EventFailed
+	Err error
+}
+
+// Observer receives events of all tasks.
+// It's called from the goroutines running the tasks,
+// so it must be safe for concurrent use.
+type Observer func(event Event)
+
+type task struct {
+	name     string
+	schedule Schedule
+	job      Job
+
+	// running guards against overlapping runs
+	running sync.Mutex
+}
+
+// Scheduler runs registered tasks according to their schedules.
+// It is safe for concurrent use by multiple goroutines.
+type Scheduler struct {
+	observer Observer
+
+	mu      sync.Mutex
+	tasks   map[string]*task
+	wg      sync.WaitGroup
+	changed chan struct{}
+}
+
+// NewScheduler constructs new Scheduler.
+// Observer may be nil.
+func NewScheduler(observer Observer) *Scheduler {
+	if observer == nil {
+		observer = func(Event) {}
+	}
+
+	return &Scheduler{
+		observer: observer,
+		tasks:    make(map[string]*task),
+		changed:  make(chan struct{}, 1),
+	}
+}
+
+// Add registers the task. Name must be unique.
+// Tasks may be added while the scheduler is running.
+func (s *Scheduler) Add(name string, schedule Schedule, job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.tasks[name]; ok {
+		return fmt.Errorf("task %q already exists", name)
+	}
+
+	if err := validateSchedule(schedule); err != nil {
+		return fmt.Errorf("task %q: %w", name, err)
+	}
+
+	s.tasks[name] = &task{
+		name:     name,
+		schedule: schedule,
+		job:      job,
+	}
+
+	s.notify()
+	return nil
+}
+
+// Remove unregisters the task. Its current run, if any, is not interrupted.
+func (s *Scheduler) Remove(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.tasks, name)
+	s.notify()
+}
+
+// notify wakes up Run to recompute the next runs
+func (s *Scheduler) notify() {
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+// RunNow runs the task immediately, outside its schedule,
+// and waits for it to finish.
+func (s *Scheduler) RunNow(ctx context.Context, name string) error {
+	s.mu.Lock()
+	t, ok := s.tasks[name]
+	s.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("task %q not found", name)
+	}
+
+	return s.run(ctx, t)
+}
+
+func (s *Scheduler) run(ctx context.Context, t *task) error {
+	if !t.running.TryLock() {
+		s.observer(Event{Task: t.name, Kind: EventSkipped, Time: time.Now()})
+		return nil
+	}
+	defer t.running.Unlock()
+
+	started := time.Now()
+	s.observer(Event{Task: t.name, Kind: EventStarted, Time: started})
+
+	err := t.job(ctx)
+
+	event := Event{
+		Task:     t.name,
+		Kind:     EventFinished,
+		Time:     time.Now(),
+		Duration: time.Since(started),
+	}
+
+	if err != nil {
+		event.Kind = EventFailed
+		event.Err = err
+	}
+
+	s.observer(event)
+	return err
+}
+
+// Run runs the tasks until the context is canceled.
+// Then it waits for the running tasks to finish and returns the context error.
+func (s *Scheduler) Run(ctx context.Context) error {
+	defer s.wg.Wait()
+
+	next := make(map[*task]time.Time)
+
+	for {
+		now := time.Now()
+
+		s.mu.Lock()
+		var (
+			earliest time.Time
+			due      []*task
+		)
+
+		scheduled := make(map[*task]time.Time, len(s.tasks))
+		for _, t := range s.tasks {
+			at, ok := next[t]
+			if !ok {
+				at = t.schedule.Next(now)
+			}
+
+			if at.IsZero() {
+				continue
+			}
+
+			if !at.After(now) {
+				due = append(due, t)
+				at = t.schedule.Next(now)
+				if at.IsZero() {
+					continue
+				}
+			}
+
+			scheduled[t] = at
+			if earliest.IsZero() || at.Before(earliest) {
+				earliest = at
+			}
+		}
+		s.mu.Unlock()
+
+		// removed tasks are forgotten
+		next = scheduled
+
+		for _, t := range due {
+			t := t
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				_ = s.run(ctx, t)
+			}()
+		}
+
+		// nil channel blocks forever if nothing is scheduled
+		var (
+			timer  *time.Timer
+			timerC <-chan time.Time
+		)
+
+		if !earliest.IsZero() {
+			timer = time.NewTimer(time.Until(earliest))
+			timerC = timer.C
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-s.changed:
+		case <-timerC:
+		}
+
+		if timer != nil {
+			timer.Stop()
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSchedulerAdd(t *testing.T) {
+	scheduler := NewScheduler(nil)
+	job := func(context.Context) error { return nil }
+
+	if err := scheduler.Add("task", Every(time.Hour), job); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := scheduler.Add("task", Every(time.Hour), job); err == nil {
+		t.Error("duplicate task was added")
+	}
+
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if err := scheduler.Add(interval.String(), Every(interval), job); err == nil {
+			t.Errorf("task with %s interval was added", interval)
+		}
+	}
+}
+
+func TestSchedulerRun(t *testing.T) {
+	const runs = 3
+
+	ran := make(chan struct{}, runs)
+	scheduler := NewScheduler(nil)
+
+	err := scheduler.Add("task", Every(time.Millisecond), func(context.Context) error {
+		select {
+		case ran <- struct{}{}:
+		default:
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- scheduler.Run(ctx) }()
+
+	for i := 0; i < runs; i++ {
+		select {
+		case <-ran:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("task ran %d times, want %d", i, runs)
+		}
+	}
+
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("got %v, want context.Canceled", err)
+	}
+}
+
+func TestSchedulerRunNow(t *testing.T) {
+	var (
+		mu     sync.Mutex
+		events []EventKind
+	)
+
+	scheduler := NewScheduler(func(event Event) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event.Kind)
+	})
+
+	errJob := errors.New("job failed")
+	started, release := make(chan struct{}), make(chan struct{})
+
+	err := scheduler.Add("task", Every(time.Hour), func(context.Context) error {
+		close(started)
+		<-release
+		return errJob
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := scheduler.RunNow(context.Background(), "missing"); err == nil {
+		t.Error("missing task was run")
+	}
+
+	done := make(chan error)
+	go func() { done <- scheduler.RunNow(context.Background(), "task") }()
+
+	<-started
+
+	// overlapping run is skipped
+	if err := scheduler.RunNow(context.Background(), "task"); err != nil {
+		t.Errorf("skipped run: %s", err)
+	}
+
+	close(release)
+	if err := <-done; !errors.Is(err, errJob) {
+		t.Errorf("got %v, want the job error", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	want := []EventKind{EventStarted, EventSkipped, EventFailed}
+	if len(events) != len(want) {
+		t.Fatalf("got events %v, want %v", events, want)
+	}
+
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("got events %v, want %v", events, want)
+			break
+		}
+	}
+}
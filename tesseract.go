@@ -0,0 +1,49 @@
+//go:build tesseract
+
+package libmangal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// TesseractExtractor is the reference TextExtractor
+// that runs the tesseract OCR command.
+//
+// It's only available with the "tesseract" build tag.
+type TesseractExtractor struct {
+	// Command is the tesseract executable. Defaults to "tesseract"
+	Command string
+
+	// Languages are the tesseract languages, e.g. "jpn", "eng".
+	// Tesseract default is used if empty.
+	Languages []string
+}
+
+func (t TesseractExtractor) ExtractText(ctx context.Context, page PageWithImage) (string, error) {
+	command := t.Command
+	if command == "" {
+		command = "tesseract"
+	}
+
+	args := []string{"stdin", "stdout"}
+	if len(t.Languages) > 0 {
+		args = append(args, "-l", strings.Join(t.Languages, "+"))
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Stdin = bytes.NewReader(page.GetImage())
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("tesseract: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
@@ -0,0 +1,102 @@
+package libmangal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/afero"
+)
+
+// suffixTextJSON is appended to the chapter path
+// to get the path of its ChapterText sidecar
+const suffixTextJSON = ".text.json"
+
+// TextExtractor extracts text from page images, e.g. with OCR.
+// Extracted text is saved next to the chapter to enable full-text search.
+//
+// See DownloadOptions.TextExtractor
+type TextExtractor interface {
+	// ExtractText returns the text of the page image
+	ExtractText(ctx context.Context, page PageWithImage) (string, error)
+}
+
+// PageText is the text of the single page
+type PageText struct {
+	// Page number starting from 1
+	Page int    `json:"page"`
+	Text string `json:"text"`
+}
+
+// ChapterText is the sidecar with the text of the chapter pages
+type ChapterText struct {
+	Manga   string     `json:"manga"`
+	Chapter string     `json:"chapter"`
+	Number  float32    `json:"number"`
+	Pages   []PageText `json:"pages"`
+}
+
+// ChapterTextPath returns the path of the ChapterText sidecar
+// of the chapter downloaded at the given path
+func ChapterTextPath(chapterPath string) string {
+	return chapterPath + suffixTextJSON
+}
+
+// extractChapterText extracts text from the pages
+// and saves it to the sidecar of the chapter at path
+func (c *Client) extractChapterText(
+	ctx context.Context,
+	extractor TextExtractor,
+	chapter Chapter,
+	path string,
+	pages []PageWithImage,
+) error {
+	c.options.Log(fmt.Sprintf("Extracting text from %d pages", len(pages)))
+
+	text := ChapterText{
+		Manga:   chapter.Volume().Manga().Info().Title,
+		Chapter: chapter.Info().Title,
+		Number:  chapter.Info().Number,
+		Pages:   make([]PageText, len(pages)),
+	}
+
+	for i, page := range pages {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		pageText, err := extractor.ExtractText(ctx, page)
+		if err != nil {
+			return fmt.Errorf("page #%03d: %w", i+1, err)
+		}
+
+		text.Pages[i] = PageText{
+			Page: i + 1,
+			Text: pageText,
+		}
+	}
+
+	marshalled, err := json.Marshal(text)
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(c.options.FS, ChapterTextPath(path), marshalled, modeFile)
+}
+
+// ReadChapterText reads the ChapterText sidecar
+// of the chapter downloaded at the given path
+func (c *Client) ReadChapterText(chapterPath string) (ChapterText, error) {
+	contents, err := afero.ReadFile(c.options.FS, ChapterTextPath(chapterPath))
+	if err != nil {
+		return ChapterText{}, err
+	}
+
+	var text ChapterText
+	if err := json.Unmarshal(contents, &text); err != nil {
+		return ChapterText{}, err
+	}
+
+	return text, nil
+}
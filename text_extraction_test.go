@@ -0,0 +1,89 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// textExtractorFunc is the TextExtractor function
+type textExtractorFunc func(ctx context.Context, page PageWithImage) (string, error)
+
+func (f textExtractorFunc) ExtractText(ctx context.Context, page PageWithImage) (string, error) {
+	return f(ctx, page)
+}
+
+func TestDownloadChapterText(t *testing.T) {
+	provider := newFakeProvider(t, 1, 2)
+	client := newTestClient(t, provider)
+
+	options := testDownloadOptions()
+	options.TextExtractor = textExtractorFunc(func(_ context.Context, page PageWithImage) (string, error) {
+		return fmt.Sprintf("text of %s", page), nil
+	})
+
+	result, err := client.DownloadChapter(context.Background(), provider.chapterList()[0], options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.TextWritten {
+		t.Fatal("text was not written")
+	}
+
+	text, err := client.ReadChapterText(result.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := ChapterText{
+		Manga:   "Fake Manga",
+		Chapter: "Chapter 1",
+		Number:  1,
+		Pages: []PageText{
+			{Page: 1, Text: "text of page 1"},
+			{Page: 2, Text: "text of page 2"},
+		},
+	}
+
+	if !reflect.DeepEqual(text, want) {
+		t.Errorf("got %+v, want %+v", text, want)
+	}
+}
+
+func TestDownloadChapterTextError(t *testing.T) {
+	provider := newFakeProvider(t, 1, 2)
+	client := newTestClient(t, provider)
+	ctx := context.Background()
+
+	errOCR := errors.New("ocr failed")
+
+	options := testDownloadOptions()
+	options.TextExtractor = textExtractorFunc(func(context.Context, PageWithImage) (string, error) {
+		return "", errOCR
+	})
+
+	// failed extraction is a warning unless strict
+	result, err := client.DownloadChapter(ctx, provider.chapterList()[0], options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.TextWritten || len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "page #001") {
+		t.Errorf("got text written %t, warnings %q", result.TextWritten, result.Warnings)
+	}
+
+	if _, err := client.ReadChapterText(result.Path); err == nil {
+		t.Error("text of the failed extraction was written")
+	}
+
+	options.Strict = true
+	options.SkipIfExists = false
+
+	if _, err := client.DownloadChapter(ctx, provider.chapterList()[0], options); !errors.Is(err, errOCR) {
+		t.Errorf("got error %v, want the extraction error", err)
+	}
+}
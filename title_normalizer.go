@@ -0,0 +1,157 @@
+package libmangal
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// TitleRule replaces matches of the pattern in the chapter title.
+// Replacement may refer to submatches, see regexp.Regexp.ReplaceAllString
+type TitleRule struct {
+	Pattern     *regexp.Regexp
+	Replacement string
+}
+
+// DefaultTitleRules returns the built-in rules that remove
+// chapter numbers, site names and separators left by providers.
+func DefaultTitleRules() []TitleRule {
+	return []TitleRule{
+		// site names, e.g. "[MangaSite]" or "(example.com)"
+		{
+			Pattern:     regexp.MustCompile(`(?i)\s*[\[(][^\])]*(?:\.(?:com|net|org|io|to|me|cc|co)\b|manga|scans?\b|comics?\b|toons?\b)[^\])]*[\])]`),
+			Replacement: "",
+		},
+		// "Read online at ..." notices
+		{
+			Pattern:     regexp.MustCompile(`(?i)\s*\bread\s+(?:it\s+)?online\b.*$`),
+			Replacement: "",
+		},
+		// leading volume and chapter numbers, e.g. "Vol.2 Ch.10"
+		{
+			Pattern:     regexp.MustCompile(`(?i)^\s*(?:vol(?:ume)?\.?\s*\d+\s*)?(?:ch(?:apter)?|ep(?:isode)?)\.?\s*\d+(?:\.\d+)?`),
+			Replacement: "",
+		},
+		// separators left around the title
+		{
+			Pattern:     regexp.MustCompile(`^[\s:\-–—|.,~]+|[\s:\-–—|,~]+$`),
+			Replacement: "",
+		},
+		{
+			Pattern:     regexp.MustCompile(`\s{2,}`),
+			Replacement: " ",
+		},
+	}
+}
+
+// TitleNormalizerOptions configures the TitleNormalizer
+type TitleNormalizerOptions struct {
+	// Rules are applied in order
+	Rules []TitleRule
+
+	// FixCase converts titles written in all caps to the title case
+	FixCase bool
+}
+
+// DefaultTitleNormalizerOptions constructs default TitleNormalizerOptions
+func DefaultTitleNormalizerOptions() TitleNormalizerOptions {
+	return TitleNormalizerOptions{
+		Rules:   DefaultTitleRules(),
+		FixCase: true,
+	}
+}
+
+// TitleNormalizer cleans chapter titles given by providers,
+// e.g. "Ch.10 : : NAME [MangaSite]" becomes "Name".
+//
+// See ClientOptions.TitleNormalizer
+type TitleNormalizer struct {
+	options TitleNormalizerOptions
+}
+
+// NewTitleNormalizer constructs new TitleNormalizer
+func NewTitleNormalizer(options TitleNormalizerOptions) *TitleNormalizer {
+	return &TitleNormalizer{options: options}
+}
+
+// Normalize returns the cleaned title.
+// The original title is returned if nothing is left after cleaning.
+func (t *TitleNormalizer) Normalize(title string) string {
+	normalized := title
+	for _, rule := range t.options.Rules {
+		normalized = rule.Pattern.ReplaceAllString(normalized, rule.Replacement)
+	}
+
+	normalized = strings.TrimSpace(normalized)
+	if normalized == "" {
+		return strings.TrimSpace(title)
+	}
+
+	if t.options.FixCase && isAllCaps(normalized) {
+		normalized = toTitleCase(normalized)
+	}
+
+	return normalized
+}
+
+// isAllCaps reports whether the text has letters and none is lowercase
+func isAllCaps(text string) bool {
+	var hasUpper bool
+	for _, r := range text {
+		if unicode.IsLower(r) {
+			return false
+		}
+
+		if unicode.IsUpper(r) {
+			hasUpper = true
+		}
+	}
+
+	return hasUpper
+}
+
+func toTitleCase(text string) string {
+	words := strings.Fields(strings.ToLower(text))
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+
+	return strings.Join(words, " ")
+}
+
+// normalizeChapterTitle normalizes the title with ClientOptions.TitleNormalizer
+func (c *Client) normalizeChapterTitle(title string) string {
+	if c.options.TitleNormalizer == nil {
+		return title
+	}
+
+	return c.options.TitleNormalizer.Normalize(title)
+}
+
+// normalizedChapter overrides the title of the chapter with the normalized one
+type normalizedChapter struct {
+	Chapter
+	info ChapterInfo
+}
+
+func (n normalizedChapter) Info() ChapterInfo {
+	return n.info
+}
+
+func (c *Client) normalizeChapter(chapter Chapter) Chapter {
+	if c.options.TitleNormalizer == nil {
+		return chapter
+	}
+
+	info := chapter.Info()
+	info.Title = c.normalizeChapterTitle(info.Title)
+
+	return normalizedChapter{
+		Chapter: chapter,
+		info:    info,
+	}
+}
+
+var _ Chapter = normalizedChapter{}
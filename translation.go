@@ -0,0 +1,119 @@
+package libmangal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Translator translates provider titles, e.g. with DeepL or Google Translate,
+// for users downloading from raws-only sources.
+//
+// See ClientOptions.Translator
+type Translator interface {
+	// Translate translates the text to the target language
+	Translate(ctx context.Context, text string) (string, error)
+}
+
+// TranslatorFunc is an adapter to use ordinary functions as Translator
+type TranslatorFunc func(ctx context.Context, text string) (string, error)
+
+func (t TranslatorFunc) Translate(ctx context.Context, text string) (string, error) {
+	return t(ctx, text)
+}
+
+// translate translates the text with ClientOptions.Translator
+// caching results in the ClientOptions.TranslationStore.
+// Original text is returned if translation fails.
+func (c *Client) translate(ctx context.Context, text string) string {
+	translator := c.options.Translator
+	if translator == nil || text == "" {
+		return text
+	}
+
+	// texts may be long and contain any characters
+	sum := sha256.Sum256([]byte(text))
+	key := hex.EncodeToString(sum[:])
+
+	store := c.options.TranslationStore
+	if store != nil {
+		var translated string
+		found, err := store.Get(key, &translated)
+		if err == nil && found {
+			return translated
+		}
+	}
+
+	translated, err := translator.Translate(ctx, text)
+	if err != nil {
+		c.options.Log(fmt.Sprintf("Failed to translate %q: %s", text, err))
+		return text
+	}
+
+	if store != nil {
+		if err := store.Set(key, translated); err != nil {
+			c.options.Log(fmt.Sprintf("Failed to cache translation: %s", err))
+		}
+	}
+
+	return translated
+}
+
+// translatedManga overrides the title of the manga with the translated one.
+// AnilistSearch is kept, so it's searched by the original title.
+type translatedManga struct {
+	Manga
+	info MangaInfo
+}
+
+func (t translatedManga) Info() MangaInfo {
+	return t.info
+}
+
+func (c *Client) translateManga(manga Manga) Manga {
+	if c.options.Translator == nil {
+		return manga
+	}
+
+	info := manga.Info()
+	if info.AnilistSearch == "" {
+		info.AnilistSearch = info.Title
+	}
+
+	info.Title = c.translate(context.Background(), info.Title)
+
+	return translatedManga{
+		Manga: manga,
+		info:  info,
+	}
+}
+
+// translatedChapter overrides the title of the chapter with the translated one
+type translatedChapter struct {
+	Chapter
+	info ChapterInfo
+}
+
+func (t translatedChapter) Info() ChapterInfo {
+	return t.info
+}
+
+func (c *Client) translateChapter(chapter Chapter) Chapter {
+	if c.options.Translator == nil {
+		return chapter
+	}
+
+	info := chapter.Info()
+	info.Title = c.translate(context.Background(), info.Title)
+
+	return translatedChapter{
+		Chapter: chapter,
+		info:    info,
+	}
+}
+
+var (
+	_ Manga   = translatedManga{}
+	_ Chapter = translatedChapter{}
+)
@@ -0,0 +1,91 @@
+package libmangal
+
+import (
+	"fmt"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// trashNameSeparator separates removal timestamp from
+// the original name of the file moved to the trash
+const trashNameSeparator = "_"
+
+// moveToTrash moves file or directory at the given path to the trash directory.
+// Removal time is encoded in the resulting name so that trash can be cleaned up later.
+func moveToTrash(fs afero.Fs, path, trashDir string) (string, error) {
+	if err := fs.MkdirAll(trashDir, modeDir); err != nil {
+		return "", err
+	}
+
+	name := strconv.FormatInt(time.Now().UnixNano(), 10) + trashNameSeparator + filepath.Base(path)
+	trashPath := filepath.Join(trashDir, name)
+
+	if err := fs.Rename(path, trashPath); err != nil {
+		return "", err
+	}
+
+	return trashPath, nil
+}
+
+// trashedAt parses the removal time from the name of the trashed file
+func trashedAt(name string) (time.Time, bool) {
+	timestamp, _, found := strings.Cut(name, trashNameSeparator)
+	if !found {
+		return time.Time{}, false
+	}
+
+	nanos, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, nanos), true
+}
+
+// EmptyTrash permanently removes chapters that were moved
+// to the trashDir by RemoveChapter more than ttl ago.
+// Zero ttl removes everything.
+//
+// It returns paths of the removed entries.
+func (c *Client) EmptyTrash(trashDir string, ttl time.Duration) ([]string, error) {
+	exists, err := afero.DirExists(c.options.FS, trashDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if !exists {
+		return nil, nil
+	}
+
+	entries, err := afero.ReadDir(c.options.FS, trashDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+	for _, entry := range entries {
+		removedAt, ok := trashedAt(entry.Name())
+		if !ok {
+			// not created by libmangal
+			continue
+		}
+
+		if time.Since(removedAt) < ttl {
+			continue
+		}
+
+		path := filepath.Join(trashDir, entry.Name())
+		c.options.Log(fmt.Sprintf("Removing %s from trash", path))
+
+		if err := c.options.FS.RemoveAll(path); err != nil {
+			return removed, err
+		}
+
+		removed = append(removed, path)
+	}
+
+	return removed, nil
+}
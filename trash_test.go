@@ -0,0 +1,147 @@
+package libmangal
+
+import (
+	"github.com/spf13/afero"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRemoveChapter(t *testing.T) {
+	client := newTestClient(t, newFakeProvider(t, 0, 0))
+	fs := client.options.FS
+
+	file := "/library/Manga/Chapter 1.cbz"
+	dir := "/library/Manga/Chapter 2"
+
+	if err := afero.WriteFile(fs, file, []byte("chapter"), modeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := afero.WriteFile(fs, filepath.Join(dir, "0001.jpg"), []byte("page"), modeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	// chapters are deleted permanently by default
+	for _, path := range []string{file, dir} {
+		if err := client.RemoveChapter(path, DefaultRemoveOptions()); err != nil {
+			t.Fatal(err)
+		}
+
+		if exists, _ := afero.Exists(fs, path); exists {
+			t.Errorf("%s was not removed", path)
+		}
+	}
+
+	if exists, _ := afero.DirExists(fs, "/library/Manga"); !exists {
+		t.Error("manga directory was removed")
+	}
+
+	if err := afero.WriteFile(fs, file, []byte("chapter"), modeFile); err != nil {
+		t.Fatal(err)
+	}
+
+	options := RemoveOptions{Trash: true}
+	if err := client.RemoveChapter(file, options); err == nil {
+		t.Error("expected an error without the trash directory")
+	}
+
+	if exists, _ := afero.Exists(fs, file); !exists {
+		t.Fatal("chapter was removed without the trash directory")
+	}
+
+	options.TrashDir = "/library/.trash"
+	if err := client.RemoveChapter(file, options); err != nil {
+		t.Fatal(err)
+	}
+
+	if exists, _ := afero.Exists(fs, file); exists {
+		t.Error("chapter was not moved to the trash")
+	}
+
+	entries, err := afero.ReadDir(fs, options.TrashDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 || !strings.HasSuffix(entries[0].Name(), trashNameSeparator+filepath.Base(file)) {
+		t.Fatalf("trash has %v", entries)
+	}
+
+	if _, ok := trashedAt(entries[0].Name()); !ok {
+		t.Errorf("removal time is not encoded in %q", entries[0].Name())
+	}
+
+	contents, err := afero.ReadFile(fs, filepath.Join(options.TrashDir, entries[0].Name()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(contents) != "chapter" {
+		t.Errorf("trashed chapter has %q", contents)
+	}
+}
+
+func TestEmptyTrash(t *testing.T) {
+	client := newTestClient(t, newFakeProvider(t, 0, 0))
+	fs := client.options.FS
+
+	const trashDir = "/library/.trash"
+
+	trashed := func(age time.Duration, name string) string {
+		return filepath.Join(trashDir, strconv.FormatInt(time.Now().Add(-age).UnixNano(), 10)+trashNameSeparator+name)
+	}
+
+	old := trashed(48*time.Hour, "Chapter 1.cbz")
+	oldDir := trashed(72*time.Hour, "Chapter 2")
+	recent := trashed(time.Minute, "Chapter 3.cbz")
+	foreign := filepath.Join(trashDir, "notes.txt")
+
+	for _, path := range []string{old, recent, foreign, filepath.Join(oldDir, "0001.jpg")} {
+		if err := afero.WriteFile(fs, path, []byte("data"), modeFile); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := client.EmptyTrash(trashDir, 24*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(removed) != 2 {
+		t.Errorf("removed %v, want %s and %s", removed, old, oldDir)
+	}
+
+	for path, want := range map[string]bool{
+		old:     false,
+		oldDir:  false,
+		recent:  true,
+		foreign: true,
+	} {
+		if exists, _ := afero.Exists(fs, path); exists != want {
+			t.Errorf("%s exists: %t, want %t", path, exists, want)
+		}
+	}
+
+	// zero ttl removes everything trashed by libmangal
+	removed, err = client.EmptyTrash(trashDir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(removed) != 1 || removed[0] != recent {
+		t.Errorf("removed %v, want %s", removed, recent)
+	}
+
+	if exists, _ := afero.Exists(fs, foreign); !exists {
+		t.Error("file not created by libmangal was removed")
+	}
+
+	// missing trash is empty
+	removed, err = client.EmptyTrash("/missing", 0)
+	if err != nil || len(removed) != 0 {
+		t.Errorf("EmptyTrash(missing) = %v, %v", removed, err)
+	}
+}
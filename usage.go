@@ -0,0 +1,184 @@
+package libmangal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Usage is the amount of data downloaded
+type Usage struct {
+	// Bytes downloaded
+	Bytes int64 `json:"bytes"`
+
+	// Requests made
+	Requests int `json:"requests"`
+
+	// UpdatedAt is the time of the last download
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// MangaUsage is the Usage of the single manga
+type MangaUsage struct {
+	Usage
+
+	// Manga title
+	Manga string `json:"manga"`
+
+	// MangaID is the id of the manga within the provider
+	MangaID string `json:"mangaId"`
+}
+
+// UsageStats are the download statistics of the provider
+// accumulated across sessions. See Client.UsageStats
+type UsageStats struct {
+	// Total usage of the provider
+	Total Usage
+
+	// Mangas are usages of each manga, the largest first
+	Mangas []MangaUsage
+}
+
+// usageTotalKey is the key of the provider total usage
+const usageTotalKey = "total"
+
+// usageTracker accumulates usage in the ClientOptions.UsageStore.
+// It's shared between the copies of the client.
+type usageTracker struct {
+	index *storeIndex
+
+	// mu guards read-modify-write of the counters
+	mu sync.Mutex
+}
+
+// recordUsage adds downloaded bytes to the manga and provider usage.
+// Errors are logged only, since usage accounting must not break downloads.
+func (c *Client) recordUsage(manga Manga, bytes int64) {
+	tracker := c.usage
+	if tracker == nil {
+		return
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	store := c.options.UsageStore
+	now := time.Now()
+
+	var total Usage
+	if _, err := store.Get(usageTotalKey, &total); err != nil {
+		c.options.Log(fmt.Sprintf("Failed to read usage: %s", err))
+		return
+	}
+
+	total.Bytes += bytes
+	total.Requests++
+	total.UpdatedAt = now
+
+	if err := store.Set(usageTotalKey, total); err != nil {
+		c.options.Log(fmt.Sprintf("Failed to record usage: %s", err))
+		return
+	}
+
+	info := manga.Info()
+	key := "manga/" + info.ID
+
+	var mangaUsage MangaUsage
+	if _, err := store.Get(key, &mangaUsage); err != nil {
+		c.options.Log(fmt.Sprintf("Failed to read usage: %s", err))
+		return
+	}
+
+	mangaUsage.Manga = info.Title
+	mangaUsage.MangaID = info.ID
+	mangaUsage.Bytes += bytes
+	mangaUsage.Requests++
+	mangaUsage.UpdatedAt = now
+
+	if err := tracker.index.set(key, mangaUsage); err != nil {
+		c.options.Log(fmt.Sprintf("Failed to record usage: %s", err))
+	}
+}
+
+// UsageStats returns the download statistics of the provider, so that
+// users on metered connections can see where their bandwidth goes.
+//
+// Usage is tracked if ClientOptions.UsageStore is set.
+// Use a separate store for each provider.
+func (c *Client) UsageStats() (UsageStats, error) {
+	tracker := c.usage
+	if tracker == nil {
+		return UsageStats{}, nil
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	store := c.options.UsageStore
+
+	var stats UsageStats
+	if _, err := store.Get(usageTotalKey, &stats.Total); err != nil {
+		return UsageStats{}, err
+	}
+
+	keys, err := tracker.index.keys()
+	if err != nil {
+		return UsageStats{}, err
+	}
+
+	for _, key := range keys {
+		if !strings.HasPrefix(key, "manga/") {
+			continue
+		}
+
+		var mangaUsage MangaUsage
+		found, err := store.Get(key, &mangaUsage)
+		if err != nil {
+			return UsageStats{}, err
+		}
+
+		if found {
+			stats.Mangas = append(stats.Mangas, mangaUsage)
+		}
+	}
+
+	sort.Slice(stats.Mangas, func(i, j int) bool {
+		return stats.Mangas[i].Bytes > stats.Mangas[j].Bytes
+	})
+
+	return stats, nil
+}
+
+// averageImageSize returns the average size of the images downloaded
+// for the manga, or for any manga of the provider if there are none yet.
+// Zero if nothing was downloaded or usage is not tracked.
+func (c *Client) averageImageSize(manga Manga) (int64, error) {
+	tracker := c.usage
+	if tracker == nil {
+		return 0, nil
+	}
+
+	tracker.mu.Lock()
+	defer tracker.mu.Unlock()
+
+	store := c.options.UsageStore
+
+	var usage MangaUsage
+	if _, err := store.Get("manga/"+manga.Info().ID, &usage); err != nil {
+		return 0, err
+	}
+
+	if usage.Requests == 0 {
+		if _, err := store.Get(usageTotalKey, &usage.Usage); err != nil {
+			return 0, err
+		}
+	}
+
+	if usage.Requests == 0 {
+		return 0, nil
+	}
+
+	return usage.Bytes / int64(usage.Requests), nil
+}
@@ -0,0 +1,66 @@
+package libmangal
+
+import (
+	"context"
+	"testing"
+)
+
+func TestUsageStats(t *testing.T) {
+	const pages = 3
+
+	provider := newFakeProvider(t, 2, pages)
+	client := newTestClient(t, provider)
+
+	for _, chapter := range provider.chapterList() {
+		if _, err := client.DownloadChapter(context.Background(), chapter, testDownloadOptions()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := client.UsageStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Usage{
+		Bytes:    int64(2 * pages * len(provider.image)),
+		Requests: 2 * pages,
+	}
+
+	if stats.Total.Bytes != want.Bytes || stats.Total.Requests != want.Requests {
+		t.Errorf("got total %+v, want %+v", stats.Total, want)
+	}
+
+	if len(stats.Mangas) != 1 {
+		t.Fatalf("got %d mangas, want 1", len(stats.Mangas))
+	}
+
+	manga := stats.Mangas[0]
+	if manga.MangaID != provider.manga().info.ID || manga.Bytes != want.Bytes || manga.Requests != want.Requests {
+		t.Errorf("got manga usage %+v", manga)
+	}
+}
+
+func TestUsageStatsDisabled(t *testing.T) {
+	options := testClientOptions()
+	options.UsageStore = nil
+
+	provider := newFakeProvider(t, 1, 1)
+	client, err := NewClient(context.Background(), provider, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.DownloadChapter(context.Background(), provider.chapterList()[0], testDownloadOptions()); err != nil {
+		t.Fatal(err)
+	}
+
+	stats, err := client.UsageStats()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stats.Total.Requests != 0 || len(stats.Mangas) != 0 {
+		t.Errorf("got %+v without the usage store", stats)
+	}
+}
@@ -0,0 +1,72 @@
+package libmangal
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+)
+
+// UserAgentProvider provides User-Agent header values for requests.
+// Implementations must be safe for concurrent use.
+//
+// See ClientOptions.UserAgent
+type UserAgentProvider interface {
+	// UserAgent returns User-Agent to use for the request to the host
+	UserAgent(host string) string
+}
+
+// StaticUserAgent always uses the same User-Agent
+type StaticUserAgent string
+
+func (s StaticUserAgent) UserAgent(string) string {
+	return string(s)
+}
+
+// randomPerHostUserAgent picks a random User-Agent from the pool
+// for each host and sticks to it, so that sessions look consistent.
+type randomPerHostUserAgent struct {
+	pool  []string
+	hosts sync.Map
+}
+
+// NewRandomPerHostUserAgent constructs UserAgentProvider that picks a random
+// User-Agent from the pool for each host and keeps using it for that host.
+// Pool must be non-empty.
+func NewRandomPerHostUserAgent(pool []string) UserAgentProvider {
+	return &randomPerHostUserAgent{pool: pool}
+}
+
+func (r *randomPerHostUserAgent) UserAgent(host string) string {
+	if userAgent, ok := r.hosts.Load(host); ok {
+		return userAgent.(string)
+	}
+
+	userAgent, _ := r.hosts.LoadOrStore(host, r.pool[rand.Intn(len(r.pool))])
+	return userAgent.(string)
+}
+
+// rotatingUserAgent cycles through the pool on each request
+type rotatingUserAgent struct {
+	pool []string
+	next atomic.Uint64
+}
+
+// NewRotatingUserAgent constructs UserAgentProvider that cycles
+// through the pool on each request regardless of the host.
+// Pool must be non-empty.
+func NewRotatingUserAgent(pool []string) UserAgentProvider {
+	return &rotatingUserAgent{pool: pool}
+}
+
+func (r *rotatingUserAgent) UserAgent(string) string {
+	return r.pool[(r.next.Add(1)-1)%uint64(len(r.pool))]
+}
+
+// userAgent returns User-Agent for the request to the host
+func (c *Client) userAgent(host string) string {
+	if c.options.UserAgent == nil {
+		return UserAgent
+	}
+
+	return c.options.UserAgent.UserAgent(host)
+}
@@ -0,0 +1,78 @@
+package libmangal
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestRandomPerHostUserAgent(t *testing.T) {
+	pool := []string{"agent-1", "agent-2", "agent-3"}
+	provider := NewRandomPerHostUserAgent(pool)
+
+	inPool := func(userAgent string) bool {
+		for _, p := range pool {
+			if p == userAgent {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	// each host sticks to its User-Agent, also when asked concurrently
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		host := fmt.Sprintf("host-%d.test", i)
+		first := provider.UserAgent(host)
+		if !inPool(first) {
+			t.Fatalf("%s: got %q, want one from the pool", host, first)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 10; j++ {
+				if got := provider.UserAgent(host); got != first {
+					t.Errorf("%s: got %q, then %q", host, first, got)
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestRotatingUserAgent(t *testing.T) {
+	provider := NewRotatingUserAgent([]string{"agent-1", "agent-2"})
+
+	var got []string
+	for i := 0; i < 5; i++ {
+		got = append(got, provider.UserAgent("manga.test"))
+	}
+
+	want := []string{"agent-1", "agent-2", "agent-1", "agent-2", "agent-1"}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestClientUserAgent(t *testing.T) {
+	client := newTestClient(t, newFakeProvider(t, 1, 1))
+
+	if got := client.userAgent("manga.test"); got != UserAgent {
+		t.Errorf("got default %q, want %q", got, UserAgent)
+	}
+
+	client.options.UserAgent = StaticUserAgent("test-agent")
+	if got := client.userAgent("manga.test"); got != "test-agent" {
+		t.Errorf("got %q, want the static User-Agent", got)
+	}
+
+	client.options.UserAgent = nil
+	if got := client.userAgent("manga.test"); got != UserAgent {
+		t.Errorf("got %q for nil provider, want %q", got, UserAgent)
+	}
+}
@@ -0,0 +1,185 @@
+package libmangal
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// VolumePath returns the path of the volume downloaded with DownloadVolume
+func (c *Client) VolumePath(volume Volume, options DownloadOptions) string {
+	directory := options.Directory
+
+	if options.CreateMangaDir {
+		directory = filepath.Join(directory, c.ComputeMangaFilename(volume.Manga()))
+	}
+
+	return filepath.Join(directory, c.ComputeVolumeFilename(volume)+options.Format.Extension())
+}
+
+// DownloadVolume downloads all chapters of the volume and saves them
+// as a single file named after the volume. See VolumePath
+//
+// Only FormatCBZ and FormatPDF are supported. ComicInfo.xml of the CBZ
+// describes the whole volume and bookmarks the first page of each chapter.
+func (c *Client) DownloadVolume(
+	ctx context.Context,
+	volume Volume,
+	options DownloadOptions,
+) (DownloadResult, error) {
+	if options.Format != FormatCBZ && options.Format != FormatPDF {
+		return DownloadResult{}, fmt.Errorf("volumes can't be downloaded as %s", options.Format.Name())
+	}
+
+	// paths depend on it
+	if err := c.resolveAnilistMangaDir(ctx, volume.Manga()); err != nil {
+		return DownloadResult{}, err
+	}
+
+	c.options.Log(fmt.Sprintf("Downloading volume %q as %s", volume, options.Format.Name()))
+
+	started := time.Now()
+
+	path := c.VolumePath(volume, options)
+	result := DownloadResult{
+		Path:   path,
+		Format: options.Format,
+	}
+
+	if options.SkipIfExists {
+		exists, err := afero.Exists(c.options.FS, path)
+		if err != nil {
+			return DownloadResult{}, err
+		}
+
+		if exists {
+			result.Skipped = true
+			return result, nil
+		}
+	}
+
+	chapters, err := c.VolumeChapters(ctx, volume)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+
+	if len(chapters) == 0 {
+		return DownloadResult{}, fmt.Errorf("volume %q has no chapters", volume)
+	}
+
+	sort.SliceStable(chapters, func(i, j int) bool {
+		return chapters[i].Info().Number < chapters[j].Info().Number
+	})
+
+	var (
+		pages     []PageWithImage
+		bookmarks []comicInfoXMLPage
+	)
+
+	for _, chapter := range chapters {
+		chapterPages, err := c.ChapterPages(ctx, chapter)
+		if err != nil {
+			return DownloadResult{}, fmt.Errorf("%s: %w", chapter, err)
+		}
+
+		downloaded, err := c.downloadAndProcessPages(ctx, chapterPages, options)
+		if err != nil {
+			return DownloadResult{}, fmt.Errorf("%s: %w", chapter, err)
+		}
+
+		bookmarks = append(bookmarks, comicInfoXMLPage{
+			Image:    len(pages),
+			Bookmark: c.normalizeChapterTitle(chapter.Info().Title),
+		})
+
+		pages = append(pages, downloaded...)
+	}
+
+	result.PageCount = len(pages)
+
+	// the file is written at once, so that failed downloads don't leave partial volumes
+	var buffer bytes.Buffer
+	switch options.Format {
+	case FormatPDF:
+		if err := c.savePDF(pages, &buffer, nil); err != nil {
+			return DownloadResult{}, err
+		}
+	case FormatCBZ:
+		comicInfoXML, err := c.getComicInfoXML(ctx, chapters[0], options.ComicInfoXMLOptions.Source)
+		if err != nil {
+			if options.Strict {
+				return DownloadResult{}, err
+			}
+
+			result.warn(err)
+		} else {
+			result.ComicInfoXMLWritten = true
+		}
+
+		comicInfoXML.Title = c.translate(ctx, volume.String())
+		comicInfoXML.Series = c.translate(ctx, comicInfoXML.Series)
+		comicInfoXML.Summary = options.Description.apply(comicInfoXML.Summary)
+		comicInfoXML.Number = 0
+		comicInfoXML.Volume = volume.Info().Number
+		comicInfoXML.Web = ""
+
+		if comicInfoXML.Manga == "" {
+			comicInfoXML.Manga = volume.Manga().Info().ReadingDirection.comicInfoManga()
+		}
+
+		wrapper := comicInfoXML.wrapper(options.ComicInfoXMLOptions)
+		wrapper.PageCount = len(pages)
+		wrapper.Pages = &comicInfoXMLPages{Page: bookmarks}
+		for _, warning := range wrapper.sanitize() {
+			result.warn(warning)
+		}
+
+		var cover []byte
+		if options.EmbedCover {
+			var coverBuffer bytes.Buffer
+			if err := c.downloadVolumeCover(ctx, volume, c.coverProviders(options), &coverBuffer); err != nil {
+				if options.Strict {
+					return DownloadResult{}, MetadataError{err}
+				}
+
+				result.warn(err)
+			} else {
+				cover = coverBuffer.Bytes()
+			}
+		}
+
+		if err := c.saveCBZ(pages, &buffer, cover, wrapper, ""); err != nil {
+			return DownloadResult{}, err
+		}
+	}
+
+	if err := c.options.FS.MkdirAll(filepath.Dir(path), modeDir); err != nil {
+		return DownloadResult{}, err
+	}
+
+	if err := afero.WriteFile(c.options.FS, path, buffer.Bytes(), modeFile); err != nil {
+		return DownloadResult{}, err
+	}
+
+	if options.WriteVolumeJson {
+		volumeJSON := c.volumeJSON(ctx, volume, chapters, options)
+		if err := c.writeVolumeJSON(VolumeJSONPath(path), volumeJSON); err != nil {
+			if options.Strict {
+				return DownloadResult{}, MetadataError{err}
+			}
+
+			result.warn(err)
+		} else {
+			result.VolumeJSONWritten = true
+		}
+	}
+
+	result.BytesWritten = int64(buffer.Len())
+	result.Duration = time.Since(started)
+
+	return result, nil
+}
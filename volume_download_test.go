@@ -0,0 +1,126 @@
+package libmangal
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"github.com/spf13/afero"
+	"io"
+	"testing"
+)
+
+func TestDownloadVolume(t *testing.T) {
+	provider := newFakeProvider(t, 2, 2)
+	client := newTestClient(t, provider)
+	ctx := context.Background()
+
+	options := testDownloadOptions()
+	volume := provider.volume()
+
+	result, err := client.DownloadVolume(ctx, volume, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Path != client.VolumePath(volume, options) || result.PageCount != 4 {
+		t.Fatalf("got %s with %d pages, want %s with 4", result.Path, result.PageCount, client.VolumePath(volume, options))
+	}
+
+	data, err := afero.ReadFile(client.options.FS, result.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		images       int
+		comicInfoXML comicInfoXMLWrapper
+	)
+
+	for _, file := range archive.File {
+		entry, err := file.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content, err := io.ReadAll(entry)
+		_ = entry.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if file.Name == filenameComicInfoXML {
+			if err := xml.Unmarshal(content, &comicInfoXML); err != nil {
+				t.Fatal(err)
+			}
+
+			continue
+		}
+
+		if !bytes.Equal(content, provider.image) {
+			t.Errorf("%s differs from the page image", file.Name)
+		}
+
+		images++
+	}
+
+	if images != 4 {
+		t.Errorf("got %d images, want 4", images)
+	}
+
+	// ComicInfo.xml describes the volume and bookmarks the chapters
+	if comicInfoXML.Volume != 1 || comicInfoXML.Number != 0 || comicInfoXML.Title != volume.String() {
+		t.Errorf("got volume %d, number %v, title %q", comicInfoXML.Volume, comicInfoXML.Number, comicInfoXML.Title)
+	}
+
+	bookmarks := make(map[int]string)
+	if comicInfoXML.Pages != nil {
+		for _, page := range comicInfoXML.Pages.Page {
+			if page.Bookmark != "" {
+				bookmarks[page.Image] = page.Bookmark
+			}
+		}
+	}
+
+	want := map[int]string{
+		0: client.normalizeChapterTitle("Chapter 1"),
+		2: client.normalizeChapterTitle("Chapter 2"),
+	}
+
+	if len(bookmarks) != len(want) || bookmarks[0] != want[0] || bookmarks[2] != want[2] {
+		t.Errorf("got bookmarks %v, want %v", bookmarks, want)
+	}
+
+	// existing volumes are skipped
+	requests := provider.requests.Load()
+
+	result, err = client.DownloadVolume(ctx, volume, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.Skipped || provider.requests.Load() != requests {
+		t.Error("existing volume was downloaded again")
+	}
+}
+
+func TestDownloadVolumeFormat(t *testing.T) {
+	provider := newFakeProvider(t, 1, 1)
+	client := newTestClient(t, provider)
+
+	options := testDownloadOptions()
+	options.Format = FormatTAR
+
+	if _, err := client.DownloadVolume(context.Background(), provider.volume(), options); err == nil {
+		t.Error("expected an error for TAR volumes")
+	}
+
+	if provider.requests.Load() != 0 {
+		t.Error("pages were downloaded")
+	}
+}
@@ -0,0 +1,156 @@
+package libmangal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/afero"
+	"path/filepath"
+	"sort"
+)
+
+const (
+	filenameVolumeJSON = "volume.json"
+
+	// suffixVolumeJSON is appended to the path of the volume
+	// downloaded as a single file to get its VolumeJSON sidecar
+	suffixVolumeJSON = ".volume.json"
+)
+
+// VolumeJSONChapter is the chapter contained in the volume
+type VolumeJSONChapter struct {
+	Number float32 `json:"number"`
+	Title  string  `json:"title"`
+	URL    string  `json:"url,omitempty"`
+}
+
+// VolumeJSON is the volume-level metadata, analogous to series.json.
+//
+// It's written as volume.json to the volume directory,
+// see DownloadOptions.WriteVolumeJson, or next to the volume
+// downloaded with DownloadVolume, see VolumeJSONPath.
+type VolumeJSON struct {
+	Manga  string `json:"manga"`
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+
+	// Cover is the URL of the volume cover. Empty if unknown.
+	Cover string `json:"cover,omitempty"`
+
+	// Chapters are chapters of the volume ordered by number
+	Chapters []VolumeJSONChapter `json:"chapters"`
+}
+
+// VolumeJSONPath returns the path of the VolumeJSON sidecar
+// of the volume downloaded with DownloadVolume at the given path
+func VolumeJSONPath(volumePath string) string {
+	return volumePath + suffixVolumeJSON
+}
+
+// ReadVolumeJSON reads the VolumeJSON at the given path.
+// It's either volume.json of the volume directory or the sidecar, see VolumeJSONPath.
+func (c *Client) ReadVolumeJSON(path string) (VolumeJSON, error) {
+	contents, err := afero.ReadFile(c.options.FS, path)
+	if err != nil {
+		return VolumeJSON{}, err
+	}
+
+	var volumeJSON VolumeJSON
+	if err := json.Unmarshal(contents, &volumeJSON); err != nil {
+		return VolumeJSON{}, err
+	}
+
+	return volumeJSON, nil
+}
+
+// contains reports whether the chapter with the given number is listed
+func (v VolumeJSON) contains(number float32) bool {
+	for _, chapter := range v.Chapters {
+		if chapter.Number == number {
+			return true
+		}
+	}
+
+	return false
+}
+
+// volumeJSON builds VolumeJSON of the volume with the given chapters
+func (c *Client) volumeJSON(
+	ctx context.Context,
+	volume Volume,
+	chapters []Chapter,
+	options DownloadOptions,
+) VolumeJSON {
+	volumeJSON := VolumeJSON{
+		Manga:    c.translate(ctx, volume.Manga().Info().Title),
+		Number:   volume.Info().Number,
+		Title:    c.ComputeVolumeFilename(volume),
+		Chapters: make([]VolumeJSONChapter, len(chapters)),
+	}
+
+	for i, chapter := range chapters {
+		info := c.translateChapter(c.normalizeChapter(chapter)).Info()
+		volumeJSON.Chapters[i] = VolumeJSONChapter{
+			Number: info.Number,
+			Title:  info.Title,
+			URL:    info.URL,
+		}
+	}
+
+	sort.SliceStable(volumeJSON.Chapters, func(i, j int) bool {
+		return volumeJSON.Chapters[i].Number < volumeJSON.Chapters[j].Number
+	})
+
+	// files are not used, since those can't be referenced by url
+	cover, ok, err := c.findCover(c.coverProviders(options), func(provider CoverProvider) (Cover, bool, error) {
+		return provider.VolumeCover(ctx, volume)
+	})
+	if err == nil && ok {
+		volumeJSON.Cover = cover.URL
+	}
+
+	return volumeJSON
+}
+
+func (c *Client) writeVolumeJSON(path string, volumeJSON VolumeJSON) error {
+	c.options.Log(fmt.Sprintf("Writing %s", filepath.Base(path)))
+
+	marshalled, err := json.MarshalIndent(volumeJSON, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return afero.WriteFile(c.options.FS, path, marshalled, modeFile)
+}
+
+// updateVolumeJSON writes volume.json to the directory of the chapter volume,
+// unless the existing one already lists the chapter.
+func (c *Client) updateVolumeJSON(
+	ctx context.Context,
+	chapter Chapter,
+	dir string,
+	options DownloadOptions,
+	existingFS afero.Fs,
+) (bool, error) {
+	path := filepath.Join(dir, filenameVolumeJSON)
+
+	contents, err := afero.ReadFile(existingFS, path)
+	if err == nil {
+		var existing VolumeJSON
+		if json.Unmarshal(contents, &existing) == nil && existing.contains(chapter.Info().Number) {
+			return false, nil
+		}
+	}
+
+	chapters, err := c.VolumeChapters(ctx, chapter.Volume())
+	if err != nil {
+		return false, err
+	}
+
+	volumeJSON := c.volumeJSON(ctx, chapter.Volume(), chapters, options)
+	if err := c.writeVolumeJSON(path, volumeJSON); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
@@ -0,0 +1,100 @@
+package libmangal
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDownloadVolumeJSON(t *testing.T) {
+	provider := newFakeProvider(t, 2, 1)
+	client := newTestClient(t, provider)
+
+	options := testDownloadOptions()
+	options.WriteVolumeJson = true
+	options.CoverProviders = []CoverProvider{StaticCoverProvider{
+		Volumes: map[int]string{1: "https://example.com/volume-1.jpg"},
+	}}
+
+	volume := provider.volume()
+
+	result, err := client.DownloadVolume(context.Background(), volume, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.VolumeJSONWritten {
+		t.Fatal("volume.json was not written")
+	}
+
+	volumeJSON, err := client.ReadVolumeJSON(VolumeJSONPath(result.Path))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := VolumeJSON{
+		Manga:  "Fake Manga",
+		Number: 1,
+		Title:  client.ComputeVolumeFilename(volume),
+		Cover:  "https://example.com/volume-1.jpg",
+		Chapters: []VolumeJSONChapter{
+			{Number: 1, Title: client.normalizeChapterTitle("Chapter 1"), URL: "https://example.com/chapter/1"},
+			{Number: 2, Title: client.normalizeChapterTitle("Chapter 2"), URL: "https://example.com/chapter/2"},
+		},
+	}
+
+	if !reflect.DeepEqual(volumeJSON, want) {
+		t.Errorf("got %+v, want %+v", volumeJSON, want)
+	}
+}
+
+func TestUpdateVolumeJSON(t *testing.T) {
+	provider := newFakeProvider(t, 1, 1)
+	client := newTestClient(t, provider)
+	ctx := context.Background()
+
+	options := testDownloadOptions()
+	options.WriteVolumeJson = true
+	options.CreateVolumeDir = true
+
+	result, err := client.DownloadChapter(ctx, provider.chapterList()[0], options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.VolumeJSONWritten {
+		t.Fatal("volume.json was not written")
+	}
+
+	path := filepath.Join(filepath.Dir(result.Path), filenameVolumeJSON)
+
+	// listed chapters don't rewrite it
+	options.SkipIfExists = false
+
+	result, err = client.DownloadChapter(ctx, provider.chapterList()[0], options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.VolumeJSONWritten {
+		t.Error("volume.json was rewritten for the listed chapter")
+	}
+
+	// new chapters of the volume are added
+	provider.chapters = 2
+
+	result, err = client.DownloadChapter(ctx, provider.chapterList()[1], options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	volumeJSON, err := client.ReadVolumeJSON(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !result.VolumeJSONWritten || len(volumeJSON.Chapters) != 2 || !volumeJSON.contains(2) {
+		t.Errorf("got %+v, want both chapters listed", volumeJSON)
+	}
+}
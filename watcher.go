@@ -0,0 +1,295 @@
+package libmangal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/syncmap"
+	"sort"
+	"time"
+)
+
+// WatcherOptions configures the Watcher
+type WatcherOptions struct {
+	// DefaultInterval is the polling interval of the series
+	// which release cadence is not known yet
+	DefaultInterval time.Duration
+
+	// MinInterval and MaxInterval clamp the polling interval
+	// computed from the release cadence
+	MinInterval time.Duration
+	MaxInterval time.Duration
+
+	// Store keeps the watch state of each series across sessions
+	Store gokv.Store
+
+	// OnNewChapters is called with the chapters that appeared
+	// since the previous check of the series.
+	// Nothing is reported on the first check.
+	OnNewChapters func(ctx context.Context, manga Manga, chapters []Chapter) error
+}
+
+// DefaultWatcherOptions constructs default WatcherOptions
+func DefaultWatcherOptions() WatcherOptions {
+	return WatcherOptions{
+		DefaultInterval: 24 * time.Hour,
+		MinInterval:     time.Hour,
+		MaxInterval:     30 * 24 * time.Hour,
+		Store:           syncmap.NewStore(syncmap.DefaultOptions),
+		OnNewChapters: func(context.Context, Manga, []Chapter) error {
+			return nil
+		},
+	}
+}
+
+// WatchState is the state of the watched series
+type WatchState struct {
+	// Chapters are the numbers of the known chapters
+	Chapters []float32 `json:"chapters"`
+
+	// Releases are the release times of the chapters: ChapterInfo.PublishedAt
+	// if the provider sets it, or the time the chapter was first seen otherwise
+	Releases []time.Time `json:"releases"`
+
+	// Cadence is the typical time between releases. Zero if unknown.
+	Cadence time.Duration `json:"cadence"`
+
+	// LastCheck is the time of the last check
+	LastCheck time.Time `json:"lastCheck"`
+
+	// NextCheck is the time the series is due to be checked
+	NextCheck time.Time `json:"nextCheck"`
+}
+
+// maxWatchReleases is the number of the latest releases kept for the cadence
+const maxWatchReleases = 16
+
+// releaseCadence returns the median interval between the releases.
+// Releases closer than an hour are considered a single batch release.
+func releaseCadence(releases []time.Time) (time.Duration, bool) {
+	sorted := make([]time.Time, len(releases))
+	copy(sorted, releases)
+
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Before(sorted[j])
+	})
+
+	var gaps []time.Duration
+	for i := 1; i < len(sorted); i++ {
+		if gap := sorted[i].Sub(sorted[i-1]); gap >= time.Hour {
+			gaps = append(gaps, gap)
+		}
+	}
+
+	if len(gaps) == 0 {
+		return 0, false
+	}
+
+	sort.Slice(gaps, func(i, j int) bool {
+		return gaps[i] < gaps[j]
+	})
+
+	return gaps[len(gaps)/2], true
+}
+
+// Watcher checks the series of the watched categories (see Category.Watch)
+// for new chapters, polling each series according to its release cadence
+// instead of a fixed global interval, e.g. weekly series are checked weekly.
+// Completed series are not checked, see Client.IsSeriesComplete
+//
+// Watcher.Check is meant to be run periodically, e.g. as a tasks.Job.
+// Check must not be run concurrently with itself,
+// otherwise the same series may be checked twice.
+type Watcher struct {
+	client  *Client
+	options WatcherOptions
+}
+
+// NewWatcher constructs new Watcher for the client
+func NewWatcher(client *Client, options WatcherOptions) *Watcher {
+	return &Watcher{
+		client:  client,
+		options: options,
+	}
+}
+
+func (w *Watcher) stateKey(info MangaInfo) string {
+	return fmt.Sprintf("%s/%s", w.client.Info().ID, info.ID)
+}
+
+// State returns the watch state of the series
+func (w *Watcher) State(info MangaInfo) (WatchState, bool, error) {
+	var state WatchState
+	found, err := w.options.Store.Get(w.stateKey(info), &state)
+	return state, found, err
+}
+
+// Check checks the watched series that are due.
+// Errors of the single series don't stop checking the others
+// and are returned joined.
+func (w *Watcher) Check(ctx context.Context) error {
+	infos, err := w.client.WatchedMangas()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, info := range infos {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		completed, err := w.client.SeriesCompleted(info)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if completed {
+			continue
+		}
+
+		state, _, err := w.State(info)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if time.Now().Before(state.NextCheck) {
+			continue
+		}
+
+		if err := w.check(ctx, info, state); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", info.Title, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (w *Watcher) check(ctx context.Context, info MangaInfo, state WatchState) error {
+	manga, found, err := w.client.FindManga(ctx, info)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return fmt.Errorf("manga not found")
+	}
+
+	now := time.Now()
+
+	// skip getting chapters if the search result tells there are no new ones
+	if withLatest, ok := manga.(MangaWithLatestChapter); ok && !state.LastCheck.IsZero() {
+		if latest, ok := withLatest.LatestChapter(); ok && containsChapterNumber(state.Chapters, latest.Number) {
+			state.LastCheck = now
+			state.NextCheck = now.Add(w.interval(state))
+			return w.options.Store.Set(w.stateKey(info), state)
+		}
+	}
+
+	chapters, err := w.client.mangaChapters(ctx, manga)
+	if err != nil {
+		return err
+	}
+
+	firstCheck := state.LastCheck.IsZero()
+
+	known := make(map[float32]struct{}, len(state.Chapters))
+	for _, number := range state.Chapters {
+		known[number] = struct{}{}
+	}
+
+	var newChapters []Chapter
+	for _, chapter := range chapters {
+		chapterInfo := chapter.Info()
+		if _, ok := known[chapterInfo.Number]; ok {
+			continue
+		}
+
+		known[chapterInfo.Number] = struct{}{}
+		state.Chapters = append(state.Chapters, chapterInfo.Number)
+
+		switch {
+		case !chapterInfo.PublishedAt.IsZero():
+			state.Releases = append(state.Releases, chapterInfo.PublishedAt)
+		case !firstCheck:
+			state.Releases = append(state.Releases, now)
+		}
+
+		newChapters = append(newChapters, chapter)
+	}
+
+	sort.Slice(state.Releases, func(i, j int) bool {
+		return state.Releases[i].Before(state.Releases[j])
+	})
+
+	if len(state.Releases) > maxWatchReleases {
+		state.Releases = state.Releases[len(state.Releases)-maxWatchReleases:]
+	}
+
+	// completion is secondary, Anilist failures must not block new chapters
+	if complete, err := w.client.seriesComplete(ctx, manga, chapters); err != nil {
+		w.client.options.Log(fmt.Sprintf("Can't check completion of %q: %s", info.Title, err))
+	} else if err := w.client.setSeriesCompleted(info, complete); err != nil {
+		return err
+	}
+
+	state.Cadence, _ = releaseCadence(state.Releases)
+	state.LastCheck = now
+	state.NextCheck = now.Add(w.interval(state))
+
+	if len(newChapters) > 0 && !firstCheck {
+		w.client.options.Log(fmt.Sprintf("%d new chapters of %q", len(newChapters), info.Title))
+
+		if err := w.options.OnNewChapters(ctx, manga, newChapters); err != nil {
+			return err
+		}
+	}
+
+	return w.options.Store.Set(w.stateKey(info), state)
+}
+
+// interval returns the polling interval of the series.
+//
+// Series are checked once per their cadence after the last release.
+// Overdue series are checked four times as often until the release appears.
+func (w *Watcher) interval(state WatchState) time.Duration {
+	if state.Cadence == 0 || len(state.Releases) == 0 {
+		return w.clampInterval(w.options.DefaultInterval)
+	}
+
+	interval := state.Cadence
+	if next := state.Releases[len(state.Releases)-1].Add(state.Cadence); next.After(state.LastCheck) {
+		interval = next.Sub(state.LastCheck)
+	} else {
+		interval /= 4
+	}
+
+	return w.clampInterval(interval)
+}
+
+func (w *Watcher) clampInterval(interval time.Duration) time.Duration {
+	if w.options.MinInterval > 0 && interval < w.options.MinInterval {
+		return w.options.MinInterval
+	}
+
+	if w.options.MaxInterval > 0 && interval > w.options.MaxInterval {
+		return w.options.MaxInterval
+	}
+
+	return interval
+}
+
+func containsChapterNumber(numbers []float32, number float32) bool {
+	for _, n := range numbers {
+		if n == number {
+			return true
+		}
+	}
+
+	return false
+}
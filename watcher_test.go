@@ -0,0 +1,176 @@
+package libmangal
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestReleaseCadence(t *testing.T) {
+	start := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	week := 7 * 24 * time.Hour
+
+	if _, ok := releaseCadence([]time.Time{start}); ok {
+		t.Error("cadence of a single release is known")
+	}
+
+	// batch releases count once, the outlier gap doesn't skew the median
+	releases := []time.Time{
+		start.Add(3 * week),
+		start,
+		start.Add(week),
+		start.Add(week + time.Minute),
+		start.Add(2 * week),
+		start.Add(8 * week),
+	}
+
+	cadence, ok := releaseCadence(releases)
+	if !ok || cadence != week {
+		t.Errorf("got cadence %s, %t, want %s", cadence, ok, week)
+	}
+}
+
+func TestWatcherInterval(t *testing.T) {
+	options := DefaultWatcherOptions()
+	watcher := NewWatcher(nil, options)
+
+	now := time.Now()
+	week := 7 * 24 * time.Hour
+
+	for _, test := range []struct {
+		name  string
+		state WatchState
+		want  time.Duration
+	}{
+		{"unknown cadence", WatchState{}, options.DefaultInterval},
+		{
+			"next release is due",
+			WatchState{Cadence: week, Releases: []time.Time{now.Add(-2 * 24 * time.Hour)}, LastCheck: now},
+			5 * 24 * time.Hour,
+		},
+		{
+			"overdue",
+			WatchState{Cadence: week, Releases: []time.Time{now.Add(-2 * week)}, LastCheck: now},
+			week / 4,
+		},
+		{
+			"clamped to the minimum",
+			WatchState{Cadence: 2 * time.Hour, Releases: []time.Time{now.Add(-time.Hour)}, LastCheck: now},
+			options.MinInterval,
+		},
+		{
+			"clamped to the maximum",
+			WatchState{Cadence: 365 * 24 * time.Hour, Releases: []time.Time{now}, LastCheck: now},
+			options.MaxInterval,
+		},
+	} {
+		if got := watcher.interval(test.state); got != test.want {
+			t.Errorf("%s: got %s, want %s", test.name, got, test.want)
+		}
+	}
+}
+
+func TestWatcherCheck(t *testing.T) {
+	provider := newFakeProvider(t, 2, 1)
+	client := newTestClient(t, provider)
+	seedAnilist(t, client, AnilistManga{ID: 1, Status: "RELEASING"})
+
+	manga := provider.manga()
+	if err := client.SetCategory(Category{Name: "Reading", Watch: true}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.AddToCategory(manga, "Reading"); err != nil {
+		t.Fatal(err)
+	}
+
+	var reported [][]float32
+	options := DefaultWatcherOptions()
+	options.OnNewChapters = func(_ context.Context, _ Manga, chapters []Chapter) error {
+		var numbers []float32
+		for _, chapter := range chapters {
+			numbers = append(numbers, chapter.Info().Number)
+		}
+
+		reported = append(reported, numbers)
+		return nil
+	}
+
+	watcher := NewWatcher(client, options)
+	ctx := context.Background()
+
+	// makeDue moves the next check of the series to the past
+	makeDue := func() {
+		t.Helper()
+
+		state, _, err := watcher.State(manga.Info())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		state.NextCheck = time.Now().Add(-time.Minute)
+		if err := options.Store.Set(watcher.stateKey(manga.Info()), state); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// nothing is reported on the first check
+	if err := watcher.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	state, found, err := watcher.State(manga.Info())
+	if err != nil || !found {
+		t.Fatalf("state not found: %v", err)
+	}
+
+	if len(state.Chapters) != 2 || len(reported) != 0 {
+		t.Fatalf("first check: %d chapters known, reported %v", len(state.Chapters), reported)
+	}
+
+	if !state.NextCheck.After(time.Now().Add(options.DefaultInterval - time.Minute)) {
+		t.Errorf("next check at %s, want after the default interval", state.NextCheck)
+	}
+
+	// series is not checked before it's due
+	provider.chapters = 3
+	if err := watcher.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reported) != 0 {
+		t.Fatalf("series was checked before it's due, reported %v", reported)
+	}
+
+	makeDue()
+	if err := watcher.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reported) != 1 || len(reported[0]) != 1 || reported[0][0] != 3 {
+		t.Fatalf("got %v, want chapter 3 reported", reported)
+	}
+
+	// completed series are not checked anymore
+	seedAnilist(t, client, AnilistManga{ID: 1, Status: "FINISHED", Chapters: 3})
+
+	makeDue()
+	if err := watcher.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	completed, err := client.SeriesCompleted(manga.Info())
+	if err != nil || !completed {
+		t.Fatalf("series is not completed: %v", err)
+	}
+
+	provider.chapters = 4
+	makeDue()
+	if err := watcher.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reported) != 1 {
+		t.Errorf("completed series was checked, reported %v", reported)
+	}
+}